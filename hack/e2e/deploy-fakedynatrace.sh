@@ -0,0 +1,27 @@
+#!/bin/bash
+# Deploys the fake Dynatrace tenant to the dynatrace namespace, the kuttl tests reach it at http://fake-dynatrace.dynatrace/api
+missingVars=0
+if [[ -z "${FAKE_DYNATRACE_IMAGE}" ]]; then
+  echo "environment variable FAKE_DYNATRACE_IMAGE not set"
+  missingVars=1
+fi
+if [[ -z "${APITOKEN}" ]]; then
+  echo "environment variable APITOKEN not set"
+  missingVars=1
+fi
+if [[ -z "${PAASTOKEN}" ]]; then
+  echo "environment variable PAASTOKEN not set"
+  missingVars=1
+fi
+if [[ $missingVars = 1 ]]; then
+  exit 1
+fi
+
+kubectl get ns dynatrace || kubectl create ns dynatrace
+
+sed -e "s|FAKE_DYNATRACE_IMAGE|${FAKE_DYNATRACE_IMAGE}|" \
+    -e "s|APITOKEN|${APITOKEN}|" \
+    -e "s|PAASTOKEN|${PAASTOKEN}|" \
+    "$(dirname "$0")/fakedynatrace/fakedynatrace.yaml" | kubectl apply -f -
+
+kubectl -n dynatrace rollout status deployment/fake-dynatrace --timeout=120s
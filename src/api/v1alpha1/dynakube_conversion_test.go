@@ -1,9 +1,9 @@
-package v1beta1
+package v1alpha1
 
 import (
 	"testing"
 
-	"github.com/Dynatrace/dynatrace-operator/src/api/v1alpha1"
+	"github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
@@ -28,30 +28,30 @@ const (
 	testStatusOneAgentInstanceKey = "test-instance"
 )
 
-func TestConversion_ConvertTFrom_Create(t *testing.T) {
+func TestConversion_ConvertTo_Create(t *testing.T) {
 	autoUpdate := true
-	oldDynakube := &v1alpha1.DynaKube{
+	oldDynakube := &DynaKube{
 		ObjectMeta: prepareObjectMeta(),
-		Spec: v1alpha1.DynaKubeSpec{
+		Spec: DynaKubeSpec{
 			APIURL: testAPIURL,
 			Tokens: testToken,
 
-			OneAgent: v1alpha1.OneAgentSpec{
+			OneAgent: OneAgentSpec{
 				AutoUpdate: &autoUpdate,
 			},
 
-			ClassicFullStack: v1alpha1.FullStackSpec{
+			ClassicFullStack: FullStackSpec{
 				Enabled: true,
 			},
-			KubernetesMonitoringSpec: v1alpha1.KubernetesMonitoringSpec{
-				CapabilityProperties: v1alpha1.CapabilityProperties{
+			KubernetesMonitoringSpec: KubernetesMonitoringSpec{
+				CapabilityProperties: CapabilityProperties{
 					Enabled: true,
 				},
 			},
 		},
 	}
-	convertedDynakube := &DynaKube{}
-	err := convertedDynakube.ConvertFrom(oldDynakube)
+	convertedDynakube := &v1beta1.DynaKube{}
+	err := oldDynakube.ConvertTo(convertedDynakube)
 	require.NoError(t, err)
 
 	assert.Equal(t, oldDynakube.ObjectMeta.Namespace, convertedDynakube.ObjectMeta.Namespace)
@@ -64,30 +64,30 @@ func TestConversion_ConvertTFrom_Create(t *testing.T) {
 	assert.Equal(t, oldDynakube.Spec.OneAgent.AutoUpdate, convertedDynakube.Spec.OneAgent.ClassicFullStack.AutoUpdate)
 }
 
-func TestConversion_ConvertFrom(t *testing.T) {
+func TestConversion_ConvertTo(t *testing.T) {
 	trueVal := true
 	time := metav1.Now()
-	oldDynakube := &v1alpha1.DynaKube{
+	oldDynakube := &DynaKube{
 		ObjectMeta: prepareObjectMeta(),
-		Spec: v1alpha1.DynaKubeSpec{
+		Spec: DynaKubeSpec{
 			APIURL:           testUrl,
 			Tokens:           testToken,
 			CustomPullSecret: testCustomPullSecret,
 			SkipCertCheck:    true,
-			Proxy: &v1alpha1.DynaKubeProxy{
+			Proxy: &DynaKubeProxy{
 				Value: testProxyValue,
 			},
 			TrustedCAs:  testTrustedCAs,
 			NetworkZone: testNetworkZone,
 			EnableIstio: true,
 
-			OneAgent: v1alpha1.OneAgentSpec{
+			OneAgent: OneAgentSpec{
 				Version:    testOneAgentVersion,
 				Image:      testOneAgentImage,
 				AutoUpdate: &trueVal,
 			},
 
-			ClassicFullStack: v1alpha1.FullStackSpec{
+			ClassicFullStack: FullStackSpec{
 				Enabled: true,
 				NodeSelector: map[string]string{
 					"key": "value",
@@ -127,19 +127,19 @@ func TestConversion_ConvertFrom(t *testing.T) {
 				UseImmutableImage:   true,
 			},
 
-			ActiveGate: v1alpha1.ActiveGateSpec{
+			ActiveGate: ActiveGateSpec{
 				Image:      testActiveGateImage,
 				AutoUpdate: &trueVal,
 			},
 
-			RoutingSpec: v1alpha1.RoutingSpec{
+			RoutingSpec: RoutingSpec{
 				CapabilityProperties: prepareAlphaCapability(),
 			},
-			KubernetesMonitoringSpec: v1alpha1.KubernetesMonitoringSpec{
+			KubernetesMonitoringSpec: KubernetesMonitoringSpec{
 				CapabilityProperties: prepareAlphaCapability(),
 			},
 		},
-		Status: v1alpha1.DynaKubeStatus{
+		Status: DynaKubeStatus{
 			Phase:                            "test-phase",
 			UpdatedTimestamp:                 time,
 			LastAPITokenProbeTimestamp:       &time,
@@ -157,22 +157,22 @@ func TestConversion_ConvertFrom(t *testing.T) {
 					Message:            "message",
 				},
 			},
-			ActiveGate: v1alpha1.ActiveGateStatus{
-				ImageStatus: v1alpha1.ImageStatus{
+			ActiveGate: ActiveGateStatus{
+				ImageStatus: ImageStatus{
 					ImageHash:               "test-activegate-imagehash",
 					ImageVersion:            "test-activegate-imageversion",
 					LastImageProbeTimestamp: &time,
 				},
 			},
-			OneAgent: v1alpha1.OneAgentStatus{
-				ImageStatus: v1alpha1.ImageStatus{
+			OneAgent: OneAgentStatus{
+				ImageStatus: ImageStatus{
 					ImageHash:               "test-oneagent-imagehash",
 					ImageVersion:            "test-oneagent-imageversion",
 					LastImageProbeTimestamp: &time,
 				},
 				UseImmutableImage: true,
 				Version:           "test-oneagent-version",
-				Instances: map[string]v1alpha1.OneAgentInstance{
+				Instances: map[string]OneAgentInstance{
 					testStatusOneAgentInstanceKey: {
 						PodName:   "test-instance-podname",
 						Version:   "test-instance-version",
@@ -184,8 +184,8 @@ func TestConversion_ConvertFrom(t *testing.T) {
 		},
 	}
 
-	convertedDynakube := &DynaKube{}
-	err := convertedDynakube.ConvertFrom(oldDynakube)
+	convertedDynakube := &v1beta1.DynaKube{}
+	err := oldDynakube.ConvertTo(convertedDynakube)
 	require.NoError(t, err)
 
 	assert.Equal(t, oldDynakube.ObjectMeta.Namespace, convertedDynakube.ObjectMeta.Namespace)
@@ -255,13 +255,13 @@ func prepareObjectMeta() metav1.ObjectMeta {
 	}
 }
 
-func prepareAlphaCapability() v1alpha1.CapabilityProperties {
+func prepareAlphaCapability() CapabilityProperties {
 	intVal := int32(3)
-	return v1alpha1.CapabilityProperties{
+	return CapabilityProperties{
 		Enabled:  true,
 		Replicas: &intVal,
 		Group:    "test-activegate-group",
-		CustomProperties: &v1alpha1.DynaKubeValueSource{
+		CustomProperties: &DynaKubeValueSource{
 			Value: "test-routing-value",
 		},
 		Resources: corev1.ResourceRequirements{
@@ -298,7 +298,7 @@ func prepareAlphaCapability() v1alpha1.CapabilityProperties {
 	}
 }
 
-func compareAlphaCapability(t *testing.T, expectedCapability v1alpha1.CapabilityProperties, actualCapability CapabilityProperties) {
+func compareAlphaCapability(t *testing.T, expectedCapability CapabilityProperties, actualCapability v1beta1.CapabilityProperties) {
 	assert.Equal(t, expectedCapability.Replicas, actualCapability.Replicas)
 	assert.Equal(t, expectedCapability.Group, actualCapability.Group)
 	assert.Equal(t, expectedCapability.CustomProperties.ValueFrom, actualCapability.CustomProperties.ValueFrom)
@@ -310,24 +310,24 @@ func compareAlphaCapability(t *testing.T, expectedCapability v1alpha1.Capability
 	assert.Equal(t, expectedCapability.Env, actualCapability.Env)
 }
 
-func TestConversion_ConvertTo(t *testing.T) {
+func TestConversion_ConvertFrom(t *testing.T) {
 	time := metav1.Now()
-	oldDynakube := &DynaKube{
+	oldDynakube := &v1beta1.DynaKube{
 		ObjectMeta: prepareObjectMeta(),
-		Spec: DynaKubeSpec{
+		Spec: v1beta1.DynaKubeSpec{
 			APIURL:           testUrl,
 			Tokens:           testToken,
 			CustomPullSecret: testCustomPullSecret,
 			SkipCertCheck:    true,
-			Proxy: &DynaKubeProxy{
+			Proxy: &v1beta1.DynaKubeProxy{
 				Value: testProxyValue,
 			},
 			TrustedCAs:  testTrustedCAs,
 			NetworkZone: testNetworkZone,
 			EnableIstio: true,
 
-			OneAgent: OneAgentSpec{
-				ClassicFullStack: &HostInjectSpec{
+			OneAgent: v1beta1.OneAgentSpec{
+				ClassicFullStack: &v1beta1.HostInjectSpec{
 					Image:   testOneAgentImage,
 					Version: testOneAgentVersion,
 					NodeSelector: map[string]string{
@@ -367,17 +367,17 @@ func TestConversion_ConvertTo(t *testing.T) {
 				},
 			},
 
-			Routing: RoutingSpec{
+			Routing: v1beta1.RoutingSpec{
 				Enabled:              true,
 				CapabilityProperties: prepareBetaCapability(),
 			},
 
-			KubernetesMonitoring: KubernetesMonitoringSpec{
+			KubernetesMonitoring: v1beta1.KubernetesMonitoringSpec{
 				Enabled:              true,
 				CapabilityProperties: prepareBetaCapability(),
 			},
 		},
-		Status: DynaKubeStatus{
+		Status: v1beta1.DynaKubeStatus{
 			Phase:                            "test-phase",
 			UpdatedTimestamp:                 time,
 			LastAPITokenProbeTimestamp:       &time,
@@ -394,9 +394,9 @@ func TestConversion_ConvertTo(t *testing.T) {
 					Message:            "message",
 				},
 			},
-			ActiveGate: ActiveGateStatus{},
-			OneAgent: OneAgentStatus{
-				Instances: map[string]OneAgentInstance{
+			ActiveGate: v1beta1.ActiveGateStatus{},
+			OneAgent: v1beta1.OneAgentStatus{
+				Instances: map[string]v1beta1.OneAgentInstance{
 					testStatusOneAgentInstanceKey: {
 						PodName:   "test-instance-podname",
 						IPAddress: "test-instance-ip",
@@ -406,8 +406,8 @@ func TestConversion_ConvertTo(t *testing.T) {
 		},
 	}
 
-	convertedDynakube := &v1alpha1.DynaKube{}
-	err := oldDynakube.ConvertTo(convertedDynakube)
+	convertedDynakube := &DynaKube{}
+	err := convertedDynakube.ConvertFrom(oldDynakube)
 	require.NoError(t, err)
 
 	assert.Equal(t, oldDynakube.ObjectMeta.Namespace, convertedDynakube.ObjectMeta.Namespace)
@@ -468,7 +468,7 @@ func TestConversion_ConvertTo(t *testing.T) {
 	assert.Equal(t, oldDynakube.Status.UpdatedTimestamp, convertedDynakube.Status.UpdatedTimestamp)
 }
 
-func compareBetaCapability(t *testing.T, expectedCapability CapabilityProperties, actualCapability v1alpha1.CapabilityProperties) {
+func compareBetaCapability(t *testing.T, expectedCapability v1beta1.CapabilityProperties, actualCapability CapabilityProperties) {
 	assert.Equal(t, expectedCapability.Replicas, actualCapability.Replicas)
 	assert.Equal(t, expectedCapability.Group, actualCapability.Group)
 	assert.Equal(t, expectedCapability.CustomProperties.ValueFrom, actualCapability.CustomProperties.ValueFrom)
@@ -480,12 +480,12 @@ func compareBetaCapability(t *testing.T, expectedCapability CapabilityProperties
 	assert.Equal(t, expectedCapability.Env, actualCapability.Env)
 }
 
-func prepareBetaCapability() CapabilityProperties {
+func prepareBetaCapability() v1beta1.CapabilityProperties {
 	intVal := int32(3)
-	return CapabilityProperties{
+	return v1beta1.CapabilityProperties{
 		Replicas: &intVal,
 		Group:    "test-activegate-group",
-		CustomProperties: &DynaKubeValueSource{
+		CustomProperties: &v1beta1.DynaKubeValueSource{
 			Value: "test-routing-value",
 		},
 		Resources: corev1.ResourceRequirements{
@@ -10,6 +10,9 @@ type DynaKubeStatus struct {
 	// Defines the current state (Running, Updating, Error, ...)
 	Phase DynaKubePhaseType `json:"phase,omitempty"`
 
+	// ObservedGeneration is the generation of the DynaKube which was reconciled last, the conditions reflect this generation
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// UpdatedTimestamp indicates when the instance was last updated
 	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Last Updated"
@@ -25,6 +28,12 @@ type DynaKubeStatus struct {
 	// LastDataIngestTokenProbeTimestamp tracks when the last request for the DataIngest token validity was sent
 	LastDataIngestTokenProbeTimestamp *metav1.Time `json:"lastDataIngestTokenProbeTimestamp,omitempty"`
 
+	// TokenSecretHash is the hash of the tokens secret at the last token probe, the tokens are probed again as soon as it changes
+	TokenSecretHash string `json:"tokenSecretHash,omitempty"`
+
+	// TokenExpirations contains the expiration dates of the tokens which expire soon by token name, they are kept between token probes
+	TokenExpirations map[string]metav1.Time `json:"tokenExpirations,omitempty"`
+
 	// Credentials used to connect back to Dynatrace.
 	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="API and PaaS Tokens"
@@ -43,12 +52,18 @@ type DynaKubeStatus struct {
 	// CommunicationHostForClient caches a communication host specific to the api url.
 	CommunicationHostForClient CommunicationHostStatus `json:"communicationHostForClient,omitempty"`
 
+	// ActiveAPIURL is the API URL which was reachable during the last reconciliation, it differs from the apiUrl after a failover
+	ActiveAPIURL string `json:"activeApiUrl,omitempty"`
+
 	// LatestAgentVersionUnixDefault caches the current agent version for unix and the default installer which is configured for the environment
 	LatestAgentVersionUnixDefault string `json:"latestAgentVersionUnixDefault,omitempty"`
 
 	// LatestAgentVersionUnixDefault caches the current agent version for unix and the PaaS installer which is configured for the environment
 	LatestAgentVersionUnixPaas string `json:"latestAgentVersionUnixPaas,omitempty"`
 
+	// MaintenanceWindow shows whether updates are currently allowed by the maintenance windows, and when the next window opens
+	MaintenanceWindow MaintenanceWindowStatus `json:"maintenanceWindow,omitempty"`
+
 	// Conditions includes status about the current state of the instance
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
@@ -56,6 +71,7 @@ type DynaKubeStatus struct {
 	ExtensionController EecStatus        `json:"eec,omitempty"`
 	Statsd              StatsdStatus     `json:"statsd,omitempty"`
 	OneAgent            OneAgentStatus   `json:"oneAgent,omitempty"`
+	Settings            SettingsStatus   `json:"settings,omitempty"`
 }
 
 type ConnectionInfoStatus struct {
@@ -129,6 +145,61 @@ type OneAgentStatus struct {
 
 	// LastHostsRequestTimestamp indicates the last timestamp the Operator queried for hosts
 	LastHostsRequestTimestamp *metav1.Time `json:"lastHostsRequestTimestamp,omitempty"`
+
+	// Rollout is the state of the staged rollout of the OneAgent pods, if enabled
+	Rollout *OneAgentRolloutStatus `json:"rollout,omitempty"`
+}
+
+type OneAgentRolloutPhase string
+
+const (
+	// RolloutCanary means the pods on the canary nodes are being updated
+	RolloutCanary OneAgentRolloutPhase = "Canary"
+	// RolloutSoaking means the canary pods are ready and must stay healthy for the soak duration
+	RolloutSoaking OneAgentRolloutPhase = "Soaking"
+	// RolloutProgressing means the pods on the remaining nodes are being updated
+	RolloutProgressing OneAgentRolloutPhase = "Progressing"
+	// RolloutComplete means all pods are up to date
+	RolloutComplete OneAgentRolloutPhase = "Complete"
+	// RolloutFailed means the canary pods didn't become healthy, the rollout is stopped
+	RolloutFailed OneAgentRolloutPhase = "Failed"
+)
+
+type OneAgentRolloutStatus struct {
+	Phase OneAgentRolloutPhase `json:"phase,omitempty"`
+
+	// PhaseTimestamp indicates when the current phase started
+	PhaseTimestamp *metav1.Time `json:"phaseTimestamp,omitempty"`
+
+	// TemplateHash is the hash of the OneAgent pod template which is rolled out
+	TemplateHash string `json:"templateHash,omitempty"`
+
+	// Version is the OneAgent version which is rolled out
+	Version string `json:"version,omitempty"`
+
+	// PreviousVersion is the OneAgent version of the last complete rollout, it is restored if the rollout fails
+	PreviousVersion string `json:"previousVersion,omitempty"`
+
+	// CanaryNodes are the nodes which are updated first
+	CanaryNodes []string `json:"canaryNodes,omitempty"`
+
+	// FailedVersion is the OneAgent version of the last failed rollout, it is not rolled out again automatically
+	FailedVersion string `json:"failedVersion,omitempty"`
+
+	// Message describes the progress of the rollout or the cause of a failure
+	Message string `json:"message,omitempty"`
+}
+
+// InProgress returns true while pods are updated or the canary pods are soaking
+func (rollout *OneAgentRolloutStatus) InProgress() bool {
+	if rollout == nil {
+		return false
+	}
+	switch rollout.Phase {
+	case RolloutCanary, RolloutSoaking, RolloutProgressing:
+		return true
+	}
+	return false
 }
 
 func (oneAgentStatus *OneAgentStatus) Name() string {
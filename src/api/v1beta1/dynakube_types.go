@@ -1,6 +1,8 @@
 package v1beta1
 
 import (
+	"strings"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -14,6 +16,16 @@ const (
 	// DataIngestTokenConditionType identifies the DataIngest Token validity condition
 	DataIngestTokenConditionType string = "DataIngestToken"
 
+	// TokenExpiringConditionType identifies the condition listing tokens which expire soon, it is removed if there are none
+	TokenExpiringConditionType string = "TokenExpiring"
+
+	// DynatraceEventsConditionType identifies the condition about the events.ingest scope of the API token, which is needed
+	// to send events about rollouts and version changes, it is removed if events are disabled
+	DynatraceEventsConditionType string = "DynatraceEvents"
+
+	// PausedConditionType identifies the condition which is set while the reconciliation is paused, it is removed afterwards
+	PausedConditionType string = "Paused"
+
 	OperatorName = "dynatrace-operator"
 )
 
@@ -36,8 +48,78 @@ const (
 
 	// ReasonTokenError is set when an unknown error has been found when verifying the token
 	ReasonTokenError string = "TokenError"
+
+	// ReasonTokenExpired is set when the expiration date of a token has passed
+	ReasonTokenExpired string = "TokenExpired"
+
+	// ReasonTokenExpiring is set on the TokenExpiring condition when a token expires soon
+	ReasonTokenExpiring string = "TokenExpiring"
+)
+
+// Conditions about the components deployed for a DynaKube, they are removed if the component is not needed
+const (
+	// OneAgentReadyConditionType identifies the condition about the rollout of the OneAgent DaemonSet
+	OneAgentReadyConditionType string = "OneAgentReady"
+
+	// CodeModulesAvailableConditionType identifies the condition about the availability of code modules for injected pods
+	CodeModulesAvailableConditionType string = "CodeModulesAvailable"
+
+	// WebhookReachableConditionType identifies the condition about the availability of the webhook injecting pods
+	WebhookReachableConditionType string = "WebhookReachable"
+
+	// IstioConfiguredConditionType identifies the condition about the Istio objects allowing access to the Dynatrace environment
+	IstioConfiguredConditionType string = "IstioConfigured"
+
+	activeGateConditionTypePrefix = "ActiveGate"
+	readyConditionTypeSuffix      = "Ready"
+)
+
+// Possible reasons for component conditions
+const (
+	// ReasonRolloutComplete is set when all pods of a workload are updated and available
+	ReasonRolloutComplete string = "RolloutComplete"
+
+	// ReasonRolloutInProgress is set when pods of a workload are not updated or not available yet
+	ReasonRolloutInProgress string = "RolloutInProgress"
+
+	// ReasonWorkloadNotFound is set when the DaemonSet, StatefulSet or Deployment of a component doesn't exist
+	ReasonWorkloadNotFound string = "WorkloadNotFound"
+
+	// ReasonWorkloadError is set when the DaemonSet, StatefulSet or Deployment of a component couldn't be queried
+	ReasonWorkloadError string = "WorkloadError"
+
+	// ReasonInstallerDownload is set on the CodeModulesAvailable condition when injected pods download the code modules themselves
+	ReasonInstallerDownload string = "InstallerDownload"
+
+	// ReasonIstioReconciled is set when the Istio objects are up to date
+	ReasonIstioReconciled string = "IstioReconciled"
+
+	// ReasonIstioError is set when the Istio objects couldn't be reconciled
+	ReasonIstioError string = "IstioError"
+
+	// ReasonReconciliationPaused is set on the Paused condition
+	ReasonReconciliationPaused string = "ReconciliationPaused"
 )
 
+// ActiveGateReadyConditionType returns the type of the condition about the rollout of the StatefulSet providing the
+// given capability, e.g. "ActiveGateKubernetesMonitoringReady" for "kubernetes-monitoring"
+func ActiveGateReadyConditionType(capability CapabilityDisplayName) string {
+	conditionType := activeGateConditionTypePrefix
+	for _, word := range strings.Split(string(capability), "-") {
+		if word != "" {
+			conditionType += strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return conditionType + readyConditionTypeSuffix
+}
+
+// IsActiveGateReadyConditionType returns true for condition types returned by ActiveGateReadyConditionType
+func IsActiveGateReadyConditionType(conditionType string) bool {
+	return strings.HasPrefix(conditionType, activeGateConditionTypePrefix) &&
+		strings.HasSuffix(conditionType, readyConditionTypeSuffix) &&
+		len(conditionType) > len(activeGateConditionTypePrefix)+len(readyConditionTypeSuffix)
+}
+
 type DynaKubeProxy struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Proxy value",order=32,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:text"}
 	Value string `json:"value,omitempty"`
@@ -55,7 +137,6 @@ type DynaKubeValueSource struct {
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
-//+kubebuilder:storageversion
 
 // DynaKube is the Schema for the DynaKube API
 // +k8s:openapi-gen=true
@@ -85,6 +166,12 @@ type DynaKubeSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="API URL",order=1,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
 	APIURL string `json:"apiUrl"`
 
+	// Optional: API URLs of the same Dynatrace environment which are used in this order by the operator if the apiUrl can't
+	// be reached, e.g. the other cluster nodes of a Managed cluster or a disaster recovery environment
+	// The ActiveGate doesn't fail over to these URLs, it connects to the communication endpoints of the environment
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Failover API URLs",order=1,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:text"}
+	FailoverAPIURLs []string `json:"failoverApiUrls,omitempty"`
+
 	// Credentials for the DynaKube to connect back to Dynatrace.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Tenant specific secrets",order=2,xDescriptors="urn:alm:descriptor:io.kubernetes:Secret"
 	Tokens string `json:"tokens,omitempty"`
@@ -107,6 +194,11 @@ type DynaKubeSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Trusted CAs",order=6,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:io.kubernetes:ConfigMap"}
 	TrustedCAs string `json:"trustedCAs,omitempty"`
 
+	// Optional: Adds a client certificate from a secret of type kubernetes.io/tls, with the fields 'tls.crt' and 'tls.key'
+	// It is presented when communicating with the Dynatrace API and the image registry, e.g. for TLS-inspecting gateways requiring mutual TLS
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Client Certificate",order=6,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:io.kubernetes:Secret"}
+	ClientCertificate string `json:"clientCertificate,omitempty"`
+
 	// Optional: Sets Network Zone for OneAgent and ActiveGate pods
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Network Zone",order=7,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:text"}
 	NetworkZone string `json:"networkZone,omitempty"`
@@ -131,6 +223,21 @@ type DynaKubeSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ActiveGate",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
 	ActiveGate ActiveGateSpec `json:"activeGate,omitempty"`
 
+	// Optional: Settings 2.0 objects which the operator creates and keeps in sync with the Dynatrace environment
+	// Changes made in the environment to the listed properties are reverted and reported in the status
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Settings",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	Settings []SettingsObjectSpec `json:"settings,omitempty"`
+
+	// Optional: Recurring maintenance windows, if set the OneAgent and ActiveGate versions, the OneAgent DaemonSet
+	// and the code modules version provided by the CSI driver are only updated while a window is open
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Maintenance Windows",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	MaintenanceWindows []MaintenanceWindowSpec `json:"maintenanceWindows,omitempty"`
+
+	// Optional: Pauses the reconciliation, while set the operator doesn't change the deployed components, the Dynatrace
+	// environment or the code modules provided by the CSI driver for this DynaKube, the status is still updated
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Paused",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	Paused bool `json:"paused,omitempty"`
+
 	//  Deprecated: Configuration for Routing
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Routing"
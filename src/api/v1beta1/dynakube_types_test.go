@@ -0,0 +1,15 @@
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActiveGateReadyConditionType(t *testing.T) {
+	assert.Equal(t, "ActiveGateKubernetesMonitoringReady", ActiveGateReadyConditionType(KubeMonCapability.DisplayName))
+	assert.Equal(t, "ActiveGateRoutingReady", ActiveGateReadyConditionType(RoutingCapability.DisplayName))
+	assert.True(t, IsActiveGateReadyConditionType("ActiveGateMetricsIngestReady"))
+	assert.False(t, IsActiveGateReadyConditionType("ActiveGateReady"))
+	assert.False(t, IsActiveGateReadyConditionType(OneAgentReadyConditionType))
+}
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/src/logger"
 )
@@ -43,7 +44,9 @@ const (
 	AnnotationFeatureCustomStatsdImage           = AnnotationFeaturePrefix + "custom-statsd-image"
 
 	// dtClient
-	AnnotationFeatureDisableHostsRequests = AnnotationFeaturePrefix + "disable-hosts-requests"
+	AnnotationFeatureDisableHostsRequests   = AnnotationFeaturePrefix + "disable-hosts-requests"
+	AnnotationFeatureApiResponseCacheTTL    = AnnotationFeaturePrefix + "api-response-cache-ttl"
+	AnnotationFeatureDisableDynatraceEvents = AnnotationFeaturePrefix + "disable-dynatrace-events"
 
 	// oneAgent
 	AnnotationFeatureOneAgentMaxUnavailable       = AnnotationFeaturePrefix + "oneagent-max-unavailable"
@@ -55,8 +58,32 @@ const (
 	AnnotationFeatureIgnoreUnknownState              = AnnotationFeaturePrefix + "ignore-unknown-state"
 	AnnotationFeatureIgnoredNamespaces               = AnnotationFeaturePrefix + "ignored-namespaces"
 	AnnotationFeatureDisableMetadataEnrichment       = AnnotationFeaturePrefix + "disable-metadata-enrichment"
+	AnnotationFeatureInjectionDryRun                 = AnnotationFeaturePrefix + "injection-dry-run"
+	AnnotationFeatureWorkloadOwnerKinds              = AnnotationFeaturePrefix + "workload-owner-kinds"
 )
 
+const defaultApiResponseCacheTTL = 60 * time.Second
+
+// DefaultWorkloadOwnerKinds are the owner kinds which are always followed to find the workload of a pod
+var DefaultWorkloadOwnerKinds = []string{
+	"ReplicaSet.apps",
+	"Deployment.apps",
+	"ReplicationController",
+	"StatefulSet.apps",
+	"DaemonSet.apps",
+	"Job.batch",
+	"CronJob.batch",
+	"DeploymentConfig.apps.openshift.io",
+	"Rollout.argoproj.io",
+	"Revision.serving.knative.dev",
+	"Configuration.serving.knative.dev",
+	"Service.serving.knative.dev",
+	"VirtualMachineInstance.kubevirt.io",
+	"VirtualMachine.kubevirt.io",
+	"SparkApplication.sparkoperator.k8s.io",
+	"ScheduledSparkApplication.sparkoperator.k8s.io",
+}
+
 var (
 	log = logger.NewDTLogger().WithName("dynakube-api")
 )
@@ -71,14 +98,27 @@ func (dk *DynaKube) FeatureDisableHostsRequests() bool {
 	return dk.getFeatureFlagRaw(AnnotationFeatureDisableHostsRequests) == "true"
 }
 
-// FeatureOneAgentMaxUnavailable is a feature flag to configure maxUnavailable on the OneAgent DaemonSets rolling upgrades.
-func (dk *DynaKube) FeatureOneAgentMaxUnavailable() int {
-	raw := dk.getFeatureFlagRaw(AnnotationFeatureOneAgentMaxUnavailable)
-	if raw == "" {
-		return 1
+// FeatureDisableDynatraceEvents is a feature flag to disable sending events about rollouts and version changes
+// to the Kubernetes cluster entity in Dynatrace.
+func (dk *DynaKube) FeatureDisableDynatraceEvents() bool {
+	return dk.getFeatureFlagRaw(AnnotationFeatureDisableDynatraceEvents) == "true"
+}
+
+// FeatureApiResponseCacheTTL is a feature flag to configure for how many seconds responses of the Dynatrace API
+// for tenant metadata (connection info, latest agent versions) are reused. 0 disables the cache.
+// Defaults to 60
+func (dk *DynaKube) FeatureApiResponseCacheTTL() time.Duration {
+	val, err := strconv.Atoi(dk.getFeatureFlagRaw(AnnotationFeatureApiResponseCacheTTL))
+	if err != nil {
+		return defaultApiResponseCacheTTL
 	}
 
-	val, err := strconv.Atoi(raw)
+	return time.Duration(val) * time.Second
+}
+
+// FeatureOneAgentMaxUnavailable is a feature flag to configure maxUnavailable on the OneAgent DaemonSets rolling upgrades.
+func (dk *DynaKube) FeatureOneAgentMaxUnavailable() int {
+	val, err := strconv.Atoi(dk.getFeatureFlagRaw(AnnotationFeatureOneAgentMaxUnavailable))
 	if err != nil {
 		return 1
 	}
@@ -105,13 +145,11 @@ func (dk *DynaKube) FeatureIgnoredNamespaces() []string {
 	if raw == "" {
 		return dk.getDefaultIgnoredNamespaces()
 	}
-	ignoredNamespaces := &[]string{}
-	err := json.Unmarshal([]byte(raw), ignoredNamespaces)
-	if err != nil {
-		log.Error(err, "failed to unmarshal ignoredNamespaces feature-flag")
+	ignoredNamespaces := []string{}
+	if err := json.Unmarshal([]byte(raw), &ignoredNamespaces); err != nil {
 		return dk.getDefaultIgnoredNamespaces()
 	}
-	return *ignoredNamespaces
+	return ignoredNamespaces
 }
 
 func (dk *DynaKube) getDefaultIgnoredNamespaces() []string {
@@ -134,6 +172,27 @@ func (dk *DynaKube) FeatureDisableMetadataEnrichment() bool {
 	return dk.getFeatureFlagRaw(AnnotationFeatureDisableMetadataEnrichment) == "true"
 }
 
+// FeatureInjectionDryRun is a feature flag to only annotate pods with a summary of the injection instead of injecting them
+func (dk *DynaKube) FeatureInjectionDryRun() bool {
+	return dk.getFeatureFlagRaw(AnnotationFeatureInjectionDryRun) == "true"
+}
+
+// FeatureWorkloadOwnerKinds is a feature flag for additional owner kinds which are followed to find the workload of a pod
+// for the metadata enrichment, in the form "Kind.group", e.g. "Rollout.argoproj.io", or just "Kind" for the core group.
+// The webhook needs permissions to get objects of these kinds. The configured kinds are added to DefaultWorkloadOwnerKinds.
+func (dk *DynaKube) FeatureWorkloadOwnerKinds() []string {
+	ownerKinds := append([]string{}, DefaultWorkloadOwnerKinds...)
+	raw := dk.getFeatureFlagRaw(AnnotationFeatureWorkloadOwnerKinds)
+	if raw == "" {
+		return ownerKinds
+	}
+	additionalOwnerKinds := []string{}
+	if err := json.Unmarshal([]byte(raw), &additionalOwnerKinds); err != nil {
+		return ownerKinds
+	}
+	return append(ownerKinds, additionalOwnerKinds...)
+}
+
 // FeatureUseActiveGateImageForStatsd is a feature flag that makes the operator use ActiveGate image when initializing Extension Controller and Statsd containers
 // (using special predefined entry points).
 func (dk *DynaKube) FeatureUseActiveGateImageForStatsd() bool {
@@ -180,12 +239,27 @@ func (dk *DynaKube) FeatureActiveGateAppArmor() bool {
 	return dk.getFeatureFlagRaw(AnnotationFeatureActiveGateAppArmor) == "true"
 }
 
-func (dk *DynaKube) getFeatureFlagRaw(annotation string) string {
+// FeatureFlagValue returns the raw value of the feature flag annotation and whether it is set, the annotation without
+// the deprecated 'alpha.' prefix takes precedence
+func (dk *DynaKube) FeatureFlagValue(annotation string) (string, bool) {
 	if raw, ok := dk.Annotations[annotation]; ok {
-		return raw
+		return raw, true
 	}
 	if raw, ok := dk.Annotations[DeprecatedFeatureFlagPrefix+annotation]; ok {
+		return raw, true
+	}
+	return "", false
+}
+
+// getFeatureFlagRaw returns the value of the feature flag, or its default if it isn't set or can't be parsed
+func (dk *DynaKube) getFeatureFlagRaw(annotation string) string {
+	flag, known := LookupFeatureFlag(annotation)
+	raw, ok := dk.FeatureFlagValue(annotation)
+	if !known {
 		return raw
 	}
-	return ""
+	if !ok || flag.Validate(raw) != nil {
+		return flag.Default
+	}
+	return raw
 }
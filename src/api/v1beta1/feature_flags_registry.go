@@ -0,0 +1,139 @@
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+type FeatureFlagType string
+
+const (
+	FeatureFlagTypeBool       FeatureFlagType = "boolean"
+	FeatureFlagTypeInt        FeatureFlagType = "non-negative integer"
+	FeatureFlagTypeString     FeatureFlagType = "string"
+	FeatureFlagTypeStringList FeatureFlagType = "JSON list of strings"
+	FeatureFlagTypeQuantity   FeatureFlagType = "resource quantity"
+)
+
+// FeatureFlag describes a feature flag annotation of the DynaKube
+// +kubebuilder:object:generate=false
+type FeatureFlag struct {
+	Annotation string
+	Type       FeatureFlagType
+
+	// Default is the value which is used if the flag isn't set or its value can't be parsed, empty if the default
+	// depends on the DynaKube
+	Default string
+
+	// Deprecated describes what to use instead, empty if the flag isn't deprecated
+	Deprecated string
+}
+
+var featureFlags = newFeatureFlagRegistry(
+	// activeGate
+	FeatureFlag{Annotation: AnnotationFeatureDisableActiveGateUpdates, Type: FeatureFlagTypeBool, Default: "false", Deprecated: replacedBy("activeGateOptions.disableUpdates")},
+	FeatureFlag{Annotation: AnnotationFeatureDisableActivegateRawImage, Type: FeatureFlagTypeBool, Default: "false", Deprecated: replacedBy("activeGateOptions.disableRawImage")},
+	FeatureFlag{Annotation: AnnotationFeatureActiveGateAppArmor, Type: FeatureFlagTypeBool, Default: "false", Deprecated: replacedBy("activeGateOptions.appArmor")},
+	FeatureFlag{Annotation: AnnotationFeatureActiveGateReadOnlyFilesystem, Type: FeatureFlagTypeBool, Default: "false", Deprecated: replacedBy("activeGateOptions.readOnlyFilesystem")},
+	FeatureFlag{Annotation: AnnotationFeatureAutomaticKubernetesApiMonitoring, Type: FeatureFlagTypeBool, Default: "false", Deprecated: replacedBy("activeGateOptions.automaticKubernetesApiMonitoring")},
+
+	// statsD
+	FeatureFlag{Annotation: AnnotationFeatureUseActiveGateImageForStatsd, Type: FeatureFlagTypeBool, Default: "false", Deprecated: replacedBy("activeGateOptions.statsd.useActiveGateImage")},
+	FeatureFlag{Annotation: AnnotationFeatureCustomEecImage, Type: FeatureFlagTypeString, Deprecated: replacedBy("activeGateOptions.extensionController.image")},
+	FeatureFlag{Annotation: AnnotationFeatureCustomStatsdImage, Type: FeatureFlagTypeString, Deprecated: replacedBy("activeGateOptions.statsd.image")},
+
+	// dtClient
+	FeatureFlag{Annotation: AnnotationFeatureDisableHostsRequests, Type: FeatureFlagTypeBool, Default: "false", Deprecated: replacedBy("dynatraceApi.disableHostsRequests")},
+	FeatureFlag{Annotation: AnnotationFeatureApiResponseCacheTTL, Type: FeatureFlagTypeInt, Default: strconv.Itoa(int(defaultApiResponseCacheTTL.Seconds())), Deprecated: replacedBy("dynatraceApi.responseCacheTTLSeconds")},
+	FeatureFlag{Annotation: AnnotationFeatureDisableDynatraceEvents, Type: FeatureFlagTypeBool, Default: "false", Deprecated: replacedBy("dynatraceApi.disableEvents")},
+
+	// oneAgent
+	FeatureFlag{Annotation: AnnotationFeatureOneAgentMaxUnavailable, Type: FeatureFlagTypeInt, Default: "1", Deprecated: replacedBy("oneAgentOptions.maxUnavailable")},
+	FeatureFlag{Annotation: AnnotationFeatureDisableReadOnlyOneAgent, Type: FeatureFlagTypeBool, Default: "false", Deprecated: replacedBy("oneAgentOptions.disableReadOnlyHostFs")},
+	FeatureFlag{Annotation: AnnotationFeatureEnableMultipleOsAgentsOnNode, Type: FeatureFlagTypeBool, Default: "false", Deprecated: replacedBy("oneAgentOptions.multipleOsAgentsOnNode")},
+
+	// injection (webhook)
+	FeatureFlag{Annotation: AnnotationFeatureEnableWebhookReinvocationPolicy, Type: FeatureFlagTypeBool, Default: "false", Deprecated: replacedBy("injection.reinvocationPolicy")},
+	FeatureFlag{Annotation: AnnotationFeatureIgnoreUnknownState, Type: FeatureFlagTypeBool, Default: "false", Deprecated: replacedBy("injection.ignoreUnknownState")},
+	FeatureFlag{Annotation: AnnotationFeatureIgnoredNamespaces, Type: FeatureFlagTypeStringList, Deprecated: replacedBy("injection.ignoredNamespaces")},
+	FeatureFlag{Annotation: AnnotationFeatureDisableMetadataEnrichment, Type: FeatureFlagTypeBool, Default: "false", Deprecated: replacedBy("injection.disableMetadataEnrichment")},
+	FeatureFlag{Annotation: AnnotationFeatureInjectionDryRun, Type: FeatureFlagTypeBool, Default: "false", Deprecated: replacedBy("injection.dryRun")},
+	FeatureFlag{Annotation: AnnotationFeatureWorkloadOwnerKinds, Type: FeatureFlagTypeStringList, Deprecated: replacedBy("injection.workloadOwnerKinds")},
+)
+
+func newFeatureFlagRegistry(flags ...FeatureFlag) map[string]FeatureFlag {
+	registry := make(map[string]FeatureFlag)
+	for _, flag := range flags {
+		registry[flag.Annotation] = flag
+	}
+
+	for _, resourceName := range ResourceNames() {
+		for annotation, field := range map[string]string{
+			AnnotationFeatureEecResourcesRequests(resourceName):    "activeGateOptions.extensionController.resources.requests",
+			AnnotationFeatureEecResourcesLimits(resourceName):      "activeGateOptions.extensionController.resources.limits",
+			AnnotationFeatureStatsdResourcesRequests(resourceName): "activeGateOptions.statsd.resources.requests",
+			AnnotationFeatureStatsdResourcesLimits(resourceName):   "activeGateOptions.statsd.resources.limits",
+		} {
+			registry[annotation] = FeatureFlag{Annotation: annotation, Type: FeatureFlagTypeQuantity, Deprecated: replacedBy(field)}
+		}
+	}
+	return registry
+}
+
+// replacedBy returns the deprecation of a flag which is replaced by a field of the v1beta2 DynaKube
+func replacedBy(field string) string {
+	return fmt.Sprintf("use spec.%s of the v1beta2 DynaKube instead", field)
+}
+
+// FeatureFlags returns all known feature flags sorted by annotation
+func FeatureFlags() []FeatureFlag {
+	flags := make([]FeatureFlag, 0, len(featureFlags))
+	for _, flag := range featureFlags {
+		flags = append(flags, flag)
+	}
+	sort.Slice(flags, func(i, j int) bool {
+		return flags[i].Annotation < flags[j].Annotation
+	})
+	return flags
+}
+
+// LookupFeatureFlag returns the feature flag for an annotation, the deprecated 'alpha.' prefix is accepted
+func LookupFeatureFlag(annotation string) (FeatureFlag, bool) {
+	flag, ok := featureFlags[strings.TrimPrefix(annotation, DeprecatedFeatureFlagPrefix)]
+	return flag, ok
+}
+
+// IsFeatureFlagAnnotation returns true if the annotation has the format of a feature flag, regardless of whether it is known
+func IsFeatureFlagAnnotation(annotation string) bool {
+	return strings.HasPrefix(strings.TrimPrefix(annotation, DeprecatedFeatureFlagPrefix), AnnotationFeaturePrefix)
+}
+
+// Validate returns an error if the value can't be parsed as the type of the feature flag
+func (flag FeatureFlag) Validate(value string) error {
+	switch flag.Type {
+	case FeatureFlagTypeBool:
+		if value != "true" && value != "false" {
+			return fmt.Errorf("expected true or false")
+		}
+	case FeatureFlagTypeInt:
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("expected a %s", flag.Type)
+		}
+	case FeatureFlagTypeStringList:
+		var list []string
+		if err := json.Unmarshal([]byte(value), &list); err != nil {
+			return fmt.Errorf("expected a %s: %w", flag.Type, err)
+		}
+	case FeatureFlagTypeQuantity:
+		if _, err := resource.ParseQuantity(value); err != nil {
+			return fmt.Errorf("expected a %s: %w", flag.Type, err)
+		}
+	}
+	return nil
+}
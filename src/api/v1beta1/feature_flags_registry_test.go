@@ -0,0 +1,71 @@
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFeatureFlagRegistry(t *testing.T) {
+	t.Run(`flags are found with and without deprecated prefix`, func(t *testing.T) {
+		flag, ok := LookupFeatureFlag(AnnotationFeatureDisableHostsRequests)
+		assert.True(t, ok)
+		assert.Equal(t, FeatureFlagTypeBool, flag.Type)
+
+		flag, ok = LookupFeatureFlag(DeprecatedFeatureFlagPrefix + AnnotationFeatureOneAgentMaxUnavailable)
+		assert.True(t, ok)
+		assert.Equal(t, AnnotationFeatureOneAgentMaxUnavailable, flag.Annotation)
+
+		_, ok = LookupFeatureFlag(AnnotationFeatureStatsdResourcesLimits(corev1.ResourceMemory))
+		assert.True(t, ok)
+
+		_, ok = LookupFeatureFlag(AnnotationFeaturePrefix + "unknown")
+		assert.False(t, ok)
+	})
+	t.Run(`flags replaced by v1beta2 fields are deprecated`, func(t *testing.T) {
+		for _, flag := range FeatureFlags() {
+			assert.NotEmpty(t, flag.Deprecated, flag.Annotation)
+		}
+
+		flag, _ := LookupFeatureFlag(AnnotationFeatureStatsdResourcesLimits(corev1.ResourceMemory))
+		assert.Equal(t, "use spec.activeGateOptions.statsd.resources.limits of the v1beta2 DynaKube instead", flag.Deprecated)
+	})
+	t.Run(`values are validated by type`, func(t *testing.T) {
+		assert.NoError(t, FeatureFlag{Type: FeatureFlagTypeBool}.Validate("false"))
+		assert.Error(t, FeatureFlag{Type: FeatureFlagTypeBool}.Validate("True"))
+		assert.NoError(t, FeatureFlag{Type: FeatureFlagTypeInt}.Validate("0"))
+		assert.Error(t, FeatureFlag{Type: FeatureFlagTypeInt}.Validate("-1"))
+		assert.NoError(t, FeatureFlag{Type: FeatureFlagTypeStringList}.Validate(`["a","b"]`))
+		assert.Error(t, FeatureFlag{Type: FeatureFlagTypeStringList}.Validate(`["a"`))
+		assert.NoError(t, FeatureFlag{Type: FeatureFlagTypeQuantity}.Validate("100m"))
+		assert.Error(t, FeatureFlag{Type: FeatureFlagTypeQuantity}.Validate("a lot"))
+		assert.NoError(t, FeatureFlag{Type: FeatureFlagTypeString}.Validate(""))
+	})
+	t.Run(`defaults are used for invalid values`, func(t *testing.T) {
+		dk := &DynaKube{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "dynatrace",
+				Annotations: map[string]string{
+					AnnotationFeatureOneAgentMaxUnavailable: "-1",
+					AnnotationFeatureApiResponseCacheTTL:    "sixty",
+					AnnotationFeatureIgnoredNamespaces:      "^dynatrace$",
+				},
+			},
+		}
+
+		assert.Equal(t, 1, dk.FeatureOneAgentMaxUnavailable())
+		assert.Equal(t, 60*time.Second, dk.FeatureApiResponseCacheTTL())
+		assert.Equal(t, dk.getDefaultIgnoredNamespaces(), dk.FeatureIgnoredNamespaces())
+	})
+	t.Run(`all flags are listed`, func(t *testing.T) {
+		flags := FeatureFlags()
+
+		assert.Len(t, flags, len(featureFlags))
+		for i := 1; i < len(flags); i++ {
+			assert.Less(t, flags[i-1].Annotation, flags[i].Annotation)
+		}
+	})
+}
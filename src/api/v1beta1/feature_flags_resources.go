@@ -9,26 +9,49 @@ const (
 	annotationFeatureActiveGate = AnnotationFeaturePrefix + "activegate-"
 	annotationFeatureEec        = annotationFeatureActiveGate + "eec-"
 	annotationFeatureStatsd     = annotationFeatureActiveGate + "statsd-"
+
+	resourceRequestsPrefix = "requests-"
+	resourceLimitsPrefix   = "limits-"
 )
 
 // FeatureEecResourcesRequests is a feature flag to define CPU or memory requests for the EEC container
 func (dk *DynaKube) FeatureEecResourcesRequests(resourceName corev1.ResourceName) *resource.Quantity {
-	return eecResourceRequirements(dk, "requests-"+resourceName)
+	return eecResourceRequirements(dk, resourceRequestsPrefix+resourceName)
 }
 
 // FeatureEecResourcesLimits is a feature flag to define CPU or memory limits for the EEC container
 func (dk *DynaKube) FeatureEecResourcesLimits(resourceName corev1.ResourceName) *resource.Quantity {
-	return eecResourceRequirements(dk, "limits-"+resourceName)
+	return eecResourceRequirements(dk, resourceLimitsPrefix+resourceName)
 }
 
 // FeatureStatsdResourcesRequests is a feature flag to define CPU or memory requests for the StatsD container
 func (dk *DynaKube) FeatureStatsdResourcesRequests(resourceName corev1.ResourceName) *resource.Quantity {
-	return statsdResourceRequirements(dk, "requests-"+resourceName)
+	return statsdResourceRequirements(dk, resourceRequestsPrefix+resourceName)
 }
 
 // FeatureStatsdResourcesLimits is a feature flag to define CPU or memory limits for the StatsD container
 func (dk *DynaKube) FeatureStatsdResourcesLimits(resourceName corev1.ResourceName) *resource.Quantity {
-	return statsdResourceRequirements(dk, "limits-"+resourceName)
+	return statsdResourceRequirements(dk, resourceLimitsPrefix+resourceName)
+}
+
+// AnnotationFeatureEecResourcesRequests returns the feature flag annotation for the requests of the given resource of the EEC container
+func AnnotationFeatureEecResourcesRequests(resourceName corev1.ResourceName) string {
+	return annotationFeatureEec + formatResourceName(resourceRequestsPrefix+resourceName)
+}
+
+// AnnotationFeatureEecResourcesLimits returns the feature flag annotation for the limits of the given resource of the EEC container
+func AnnotationFeatureEecResourcesLimits(resourceName corev1.ResourceName) string {
+	return annotationFeatureEec + formatResourceName(resourceLimitsPrefix+resourceName)
+}
+
+// AnnotationFeatureStatsdResourcesRequests returns the feature flag annotation for the requests of the given resource of the StatsD container
+func AnnotationFeatureStatsdResourcesRequests(resourceName corev1.ResourceName) string {
+	return annotationFeatureStatsd + formatResourceName(resourceRequestsPrefix+resourceName)
+}
+
+// AnnotationFeatureStatsdResourcesLimits returns the feature flag annotation for the limits of the given resource of the StatsD container
+func AnnotationFeatureStatsdResourcesLimits(resourceName corev1.ResourceName) string {
+	return annotationFeatureStatsd + formatResourceName(resourceLimitsPrefix+resourceName)
 }
 
 // E.g. "operator.dynatrace.com/feature-activegate-eec-resources-limits-cpu": "100m"
@@ -0,0 +1,77 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// InjectionConfigSpec defines the defaults for the OneAgent injection into the pods of its namespace
+// The annotations of a pod take precedence over these values, unset values fall back to the defaults of the DynaKube
+// +k8s:openapi-gen=true
+type InjectionConfigSpec struct {
+	// Optional: Flavor of the code modules which are downloaded, defaults to multidistro
+	// Overridden by the oneagent.dynatrace.com/flavor annotation of a pod
+	// +kubebuilder:validation:Enum=default;musl;multidistro
+	Flavor string `json:"flavor,omitempty"`
+
+	// Optional: Code module technologies which are downloaded, defaults to all
+	// Overridden by the oneagent.dynatrace.com/technologies annotation of a pod
+	// +listType=set
+	Technologies []string `json:"technologies,omitempty"`
+
+	// Optional: Directory the OneAgent is made available from in the containers, defaults to /opt/dynatrace/oneagent-paas
+	// Overridden by the oneagent.dynatrace.com/install-path annotation of a pod
+	InstallPath string `json:"installPath,omitempty"`
+
+	// Optional: Url the code modules are downloaded from, defaults to the PaaS installer url of the tenant
+	// Overridden by the oneagent.dynatrace.com/installer-url annotation of a pod
+	InstallerURL string `json:"installerUrl,omitempty"`
+
+	// Optional: Whether the init container fails if the code modules can't be installed, defaults to silent
+	// Overridden by the oneagent.dynatrace.com/failure-policy annotation of a pod
+	// +kubebuilder:validation:Enum=silent;fail
+	FailurePolicy string `json:"failurePolicy,omitempty"`
+
+	// Optional: Don't inject the pods of the namespace, only annotate them with a summary of the injection
+	// The dry run can also be enabled for all namespaces of a DynaKube
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// InjectionConfigStatus defines the observed state of InjectionConfig
+// +k8s:openapi-gen=true
+type InjectionConfigStatus struct {
+	// DynaKube is the name of the DynaKube the namespace is assigned to, updated by the operator when the DynaKube is reconciled
+	DynaKube string `json:"dynakube,omitempty"`
+
+	// ObservedGeneration is the generation of the InjectionConfig which was observed by the operator
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// InjectionConfig is the Schema for the injectionconfigs API
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=injectionconfigs,scope=Namespaced,categories=dynatrace
+// +kubebuilder:printcolumn:name="DynaKube",type=string,JSONPath=`.status.dynakube`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +operator-sdk:csv:customresourcedefinitions:displayName="Dynatrace InjectionConfig"
+type InjectionConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   InjectionConfigSpec   `json:"spec,omitempty"`
+	Status InjectionConfigStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// InjectionConfigList contains a list of InjectionConfig
+type InjectionConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []InjectionConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&InjectionConfig{}, &InjectionConfigList{})
+}
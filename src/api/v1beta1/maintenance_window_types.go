@@ -0,0 +1,31 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type MaintenanceWindowSpec struct {
+	// Cron expression with the fields "minute hour day-of-month month day-of-week" defining when the window opens,
+	// e.g. "0 22 * * 6" opens the window every Saturday at 22:00
+	// +kubebuilder:validation:Required
+	Schedule string `json:"schedule"`
+
+	// How long the window stays open, e.g. "4h"
+	// +kubebuilder:validation:Required
+	Duration metav1.Duration `json:"duration"`
+
+	// Optional: IANA time zone the schedule is evaluated in, e.g. "Europe/Vienna"
+	// Defaults to UTC
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+type MaintenanceWindowStatus struct {
+	// Active is true while a maintenance window is open
+	Active bool `json:"active,omitempty"`
+
+	// NextStart is the start of the open maintenance window, or of the next one if none is open
+	NextStart *metav1.Time `json:"nextStart,omitempty"`
+
+	// NextEnd is the end of the window starting at NextStart
+	NextEnd *metav1.Time `json:"nextEnd,omitempty"`
+}
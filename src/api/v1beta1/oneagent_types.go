@@ -2,6 +2,7 @@ package v1beta1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type OneAgentMode string
@@ -84,6 +85,65 @@ type HostInjectSpec struct {
 	// Example: {major.minor.release} - 1.200.0
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="OneAgent version",order=11,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:text"}
 	Version string `json:"version,omitempty"`
+
+	// Optional: Rolls out changes of the OneAgent pods to canary nodes first, and to the remaining nodes only after the
+	// canary pods stayed healthy for the soak duration. If the canary pods fail, the previous OneAgent version is restored
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Staged rollout",order=27,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	StagedRollout *StagedRolloutSpec `json:"stagedRollout,omitempty"`
+
+	// Optional: Node groups with different OneAgent settings, e.g. for GPU or arm64 node pools
+	// Each node group gets its own DaemonSet, the nodes of the groups are excluded from the default DaemonSet
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Node groups",order=28,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	// +listType=map
+	// +listMapKey=name
+	NodeGroups []NodeGroupSpec `json:"nodeGroups,omitempty"`
+}
+
+type NodeGroupSpec struct {
+	// Name of the node group, it is appended to the name of its DaemonSet
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=20
+	Name string `json:"name"`
+
+	// Node selector of the nodes in the group, it is merged with the node selector of the OneAgent spec
+	// Node groups must not overlap
+	NodeSelector map[string]string `json:"nodeSelector"`
+
+	// Optional: Tolerations which are added to the tolerations of the OneAgent spec
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Optional: Resource requests and limits which override the ones of the OneAgent spec
+	OneAgentResources corev1.ResourceRequirements `json:"oneAgentResources,omitempty"`
+
+	// Optional: Arguments to the OneAgent installer, arguments which are also set in the OneAgent spec are overridden
+	// +listType=set
+	Args []string `json:"args,omitempty"`
+
+	// Optional: Environment variables which override the ones of the OneAgent spec with the same name
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// Optional: Host group of the OneAgents in the node group
+	HostGroup string `json:"hostGroup,omitempty"`
+}
+
+type StagedRolloutSpec struct {
+	// Optional: Nodes matching the selector are the canary nodes
+	// Takes precedence over canaryPercentage
+	CanaryNodeSelector *metav1.LabelSelector `json:"canaryNodeSelector,omitempty"`
+
+	// Optional: Percentage of the nodes running OneAgent which are used as canary nodes, at least one node is used
+	// Defaults to 10
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	CanaryPercentage *int32 `json:"canaryPercentage,omitempty"`
+
+	// Optional: How long the canary pods have to become ready, the rollout fails afterwards
+	// Defaults to 10m
+	ReadyTimeout *metav1.Duration `json:"readyTimeout,omitempty"`
+
+	// Optional: How long the canary pods have to stay ready without restarting before the remaining nodes are updated
+	// Defaults to 10m
+	SoakDuration *metav1.Duration `json:"soakDuration,omitempty"`
 }
 
 type ApplicationMonitoringSpec struct {
@@ -107,4 +167,27 @@ type AppInjectionSpec struct {
 	// Optional: the Dynatrace installer container image
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="CodeModulesImage",order=12,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:text"}
 	CodeModulesImage string `json:"codeModulesImage,omitempty"`
+
+	// Optional: Select the containers of injected pods which are instrumented, by default all of them are
+	// Overridden by the oneagent.dynatrace.com/include-containers and oneagent.dynatrace.com/exclude-containers annotations of a pod
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Container selection",order=16,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:hidden"}
+	ContainerSelection ContainerSelectionSpec `json:"containerSelection,omitempty"`
+}
+
+type ContainerSelectionSpec struct {
+	// Optional: If set, only the containers matching one of these rules are instrumented
+	Include []ContainerSelector `json:"include,omitempty"`
+
+	// Optional: The containers matching one of these rules are not instrumented, e.g. sidecars like istio-proxy
+	// Takes precedence over include
+	Exclude []ContainerSelector `json:"exclude,omitempty"`
+}
+
+// ContainerSelector matches containers by name and image, a container has to match all fields which are set
+type ContainerSelector struct {
+	// Optional: Regular expression which has to match the whole name of the container
+	Name string `json:"name,omitempty"`
+
+	// Optional: Regular expression which has to match the whole image of the container
+	Image string `json:"image,omitempty"`
 }
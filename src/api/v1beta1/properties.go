@@ -19,6 +19,7 @@ package v1beta1
 import (
 	"fmt"
 	"net/url"
+	"regexp"
 	"strings"
 
 	"github.com/Dynatrace/dynatrace-operator/src/dtclient"
@@ -69,6 +70,20 @@ func (dk *DynaKube) OneAgentDaemonsetName() string {
 	return fmt.Sprintf("%s-%s", dk.Name, PodNameOsAgent)
 }
 
+// OneAgentNodeGroupDaemonsetName returns the name of the DaemonSet rolling out OneAgent to the nodes of the node group
+func (dk *DynaKube) OneAgentNodeGroupDaemonsetName(nodeGroup string) string {
+	return fmt.Sprintf("%s-%s", dk.OneAgentDaemonsetName(), nodeGroup)
+}
+
+// OneAgentDaemonsetNames returns the names of all OneAgent DaemonSets, the default one first
+func (dk *DynaKube) OneAgentDaemonsetNames() []string {
+	names := []string{dk.OneAgentDaemonsetName()}
+	for _, nodeGroup := range dk.NodeGroups() {
+		names = append(names, dk.OneAgentNodeGroupDaemonsetName(nodeGroup.Name))
+	}
+	return names
+}
+
 func (dk *DynaKube) DeprecatedActiveGateMode() bool {
 	return dk.Spec.KubernetesMonitoring.Enabled || dk.Spec.Routing.Enabled
 }
@@ -190,6 +205,25 @@ func (dk *DynaKube) InitResources() *corev1.ResourceRequirements {
 	return nil
 }
 
+// ContainerSelection returns the rules selecting the containers of injected pods which are instrumented
+func (dk *DynaKube) ContainerSelection() ContainerSelectionSpec {
+	if dk.ApplicationMonitoringMode() {
+		return dk.Spec.OneAgent.ApplicationMonitoring.ContainerSelection
+	} else if dk.CloudNativeFullstackMode() {
+		return dk.Spec.OneAgent.CloudNativeFullStack.ContainerSelection
+	}
+	return ContainerSelectionSpec{}
+}
+
+// CompileContainerPattern compiles the name or image pattern of a ContainerSelector, which has to match the whole value.
+// The pattern is checked on its own first, so it can't escape the anchors, e.g. with "a)|(b".
+func CompileContainerPattern(pattern string) (*regexp.Regexp, error) {
+	if _, err := regexp.Compile(pattern); err != nil {
+		return nil, err
+	}
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
 func (dk *DynaKube) OneAgentResources() *corev1.ResourceRequirements {
 	if dk.ClassicFullStackMode() {
 		return &dk.Spec.OneAgent.ClassicFullStack.OneAgentResources
@@ -212,6 +246,30 @@ func (dk *DynaKube) NodeSelector() map[string]string {
 	return nil
 }
 
+// NodeGroups returns the node groups which get their own OneAgent DaemonSet
+func (dk *DynaKube) NodeGroups() []NodeGroupSpec {
+	if dk.ClassicFullStackMode() {
+		return dk.Spec.OneAgent.ClassicFullStack.NodeGroups
+	} else if dk.HostMonitoringMode() {
+		return dk.Spec.OneAgent.HostMonitoring.NodeGroups
+	} else if dk.CloudNativeFullstackMode() {
+		return dk.Spec.OneAgent.CloudNativeFullStack.NodeGroups
+	}
+	return nil
+}
+
+// StagedRollout returns the staged rollout configuration of the OneAgent pods, nil if they are updated all at once
+func (dk *DynaKube) StagedRollout() *StagedRolloutSpec {
+	if dk.ClassicFullStackMode() {
+		return dk.Spec.OneAgent.ClassicFullStack.StagedRollout
+	} else if dk.HostMonitoringMode() {
+		return dk.Spec.OneAgent.HostMonitoring.StagedRollout
+	} else if dk.CloudNativeFullstackMode() {
+		return dk.Spec.OneAgent.CloudNativeFullStack.StagedRollout
+	}
+	return nil
+}
+
 func (dk *DynaKube) Version() string {
 	if dk.ClassicFullStackMode() {
 		return dk.Spec.OneAgent.ClassicFullStack.Version
@@ -250,6 +308,17 @@ func (dk *DynaKube) ImmutableOneAgentImage() string {
 	return fmt.Sprintf("%s/linux/oneagent:%s", registry, tag)
 }
 
+// PinnedOneAgentImage returns the OneAgent image tagged with the version from the status instead of latest,
+// so pods recreated during a staged rollout don't pick up a newer version than the one which is rolled out.
+func (dk *DynaKube) PinnedOneAgentImage() string {
+	if dk.Image() != "" || dk.Version() != "" || dk.Status.OneAgent.Version == "" || dk.Spec.APIURL == "" {
+		return dk.ImmutableOneAgentImage()
+	}
+
+	registry := buildImageRegistry(dk.Spec.APIURL)
+	return fmt.Sprintf("%s/linux/oneagent:%s", registry, truncateBuildDate(dk.Status.OneAgent.Version))
+}
+
 func truncateBuildDate(version string) string {
 	const versionSeperator = "."
 	const buildDateIndex = 3
@@ -272,6 +341,11 @@ func (dk *DynaKube) Tokens() string {
 	return dk.Name
 }
 
+// APIURLs returns the apiUrl followed by the failover API URLs, in the order they are tried
+func (dk *DynaKube) APIURLs() []string {
+	return append([]string{dk.Spec.APIURL}, dk.Spec.FailoverAPIURLs...)
+}
+
 func (dk *DynaKube) CommunicationHostForClient() dtclient.CommunicationHost {
 	return dtclient.CommunicationHost(dk.Status.CommunicationHostForClient)
 }
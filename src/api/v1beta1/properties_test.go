@@ -23,7 +23,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-const testAPIURL = "http://test-endpoint/api"
+const (
+	testAPIURL = "http://test-endpoint/api"
+	testName   = "test-name"
+)
 
 func TestActiveGateImage(t *testing.T) {
 	t.Run(`ActiveGateImage with no API URL`, func(t *testing.T) {
@@ -119,6 +122,27 @@ func TestOneAgentImage(t *testing.T) {
 	})
 }
 
+func TestPinnedOneAgentImage(t *testing.T) {
+	t.Run(`tagged with the version from the status`, func(t *testing.T) {
+		dk := DynaKube{
+			Spec:   DynaKubeSpec{APIURL: testAPIURL, OneAgent: OneAgentSpec{ClassicFullStack: &HostInjectSpec{}}},
+			Status: DynaKubeStatus{OneAgent: OneAgentStatus{VersionStatus: VersionStatus{Version: "1.239.14.20220325-164521"}}},
+		}
+		assert.Equal(t, "test-endpoint/linux/oneagent:1.239.14", dk.PinnedOneAgentImage())
+	})
+	t.Run(`custom version takes precedence`, func(t *testing.T) {
+		dk := DynaKube{
+			Spec:   DynaKubeSpec{APIURL: testAPIURL, OneAgent: OneAgentSpec{ClassicFullStack: &HostInjectSpec{Version: "1.234.5"}}},
+			Status: DynaKubeStatus{OneAgent: OneAgentStatus{VersionStatus: VersionStatus{Version: "1.239.14.20220325-164521"}}},
+		}
+		assert.Equal(t, "test-endpoint/linux/oneagent:1.234.5", dk.PinnedOneAgentImage())
+	})
+	t.Run(`latest without version in the status`, func(t *testing.T) {
+		dk := DynaKube{Spec: DynaKubeSpec{APIURL: testAPIURL, OneAgent: OneAgentSpec{ClassicFullStack: &HostInjectSpec{}}}}
+		assert.Equal(t, "test-endpoint/linux/oneagent:latest", dk.PinnedOneAgentImage())
+	})
+}
+
 func TestOneAgentDaemonsetName(t *testing.T) {
 	instance := &DynaKube{
 		ObjectMeta: metav1.ObjectMeta{
@@ -128,6 +152,25 @@ func TestOneAgentDaemonsetName(t *testing.T) {
 	assert.Equal(t, "test-name-oneagent", instance.OneAgentDaemonsetName())
 }
 
+func TestOneAgentDaemonsetNames(t *testing.T) {
+	instance := &DynaKube{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: testName,
+		},
+		Spec: DynaKubeSpec{
+			OneAgent: OneAgentSpec{
+				HostMonitoring: &HostInjectSpec{
+					NodeGroups: []NodeGroupSpec{{Name: "gpu"}, {Name: "arm"}},
+				},
+			},
+		},
+	}
+	assert.Equal(t, []string{"test-name-oneagent", "test-name-oneagent-gpu", "test-name-oneagent-arm"}, instance.OneAgentDaemonsetNames())
+
+	instance.Spec.OneAgent.HostMonitoring = nil
+	assert.Equal(t, []string{"test-name-oneagent"}, instance.OneAgentDaemonsetNames())
+}
+
 func TestTokens(t *testing.T) {
 	testName := "test-name"
 	testValue := "test-value"
@@ -145,6 +188,19 @@ func TestTokens(t *testing.T) {
 	})
 }
 
+func TestAPIURLs(t *testing.T) {
+	dk := DynaKube{Spec: DynaKubeSpec{
+		APIURL:          "https://node1.managed.test/e/tenant/api",
+		FailoverAPIURLs: []string{"https://node2.managed.test/e/tenant/api", "https://dr.test/e/tenant/api"},
+	}}
+
+	assert.Equal(t, []string{
+		"https://node1.managed.test/e/tenant/api",
+		"https://node2.managed.test/e/tenant/api",
+		"https://dr.test/e/tenant/api",
+	}, dk.APIURLs())
+}
+
 func TestTenantUUID(t *testing.T) {
 	t.Run("happy path", func(t *testing.T) {
 		apiUrl := "https://demo.dev.dynatracelabs.com/api"
@@ -0,0 +1,76 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type SettingsObjectState string
+
+const (
+	// SettingsObjectCreated means the object did not exist and was created by the operator
+	SettingsObjectCreated SettingsObjectState = "Created"
+	// SettingsObjectInSync means the object in the environment matches the spec
+	SettingsObjectInSync SettingsObjectState = "InSync"
+	// SettingsObjectDriftCorrected means the object in the environment was changed and has been reverted to the spec
+	SettingsObjectDriftCorrected SettingsObjectState = "DriftCorrected"
+	// SettingsObjectFailed means the object could not be synced, see the message for details
+	SettingsObjectFailed SettingsObjectState = "Failed"
+)
+
+type SettingsObjectSpec struct {
+	// Schema of the settings object, e.g. builtin:anomaly-detection.kubernetes.cluster
+	// +kubebuilder:validation:Required
+	SchemaID string `json:"schemaId"`
+
+	// Optional: Version of the schema the value conforms to
+	// Defaults to the latest version of the schema
+	SchemaVersion string `json:"schemaVersion,omitempty"`
+
+	// Optional: Scope of the settings object, e.g. 'environment' or an entity ID
+	// Defaults to the Kubernetes cluster entity of this cluster
+	Scope string `json:"scope,omitempty"`
+
+	// Optional: Key which tells apart the objects of a multi-object schema in the same scope, e.g. builtin:anomaly-detection.metric-events
+	// Required if the schema is declared more than once per scope, single-object schemas can only be declared once per scope
+	Key string `json:"key,omitempty"`
+
+	// Properties of the settings object kept in sync by the operator
+	// Properties not listed are left as they are in the environment
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Value runtime.RawExtension `json:"value"`
+}
+
+type SettingsStatus struct {
+	// LastSyncTimestamp indicates when the settings objects were last compared with the environment
+	LastSyncTimestamp *metav1.Time `json:"lastSyncTimestamp,omitempty"`
+
+	// SpecHash is the hash of the settings in the spec at the last sync, changes are synced without waiting for the next sync
+	SpecHash string `json:"specHash,omitempty"`
+
+	// Objects contains the sync state of every settings object in the spec
+	Objects []SettingsObjectStatus `json:"objects,omitempty"`
+}
+
+type SettingsObjectStatus struct {
+	SchemaID string `json:"schemaId"`
+
+	// Scope is the scope the object has been synced to, including the defaulted cluster scope
+	Scope string `json:"scope,omitempty"`
+
+	// Key of the settings object in the spec
+	Key string `json:"key,omitempty"`
+
+	// ObjectID of the settings object in the environment
+	ObjectID string `json:"objectId,omitempty"`
+
+	State SettingsObjectState `json:"state,omitempty"`
+
+	// Message describes the drifted properties or the cause of a failure
+	Message string `json:"message,omitempty"`
+
+	// LastDriftTimestamp indicates when the object was last found to differ from the spec
+	LastDriftTimestamp *metav1.Time `json:"lastDriftTimestamp,omitempty"`
+}
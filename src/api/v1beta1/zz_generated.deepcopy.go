@@ -81,6 +81,7 @@ func (in *ActiveGateStatus) DeepCopy() *ActiveGateStatus {
 func (in *AppInjectionSpec) DeepCopyInto(out *AppInjectionSpec) {
 	*out = *in
 	in.InitResources.DeepCopyInto(&out.InitResources)
+	in.ContainerSelection.DeepCopyInto(&out.ContainerSelection)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppInjectionSpec.
@@ -227,6 +228,46 @@ func (in *ConnectionInfoStatus) DeepCopy() *ConnectionInfoStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerSelectionSpec) DeepCopyInto(out *ContainerSelectionSpec) {
+	*out = *in
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]ContainerSelector, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]ContainerSelector, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerSelectionSpec.
+func (in *ContainerSelectionSpec) DeepCopy() *ContainerSelectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerSelectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerSelector) DeepCopyInto(out *ContainerSelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerSelector.
+func (in *ContainerSelector) DeepCopy() *ContainerSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DynaKube) DeepCopyInto(out *DynaKube) {
 	*out = *in
@@ -304,6 +345,11 @@ func (in *DynaKubeProxy) DeepCopy() *DynaKubeProxy {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DynaKubeSpec) DeepCopyInto(out *DynaKubeSpec) {
 	*out = *in
+	if in.FailoverAPIURLs != nil {
+		in, out := &in.FailoverAPIURLs, &out.FailoverAPIURLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Proxy != nil {
 		in, out := &in.Proxy, &out.Proxy
 		*out = new(DynaKubeProxy)
@@ -312,6 +358,18 @@ func (in *DynaKubeSpec) DeepCopyInto(out *DynaKubeSpec) {
 	in.NamespaceSelector.DeepCopyInto(&out.NamespaceSelector)
 	in.OneAgent.DeepCopyInto(&out.OneAgent)
 	in.ActiveGate.DeepCopyInto(&out.ActiveGate)
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = make([]SettingsObjectSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindowSpec, len(*in))
+		copy(*out, *in)
+	}
 	in.Routing.DeepCopyInto(&out.Routing)
 	in.KubernetesMonitoring.DeepCopyInto(&out.KubernetesMonitoring)
 }
@@ -342,12 +400,20 @@ func (in *DynaKubeStatus) DeepCopyInto(out *DynaKubeStatus) {
 		in, out := &in.LastDataIngestTokenProbeTimestamp, &out.LastDataIngestTokenProbeTimestamp
 		*out = (*in).DeepCopy()
 	}
+	if in.TokenExpirations != nil {
+		in, out := &in.TokenExpirations, &out.TokenExpirations
+		*out = make(map[string]metav1.Time, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	if in.LastClusterVersionProbeTimestamp != nil {
 		in, out := &in.LastClusterVersionProbeTimestamp, &out.LastClusterVersionProbeTimestamp
 		*out = (*in).DeepCopy()
 	}
 	in.ConnectionInfo.DeepCopyInto(&out.ConnectionInfo)
 	out.CommunicationHostForClient = in.CommunicationHostForClient
+	in.MaintenanceWindow.DeepCopyInto(&out.MaintenanceWindow)
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -359,6 +425,7 @@ func (in *DynaKubeStatus) DeepCopyInto(out *DynaKubeStatus) {
 	in.ExtensionController.DeepCopyInto(&out.ExtensionController)
 	in.Statsd.DeepCopyInto(&out.Statsd)
 	in.OneAgent.DeepCopyInto(&out.OneAgent)
+	in.Settings.DeepCopyInto(&out.Settings)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynaKubeStatus.
@@ -444,6 +511,18 @@ func (in *HostInjectSpec) DeepCopyInto(out *HostInjectSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.StagedRollout != nil {
+		in, out := &in.StagedRollout, &out.StagedRollout
+		*out = new(StagedRolloutSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeGroups != nil {
+		in, out := &in.NodeGroups, &out.NodeGroups
+		*out = make([]NodeGroupSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostInjectSpec.
@@ -456,6 +535,100 @@ func (in *HostInjectSpec) DeepCopy() *HostInjectSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InjectionConfig) DeepCopyInto(out *InjectionConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InjectionConfig.
+func (in *InjectionConfig) DeepCopy() *InjectionConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(InjectionConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InjectionConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InjectionConfigList) DeepCopyInto(out *InjectionConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]InjectionConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InjectionConfigList.
+func (in *InjectionConfigList) DeepCopy() *InjectionConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(InjectionConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InjectionConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InjectionConfigSpec) DeepCopyInto(out *InjectionConfigSpec) {
+	*out = *in
+	if in.Technologies != nil {
+		in, out := &in.Technologies, &out.Technologies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InjectionConfigSpec.
+func (in *InjectionConfigSpec) DeepCopy() *InjectionConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InjectionConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InjectionConfigStatus) DeepCopyInto(out *InjectionConfigStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InjectionConfigStatus.
+func (in *InjectionConfigStatus) DeepCopy() *InjectionConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InjectionConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubernetesMonitoringSpec) DeepCopyInto(out *KubernetesMonitoringSpec) {
 	*out = *in
@@ -472,6 +645,87 @@ func (in *KubernetesMonitoringSpec) DeepCopy() *KubernetesMonitoringSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindowSpec) DeepCopyInto(out *MaintenanceWindowSpec) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindowSpec.
+func (in *MaintenanceWindowSpec) DeepCopy() *MaintenanceWindowSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindowSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindowStatus) DeepCopyInto(out *MaintenanceWindowStatus) {
+	*out = *in
+	if in.NextStart != nil {
+		in, out := &in.NextStart, &out.NextStart
+		*out = (*in).DeepCopy()
+	}
+	if in.NextEnd != nil {
+		in, out := &in.NextEnd, &out.NextEnd
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindowStatus.
+func (in *MaintenanceWindowStatus) DeepCopy() *MaintenanceWindowStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindowStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeGroupSpec) DeepCopyInto(out *NodeGroupSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.OneAgentResources.DeepCopyInto(&out.OneAgentResources)
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroupSpec.
+func (in *NodeGroupSpec) DeepCopy() *NodeGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OneAgentInstance) DeepCopyInto(out *OneAgentInstance) {
 	*out = *in
@@ -487,6 +741,30 @@ func (in *OneAgentInstance) DeepCopy() *OneAgentInstance {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OneAgentRolloutStatus) DeepCopyInto(out *OneAgentRolloutStatus) {
+	*out = *in
+	if in.PhaseTimestamp != nil {
+		in, out := &in.PhaseTimestamp, &out.PhaseTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.CanaryNodes != nil {
+		in, out := &in.CanaryNodes, &out.CanaryNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OneAgentRolloutStatus.
+func (in *OneAgentRolloutStatus) DeepCopy() *OneAgentRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OneAgentRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OneAgentSpec) DeepCopyInto(out *OneAgentSpec) {
 	*out = *in
@@ -537,6 +815,11 @@ func (in *OneAgentStatus) DeepCopyInto(out *OneAgentStatus) {
 		in, out := &in.LastHostsRequestTimestamp, &out.LastHostsRequestTimestamp
 		*out = (*in).DeepCopy()
 	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(OneAgentRolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OneAgentStatus.
@@ -565,6 +848,102 @@ func (in *RoutingSpec) DeepCopy() *RoutingSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SettingsObjectSpec) DeepCopyInto(out *SettingsObjectSpec) {
+	*out = *in
+	in.Value.DeepCopyInto(&out.Value)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SettingsObjectSpec.
+func (in *SettingsObjectSpec) DeepCopy() *SettingsObjectSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SettingsObjectSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SettingsObjectStatus) DeepCopyInto(out *SettingsObjectStatus) {
+	*out = *in
+	if in.LastDriftTimestamp != nil {
+		in, out := &in.LastDriftTimestamp, &out.LastDriftTimestamp
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SettingsObjectStatus.
+func (in *SettingsObjectStatus) DeepCopy() *SettingsObjectStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SettingsObjectStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SettingsStatus) DeepCopyInto(out *SettingsStatus) {
+	*out = *in
+	if in.LastSyncTimestamp != nil {
+		in, out := &in.LastSyncTimestamp, &out.LastSyncTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.Objects != nil {
+		in, out := &in.Objects, &out.Objects
+		*out = make([]SettingsObjectStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SettingsStatus.
+func (in *SettingsStatus) DeepCopy() *SettingsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SettingsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StagedRolloutSpec) DeepCopyInto(out *StagedRolloutSpec) {
+	*out = *in
+	if in.CanaryNodeSelector != nil {
+		in, out := &in.CanaryNodeSelector, &out.CanaryNodeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CanaryPercentage != nil {
+		in, out := &in.CanaryPercentage, &out.CanaryPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ReadyTimeout != nil {
+		in, out := &in.ReadyTimeout, &out.ReadyTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.SoakDuration != nil {
+		in, out := &in.SoakDuration, &out.SoakDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StagedRolloutSpec.
+func (in *StagedRolloutSpec) DeepCopy() *StagedRolloutSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StagedRolloutSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StatsdStatus) DeepCopyInto(out *StatsdStatus) {
 	*out = *in
@@ -0,0 +1,154 @@
+package v1beta2
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+)
+
+// ConvertTo converts v1beta2 to v1beta1
+// The typed options are written as feature flag annotations, they take precedence over annotations with the same name.
+func (src *DynaKube) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.DynaKube)
+	src = src.DeepCopy()
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Status = src.Status
+
+	// DynakubeSpec
+	dst.Spec.APIURL = src.Spec.APIURL
+	dst.Spec.FailoverAPIURLs = src.Spec.FailoverAPIURLs
+	dst.Spec.Tokens = src.Spec.Tokens
+	dst.Spec.CustomPullSecret = src.Spec.CustomPullSecret
+	dst.Spec.SkipCertCheck = src.Spec.SkipCertCheck
+	dst.Spec.Proxy = src.Spec.Proxy
+	dst.Spec.TrustedCAs = src.Spec.TrustedCAs
+	dst.Spec.ClientCertificate = src.Spec.ClientCertificate
+	dst.Spec.NetworkZone = src.Spec.NetworkZone
+	dst.Spec.EnableIstio = src.Spec.EnableIstio
+	dst.Spec.NamespaceSelector = src.Spec.NamespaceSelector
+	dst.Spec.OneAgent = src.Spec.OneAgent
+	dst.Spec.ActiveGate = src.Spec.ActiveGate
+	dst.Spec.Settings = src.Spec.Settings
+	dst.Spec.MaintenanceWindows = src.Spec.MaintenanceWindows
+	dst.Spec.Paused = src.Spec.Paused
+	dst.Spec.Routing = src.Spec.Routing
+	dst.Spec.KubernetesMonitoring = src.Spec.KubernetesMonitoring
+
+	flags := featureFlags(dst.Annotations)
+	if flags == nil {
+		flags = featureFlags{}
+	}
+
+	// dtClient
+	flags.setBool(v1beta1.AnnotationFeatureDisableHostsRequests, src.Spec.DynatraceApi.DisableHostsRequests)
+	flags.setInt32(v1beta1.AnnotationFeatureApiResponseCacheTTL, src.Spec.DynatraceApi.ResponseCacheTTLSeconds)
+	flags.setBool(v1beta1.AnnotationFeatureDisableDynatraceEvents, src.Spec.DynatraceApi.DisableEvents)
+
+	// oneAgent
+	flags.setInt32(v1beta1.AnnotationFeatureOneAgentMaxUnavailable, src.Spec.OneAgentOptions.MaxUnavailable)
+	flags.setBool(v1beta1.AnnotationFeatureDisableReadOnlyOneAgent, src.Spec.OneAgentOptions.DisableReadOnlyHostFs)
+	flags.setBool(v1beta1.AnnotationFeatureEnableMultipleOsAgentsOnNode, src.Spec.OneAgentOptions.MultipleOsAgentsOnNode)
+
+	// activeGate
+	activeGateOptions := src.Spec.ActiveGateOptions
+	flags.setBool(v1beta1.AnnotationFeatureDisableActiveGateUpdates, activeGateOptions.DisableUpdates)
+	flags.setBool(v1beta1.AnnotationFeatureDisableActivegateRawImage, activeGateOptions.DisableRawImage)
+	flags.setBool(v1beta1.AnnotationFeatureActiveGateAppArmor, activeGateOptions.AppArmor)
+	flags.setBool(v1beta1.AnnotationFeatureActiveGateReadOnlyFilesystem, activeGateOptions.ReadOnlyFilesystem)
+	flags.setBool(v1beta1.AnnotationFeatureAutomaticKubernetesApiMonitoring, activeGateOptions.AutomaticKubernetesApiMonitoring)
+
+	// statsD
+	flags.setString(v1beta1.AnnotationFeatureCustomEecImage, activeGateOptions.ExtensionController.Image)
+	flags.setResourceList(v1beta1.AnnotationFeatureEecResourcesRequests(""), activeGateOptions.ExtensionController.Resources.Requests)
+	flags.setResourceList(v1beta1.AnnotationFeatureEecResourcesLimits(""), activeGateOptions.ExtensionController.Resources.Limits)
+	flags.setString(v1beta1.AnnotationFeatureCustomStatsdImage, activeGateOptions.Statsd.Image)
+	flags.setBool(v1beta1.AnnotationFeatureUseActiveGateImageForStatsd, activeGateOptions.Statsd.UseActiveGateImage)
+	flags.setResourceList(v1beta1.AnnotationFeatureStatsdResourcesRequests(""), activeGateOptions.Statsd.Resources.Requests)
+	flags.setResourceList(v1beta1.AnnotationFeatureStatsdResourcesLimits(""), activeGateOptions.Statsd.Resources.Limits)
+
+	// injection (webhook)
+	flags.setBool(v1beta1.AnnotationFeatureEnableWebhookReinvocationPolicy, src.Spec.Injection.ReinvocationPolicy)
+	flags.setBool(v1beta1.AnnotationFeatureIgnoreUnknownState, src.Spec.Injection.IgnoreUnknownState)
+	flags.setStringList(v1beta1.AnnotationFeatureIgnoredNamespaces, src.Spec.Injection.IgnoredNamespaces)
+	flags.setStringList(v1beta1.AnnotationFeatureWorkloadOwnerKinds, src.Spec.Injection.WorkloadOwnerKinds)
+	flags.setBool(v1beta1.AnnotationFeatureDisableMetadataEnrichment, src.Spec.Injection.DisableMetadataEnrichment)
+	flags.setBool(v1beta1.AnnotationFeatureInjectionDryRun, src.Spec.Injection.DryRun)
+
+	if len(flags) > 0 {
+		dst.Annotations = flags
+	}
+	return nil
+}
+
+// ConvertFrom converts v1beta1 to v1beta2
+// Feature flag annotations are moved to the typed options only if ConvertTo restores them unchanged,
+// e.g. deprecated "alpha." annotations or values which can't be parsed are kept as annotations.
+func (dst *DynaKube) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.DynaKube).DeepCopy()
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Status = src.Status
+
+	// DynakubeSpec
+	dst.Spec.APIURL = src.Spec.APIURL
+	dst.Spec.FailoverAPIURLs = src.Spec.FailoverAPIURLs
+	dst.Spec.Tokens = src.Spec.Tokens
+	dst.Spec.CustomPullSecret = src.Spec.CustomPullSecret
+	dst.Spec.SkipCertCheck = src.Spec.SkipCertCheck
+	dst.Spec.Proxy = src.Spec.Proxy
+	dst.Spec.TrustedCAs = src.Spec.TrustedCAs
+	dst.Spec.ClientCertificate = src.Spec.ClientCertificate
+	dst.Spec.NetworkZone = src.Spec.NetworkZone
+	dst.Spec.EnableIstio = src.Spec.EnableIstio
+	dst.Spec.NamespaceSelector = src.Spec.NamespaceSelector
+	dst.Spec.OneAgent = src.Spec.OneAgent
+	dst.Spec.ActiveGate = src.Spec.ActiveGate
+	dst.Spec.Settings = src.Spec.Settings
+	dst.Spec.MaintenanceWindows = src.Spec.MaintenanceWindows
+	dst.Spec.Paused = src.Spec.Paused
+	dst.Spec.Routing = src.Spec.Routing
+	dst.Spec.KubernetesMonitoring = src.Spec.KubernetesMonitoring
+
+	flags := featureFlags(dst.Annotations)
+
+	// dtClient
+	dst.Spec.DynatraceApi.DisableHostsRequests = flags.takeBool(v1beta1.AnnotationFeatureDisableHostsRequests)
+	dst.Spec.DynatraceApi.ResponseCacheTTLSeconds = flags.takeInt32(v1beta1.AnnotationFeatureApiResponseCacheTTL, 0)
+	dst.Spec.DynatraceApi.DisableEvents = flags.takeBool(v1beta1.AnnotationFeatureDisableDynatraceEvents)
+
+	// oneAgent
+	dst.Spec.OneAgentOptions.MaxUnavailable = flags.takeInt32(v1beta1.AnnotationFeatureOneAgentMaxUnavailable, 1)
+	dst.Spec.OneAgentOptions.DisableReadOnlyHostFs = flags.takeBool(v1beta1.AnnotationFeatureDisableReadOnlyOneAgent)
+	dst.Spec.OneAgentOptions.MultipleOsAgentsOnNode = flags.takeBool(v1beta1.AnnotationFeatureEnableMultipleOsAgentsOnNode)
+
+	// activeGate
+	activeGateOptions := &dst.Spec.ActiveGateOptions
+	activeGateOptions.DisableUpdates = flags.takeBool(v1beta1.AnnotationFeatureDisableActiveGateUpdates)
+	activeGateOptions.DisableRawImage = flags.takeBool(v1beta1.AnnotationFeatureDisableActivegateRawImage)
+	activeGateOptions.AppArmor = flags.takeBool(v1beta1.AnnotationFeatureActiveGateAppArmor)
+	activeGateOptions.ReadOnlyFilesystem = flags.takeBool(v1beta1.AnnotationFeatureActiveGateReadOnlyFilesystem)
+	activeGateOptions.AutomaticKubernetesApiMonitoring = flags.takeBool(v1beta1.AnnotationFeatureAutomaticKubernetesApiMonitoring)
+
+	// statsD
+	activeGateOptions.ExtensionController.Image = flags.takeString(v1beta1.AnnotationFeatureCustomEecImage)
+	activeGateOptions.ExtensionController.Resources.Requests = flags.takeResourceList(v1beta1.AnnotationFeatureEecResourcesRequests(""))
+	activeGateOptions.ExtensionController.Resources.Limits = flags.takeResourceList(v1beta1.AnnotationFeatureEecResourcesLimits(""))
+	activeGateOptions.Statsd.Image = flags.takeString(v1beta1.AnnotationFeatureCustomStatsdImage)
+	activeGateOptions.Statsd.UseActiveGateImage = flags.takeBool(v1beta1.AnnotationFeatureUseActiveGateImageForStatsd)
+	activeGateOptions.Statsd.Resources.Requests = flags.takeResourceList(v1beta1.AnnotationFeatureStatsdResourcesRequests(""))
+	activeGateOptions.Statsd.Resources.Limits = flags.takeResourceList(v1beta1.AnnotationFeatureStatsdResourcesLimits(""))
+
+	// injection (webhook)
+	dst.Spec.Injection.ReinvocationPolicy = flags.takeBool(v1beta1.AnnotationFeatureEnableWebhookReinvocationPolicy)
+	dst.Spec.Injection.IgnoreUnknownState = flags.takeBool(v1beta1.AnnotationFeatureIgnoreUnknownState)
+	dst.Spec.Injection.IgnoredNamespaces = flags.takeStringList(v1beta1.AnnotationFeatureIgnoredNamespaces)
+	dst.Spec.Injection.WorkloadOwnerKinds = flags.takeStringList(v1beta1.AnnotationFeatureWorkloadOwnerKinds)
+	dst.Spec.Injection.DisableMetadataEnrichment = flags.takeBool(v1beta1.AnnotationFeatureDisableMetadataEnrichment)
+	dst.Spec.Injection.DryRun = flags.takeBool(v1beta1.AnnotationFeatureInjectionDryRun)
+
+	if len(flags) == 0 {
+		dst.Annotations = nil
+	}
+	return nil
+}
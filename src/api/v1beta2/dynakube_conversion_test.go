@@ -0,0 +1,212 @@
+package v1beta2
+
+import (
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	testNamespace    = "test-namespace"
+	testName         = "test-name"
+	testAPIURL       = "https://test-api-url/api"
+	testToken        = "test-token"
+	testNetworkZone  = "test-networkzone"
+	testEecImage     = "test-eec-image"
+	testOtherFeature = "operator.dynatrace.com/other"
+)
+
+func newV1beta1DynaKube(annotations map[string]string) *v1beta1.DynaKube {
+	return &v1beta1.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        testName,
+			Namespace:   testNamespace,
+			Annotations: annotations,
+		},
+		Spec: v1beta1.DynaKubeSpec{
+			APIURL:      testAPIURL,
+			Tokens:      testToken,
+			NetworkZone: testNetworkZone,
+			OneAgent: v1beta1.OneAgentSpec{
+				CloudNativeFullStack: &v1beta1.CloudNativeFullStackSpec{},
+			},
+		},
+		Status: v1beta1.DynaKubeStatus{
+			Phase: v1beta1.Running,
+		},
+	}
+}
+
+func TestConversion_ConvertFrom(t *testing.T) {
+	t.Run(`feature flags are moved to typed fields`, func(t *testing.T) {
+		src := newV1beta1DynaKube(map[string]string{
+			v1beta1.AnnotationFeatureDisableHostsRequests:                           "true",
+			v1beta1.AnnotationFeatureApiResponseCacheTTL:                            "0",
+			v1beta1.AnnotationFeatureOneAgentMaxUnavailable:                         "3",
+			v1beta1.AnnotationFeatureDisableActiveGateUpdates:                       "false",
+			v1beta1.AnnotationFeatureCustomEecImage:                                 testEecImage,
+			v1beta1.AnnotationFeatureEecResourcesLimits(corev1.ResourceCPU):         "500m",
+			v1beta1.AnnotationFeatureStatsdResourcesRequests(corev1.ResourceMemory): "128Mi",
+			v1beta1.AnnotationFeatureIgnoredNamespaces:                              `["^dynatrace$","^kube-.*"]`,
+			testOtherFeature: "value",
+		})
+		dst := &DynaKube{}
+
+		err := dst.ConvertFrom(src)
+
+		require.NoError(t, err)
+		assert.Equal(t, testName, dst.Name)
+		assert.Equal(t, testAPIURL, dst.Spec.APIURL)
+		assert.Equal(t, testNetworkZone, dst.Spec.NetworkZone)
+		assert.NotNil(t, dst.Spec.OneAgent.CloudNativeFullStack)
+		assert.Equal(t, v1beta1.Running, dst.Status.Phase)
+
+		assert.Equal(t, map[string]string{testOtherFeature: "value"}, dst.Annotations)
+		assert.True(t, *dst.Spec.DynatraceApi.DisableHostsRequests)
+		assert.Equal(t, int32(0), *dst.Spec.DynatraceApi.ResponseCacheTTLSeconds)
+		assert.Equal(t, int32(3), *dst.Spec.OneAgentOptions.MaxUnavailable)
+		assert.False(t, *dst.Spec.ActiveGateOptions.DisableUpdates)
+		assert.Nil(t, dst.Spec.ActiveGateOptions.AppArmor)
+		assert.Equal(t, testEecImage, dst.Spec.ActiveGateOptions.ExtensionController.Image)
+		eecCPULimit := dst.Spec.ActiveGateOptions.ExtensionController.Resources.Limits[corev1.ResourceCPU]
+		assert.Equal(t, "500m", eecCPULimit.String())
+		assert.Nil(t, dst.Spec.ActiveGateOptions.ExtensionController.Resources.Requests)
+		statsdMemoryRequest := dst.Spec.ActiveGateOptions.Statsd.Resources.Requests[corev1.ResourceMemory]
+		assert.Equal(t, "128Mi", statsdMemoryRequest.String())
+		assert.Equal(t, []string{"^dynatrace$", "^kube-.*"}, dst.Spec.Injection.IgnoredNamespaces)
+
+		// the source must not be modified
+		assert.Len(t, src.Annotations, 9)
+	})
+	t.Run(`non-canonical feature flags are kept as annotations`, func(t *testing.T) {
+		annotations := map[string]string{
+			v1beta1.DeprecatedFeatureFlagPrefix + v1beta1.AnnotationFeatureDisableHostsRequests: "true",
+			v1beta1.AnnotationFeatureActiveGateAppArmor:                                         "True",
+			v1beta1.AnnotationFeatureOneAgentMaxUnavailable:                                     "0",
+			v1beta1.AnnotationFeatureApiResponseCacheTTL:                                        "sixty",
+			v1beta1.AnnotationFeatureEecResourcesLimits(corev1.ResourceMemory):                  "0.5Gi",
+			v1beta1.AnnotationFeatureIgnoredNamespaces:                                          `[ "^dynatrace$" ]`,
+		}
+		src := newV1beta1DynaKube(annotations)
+		dst := &DynaKube{}
+
+		err := dst.ConvertFrom(src)
+
+		require.NoError(t, err)
+		assert.Equal(t, annotations, dst.Annotations)
+		assert.Equal(t, DynatraceApiSpec{}, dst.Spec.DynatraceApi)
+		assert.Equal(t, OneAgentOptionsSpec{}, dst.Spec.OneAgentOptions)
+		assert.Equal(t, ActiveGateOptionsSpec{}, dst.Spec.ActiveGateOptions)
+		assert.Equal(t, InjectionSpec{}, dst.Spec.Injection)
+	})
+}
+
+func TestConversion_ConvertTo(t *testing.T) {
+	trueVal := true
+	maxUnavailable := int32(2)
+	src := &DynaKube{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testName,
+			Namespace: testNamespace,
+			Annotations: map[string]string{
+				v1beta1.AnnotationFeatureActiveGateAppArmor: "false",
+				testOtherFeature: "value",
+			},
+		},
+		Spec: DynaKubeSpec{
+			APIURL: testAPIURL,
+			Tokens: testToken,
+			OneAgentOptions: OneAgentOptionsSpec{
+				MaxUnavailable: &maxUnavailable,
+			},
+			ActiveGateOptions: ActiveGateOptionsSpec{
+				AppArmor: &trueVal,
+				Statsd: StatsdSpec{
+					Resources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+					},
+				},
+			},
+			Injection: InjectionSpec{
+				IgnoredNamespaces:  []string{"^dynatrace$"},
+				WorkloadOwnerKinds: []string{"Workload.example.com"},
+			},
+		},
+	}
+	dst := &v1beta1.DynaKube{}
+
+	err := src.ConvertTo(dst)
+
+	require.NoError(t, err)
+	assert.Equal(t, testAPIURL, dst.Spec.APIURL)
+	assert.Equal(t, map[string]string{
+		v1beta1.AnnotationFeatureActiveGateAppArmor:                        "true",
+		v1beta1.AnnotationFeatureOneAgentMaxUnavailable:                    "2",
+		v1beta1.AnnotationFeatureStatsdResourcesLimits(corev1.ResourceCPU): "1",
+		v1beta1.AnnotationFeatureIgnoredNamespaces:                         `["^dynatrace$"]`,
+		v1beta1.AnnotationFeatureWorkloadOwnerKinds:                        `["Workload.example.com"]`,
+		testOtherFeature: "value",
+	}, dst.Annotations)
+	assert.True(t, dst.FeatureActiveGateAppArmor())
+	assert.Equal(t, 2, dst.FeatureOneAgentMaxUnavailable())
+	assert.Equal(t, []string{"^dynatrace$"}, dst.FeatureIgnoredNamespaces())
+	assert.Equal(t, append(v1beta1.DefaultWorkloadOwnerKinds, "Workload.example.com"), dst.FeatureWorkloadOwnerKinds())
+	assert.Equal(t, "1", dst.FeatureStatsdResourcesLimits(corev1.ResourceCPU).String())
+
+	// the source must not be modified
+	assert.Equal(t, "false", src.Annotations[v1beta1.AnnotationFeatureActiveGateAppArmor])
+}
+
+func TestConversion_RoundTrip(t *testing.T) {
+	annotations := map[string]string{
+		v1beta1.AnnotationFeatureDisableHostsRequests:                                            "false",
+		v1beta1.AnnotationFeatureApiResponseCacheTTL:                                             "120",
+		v1beta1.AnnotationFeatureDisableDynatraceEvents:                                          "true",
+		v1beta1.AnnotationFeatureOneAgentMaxUnavailable:                                          "-1",
+		v1beta1.AnnotationFeatureDisableReadOnlyOneAgent:                                         "true",
+		v1beta1.AnnotationFeatureEnableMultipleOsAgentsOnNode:                                    "yes",
+		v1beta1.AnnotationFeatureDisableActiveGateUpdates:                                        "true",
+		v1beta1.AnnotationFeatureDisableActivegateRawImage:                                       "true",
+		v1beta1.AnnotationFeatureActiveGateAppArmor:                                              "1",
+		v1beta1.AnnotationFeatureActiveGateReadOnlyFilesystem:                                    "true",
+		v1beta1.AnnotationFeatureAutomaticKubernetesApiMonitoring:                                "true",
+		v1beta1.AnnotationFeatureUseActiveGateImageForStatsd:                                     "true",
+		v1beta1.AnnotationFeatureCustomEecImage:                                                  testEecImage,
+		v1beta1.AnnotationFeatureCustomStatsdImage:                                               "",
+		v1beta1.AnnotationFeatureEecResourcesRequests(corev1.ResourceCPU):                        "100m",
+		v1beta1.AnnotationFeatureEecResourcesLimits(corev1.ResourceMemory):                       "1000Mi",
+		v1beta1.AnnotationFeatureStatsdResourcesRequests(corev1.ResourceCPU):                     "0.1",
+		v1beta1.AnnotationFeatureEnableWebhookReinvocationPolicy:                                 "true",
+		v1beta1.AnnotationFeatureIgnoreUnknownState:                                              "false",
+		v1beta1.AnnotationFeatureIgnoredNamespaces:                                               "[]",
+		v1beta1.DeprecatedFeatureFlagPrefix + v1beta1.AnnotationFeatureDisableMetadataEnrichment: "true",
+		testOtherFeature: "value",
+	}
+
+	t.Run(`v1beta1 to v1beta2 and back`, func(t *testing.T) {
+		src := newV1beta1DynaKube(annotations)
+		converted := &DynaKube{}
+		dst := &v1beta1.DynaKube{}
+
+		require.NoError(t, converted.ConvertFrom(src))
+		require.NoError(t, converted.ConvertTo(dst))
+
+		assert.Equal(t, src, dst)
+	})
+	t.Run(`v1beta2 to v1beta1 and back`, func(t *testing.T) {
+		src := &DynaKube{}
+		require.NoError(t, src.ConvertFrom(newV1beta1DynaKube(annotations)))
+		converted := &v1beta1.DynaKube{}
+		dst := &DynaKube{}
+
+		require.NoError(t, src.ConvertTo(converted))
+		require.NoError(t, dst.ConvertFrom(converted))
+
+		assert.Equal(t, src, dst)
+	})
+}
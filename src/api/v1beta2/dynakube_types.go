@@ -0,0 +1,149 @@
+package v1beta2
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+//+kubebuilder:storageversion
+
+// DynaKube is the Schema for the DynaKube API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=dynakubes,scope=Namespaced,categories=dynatrace
+// +kubebuilder:printcolumn:name="ApiUrl",type=string,JSONPath=`.spec.apiUrl`
+// +kubebuilder:printcolumn:name="Tokens",type=string,JSONPath=`.status.tokens`
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +operator-sdk:csv:customresourcedefinitions:displayName="Dynatrace DynaKube"
+// +operator-sdk:csv:customresourcedefinitions:resources={{StatefulSet,v1,},{DaemonSet,v1,},{Pod,v1,}}
+type DynaKube struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DynaKubeSpec           `json:"spec,omitempty"`
+	Status v1beta1.DynaKubeStatus `json:"status,omitempty"`
+}
+
+// DynaKubeSpec defines the desired state of DynaKube
+// Compared to v1beta1 the behaviors configured by "operator.dynatrace.com/feature-*" annotations are typed fields
+// +k8s:openapi-gen=true
+type DynaKubeSpec struct {
+	// Location of the Dynatrace API to connect to, including your specific environment UUID
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="API URL",order=1,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	APIURL string `json:"apiUrl"`
+
+	// Optional: API URLs of the same Dynatrace environment which are used in this order by the operator if the apiUrl can't
+	// be reached, e.g. the other cluster nodes of a Managed cluster or a disaster recovery environment
+	// The ActiveGate doesn't fail over to these URLs, it connects to the communication endpoints of the environment
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Failover API URLs",order=1,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:text"}
+	FailoverAPIURLs []string `json:"failoverApiUrls,omitempty"`
+
+	// Credentials for the DynaKube to connect back to Dynatrace.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Tenant specific secrets",order=2,xDescriptors="urn:alm:descriptor:io.kubernetes:Secret"
+	Tokens string `json:"tokens,omitempty"`
+
+	// Optional: Pull secret for your private registry
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Custom PullSecret",order=8,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:io.kubernetes:Secret"}
+	CustomPullSecret string `json:"customPullSecret,omitempty"`
+
+	// Disable certificate validation checks for installer download and API communication
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Skip Certificate Check",order=3,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	SkipCertCheck bool `json:"skipCertCheck,omitempty"`
+
+	// Optional: Set custom proxy settings either directly or from a secret with the field 'proxy'
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Proxy",order=3,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	Proxy *v1beta1.DynaKubeProxy `json:"proxy,omitempty"`
+
+	// Optional: Adds custom RootCAs from a configmap
+	// This property only affects certificates used to communicate with the Dynatrace API.
+	// The property is not applied to the ActiveGate
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Trusted CAs",order=6,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:io.kubernetes:ConfigMap"}
+	TrustedCAs string `json:"trustedCAs,omitempty"`
+
+	// Optional: Adds a client certificate from a secret of type kubernetes.io/tls, with the fields 'tls.crt' and 'tls.key'
+	// It is presented when communicating with the Dynatrace API and the image registry, e.g. for TLS-inspecting gateways requiring mutual TLS
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Client Certificate",order=6,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:io.kubernetes:Secret"}
+	ClientCertificate string `json:"clientCertificate,omitempty"`
+
+	// Optional: Sets Network Zone for OneAgent and ActiveGate pods
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Network Zone",order=7,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:text"}
+	NetworkZone string `json:"networkZone,omitempty"`
+
+	// If enabled, Istio on the cluster will be configured automatically to allow access to the Dynatrace environment
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable Istio automatic management",order=9,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	EnableIstio bool `json:"enableIstio,omitempty"`
+
+	// Optional: set a namespace selector to limit which namespaces are monitored
+	// By default, all namespaces will be monitored
+	// Has no effect during classicFullStack and hostMonitoring mode
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Namespace Selector",order=17,xDescriptors="urn:alm:descriptor:com.tectonic.ui:selector:core:v1:Namespace"
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Optional: Configures how the operator and its components communicate with the Dynatrace API
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Dynatrace API",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	DynatraceApi DynatraceApiSpec `json:"dynatraceApi,omitempty"`
+
+	// General configuration about OneAgent instances
+	// +kubebuilder:validation:MaxProperties=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="OneAgent",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	OneAgent v1beta1.OneAgentSpec `json:"oneAgent,omitempty"`
+
+	// Optional: Configuration shared by all OneAgent modes
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="OneAgent Options",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	OneAgentOptions OneAgentOptionsSpec `json:"oneAgentOptions,omitempty"`
+
+	// General configuration about ActiveGate instances
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ActiveGate",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	ActiveGate v1beta1.ActiveGateSpec `json:"activeGate,omitempty"`
+
+	// Optional: Configuration shared by all ActiveGate capabilities, including the extension controller and StatsD containers
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ActiveGate Options",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	ActiveGateOptions ActiveGateOptionsSpec `json:"activeGateOptions,omitempty"`
+
+	// Optional: Configures the injection of OneAgent code modules and metadata into pods by the webhook
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Injection",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	Injection InjectionSpec `json:"injection,omitempty"`
+
+	// Optional: Settings 2.0 objects which the operator creates and keeps in sync with the Dynatrace environment
+	// Changes made in the environment to the listed properties are reverted and reported in the status
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Settings",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	Settings []v1beta1.SettingsObjectSpec `json:"settings,omitempty"`
+
+	// Optional: Recurring maintenance windows, if set the OneAgent and ActiveGate versions, the OneAgent DaemonSet
+	// and the code modules version provided by the CSI driver are only updated while a window is open
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Maintenance Windows",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	MaintenanceWindows []v1beta1.MaintenanceWindowSpec `json:"maintenanceWindows,omitempty"`
+
+	// Optional: Pauses the reconciliation, while set the operator doesn't change the deployed components, the Dynatrace
+	// environment or the code modules provided by the CSI driver for this DynaKube, the status is still updated
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Paused",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	Paused bool `json:"paused,omitempty"`
+
+	//  Deprecated: Configuration for Routing
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Routing"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Routing v1beta1.RoutingSpec `json:"routing,omitempty"`
+
+	//  Deprecated: Configuration for Kubernetes Monitoring
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Kubernetes Monitoring"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	KubernetesMonitoring v1beta1.KubernetesMonitoringSpec `json:"kubernetesMonitoring,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DynaKubeList contains a list of DynaKube
+type DynaKubeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DynaKube `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DynaKube{}, &DynaKubeList{})
+}
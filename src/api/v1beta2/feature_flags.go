@@ -0,0 +1,136 @@
+package v1beta2
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// featureFlags are the annotations of a v1beta1 DynaKube, see v1beta1/feature_flags.go.
+//
+// The take* methods remove an annotation and return its typed value, but only if the value is written as the
+// corresponding set* method writes it, so converting to v1beta2 and back doesn't change any annotation.
+type featureFlags map[string]string
+
+func (flags featureFlags) setBool(annotation string, value *bool) {
+	if value != nil {
+		flags[annotation] = strconv.FormatBool(*value)
+	}
+}
+
+func (flags featureFlags) takeBool(annotation string) *bool {
+	raw, ok := flags[annotation]
+	if !ok {
+		return nil
+	}
+
+	value, err := strconv.ParseBool(raw)
+	if err != nil || strconv.FormatBool(value) != raw {
+		return nil
+	}
+
+	delete(flags, annotation)
+	return &value
+}
+
+func (flags featureFlags) setInt32(annotation string, value *int32) {
+	if value != nil {
+		flags[annotation] = strconv.FormatInt(int64(*value), 10)
+	}
+}
+
+// takeInt32 keeps values below minimum as annotation, as they would be rejected by the validation of the typed field
+func (flags featureFlags) takeInt32(annotation string, minimum int32) *int32 {
+	raw, ok := flags[annotation]
+	if !ok {
+		return nil
+	}
+
+	parsed, err := strconv.ParseInt(raw, 10, 32)
+	if err != nil || strconv.FormatInt(parsed, 10) != raw || parsed < int64(minimum) {
+		return nil
+	}
+
+	delete(flags, annotation)
+	value := int32(parsed)
+	return &value
+}
+
+func (flags featureFlags) setString(annotation string, value string) {
+	if value != "" {
+		flags[annotation] = value
+	}
+}
+
+func (flags featureFlags) takeString(annotation string) string {
+	value := flags[annotation]
+	if value == "" {
+		return ""
+	}
+
+	delete(flags, annotation)
+	return value
+}
+
+func (flags featureFlags) setStringList(annotation string, value []string) {
+	if len(value) == 0 {
+		return
+	}
+
+	raw, err := json.Marshal(value)
+	if err == nil {
+		flags[annotation] = string(raw)
+	}
+}
+
+func (flags featureFlags) takeStringList(annotation string) []string {
+	raw, ok := flags[annotation]
+	if !ok {
+		return nil
+	}
+
+	var value []string
+	if err := json.Unmarshal([]byte(raw), &value); err != nil || len(value) == 0 {
+		return nil
+	}
+	if formatted, err := json.Marshal(value); err != nil || string(formatted) != raw {
+		return nil
+	}
+
+	delete(flags, annotation)
+	return value
+}
+
+// setResourceList writes one annotation per resource, named by appending the resource name to annotationPrefix
+func (flags featureFlags) setResourceList(annotationPrefix string, value corev1.ResourceList) {
+	for resourceName, quantity := range value {
+		flags[annotationPrefix+string(resourceName)] = quantity.String()
+	}
+}
+
+func (flags featureFlags) takeResourceList(annotationPrefix string) corev1.ResourceList {
+	var value corev1.ResourceList
+
+	for annotation, raw := range flags {
+		resourceName := strings.TrimPrefix(annotation, annotationPrefix)
+		if resourceName == annotation || resourceName == "" {
+			continue
+		}
+
+		quantity, err := resource.ParseQuantity(raw)
+		if err != nil || quantity.String() != raw {
+			continue
+		}
+
+		if value == nil {
+			value = corev1.ResourceList{}
+		}
+		value[corev1.ResourceName(resourceName)] = quantity
+		delete(flags, annotation)
+	}
+
+	return value
+}
@@ -0,0 +1,122 @@
+package v1beta2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// The fields below replace the "operator.dynatrace.com/feature-*" annotations of v1beta1, see v1beta1/feature_flags.go.
+// Booleans are pointers, so an explicitly disabled behavior survives the conversion to v1beta1 and back.
+
+type DynatraceApiSpec struct {
+	// Optional: Disable queries to the Hosts API
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Disable hosts requests",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	DisableHostsRequests *bool `json:"disableHostsRequests,omitempty"`
+
+	// Optional: For how many seconds responses for tenant metadata (connection info, latest agent versions) are reused
+	// 0 disables the cache, defaults to 60
+	// +kubebuilder:validation:Minimum=0
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Response cache TTL",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:number"}
+	ResponseCacheTTLSeconds *int32 `json:"responseCacheTTLSeconds,omitempty"`
+
+	// Optional: Disable sending events about rollouts and version changes to the Kubernetes cluster entity
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Disable Dynatrace events",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	DisableEvents *bool `json:"disableEvents,omitempty"`
+}
+
+type OneAgentOptionsSpec struct {
+	// Optional: maxUnavailable of the rolling updates of the OneAgent DaemonSets, defaults to 1
+	// +kubebuilder:validation:Minimum=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Max unavailable",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:number"}
+	MaxUnavailable *int32 `json:"maxUnavailable,omitempty"`
+
+	// Optional: Mount the host filesystem writable instead of using a volume provided by the CSI driver for logs and such
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Disable read-only host filesystem",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	DisableReadOnlyHostFs *bool `json:"disableReadOnlyHostFs,omitempty"`
+
+	// Optional: Allow multiple OneAgents running on the same host
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Multiple OneAgents on node",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	MultipleOsAgentsOnNode *bool `json:"multipleOsAgentsOnNode,omitempty"`
+}
+
+type ActiveGateOptionsSpec struct {
+	// Optional: Disable ActiveGate updates
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Disable updates",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	DisableUpdates *bool `json:"disableUpdates,omitempty"`
+
+	// Optional: Fetch tenant UUID, token and communication endpoints from the cluster instead of using the ones embedded in the image
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Disable raw image",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	DisableRawImage *bool `json:"disableRawImage,omitempty"`
+
+	// Optional: Enable AppArmor in the ActiveGate container
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="AppArmor",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	AppArmor *bool `json:"appArmor,omitempty"`
+
+	// Optional: Mount the root filesystem of the ActiveGate container read-only
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Read-only filesystem",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	ReadOnlyFilesystem *bool `json:"readOnlyFilesystem,omitempty"`
+
+	// Optional: Ensure that the settings for monitoring this Kubernetes cluster exist in Dynatrace
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Automatic Kubernetes API monitoring",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	AutomaticKubernetesApiMonitoring *bool `json:"automaticKubernetesApiMonitoring,omitempty"`
+
+	// Optional: Configuration of the extension controller container
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Extension controller",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	ExtensionController ExtensionControllerSpec `json:"extensionController,omitempty"`
+
+	// Optional: Configuration of the StatsD container
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="StatsD",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	Statsd StatsdSpec `json:"statsd,omitempty"`
+}
+
+type ExtensionControllerSpec struct {
+	// Optional: Custom extension controller image
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Image",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:text"}
+	Image string `json:"image,omitempty"`
+
+	// Optional: CPU and memory requests and limits of the extension controller container
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Resource Requirements",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:resourceRequirements"}
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+type StatsdSpec struct {
+	// Optional: Custom StatsD image
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Image",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:text"}
+	Image string `json:"image,omitempty"`
+
+	// Optional: Run the extension controller and StatsD containers from the ActiveGate image, using special entry points
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Use ActiveGate image",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	UseActiveGateImage *bool `json:"useActiveGateImage,omitempty"`
+
+	// Optional: CPU and memory requests and limits of the StatsD container
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Resource Requirements",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:resourceRequirements"}
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+type InjectionSpec struct {
+	// Optional: Reinvoke the webhook to instrument containers added by other webhooks
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Reinvocation policy",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	ReinvocationPolicy *bool `json:"reinvocationPolicy,omitempty"`
+
+	// Optional: Inject even when the DynaKube is in an UNKNOWN state, this may cause an extra host to appear in the environment for each process
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Ignore unknown state",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	IgnoreUnknownState *bool `json:"ignoreUnknownState,omitempty"`
+
+	// Optional: Regular expressions for namespaces which are never injected
+	// Defaults to the namespace of the DynaKube, "^kube-.*" and "^openshift(-.*)?"
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Ignored namespaces",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:text"}
+	IgnoredNamespaces []string `json:"ignoredNamespaces,omitempty"`
+
+	// Optional: Disable the enrichment of pods with metadata
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Disable metadata enrichment",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	DisableMetadataEnrichment *bool `json:"disableMetadataEnrichment,omitempty"`
+
+	// Optional: Don't inject pods, only annotate them with a summary of the injection and report it in events and metrics
+	// Can also be enabled per namespace by an InjectionConfig
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Dry run",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	DryRun *bool `json:"dryRun,omitempty"`
+
+	// Optional: Additional owner kinds which are followed to find the workload of a pod for the metadata enrichment
+	// In the form "Kind.group", e.g. "Rollout.argoproj.io", or just "Kind" for the core group, the webhook needs permissions to get them
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Workload owner kinds",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:text"}
+	WorkloadOwnerKinds []string `json:"workloadOwnerKinds,omitempty"`
+}
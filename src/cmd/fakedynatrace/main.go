@@ -0,0 +1,63 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// fakedynatrace serves a fake Dynatrace tenant, so the operator can be tested end-to-end without a real tenant.
+// Failures can be scripted at runtime via the /fake/ control endpoints, see testing/fakedynatrace.
+package main
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/Dynatrace/dynatrace-operator/src/logger"
+	"github.com/Dynatrace/dynatrace-operator/src/testing/fakedynatrace"
+	"github.com/spf13/pflag"
+)
+
+var (
+	log = logger.NewDTLogger().WithName("fake-dynatrace-main")
+
+	listenAddress = pflag.String("listen", ":8080", "address to serve the fake tenant on")
+	certFile      = pflag.String("tls-cert", "", "certificate to serve HTTPS with, HTTP is used if empty")
+	keyFile       = pflag.String("tls-key", "", "key for the certificate given by --tls-cert")
+	tenantUUID    = pflag.String("tenant-uuid", "", "UUID of the fake tenant")
+	apiToken      = pflag.String("api-token", "", "accepted API token")
+	paasToken     = pflag.String("paas-token", "", "accepted PaaS token")
+	agentVersions = pflag.StringSlice("agent-versions", nil, "available OneAgent versions, the last one is the latest")
+	endpoints     = pflag.StringSlice("communication-endpoints", nil, "communication endpoints returned by the connection info")
+)
+
+func main() {
+	pflag.Parse()
+
+	tenant := fakedynatrace.NewTenant(fakedynatrace.Config{
+		TenantUUID:             *tenantUUID,
+		APIToken:               *apiToken,
+		PaaSToken:              *paasToken,
+		AgentVersions:          *agentVersions,
+		CommunicationEndpoints: *endpoints,
+	})
+	config := tenant.TenantConfig()
+	log.Info("serving fake tenant", "address", *listenAddress, "apiPath", fakedynatrace.APIPath,
+		"tenantUUID", config.TenantUUID, "agentVersions", config.AgentVersions)
+
+	var err error
+	if *certFile != "" {
+		err = http.ListenAndServeTLS(*listenAddress, *certFile, *keyFile, tenant)
+	} else {
+		err = http.ListenAndServe(*listenAddress, tenant)
+	}
+	log.Error(err, "fake tenant stopped")
+	os.Exit(1)
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/src/controllers/certificates"
 	"github.com/Dynatrace/dynatrace-operator/src/controllers/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/src/controllers/nodes"
+	"github.com/Dynatrace/dynatrace-operator/src/controllers/storageversion"
 	"github.com/Dynatrace/dynatrace-operator/src/kubesystem"
 	"github.com/Dynatrace/dynatrace-operator/src/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
@@ -51,6 +52,7 @@ func setupOperator(ns string, cfg *rest.Config) (manager.Manager, error) {
 	funcs := []func(manager.Manager, string) error{
 		dynakube.Add,
 		nodes.Add,
+		storageversion.Add,
 	}
 	if !kubesystem.DeployedViaOLM() {
 		funcs = append(funcs, certificates.Add)
@@ -3,6 +3,7 @@ package main
 import (
 	"github.com/Dynatrace/dynatrace-operator/src/standalone"
 	"github.com/spf13/afero"
+	ctrl "sigs.k8s.io/controller-runtime"
 )
 
 func startStandAloneInit() error {
@@ -10,5 +11,5 @@ func startStandAloneInit() error {
 	if err != nil {
 		return err
 	}
-	return standaloneRunner.Run()
+	return standaloneRunner.Run(ctrl.SetupSignalHandler())
 }
@@ -1,12 +1,22 @@
 package automaticapimonitoring
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/Dynatrace/dynatrace-operator/src/dtclient"
 	"github.com/pkg/errors"
 )
 
+const kubernetesSettingsSchemaID = "builtin:cloud.kubernetes"
+
+// kubernetesSettingValue contains the properties of the kubernetes cluster setting which identify the DynaKube it was created for
+type kubernetesSettingValue struct {
+	Label     string `json:"label"`
+	ClusterId string `json:"clusterId"`
+}
+
 type AutomaticApiMonitoringReconciler struct {
 	dtc            dtclient.Client
 	name           string
@@ -21,8 +31,8 @@ func NewReconciler(dtc dtclient.Client, name, kubeSystemUUID string) *AutomaticA
 	}
 }
 
-func (r *AutomaticApiMonitoringReconciler) Reconcile() error {
-	objectID, err := r.ensureSettingExists()
+func (r *AutomaticApiMonitoringReconciler) Reconcile(ctx context.Context) error {
+	objectID, err := r.ensureSettingExists(ctx)
 
 	if err != nil {
 		return err
@@ -37,19 +47,19 @@ func (r *AutomaticApiMonitoringReconciler) Reconcile() error {
 	return nil
 }
 
-func (r *AutomaticApiMonitoringReconciler) ensureSettingExists() (string, error) {
+func (r *AutomaticApiMonitoringReconciler) ensureSettingExists(ctx context.Context) (string, error) {
 	if r.kubeSystemUUID == "" {
 		return "", errors.New("no kube-system namespace UUID given")
 	}
 
 	// check if ME with UID exists
-	var monitoredEntities, err = r.dtc.GetMonitoredEntitiesForKubeSystemUUID(r.kubeSystemUUID)
+	var monitoredEntities, err = r.dtc.GetMonitoredEntitiesForKubeSystemUUID(ctx, r.kubeSystemUUID)
 	if err != nil {
 		return "", fmt.Errorf("error while loading MEs: %s", err.Error())
 	}
 
 	// check if Setting for ME exists
-	settings, err := r.dtc.GetSettingsForMonitoredEntities(monitoredEntities)
+	settings, err := r.dtc.GetSettingsForMonitoredEntities(ctx, monitoredEntities)
 	if err != nil {
 		return "", fmt.Errorf("error trying to check if setting exists %s", err.Error())
 	}
@@ -60,7 +70,7 @@ func (r *AutomaticApiMonitoringReconciler) ensureSettingExists() (string, error)
 
 	// determine newest ME (can be empty string), and create or update a settings object accordingly
 	meID := determineNewestMonitoredEntity(monitoredEntities)
-	objectID, err := r.dtc.CreateOrUpdateKubernetesSetting(r.name, r.kubeSystemUUID, meID)
+	objectID, err := r.dtc.CreateOrUpdateKubernetesSetting(ctx, r.name, r.kubeSystemUUID, meID)
 
 	if err != nil {
 		return "", err
@@ -69,6 +79,40 @@ func (r *AutomaticApiMonitoringReconciler) ensureSettingExists() (string, error)
 	return objectID, nil
 }
 
+// Cleanup removes the kubernetes cluster settings which were created for the DynaKube and returns their object ids,
+// settings created by other DynaKubes or by users are kept
+func (r *AutomaticApiMonitoringReconciler) Cleanup(ctx context.Context) ([]string, error) {
+	if r.kubeSystemUUID == "" {
+		return nil, errors.New("no kube-system namespace UUID given")
+	}
+
+	monitoredEntities, err := r.dtc.GetMonitoredEntitiesForKubeSystemUUID(ctx, r.kubeSystemUUID)
+	if err != nil {
+		return nil, fmt.Errorf("error while loading MEs: %s", err.Error())
+	}
+
+	var removed []string
+	for _, entity := range monitoredEntities {
+		objects, err := r.dtc.ListSettingsObjects(ctx, kubernetesSettingsSchemaID, entity.EntityId)
+		if err != nil {
+			return removed, fmt.Errorf("error while loading settings of ME %s: %s", entity.EntityId, err.Error())
+		}
+
+		for _, object := range objects {
+			var value kubernetesSettingValue
+			if err := json.Unmarshal(object.Value, &value); err != nil || value.Label != r.name || value.ClusterId != r.kubeSystemUUID {
+				continue
+			}
+			if err := r.dtc.DeleteSettingsObject(ctx, object.ObjectID); err != nil {
+				return removed, err
+			}
+			log.Info("removed kubernetes cluster setting", "name", r.name, "cluster", r.kubeSystemUUID, "object id", object.ObjectID)
+			removed = append(removed, object.ObjectID)
+		}
+	}
+	return removed, nil
+}
+
 // determineNewestMonitoredEntity returns the UUID of the newest entities; or empty string if the slice of entities is empty
 func determineNewestMonitoredEntity(entities []dtclient.MonitoredEntity) string {
 	if len(entities) == 0 {
@@ -1,6 +1,7 @@
 package automaticapimonitoring
 
 import (
+	"context"
 	"testing"
 
 	"github.com/Dynatrace/dynatrace-operator/src/dtclient"
@@ -69,7 +70,7 @@ func TestReconcile(t *testing.T) {
 		r := createDefaultReconciler(t)
 
 		// act
-		err := r.Reconcile()
+		err := r.Reconcile(context.TODO())
 
 		// assert
 		assert.NoError(t, err)
@@ -80,7 +81,7 @@ func TestReconcile(t *testing.T) {
 		r := createReconciler(t, testUID, []dtclient.MonitoredEntity{}, dtclient.GetSettingsResponse{}, testObjectID)
 
 		// act
-		actual, err := r.ensureSettingExists()
+		actual, err := r.ensureSettingExists(context.TODO())
 
 		// assert
 		assert.NoError(t, err)
@@ -93,7 +94,7 @@ func TestReconcile(t *testing.T) {
 		r := createReconciler(t, testUID, entities, dtclient.GetSettingsResponse{}, testObjectID)
 
 		// act
-		actual, err := r.ensureSettingExists()
+		actual, err := r.ensureSettingExists(context.TODO())
 
 		// assert
 		assert.NoError(t, err)
@@ -106,7 +107,7 @@ func TestReconcile(t *testing.T) {
 		r := createReconciler(t, testUID, entities, dtclient.GetSettingsResponse{TotalCount: 1}, testObjectID)
 
 		// act
-		actual, err := r.ensureSettingExists()
+		actual, err := r.ensureSettingExists(context.TODO())
 
 		// assert
 		assert.NoError(t, err)
@@ -120,7 +121,7 @@ func TestReconcileErrors(t *testing.T) {
 		r := createReconciler(t, "", []dtclient.MonitoredEntity{}, dtclient.GetSettingsResponse{}, testObjectID)
 
 		// act
-		actual, err := r.ensureSettingExists()
+		actual, err := r.ensureSettingExists(context.TODO())
 
 		// assert
 		assert.Error(t, err)
@@ -132,7 +133,7 @@ func TestReconcileErrors(t *testing.T) {
 		r := createReconcilerWithError(t, errors.New("could not get monitored entities"), nil, nil)
 
 		// act
-		actual, err := r.ensureSettingExists()
+		actual, err := r.ensureSettingExists(context.TODO())
 
 		// assert
 		assert.Error(t, err)
@@ -144,7 +145,7 @@ func TestReconcileErrors(t *testing.T) {
 		r := createReconcilerWithError(t, nil, errors.New("could not get settings for monitored entities"), nil)
 
 		// act
-		actual, err := r.ensureSettingExists()
+		actual, err := r.ensureSettingExists(context.TODO())
 
 		// assert
 		assert.Error(t, err)
@@ -156,7 +157,7 @@ func TestReconcileErrors(t *testing.T) {
 		r := createReconcilerWithError(t, nil, nil, errors.New("could not create monitored entity"))
 
 		// act
-		actual, err := r.ensureSettingExists()
+		actual, err := r.ensureSettingExists(context.TODO())
 
 		// assert
 		assert.Error(t, err)
@@ -164,6 +165,40 @@ func TestReconcileErrors(t *testing.T) {
 	})
 }
 
+func TestCleanup(t *testing.T) {
+	entities := createMonitoredEntities()
+	createCleanupClient := func() *dtclient.MockDynatraceClient {
+		mockClient := &dtclient.MockDynatraceClient{}
+		mockClient.On("GetMonitoredEntitiesForKubeSystemUUID", testUID).Return(entities, nil)
+		mockClient.On("ListSettingsObjects", kubernetesSettingsSchemaID, entities[0].EntityId).Return([]dtclient.SettingsObject{
+			{ObjectID: testObjectID, Value: []byte(`{"label":"` + testName + `","clusterId":"` + testUID + `"}`)},
+			{ObjectID: "other-dynakube", Value: []byte(`{"label":"other","clusterId":"` + testUID + `"}`)},
+		}, nil)
+		mockClient.On("ListSettingsObjects", kubernetesSettingsSchemaID, entities[1].EntityId).Return([]dtclient.SettingsObject{}, nil)
+		return mockClient
+	}
+
+	t.Run(`only settings of the dynakube are removed`, func(t *testing.T) {
+		mockClient := createCleanupClient()
+		mockClient.On("DeleteSettingsObject", testObjectID).Return(nil)
+
+		removed, err := NewReconciler(mockClient, testName, testUID).Cleanup(context.TODO())
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{testObjectID}, removed)
+		mockClient.AssertNotCalled(t, "DeleteSettingsObject", "other-dynakube")
+	})
+	t.Run(`error if setting can't be removed`, func(t *testing.T) {
+		mockClient := createCleanupClient()
+		mockClient.On("DeleteSettingsObject", testObjectID).Return(errors.New("tenant not reachable"))
+
+		removed, err := NewReconciler(mockClient, testName, testUID).Cleanup(context.TODO())
+
+		assert.Error(t, err)
+		assert.Empty(t, removed)
+	})
+}
+
 func TestDetermineNewestMonitoredEntity(t *testing.T) {
 	t.Run(`newest monitored entity is correctly calculated`, func(t *testing.T) {
 		// arrange
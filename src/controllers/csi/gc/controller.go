@@ -71,13 +71,13 @@ func (gc *CSIGarbageCollector) Reconcile(ctx context.Context, request reconcile.
 		return reconcileResult, nil
 	}
 
-	ci, err := dtc.GetConnectionInfo()
+	ci, err := dtc.GetConnectionInfo(ctx)
 	if err != nil {
 		log.Info("failed to fetch connection info")
 		return reconcileResult, nil
 	}
 
-	latestAgentVersion, err := dtc.GetLatestAgentVersion(dtclient.OsUnix, dtclient.InstallerTypePaaS)
+	latestAgentVersion, err := dtc.GetLatestAgentVersion(ctx, dtclient.OsUnix, dtclient.InstallerTypePaaS)
 	if err != nil {
 		log.Info("failed to query OneAgent version")
 		return reconcileResult, nil
@@ -1,6 +1,7 @@
 package csiprovisioner
 
 import (
+	"context"
 	"os"
 
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
@@ -48,7 +49,7 @@ func newAgentUpdater(
 	}
 }
 
-func (updater *agentUpdater) updateAgent(installedVersion, tenantUUID string, previousHash string, latestProcessModuleConfigCache *processModuleConfigCache) (string, error) {
+func (updater *agentUpdater) updateAgent(ctx context.Context, installedVersion, tenantUUID string, previousHash string, latestProcessModuleConfigCache *processModuleConfigCache) (string, error) {
 	dk := updater.dk
 	targetVersion := updater.getOneAgentVersionFromInstance()
 	targetDir := updater.path.AgentBinaryDirForVersion(tenantUUID, targetVersion)
@@ -60,7 +61,13 @@ func (updater *agentUpdater) updateAgent(installedVersion, tenantUUID string, pr
 			"target directory", targetDir)
 
 		updater.installer.SetVersion(targetVersion)
-		if err := updater.installer.InstallAgent(targetDir); err != nil {
+		if err := updater.installer.InstallAgent(ctx, targetDir); dtclient.IsDownloadVerificationError(err) {
+			updater.recorder.Eventf(dk,
+				corev1.EventTypeWarning,
+				failedVerifyAgentVersionEvent,
+				"Downloaded agent version: %s for tenant: %s is corrupted, err: %s", targetVersion, tenantUUID, err)
+			return "", err
+		} else if err != nil {
 			updater.recorder.Eventf(dk,
 				corev1.EventTypeWarning,
 				failedInstallAgentVersionEvent,
@@ -1,6 +1,7 @@
 package csiprovisioner
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -94,6 +95,7 @@ func TestUpdateAgent(t *testing.T) {
 			Return(nil)
 
 		currentVersion, err := updater.updateAgent(
+			context.TODO(),
 			testVersion,
 			testTenantUUID,
 			previousHash,
@@ -138,6 +140,7 @@ func TestUpdateAgent(t *testing.T) {
 		updater.fs.MkdirAll(targetDir, 0755)
 
 		currentVersion, err := updater.updateAgent(
+			context.TODO(),
 			testVersion,
 			testTenantUUID,
 			previousHash,
@@ -164,6 +167,7 @@ func TestUpdateAgent(t *testing.T) {
 		updater.fs.MkdirAll(targetDir, 0755)
 
 		currentVersion, err := updater.updateAgent(
+			context.TODO(),
 			testVersion,
 			testTenantUUID,
 			previousHash,
@@ -196,6 +200,7 @@ func TestUpdateAgent(t *testing.T) {
 			Return(fmt.Errorf("BOOM"))
 
 		currentVersion, err := updater.updateAgent(
+			context.TODO(),
 			testVersion,
 			testTenantUUID,
 			previousHash,
@@ -213,6 +218,47 @@ func TestUpdateAgent(t *testing.T) {
 			},
 		)
 	})
+	t.Run(`corrupted download`, func(t *testing.T) {
+		dk := dynatracev1beta1.DynaKube{
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				OneAgent: dynatracev1beta1.OneAgentSpec{
+					CloudNativeFullStack: &dynatracev1beta1.CloudNativeFullStackSpec{
+						HostInjectSpec: dynatracev1beta1.HostInjectSpec{
+							Version: testVersion,
+						},
+					},
+				},
+			},
+		}
+		updater := createTestAgentUpdater(t, &dk)
+		processModuleCache := createTestProcessModuleConfigCache("1")
+		targetDir := updater.path.AgentBinaryDirForVersion(testTenantUUID, testVersion)
+		updater.installer.(*installer.InstallerMock).
+			On("SetVersion", testVersion).
+			Return()
+		updater.installer.(*installer.InstallerMock).
+			On("InstallAgent", targetDir).
+			Return(fmt.Errorf("failed to install agent: %w", &dtclient.DownloadVerificationError{Property: "size", Expected: "2", Actual: "1"}))
+
+		currentVersion, err := updater.updateAgent(
+			context.TODO(),
+			testVersion,
+			testTenantUUID,
+			"",
+			&processModuleCache)
+
+		require.Error(t, err)
+		assert.Equal(t, "", currentVersion)
+		t_utils.AssertEvents(t,
+			updater.recorder.(*record.FakeRecorder).Events,
+			t_utils.Events{
+				t_utils.Event{
+					EventType: corev1.EventTypeWarning,
+					Reason:    failedVerifyAgentVersionEvent,
+				},
+			},
+		)
+	})
 }
 
 func updateOneagent(t *testing.T, alreadyInstalled bool) {
@@ -248,6 +294,7 @@ func updateOneagent(t *testing.T, alreadyInstalled bool) {
 	}
 
 	currentVersion, err := updater.updateAgent(
+		context.TODO(),
 		"other",
 		testTenantUUID,
 		previousHash,
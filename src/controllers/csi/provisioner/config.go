@@ -6,6 +6,7 @@ import (
 
 const (
 	failedInstallAgentVersionEvent = "FailedInstallAgentVersion"
+	failedVerifyAgentVersionEvent  = "FailedVerifyAgentVersion"
 	installAgentVersionEvent       = "InstallAgentVersion"
 )
 
@@ -90,6 +90,11 @@ func (provisioner *OneAgentProvisioner) Reconcile(ctx context.Context, request r
 		return reconcile.Result{RequeueAfter: longRequeueDuration}, nil
 	}
 
+	if dk.Spec.Paused {
+		log.Info("reconciliation is paused, code modules are not updated", "dynakube", dk.Name)
+		return reconcile.Result{RequeueAfter: defaultRequeueDuration}, nil
+	}
+
 	if dk.ConnectionInfo().TenantUUID == "" {
 		log.Info("DynaKube instance has not been reconciled yet and some values usually cached are missing, retrying in a few seconds")
 		return reconcile.Result{RequeueAfter: shortRequeueDuration}, nil
@@ -132,7 +137,7 @@ func (provisioner *OneAgentProvisioner) Reconcile(ctx context.Context, request r
 	}
 	log.Info("csi directories exist", "path", provisioner.path.EnvDir(dynakube.TenantUUID))
 
-	latestProcessModuleConfig, storedHash, err := provisioner.getProcessModuleConfig(dtc, dynakube.TenantUUID)
+	latestProcessModuleConfig, storedHash, err := provisioner.getProcessModuleConfig(ctx, dtc, dynakube.TenantUUID)
 	if err != nil {
 		log.Error(err, "error when getting the latest ruxitagentproc.conf")
 		return reconcile.Result{}, err
@@ -141,7 +146,7 @@ func (provisioner *OneAgentProvisioner) Reconcile(ctx context.Context, request r
 	latestProcessModuleConfigCache := newProcessModuleConfigCache(latestProcessModuleConfig)
 
 	agentUpdater := newAgentUpdater(dtc, provisioner.path, provisioner.fs, provisioner.recorder, dk)
-	if updatedVersion, err := agentUpdater.updateAgent(dynakube.LatestVersion, dynakube.TenantUUID, storedHash, latestProcessModuleConfigCache); err != nil {
+	if updatedVersion, err := agentUpdater.updateAgent(ctx, dynakube.LatestVersion, dynakube.TenantUUID, storedHash, latestProcessModuleConfigCache); err != nil {
 		log.Info("error when updating agent", "error", err.Error())
 		// reporting error but not returning it to avoid immediate requeue and subsequently calling the API every few seconds
 		return reconcile.Result{RequeueAfter: defaultRequeueDuration}, nil
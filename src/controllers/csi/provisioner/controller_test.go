@@ -110,6 +110,27 @@ func TestOneAgentProvisioner_Reconcile(t *testing.T) {
 		assert.NotNil(t, result)
 		assert.Equal(t, reconcile.Result{RequeueAfter: 30 * time.Minute}, result)
 	})
+	t.Run(`reconciliation paused`, func(t *testing.T) {
+		provisioner := &OneAgentProvisioner{
+			apiReader: fake.NewClient(
+				&dynatracev1beta1.DynaKube{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: dkName,
+					},
+					Spec: dynatracev1beta1.DynaKubeSpec{
+						OneAgent: dynatracev1beta1.OneAgentSpec{
+							ApplicationMonitoring: buildValidApplicationMonitoringSpec(t),
+						},
+						Paused: true,
+					},
+				},
+			),
+		}
+		result, err := provisioner.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: dkName}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, reconcile.Result{RequeueAfter: defaultRequeueDuration}, result)
+	})
 	t.Run(`no tokens`, func(t *testing.T) {
 		provisioner := &OneAgentProvisioner{
 			apiReader: fake.NewClient(
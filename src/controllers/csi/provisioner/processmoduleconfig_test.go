@@ -1,6 +1,7 @@
 package csiprovisioner
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"testing"
@@ -58,7 +59,7 @@ func TestGetProcessModuleConfig(t *testing.T) {
 			fs: memFs,
 		}
 
-		response, storedHash, err := provisioner.getProcessModuleConfig(mockClient, testTenantUUID)
+		response, storedHash, err := provisioner.getProcessModuleConfig(context.TODO(), mockClient, testTenantUUID)
 
 		require.Nil(t, err)
 		assert.Equal(t, testProcessModuleConfig, *response)
@@ -74,7 +75,7 @@ func TestGetProcessModuleConfig(t *testing.T) {
 			fs: memFs,
 		}
 
-		response, storedHash, err := provisioner.getProcessModuleConfig(mockClient, testTenantUUID)
+		response, storedHash, err := provisioner.getProcessModuleConfig(context.TODO(), mockClient, testTenantUUID)
 
 		require.Nil(t, err)
 		assert.Equal(t, testProcessModuleConfigCache.ProcessModuleConfig, response)
@@ -90,7 +91,7 @@ func TestGetProcessModuleConfig(t *testing.T) {
 			fs: memFs,
 		}
 
-		response, storedHash, err := provisioner.getProcessModuleConfig(mockClient, testTenantUUID)
+		response, storedHash, err := provisioner.getProcessModuleConfig(context.TODO(), mockClient, testTenantUUID)
 
 		require.Nil(t, err)
 		assert.Equal(t, testProcessModuleConfig, *response)
@@ -42,8 +42,8 @@ func NewTenantSecretReconciler(clt client.Client, apiReader client.Reader, schem
 	}
 }
 
-func (r *TenantSecretReconciler) Reconcile() error {
-	err := r.reconcileSecret()
+func (r *TenantSecretReconciler) Reconcile(ctx context.Context) error {
+	err := r.reconcileSecret(ctx)
 	if err != nil {
 		log.Error(err, "could not reconcile ActiveGate tenant secret")
 		return errors.WithStack(err)
@@ -52,8 +52,8 @@ func (r *TenantSecretReconciler) Reconcile() error {
 	return nil
 }
 
-func (r *TenantSecretReconciler) reconcileSecret() error {
-	agSecretData, err := r.getActiveGateTenantInfo()
+func (r *TenantSecretReconciler) reconcileSecret(ctx context.Context) error {
+	agSecretData, err := r.getActiveGateTenantInfo(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to fetch ActiveGate tenant info: %w", err)
 	}
@@ -66,8 +66,8 @@ func (r *TenantSecretReconciler) reconcileSecret() error {
 	return r.updateSecretIfOutdated(agSecret, agSecretData)
 }
 
-func (r *TenantSecretReconciler) getActiveGateTenantInfo() (map[string][]byte, error) {
-	tenantInfo, err := r.dtc.GetActiveGateTenantInfo()
+func (r *TenantSecretReconciler) getActiveGateTenantInfo(ctx context.Context) (map[string][]byte, error) {
+	tenantInfo, err := r.dtc.GetActiveGateTenantInfo(ctx)
 
 	if err != nil {
 		return nil, errors.WithStack(err)
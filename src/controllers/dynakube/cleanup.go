@@ -0,0 +1,149 @@
+package dynakube
+
+import (
+	"context"
+	"time"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	"github.com/Dynatrace/dynatrace-operator/src/controllers/activegate/reconciler/automaticapimonitoring"
+	"github.com/Dynatrace/dynatrace-operator/src/controllers/dynakube/istio"
+	dtingestendpoint "github.com/Dynatrace/dynatrace-operator/src/ingestendpoint"
+	"github.com/Dynatrace/dynatrace-operator/src/mapper"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/src/webhook"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// cleanupFinalizer keeps the DynaKube until the objects which aren't garbage collected with it are removed
+	cleanupFinalizer = "dynatrace.com/cleanup"
+
+	// tenantCleanupTimeout is how long the cleanup of the Dynatrace environment is retried after the DynaKube was
+	// deleted, afterwards the DynaKube is removed anyway
+	tenantCleanupTimeout = 10 * time.Minute
+
+	namespacesCleanedUpEvent = "NamespacesCleanedUp"
+	istioCleanedUpEvent      = "IstioCleanedUp"
+	tenantCleanedUpEvent     = "TenantCleanedUp"
+	tenantCleanupFailedEvent = "TenantCleanupFailed"
+)
+
+// addCleanupFinalizer adds the finalizer to DynaKubes which don't have it yet
+func (controller *DynakubeController) addCleanupFinalizer(ctx context.Context, instance *dynatracev1beta1.DynaKube) error {
+	if controllerutil.ContainsFinalizer(instance, cleanupFinalizer) {
+		return nil
+	}
+	controllerutil.AddFinalizer(instance, cleanupFinalizer)
+	return errors.WithStack(controller.client.Update(ctx, instance))
+}
+
+// cleanup removes the objects of a deleted DynaKube which aren't garbage collected, in this order:
+// the secrets and labels of the monitored namespaces, the Istio objects and the settings in the Dynatrace environment.
+// It runs even if the reconciliation is paused. If the Dynatrace environment can't be reached the cleanup is retried
+// until tenantCleanupTimeout has passed since the deletion, afterwards the finalizer is removed anyway.
+func (controller *DynakubeController) cleanup(ctx context.Context, instance *dynatracev1beta1.DynaKube, dkMapper *mapper.DynakubeMapper) (reconcile.Result, error) {
+	if !controllerutil.ContainsFinalizer(instance, cleanupFinalizer) {
+		return reconcile.Result{}, nil
+	}
+	log.Info("cleaning up deleted DynaKube", "namespace", instance.Namespace, "name", instance.Name)
+
+	if err := controller.cleanupNamespaces(ctx, instance, dkMapper); err != nil {
+		return reconcile.Result{}, err
+	}
+	if err := controller.cleanupIstio(instance); err != nil {
+		return reconcile.Result{}, err
+	}
+	if err := controller.cleanupTenant(ctx, instance); err != nil {
+		if time.Since(instance.DeletionTimestamp.Time) < tenantCleanupTimeout {
+			log.Info("could not clean up the Dynatrace environment, retrying", "error", err.Error())
+			return reconcile.Result{RequeueAfter: shortUpdateInterval}, nil
+		}
+		controller.recordEvent(instance, corev1.EventTypeWarning, tenantCleanupFailedEvent,
+			"Gave up removing the settings in the Dynatrace environment after %s, they have to be removed manually: %s", tenantCleanupTimeout, err)
+	}
+
+	controllerutil.RemoveFinalizer(instance, cleanupFinalizer)
+	return reconcile.Result{}, errors.WithStack(controller.client.Update(ctx, instance))
+}
+
+// cleanupNamespaces removes the config and data-ingest secrets from the monitored namespaces before their labels,
+// so a failed attempt can be retried
+func (controller *DynakubeController) cleanupNamespaces(ctx context.Context, instance *dynatracev1beta1.DynaKube, dkMapper *mapper.DynakubeMapper) error {
+	namespaces, err := mapper.GetNamespacesForDynakube(ctx, controller.apiReader, instance.Name)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(namespaces) == 0 {
+		return nil
+	}
+
+	for _, namespace := range namespaces {
+		for _, secretName := range []string{dtwebhook.SecretConfigName, dtingestendpoint.SecretEndpointName} {
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: namespace.Name}}
+			if err := controller.client.Delete(ctx, secret); err != nil && !k8serrors.IsNotFound(err) {
+				return errors.WithMessagef(err, "failed to delete secret %s from namespace %s", secretName, namespace.Name)
+			}
+		}
+	}
+
+	if err := dkMapper.UnmapFromDynaKube(); err != nil {
+		return err
+	}
+	controller.recordEvent(instance, corev1.EventTypeNormal, namespacesCleanedUpEvent,
+		"Removed the injection label and secrets from %d namespaces", len(namespaces))
+	return nil
+}
+
+func (controller *DynakubeController) cleanupIstio(instance *dynatracev1beta1.DynaKube) error {
+	if !instance.Spec.EnableIstio {
+		return nil
+	}
+
+	istioReconciler := istio.NewIstioReconciler(controller.config, controller.scheme)
+	if istioReconciler == nil {
+		return errors.New("istio: failed to initialize client")
+	}
+	removed, err := istioReconciler.RemoveIstioConfigurations(instance)
+	if err != nil {
+		return err
+	}
+	if removed {
+		controller.recordEvent(instance, corev1.EventTypeNormal, istioCleanedUpEvent, "Removed the Istio ServiceEntries and VirtualServices")
+	}
+	return nil
+}
+
+// cleanupTenant removes the kubernetes cluster settings created by the automatic kubernetes api monitoring
+func (controller *DynakubeController) cleanupTenant(ctx context.Context, instance *dynatracev1beta1.DynaKube) error {
+	if instance.Status.KubeSystemUUID == "" ||
+		!instance.FeatureAutomaticKubernetesApiMonitoring() ||
+		!instance.KubernetesMonitoringMode() {
+		return nil
+	}
+
+	properties, err := NewDynatraceClientProperties(ctx, controller.apiReader, *instance)
+	if err != nil {
+		return err
+	}
+	dtc, err := controller.dtcBuildFunc(*properties)
+	if err != nil {
+		return err
+	}
+
+	removed, err := automaticapimonitoring.NewReconciler(dtc, instance.Name, instance.Status.KubeSystemUUID).Cleanup(ctx)
+	if len(removed) > 0 {
+		controller.recordEvent(instance, corev1.EventTypeNormal, tenantCleanedUpEvent,
+			"Removed the kubernetes cluster settings %v from the Dynatrace environment", removed)
+	}
+	return err
+}
+
+func (controller *DynakubeController) recordEvent(instance *dynatracev1beta1.DynaKube, eventType, reason, messageFmt string, args ...interface{}) {
+	if controller.recorder != nil {
+		controller.recorder.Eventf(instance, eventType, reason, messageFmt, args...)
+	}
+}
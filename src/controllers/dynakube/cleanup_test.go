@@ -0,0 +1,167 @@
+package dynakube
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	"github.com/Dynatrace/dynatrace-operator/src/dtclient"
+	dtingestendpoint "github.com/Dynatrace/dynatrace-operator/src/ingestendpoint"
+	"github.com/Dynatrace/dynatrace-operator/src/kubesystem"
+	"github.com/Dynatrace/dynatrace-operator/src/mapper"
+	"github.com/Dynatrace/dynatrace-operator/src/scheme"
+	"github.com/Dynatrace/dynatrace-operator/src/scheme/fake"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/src/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const testMonitoredNamespace = "test-monitored-namespace"
+
+func TestCleanup(t *testing.T) {
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: testName}}
+	settingsObject := dtclient.SettingsObject{
+		ObjectID: testObjectID,
+		Value:    []byte(`{"label":"` + testName + `","clusterId":"` + testUID + `"}`),
+	}
+	newDeletedInstance := func(deletedSince time.Duration) *dynatracev1beta1.DynaKube {
+		return &dynatracev1beta1.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              testName,
+				Namespace:         testNamespace,
+				Annotations:       map[string]string{dynatracev1beta1.AnnotationFeatureAutomaticKubernetesApiMonitoring: "true"},
+				Finalizers:        []string{cleanupFinalizer},
+				DeletionTimestamp: &metav1.Time{Time: time.Now().Add(-deletedSince)},
+			},
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				APIURL:               testHost,
+				KubernetesMonitoring: dynatracev1beta1.KubernetesMonitoringSpec{Enabled: true},
+			},
+			Status: dynatracev1beta1.DynaKubeStatus{KubeSystemUUID: testUID},
+		}
+	}
+	newMockClient := func(deleteErr error) *dtclient.MockDynatraceClient {
+		mockClient := &dtclient.MockDynatraceClient{}
+		mockClient.On("GetMonitoredEntitiesForKubeSystemUUID", testUID).
+			Return([]dtclient.MonitoredEntity{{EntityId: "KUBERNETES_CLUSTER-1"}}, nil)
+		mockClient.On("ListSettingsObjects", "builtin:cloud.kubernetes", "KUBERNETES_CLUSTER-1").
+			Return([]dtclient.SettingsObject{settingsObject}, nil)
+		mockClient.On("DeleteSettingsObject", testObjectID).Return(deleteErr)
+		return mockClient
+	}
+
+	t.Run(`finalizer is added`, func(t *testing.T) {
+		instance := &dynatracev1beta1.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: testNamespace},
+			Spec:       dynatracev1beta1.DynaKubeSpec{APIURL: testHost},
+		}
+		mockClient := createDTMockClient(dtclient.TokenScopes{dtclient.TokenScopeInstallerDownload},
+			dtclient.TokenScopes{dtclient.TokenScopeDataExport})
+		controller := createFakeClientAndReconcile(mockClient, instance, testPaasToken, testAPIToken)
+
+		_, err := controller.Reconcile(context.TODO(), request)
+		require.NoError(t, err)
+
+		var dynakube dynatracev1beta1.DynaKube
+		require.NoError(t, controller.client.Get(context.TODO(), request.NamespacedName, &dynakube))
+		assert.Contains(t, dynakube.Finalizers, cleanupFinalizer)
+	})
+	t.Run(`namespaces and tenant are cleaned up`, func(t *testing.T) {
+		controller, recorder := createCleanupController(newMockClient(nil), newDeletedInstance(time.Minute))
+
+		result, err := controller.Reconcile(context.TODO(), request)
+		require.NoError(t, err)
+		assert.Equal(t, reconcile.Result{}, result)
+
+		var namespace corev1.Namespace
+		require.NoError(t, controller.client.Get(context.TODO(), client.ObjectKey{Name: testMonitoredNamespace}, &namespace))
+		assert.NotContains(t, namespace.Labels, mapper.InstanceLabel)
+		for _, secretName := range []string{dtwebhook.SecretConfigName, dtingestendpoint.SecretEndpointName} {
+			err = controller.client.Get(context.TODO(), client.ObjectKey{Name: secretName, Namespace: testMonitoredNamespace}, &corev1.Secret{})
+			assert.True(t, k8serrors.IsNotFound(err))
+		}
+
+		err = controller.client.Get(context.TODO(), request.NamespacedName, &dynatracev1beta1.DynaKube{})
+		assert.True(t, k8serrors.IsNotFound(err))
+
+		assert.Equal(t, []string{
+			"Normal " + namespacesCleanedUpEvent + " Removed the injection label and secrets from 1 namespaces",
+			"Normal " + tenantCleanedUpEvent + " Removed the kubernetes cluster settings [" + testObjectID + "] from the Dynatrace environment",
+		}, recordedEvents(recorder))
+	})
+	t.Run(`cleanup of unreachable tenant is retried`, func(t *testing.T) {
+		controller, recorder := createCleanupController(newMockClient(errors.New("tenant not reachable")), newDeletedInstance(time.Minute))
+
+		result, err := controller.Reconcile(context.TODO(), request)
+		require.NoError(t, err)
+		assert.Equal(t, reconcile.Result{RequeueAfter: shortUpdateInterval}, result)
+
+		var dynakube dynatracev1beta1.DynaKube
+		require.NoError(t, controller.client.Get(context.TODO(), request.NamespacedName, &dynakube))
+		assert.Contains(t, dynakube.Finalizers, cleanupFinalizer)
+		assert.Len(t, recordedEvents(recorder), 1)
+	})
+	t.Run(`cleanup of unreachable tenant is given up after timeout`, func(t *testing.T) {
+		controller, recorder := createCleanupController(newMockClient(errors.New("tenant not reachable")), newDeletedInstance(tenantCleanupTimeout+time.Minute))
+
+		result, err := controller.Reconcile(context.TODO(), request)
+		require.NoError(t, err)
+		assert.Equal(t, reconcile.Result{}, result)
+
+		err = controller.client.Get(context.TODO(), request.NamespacedName, &dynatracev1beta1.DynaKube{})
+		assert.True(t, k8serrors.IsNotFound(err))
+
+		events := recordedEvents(recorder)
+		require.Len(t, events, 2)
+		assert.Contains(t, events[1], "Warning "+tenantCleanupFailedEvent)
+	})
+}
+
+func createCleanupController(mockClient dtclient.Client, instance *dynatracev1beta1.DynaKube) (*DynakubeController, *record.FakeRecorder) {
+	fakeClient := fake.NewClient(instance,
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: testNamespace},
+			Data:       map[string][]byte{dtclient.DynatraceApiToken: []byte(testAPIToken)},
+		},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: kubesystem.Namespace, UID: testUID}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   testMonitoredNamespace,
+			Labels: map[string]string{mapper.InstanceLabel: testName},
+		}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: dtwebhook.SecretConfigName, Namespace: testMonitoredNamespace}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: dtingestendpoint.SecretEndpointName, Namespace: testMonitoredNamespace}},
+	)
+	recorder := record.NewFakeRecorder(10)
+
+	return &DynakubeController{
+		client:    fakeClient,
+		apiReader: fakeClient,
+		scheme:    scheme.Scheme,
+		dtcBuildFunc: func(DynatraceClientProperties) (dtclient.Client, error) {
+			return mockClient, nil
+		},
+		operatorNamespace: testNamespace,
+		recorder:          recorder,
+	}, recorder
+}
+
+func recordedEvents(recorder *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
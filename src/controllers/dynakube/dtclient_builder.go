@@ -3,6 +3,7 @@ package dynakube
 import (
 	"context"
 	"fmt"
+	"time"
 
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
 	"github.com/Dynatrace/dynatrace-operator/src/dtclient"
@@ -21,11 +22,14 @@ type DynatraceClientProperties struct {
 	Secret              *corev1.Secret
 	Proxy               *DynatraceClientProxy
 	ApiUrl              string
+	FailoverApiUrls     []string
 	Namespace           string
 	NetworkZone         string
 	TrustedCerts        string
+	ClientCertificate   string
 	SkipCertCheck       bool
 	DisableHostRequests bool
+	ResponseCacheTTL    time.Duration
 }
 
 type DynatraceClientProxy struct {
@@ -43,12 +47,15 @@ func NewDynatraceClientProperties(ctx context.Context, apiReader client.Reader,
 		ApiReader:           apiReader,
 		Secret:              &tokens,
 		ApiUrl:              dk.Spec.APIURL,
+		FailoverApiUrls:     dk.Spec.FailoverAPIURLs,
 		Namespace:           dk.Namespace,
 		Proxy:               (*DynatraceClientProxy)(dk.Spec.Proxy),
 		NetworkZone:         dk.Spec.NetworkZone,
 		TrustedCerts:        dk.Spec.TrustedCAs,
+		ClientCertificate:   dk.Spec.ClientCertificate,
 		SkipCertCheck:       dk.Spec.SkipCertCheck,
 		DisableHostRequests: dk.FeatureDisableHostsRequests(),
+		ResponseCacheTTL:    dk.FeatureApiResponseCacheTTL(),
 	}, err
 }
 
@@ -67,6 +74,8 @@ func BuildDynatraceClient(properties DynatraceClientProperties) (dtclient.Client
 	opts.appendCertCheck(properties.SkipCertCheck)
 	opts.appendNetworkZone(properties.NetworkZone)
 	opts.appendDisableHostsRequests(properties.DisableHostRequests)
+	opts.appendResponseCacheTTL(properties.ResponseCacheTTL)
+	opts.appendFailoverURLs(properties.FailoverApiUrls)
 
 	err = opts.appendProxySettings(apiReader, properties.Proxy, namespace)
 	if err != nil {
@@ -78,6 +87,11 @@ func BuildDynatraceClient(properties DynatraceClientProperties) (dtclient.Client
 		return nil, errors.WithStack(err)
 	}
 
+	err = opts.appendClientCertificate(apiReader, properties.ClientCertificate, namespace)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
 	return dtclient.NewClient(properties.ApiUrl, tokens.ApiToken, tokens.PaasToken, opts.Opts...)
 }
 
@@ -108,6 +122,18 @@ func (opts *options) appendDisableHostsRequests(disableHostsRequests bool) {
 	opts.Opts = append(opts.Opts, dtclient.DisableHostsRequests(disableHostsRequests))
 }
 
+func (opts *options) appendResponseCacheTTL(ttl time.Duration) {
+	if ttl > 0 {
+		opts.Opts = append(opts.Opts, dtclient.ResponseCacheTTL(ttl))
+	}
+}
+
+func (opts *options) appendFailoverURLs(failoverURLs []string) {
+	if len(failoverURLs) > 0 {
+		opts.Opts = append(opts.Opts, dtclient.FailoverURLs(failoverURLs...))
+	}
+}
+
 func (opts *options) appendProxySettings(apiReader client.Reader, proxyEntry *DynatraceClientProxy, namespace string) error {
 	if p := proxyEntry; p != nil {
 		if p.ValueFrom != "" {
@@ -142,3 +168,22 @@ func (opts *options) appendTrustedCerts(apiReader client.Reader, trustedCerts st
 	}
 	return nil
 }
+
+func (opts *options) appendClientCertificate(apiReader client.Reader, clientCertificate string, namespace string) error {
+	if clientCertificate != "" {
+		certSecret := &corev1.Secret{}
+		if err := apiReader.Get(context.TODO(), client.ObjectKey{Namespace: namespace, Name: clientCertificate}, certSecret); err != nil {
+			return fmt.Errorf("failed to get client certificate secret: %w", err)
+		}
+		cert, err := kubeobjects.ExtractToken(certSecret, dtclient.ClientCertificateSecretCertKey)
+		if err != nil {
+			return fmt.Errorf("failed to extract client certificate secret field: %w", err)
+		}
+		key, err := kubeobjects.ExtractToken(certSecret, dtclient.ClientCertificateSecretKeyKey)
+		if err != nil {
+			return fmt.Errorf("failed to extract client certificate secret field: %w", err)
+		}
+		opts.Opts = append(opts.Opts, dtclient.ClientCertificate([]byte(cert), []byte(key)))
+	}
+	return nil
+}
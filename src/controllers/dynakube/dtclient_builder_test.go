@@ -143,6 +143,42 @@ func TestBuildDynatraceClient(t *testing.T) {
 		assert.Error(t, err)
 		assert.Nil(t, dtc)
 	})
+	t.Run(`BuildDynatraceClient handles missing or incomplete client certificate secret`, func(t *testing.T) {
+		secret := corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testName,
+				Namespace: testNamespace,
+			},
+			Data: map[string][]byte{
+				dtclient.DynatraceApiToken:  []byte(testValue),
+				dtclient.DynatracePaasToken: []byte(testValueAlternative),
+			}}
+		certSecret := corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testKey,
+				Namespace: testNamespace,
+			},
+			Data: map[string][]byte{
+				dtclient.ClientCertificateSecretCertKey: []byte(testValue),
+			}}
+		dtf := DynatraceClientProperties{
+			ApiReader:         fake.NewClient(&secret),
+			Secret:            &secret,
+			ApiUrl:            testEndpoint,
+			Namespace:         testNamespace,
+			ClientCertificate: testKey,
+		}
+		dtc, err := BuildDynatraceClient(dtf)
+
+		assert.Error(t, err)
+		assert.Nil(t, dtc)
+
+		dtf.ApiReader = fake.NewClient(&secret, &certSecret)
+		dtc, err = BuildDynatraceClient(dtf)
+
+		assert.EqualError(t, err, "failed to extract client certificate secret field: missing token "+dtclient.ClientCertificateSecretKeyKey)
+		assert.Nil(t, dtc)
+	})
 }
 
 // func TestOptions(t *testing.T) {
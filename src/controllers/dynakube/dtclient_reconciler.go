@@ -5,25 +5,37 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
 	"github.com/Dynatrace/dynatrace-operator/src/dtclient"
+	"github.com/Dynatrace/dynatrace-operator/src/kubeobjects"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const (
+	// tokenExpiryWarningPeriod is how long before the expiration of a token the TokenExpiring condition is set
+	tokenExpiryWarningPeriod = 7 * 24 * time.Hour
+
+	tokensRotatedEvent = "TokensRotated"
+)
+
 type DynatraceClientReconciler struct {
 	Client                               client.Client
 	DynatraceClientFunc                  DynatraceClientFunc
+	Recorder                             record.EventRecorder
 	Now                                  metav1.Time
 	ApiToken, PaasToken, DataIngestToken string
 	ValidTokens                          bool
 	dkName, ns, secretKey                string
+	instance                             *dynatracev1beta1.DynaKube
 	status                               *dynatracev1beta1.DynaKubeStatus
 }
 
@@ -32,6 +44,9 @@ type tokenConfig struct {
 	Key, Value string
 	Scopes     []string
 	Timestamp  **metav1.Time
+
+	// CheckEventsScope enables the check of the optional events.ingest scope, see DynatraceEventsConditionType
+	CheckEventsScope bool
 }
 
 func (r *DynatraceClientReconciler) Reconcile(ctx context.Context, instance *dynatracev1beta1.DynaKube) (dtclient.Client, bool, error) {
@@ -45,6 +60,7 @@ func (r *DynatraceClientReconciler) Reconcile(ctx context.Context, instance *dyn
 		dtf = BuildDynatraceClient
 	}
 
+	r.instance = instance
 	r.status = &instance.Status
 	r.ns = instance.GetNamespace()
 	r.dkName = instance.GetName()
@@ -70,6 +86,12 @@ func (r *DynatraceClientReconciler) Reconcile(ctx context.Context, instance *dyn
 		return nil, updateCR, err
 	}
 
+	upd, err := r.detectTokenRotation(secret)
+	if err != nil {
+		return nil, updateCR, err
+	}
+	updateCR = upd || updateCR
+
 	if r.ApiToken == "" {
 		msg := fmt.Sprintf("Token %s on secret %s missing", dtclient.DynatraceApiToken, r.secretKey)
 		updateCR = r.setAndLogCondition(&r.status.Conditions, metav1.Condition{
@@ -88,11 +110,14 @@ func (r *DynatraceClientReconciler) Reconcile(ctx context.Context, instance *dyn
 		Secret:              secret,
 		Proxy:               convertProxy(instance.Spec.Proxy),
 		ApiUrl:              instance.Spec.APIURL,
+		FailoverApiUrls:     instance.Spec.FailoverAPIURLs,
 		Namespace:           r.ns,
 		NetworkZone:         instance.Spec.NetworkZone,
 		TrustedCerts:        instance.Spec.TrustedCAs,
+		ClientCertificate:   instance.Spec.ClientCertificate,
 		SkipCertCheck:       instance.Spec.SkipCertCheck,
 		DisableHostRequests: instance.FeatureDisableHostsRequests(),
+		ResponseCacheTTL:    instance.FeatureApiResponseCacheTTL(),
 	})
 
 	if err != nil {
@@ -147,6 +172,18 @@ func (r *DynatraceClientReconciler) Reconcile(ctx context.Context, instance *dyn
 			dtclient.TokenScopeSettingsWrite)
 	}
 
+	if instance.FeatureDisableDynatraceEvents() {
+		updateCR = r.removeCondition(dynatracev1beta1.DynatraceEventsConditionType) || updateCR
+	} else {
+		tokens[0].CheckEventsScope = true
+	}
+
+	if len(instance.Spec.Settings) > 0 {
+		tokens[0].Scopes = appendMissingScopes(tokens[0].Scopes,
+			dtclient.TokenScopeSettingsRead,
+			dtclient.TokenScopeSettingsWrite)
+	}
+
 	if r.DataIngestToken != "" {
 		tokens = append(tokens, tokenConfig{
 			Type:      dynatracev1beta1.DataIngestTokenConditionType,
@@ -158,13 +195,40 @@ func (r *DynatraceClientReconciler) Reconcile(ctx context.Context, instance *dyn
 	}
 
 	for _, token := range tokens {
-		updateCR = r.CheckToken(dtc, token) || updateCR
+		updateCR = r.CheckToken(ctx, dtc, token) || updateCR
 	}
 
+	updateCR = r.removeTokenExpirationsOfMissingTokens(tokens) || updateCR
+	updateCR = r.updateTokenExpiringCondition() || updateCR
+
 	return dtc, updateCR, nil
 }
 
-func (r *DynatraceClientReconciler) CheckToken(dtc dtclient.Client, token tokenConfig) bool {
+// detectTokenRotation resets the probe timestamps if the tokens secret changed since the last reconcile,
+// so rotated tokens are validated and propagated immediately instead of after the next probe interval.
+func (r *DynatraceClientReconciler) detectTokenRotation(secret *corev1.Secret) (bool, error) {
+	secretHash, err := kubeobjects.GenerateHash(secret.Data)
+	if err != nil {
+		return false, err
+	}
+
+	if r.status.TokenSecretHash == secretHash {
+		return false, nil
+	}
+
+	if r.status.TokenSecretHash != "" {
+		log.Info("tokens secret changed, validating tokens again", "dynakube", r.dkName, "secret", r.secretKey)
+		r.recordEvent(corev1.EventTypeNormal, tokensRotatedEvent, "Tokens on secret %s changed, validating the new tokens", r.secretKey)
+
+		r.status.LastAPITokenProbeTimestamp = nil
+		r.status.LastPaaSTokenProbeTimestamp = nil
+		r.status.LastDataIngestTokenProbeTimestamp = nil
+	}
+	r.status.TokenSecretHash = secretHash
+	return true, nil
+}
+
+func (r *DynatraceClientReconciler) CheckToken(ctx context.Context, dtc dtclient.Client, token tokenConfig) bool {
 	if strings.TrimSpace(token.Value) != token.Value {
 		return r.setAndLogCondition(&r.status.Conditions, metav1.Condition{
 			Type:    token.Type,
@@ -186,7 +250,7 @@ func (r *DynatraceClientReconciler) CheckToken(dtc dtclient.Client, token tokenC
 
 	nowCopy := r.Now
 	*token.Timestamp = &nowCopy
-	ss, err := dtc.GetTokenScopes(token.Value)
+	tokenInfo, err := dtc.GetTokenInfo(ctx, token.Value)
 
 	var serr dtclient.ServerError
 	if ok := errors.As(err, &serr); ok && serr.Code == http.StatusUnauthorized {
@@ -209,9 +273,28 @@ func (r *DynatraceClientReconciler) CheckToken(dtc dtclient.Client, token tokenC
 		return true
 	}
 
+	if tokenInfo.Expires != nil && !tokenInfo.Expires.After(r.Now.Time) {
+		message := fmt.Sprintf("Token %s on secret %s expired at %s", token.Key, r.secretKey, tokenInfo.Expires.UTC().Format(time.RFC3339))
+		if r.setAndLogCondition(&r.status.Conditions, metav1.Condition{
+			Type:    token.Type,
+			Status:  metav1.ConditionFalse,
+			Reason:  dynatracev1beta1.ReasonTokenExpired,
+			Message: message,
+		}) {
+			r.recordEvent(corev1.EventTypeWarning, dynatracev1beta1.ReasonTokenExpired, "%s", message)
+		}
+		delete(r.status.TokenExpirations, token.Key)
+		return true
+	}
+
+	r.updateTokenExpiration(token.Key, tokenInfo.Expires)
+	if token.CheckEventsScope {
+		r.updateEventsCondition(tokenInfo.Scopes)
+	}
+
 	missingScopes := make([]string, 0)
 	for _, s := range token.Scopes {
-		if !ss.Contains(s) {
+		if !tokenInfo.Scopes.Contains(s) {
 			missingScopes = append(missingScopes, s)
 		}
 	}
@@ -234,14 +317,119 @@ func (r *DynatraceClientReconciler) CheckToken(dtc dtclient.Client, token tokenC
 	return true
 }
 
+// updateTokenExpiration keeps the expiration date of the probed token in the status if it expires soon, so the
+// TokenExpiring condition doesn't depend on which tokens were probed in this reconcile. It is removed as soon as a
+// probe shows the token was rotated.
+func (r *DynatraceClientReconciler) updateTokenExpiration(key string, expires *time.Time) {
+	if expires == nil || !expires.Before(r.Now.Add(tokenExpiryWarningPeriod)) {
+		delete(r.status.TokenExpirations, key)
+		return
+	}
+
+	if r.status.TokenExpirations == nil {
+		r.status.TokenExpirations = make(map[string]metav1.Time)
+	}
+	r.status.TokenExpirations[key] = metav1.NewTime(expires.UTC())
+}
+
+// removeTokenExpirationsOfMissingTokens forgets the expiration dates of tokens which were removed from the secret
+func (r *DynatraceClientReconciler) removeTokenExpirationsOfMissingTokens(tokens []tokenConfig) bool {
+	removed := false
+	for key := range r.status.TokenExpirations {
+		found := false
+		for _, token := range tokens {
+			found = found || token.Key == key
+		}
+		if !found {
+			delete(r.status.TokenExpirations, key)
+			removed = true
+		}
+	}
+	return removed
+}
+
+// updateTokenExpiringCondition sets the TokenExpiring condition and sends a warning event when the list of tokens
+// expiring soon changed. Expiring tokens are still valid, so unlike setAndLogCondition it doesn't affect ValidTokens.
+func (r *DynatraceClientReconciler) updateTokenExpiringCondition() bool {
+	if len(r.status.TokenExpirations) == 0 {
+		return r.removeCondition(dynatracev1beta1.TokenExpiringConditionType)
+	}
+
+	keys := make([]string, 0, len(r.status.TokenExpirations))
+	for key := range r.status.TokenExpirations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	expiringTokens := make([]string, 0, len(keys))
+	for _, key := range keys {
+		expires := r.status.TokenExpirations[key]
+		expiringTokens = append(expiringTokens, fmt.Sprintf("%s expires at %s", key, expires.UTC().Format(time.RFC3339)))
+	}
+
+	message := fmt.Sprintf("Tokens on secret %s must be rotated soon: %s", r.secretKey, strings.Join(expiringTokens, ", "))
+	c := meta.FindStatusCondition(r.status.Conditions, dynatracev1beta1.TokenExpiringConditionType)
+	if c != nil && c.Message == message {
+		return false
+	}
+
+	log.Info("tokens expiring soon", "dynakube", r.dkName, "msg", message)
+	r.recordEvent(corev1.EventTypeWarning, dynatracev1beta1.ReasonTokenExpiring, "%s", message)
+	meta.SetStatusCondition(&r.status.Conditions, metav1.Condition{
+		Type:               dynatracev1beta1.TokenExpiringConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             dynatracev1beta1.ReasonTokenExpiring,
+		Message:            message,
+		LastTransitionTime: r.Now,
+	})
+	return true
+}
+
+// updateEventsCondition reports whether the API token has the optional events.ingest scope. Without it events can't be sent,
+// but the token is still valid, so unlike setAndLogCondition it doesn't affect ValidTokens.
+func (r *DynatraceClientReconciler) updateEventsCondition(scopes dtclient.TokenScopes) {
+	condition := metav1.Condition{
+		Type:    dynatracev1beta1.DynatraceEventsConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  dynatracev1beta1.ReasonTokenReady,
+		Message: "Ready",
+	}
+	if !scopes.Contains(dtclient.TokenScopeEventsIngest) {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = dynatracev1beta1.ReasonTokenScopeMissing
+		condition.Message = fmt.Sprintf("Token %s on secret %s missing scope %s, events about rollouts and version changes are not sent",
+			dtclient.DynatraceApiToken, r.secretKey, dtclient.TokenScopeEventsIngest)
+	}
+
+	c := meta.FindStatusCondition(r.status.Conditions, condition.Type)
+	if c != nil && c.Status == condition.Status && c.Message == condition.Message {
+		return
+	}
+	if condition.Status == metav1.ConditionFalse {
+		log.Info("events are not sent", "dynakube", r.dkName, "msg", condition.Message)
+	}
+	condition.LastTransitionTime = r.Now
+	meta.SetStatusCondition(&r.status.Conditions, condition)
+}
+
 func (r *DynatraceClientReconciler) removePaaSTokenCondition() bool {
-	if meta.FindStatusCondition(r.status.Conditions, dynatracev1beta1.PaaSTokenConditionType) != nil {
-		meta.RemoveStatusCondition(&r.status.Conditions, dynatracev1beta1.PaaSTokenConditionType)
+	return r.removeCondition(dynatracev1beta1.PaaSTokenConditionType)
+}
+
+func (r *DynatraceClientReconciler) removeCondition(conditionType string) bool {
+	if meta.FindStatusCondition(r.status.Conditions, conditionType) != nil {
+		meta.RemoveStatusCondition(&r.status.Conditions, conditionType)
 		return true
 	}
 	return false
 }
 
+func (r *DynatraceClientReconciler) recordEvent(eventType, reason, messageFmt string, args ...interface{}) {
+	if r.Recorder != nil {
+		r.Recorder.Eventf(r.instance, eventType, reason, messageFmt, args...)
+	}
+}
+
 func (r *DynatraceClientReconciler) getSecret(ctx context.Context, instance *dynatracev1beta1.DynaKube) (*corev1.Secret, error) {
 	secretName := instance.Tokens()
 	ns := instance.GetNamespace()
@@ -278,6 +466,15 @@ func (r *DynatraceClientReconciler) setAndLogCondition(conditions *[]metav1.Cond
 	return true
 }
 
+func appendMissingScopes(scopes []string, additionalScopes ...string) []string {
+	for _, additionalScope := range additionalScopes {
+		if !dtclient.TokenScopes(scopes).Contains(additionalScope) {
+			scopes = append(scopes, additionalScope)
+		}
+	}
+	return scopes
+}
+
 func convertProxy(proxy *dynatracev1beta1.DynaKubeProxy) *DynatraceClientProxy {
 	if proxy == nil {
 		return nil
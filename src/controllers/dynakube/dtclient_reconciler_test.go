@@ -8,6 +8,7 @@ import (
 
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
 	"github.com/Dynatrace/dynatrace-operator/src/dtclient"
+	"github.com/Dynatrace/dynatrace-operator/src/kubeobjects"
 	"github.com/Dynatrace/dynatrace-operator/src/scheme/fake"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -15,6 +16,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 )
 
 func TestReconcileDynatraceClient_TokenValidation(t *testing.T) {
@@ -82,10 +84,10 @@ func TestReconcileDynatraceClient_TokenValidation(t *testing.T) {
 		dk := base.DeepCopy()
 		c := fake.NewClient(NewSecret(dynaKube, namespace, map[string]string{dtclient.DynatraceApiToken: "84"}))
 		dtcMock := &dtclient.MockDynatraceClient{}
-		dtcMock.On("GetTokenScopes", "84").Return(
-			dtclient.TokenScopes{dtclient.TokenScopeDataExport,
+		dtcMock.On("GetTokenInfo", "84").Return(
+			&dtclient.TokenInfo{Scopes: dtclient.TokenScopes{dtclient.TokenScopeDataExport,
 				dtclient.TokenScopeInstallerDownload,
-			}, nil)
+			}}, nil)
 
 		rec := &DynatraceClientReconciler{
 			Client:              c,
@@ -111,8 +113,8 @@ func TestReconcileDynatraceClient_TokenValidation(t *testing.T) {
 		c := fake.NewClient(NewSecret(dynaKube, namespace, map[string]string{dtclient.DynatracePaasToken: "42", dtclient.DynatraceApiToken: "84"}))
 
 		dtcMock := &dtclient.MockDynatraceClient{}
-		dtcMock.On("GetTokenScopes", "42").Return(dtclient.TokenScopes(nil), dtclient.ServerError{Code: 401, Message: "Token Authentication failed"})
-		dtcMock.On("GetTokenScopes", "84").Return(dtclient.TokenScopes(nil), fmt.Errorf("random error"))
+		dtcMock.On("GetTokenInfo", "42").Return((*dtclient.TokenInfo)(nil), dtclient.ServerError{Code: 401, Message: "Token Authentication failed"})
+		dtcMock.On("GetTokenInfo", "84").Return((*dtclient.TokenInfo)(nil), fmt.Errorf("random error"))
 
 		rec := &DynatraceClientReconciler{
 			Client:              c,
@@ -139,7 +141,7 @@ func TestReconcileDynatraceClient_TokenValidation(t *testing.T) {
 		c := fake.NewClient(NewSecret(dynaKube, namespace, map[string]string{dtclient.DynatracePaasToken: "42", dtclient.DynatraceApiToken: " \t84\n  "}))
 
 		dtcMock := &dtclient.MockDynatraceClient{}
-		dtcMock.On("GetTokenScopes", "42").Return(dtclient.TokenScopes{dtclient.TokenScopeDataExport}, nil)
+		dtcMock.On("GetTokenInfo", "42").Return(&dtclient.TokenInfo{Scopes: dtclient.TokenScopes{dtclient.TokenScopeDataExport}}, nil)
 
 		rec := &DynatraceClientReconciler{
 			Client:              c,
@@ -165,8 +167,8 @@ func TestReconcileDynatraceClient_TokenValidation(t *testing.T) {
 		c := fake.NewClient(NewSecret(dynaKube, namespace, map[string]string{dtclient.DynatracePaasToken: "42", dtclient.DynatraceApiToken: "84"}))
 
 		dtcMock := &dtclient.MockDynatraceClient{}
-		dtcMock.On("GetTokenScopes", "42").Return(dtclient.TokenScopes{dtclient.TokenScopeInstallerDownload}, nil)
-		dtcMock.On("GetTokenScopes", "84").Return(dtclient.TokenScopes{dtclient.TokenScopeDataExport}, nil)
+		dtcMock.On("GetTokenInfo", "42").Return(&dtclient.TokenInfo{Scopes: dtclient.TokenScopes{dtclient.TokenScopeInstallerDownload}}, nil)
+		dtcMock.On("GetTokenInfo", "84").Return(&dtclient.TokenInfo{Scopes: dtclient.TokenScopes{dtclient.TokenScopeDataExport}}, nil)
 
 		rec := &DynatraceClientReconciler{
 			Client:              c,
@@ -196,11 +198,11 @@ func TestReconcileDynatraceClient_TokenValidation(t *testing.T) {
 		c := fake.NewClient(NewSecret(dynaKube, namespace, map[string]string{dtclient.DynatracePaasToken: "42", dtclient.DynatraceApiToken: "84"}))
 
 		dtcMock := &dtclient.MockDynatraceClient{}
-		dtcMock.On("GetTokenScopes", "42").Return(dtclient.TokenScopes{dtclient.TokenScopeInstallerDownload}, nil)
-		dtcMock.On("GetTokenScopes", "84").Return(dtclient.TokenScopes{dtclient.TokenScopeDataExport,
+		dtcMock.On("GetTokenInfo", "42").Return(&dtclient.TokenInfo{Scopes: dtclient.TokenScopes{dtclient.TokenScopeInstallerDownload}}, nil)
+		dtcMock.On("GetTokenInfo", "84").Return(&dtclient.TokenInfo{Scopes: dtclient.TokenScopes{dtclient.TokenScopeDataExport,
 			dtclient.TokenScopeSettingsRead,
 			dtclient.TokenScopeSettingsWrite,
-		}, nil)
+		}}, nil)
 
 		rec := &DynatraceClientReconciler{
 			Client:              c,
@@ -229,10 +231,10 @@ func TestReconcileDynatraceClient_TokenValidation(t *testing.T) {
 		c := fake.NewClient(NewSecret(dynaKube, namespace, map[string]string{dtclient.DynatraceApiToken: "84", dtclient.DynatraceDataIngestToken: "69"}))
 
 		dtcMock := &dtclient.MockDynatraceClient{}
-		dtcMock.On("GetTokenScopes", "84").Return(dtclient.TokenScopes{dtclient.TokenScopeDataExport,
+		dtcMock.On("GetTokenInfo", "84").Return(&dtclient.TokenInfo{Scopes: dtclient.TokenScopes{dtclient.TokenScopeDataExport,
 			dtclient.TokenScopeInstallerDownload,
-		}, nil)
-		dtcMock.On("GetTokenScopes", "69").Return(dtclient.TokenScopes{dtclient.TokenScopeDataExport}, nil)
+		}}, nil)
+		dtcMock.On("GetTokenInfo", "69").Return(&dtclient.TokenInfo{Scopes: dtclient.TokenScopes{dtclient.TokenScopeDataExport}}, nil)
 
 		rec := &DynatraceClientReconciler{
 			Client:              c,
@@ -250,6 +252,32 @@ func TestReconcileDynatraceClient_TokenValidation(t *testing.T) {
 		AssertCondition(t, dk, dynatracev1beta1.DataIngestTokenConditionType, false, dynatracev1beta1.ReasonTokenScopeMissing, "Token on secret dynatrace:dynakube missing scopes [metrics.ingest]")
 		mock.AssertExpectationsForObjects(t, dtcMock)
 	})
+	t.Run("API token has missing scopes for settings objects", func(t *testing.T) {
+		dk := base.DeepCopy()
+		dk.Spec.Settings = []dynatracev1beta1.SettingsObjectSpec{{SchemaID: "builtin:test"}}
+		c := fake.NewClient(NewSecret(dynaKube, namespace, map[string]string{dtclient.DynatraceApiToken: "84"}))
+
+		dtcMock := &dtclient.MockDynatraceClient{}
+		dtcMock.On("GetTokenInfo", "84").Return(&dtclient.TokenInfo{Scopes: dtclient.TokenScopes{dtclient.TokenScopeDataExport,
+			dtclient.TokenScopeInstallerDownload,
+		}}, nil)
+
+		rec := &DynatraceClientReconciler{
+			Client:              c,
+			DynatraceClientFunc: StaticDynatraceClient(dtcMock),
+			Now:                 metav1.Now(),
+		}
+
+		dtc, ucr, err := rec.Reconcile(context.TODO(), dk)
+		assert.Equal(t, dtcMock, dtc)
+		assert.True(t, ucr)
+		assert.NoError(t, err)
+		assert.False(t, rec.ValidTokens)
+
+		AssertCondition(t, dk, dynatracev1beta1.APITokenConditionType, false, dynatracev1beta1.ReasonTokenScopeMissing,
+			"Token on secret dynatrace:dynakube missing scopes [settings.read, settings.write]")
+		mock.AssertExpectationsForObjects(t, dtcMock)
+	})
 }
 
 func TestReconcileDynatraceClient_ProbeRequests(t *testing.T) {
@@ -280,7 +308,12 @@ func TestReconcileDynatraceClient_ProbeRequests(t *testing.T) {
 		Message: "Ready",
 	})
 
-	c := fake.NewClient(NewSecret(dkName, namespace, map[string]string{dtclient.DynatracePaasToken: "42", dtclient.DynatraceApiToken: "84"}))
+	secret := NewSecret(dkName, namespace, map[string]string{dtclient.DynatracePaasToken: "42", dtclient.DynatraceApiToken: "84"})
+	secretHash, err := kubeobjects.GenerateHash(secret.Data)
+	require.NoError(t, err)
+	base.Status.TokenSecretHash = secretHash
+
+	c := fake.NewClient(secret)
 
 	t.Run("No request if last probe was recent", func(t *testing.T) {
 		lastAPIProbe := metav1.NewTime(now.Add(-3 * time.Minute))
@@ -320,8 +353,8 @@ func TestReconcileDynatraceClient_ProbeRequests(t *testing.T) {
 		dk.Status.LastPaaSTokenProbeTimestamp = &lastPaaSProbe
 
 		dtcMock := &dtclient.MockDynatraceClient{}
-		dtcMock.On("GetTokenScopes", "42").Return(dtclient.TokenScopes{dtclient.TokenScopeInstallerDownload}, nil)
-		dtcMock.On("GetTokenScopes", "84").Return(dtclient.TokenScopes{dtclient.TokenScopeDataExport}, nil)
+		dtcMock.On("GetTokenInfo", "42").Return(&dtclient.TokenInfo{Scopes: dtclient.TokenScopes{dtclient.TokenScopeInstallerDownload}}, nil)
+		dtcMock.On("GetTokenInfo", "84").Return(&dtclient.TokenInfo{Scopes: dtclient.TokenScopes{dtclient.TokenScopeDataExport}}, nil)
 
 		rec := &DynatraceClientReconciler{
 			Client:              c,
@@ -341,6 +374,159 @@ func TestReconcileDynatraceClient_ProbeRequests(t *testing.T) {
 		}
 		mock.AssertExpectationsForObjects(t, dtcMock)
 	})
+
+	t.Run("Make request if tokens secret changed since last probe", func(t *testing.T) {
+		lastAPIProbe := metav1.NewTime(now.Add(-3 * time.Minute))
+		lastPaaSProbe := metav1.NewTime(now.Add(-3 * time.Minute))
+
+		dk := base.DeepCopy()
+		dk.Status.LastAPITokenProbeTimestamp = &lastAPIProbe
+		dk.Status.LastPaaSTokenProbeTimestamp = &lastPaaSProbe
+		dk.Status.TokenSecretHash = "outdated"
+
+		dtcMock := &dtclient.MockDynatraceClient{}
+		dtcMock.On("GetTokenInfo", "42").Return(&dtclient.TokenInfo{Scopes: dtclient.TokenScopes{dtclient.TokenScopeInstallerDownload}}, nil)
+		dtcMock.On("GetTokenInfo", "84").Return(&dtclient.TokenInfo{Scopes: dtclient.TokenScopes{dtclient.TokenScopeDataExport}}, nil)
+		recorder := record.NewFakeRecorder(10)
+
+		rec := &DynatraceClientReconciler{
+			Client:              c,
+			DynatraceClientFunc: StaticDynatraceClient(dtcMock),
+			Recorder:            recorder,
+			Now:                 now,
+		}
+
+		_, ucr, err := rec.Reconcile(context.TODO(), dk)
+		assert.True(t, ucr)
+		assert.NoError(t, err)
+		assert.True(t, rec.ValidTokens)
+		assert.Equal(t, secretHash, dk.Status.TokenSecretHash)
+		if assert.NotNil(t, dk.Status.LastAPITokenProbeTimestamp) {
+			assert.Equal(t, *dk.Status.LastAPITokenProbeTimestamp, now)
+		}
+		require.Len(t, recorder.Events, 1)
+		assert.Equal(t, "Normal TokensRotated Tokens on secret dynatrace:dynakube changed, validating the new tokens", <-recorder.Events)
+		mock.AssertExpectationsForObjects(t, dtcMock)
+	})
+}
+
+func TestReconcileDynatraceClient_TokenExpiration(t *testing.T) {
+	now := metav1.Now()
+
+	namespace := "dynatrace"
+	dkName := "dynakube"
+	base := dynatracev1beta1.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{Name: dkName, Namespace: namespace},
+		Spec: dynatracev1beta1.DynaKubeSpec{
+			APIURL: "https://ENVIRONMENTID.live.dynatrace.com/api",
+			Tokens: dkName,
+		},
+	}
+	c := fake.NewClient(NewSecret(dkName, namespace, map[string]string{dtclient.DynatraceApiToken: "84"}))
+	scopes := dtclient.TokenScopes{dtclient.TokenScopeDataExport, dtclient.TokenScopeInstallerDownload}
+
+	t.Run("API token expires soon", func(t *testing.T) {
+		dk := base.DeepCopy()
+		expires := time.Date(now.Year()+1, 1, 1, 0, 0, 0, 0, time.UTC)
+		reconcileNow := metav1.NewTime(expires.Add(-24 * time.Hour))
+
+		dtcMock := &dtclient.MockDynatraceClient{}
+		dtcMock.On("GetTokenInfo", "84").Return(&dtclient.TokenInfo{Scopes: scopes, Expires: &expires}, nil)
+		recorder := record.NewFakeRecorder(10)
+
+		rec := &DynatraceClientReconciler{
+			Client:              c,
+			DynatraceClientFunc: StaticDynatraceClient(dtcMock),
+			Recorder:            recorder,
+			Now:                 reconcileNow,
+		}
+
+		_, ucr, err := rec.Reconcile(context.TODO(), dk)
+		assert.True(t, ucr)
+		assert.NoError(t, err)
+		assert.True(t, rec.ValidTokens)
+
+		message := fmt.Sprintf("Tokens on secret dynatrace:dynakube must be rotated soon: apiToken expires at %s", expires.Format(time.RFC3339))
+		AssertCondition(t, dk, dynatracev1beta1.APITokenConditionType, true, dynatracev1beta1.ReasonTokenReady, "Ready")
+		AssertCondition(t, dk, dynatracev1beta1.TokenExpiringConditionType, true, dynatracev1beta1.ReasonTokenExpiring, message)
+		require.Len(t, recorder.Events, 1)
+		assert.Equal(t, "Warning TokenExpiring "+message, <-recorder.Events)
+
+		// the condition is removed once the token has been rotated
+		dk.Status.LastAPITokenProbeTimestamp = nil
+		dtcMock = &dtclient.MockDynatraceClient{}
+		dtcMock.On("GetTokenInfo", "84").Return(&dtclient.TokenInfo{Scopes: scopes}, nil)
+		rec.DynatraceClientFunc = StaticDynatraceClient(dtcMock)
+
+		_, ucr, err = rec.Reconcile(context.TODO(), dk)
+		assert.True(t, ucr)
+		assert.NoError(t, err)
+		assert.Nil(t, meta.FindStatusCondition(dk.Status.Conditions, dynatracev1beta1.TokenExpiringConditionType))
+	})
+	t.Run("condition is kept while other tokens are probed", func(t *testing.T) {
+		dk := base.DeepCopy()
+		expires := time.Date(now.Year()+1, 1, 1, 0, 0, 0, 0, time.UTC)
+		reconcileNow := metav1.NewTime(expires.Add(-24 * time.Hour))
+		c := fake.NewClient(NewSecret(dkName, namespace, map[string]string{
+			dtclient.DynatraceApiToken:        "84",
+			dtclient.DynatraceDataIngestToken: "69",
+		}))
+
+		dtcMock := &dtclient.MockDynatraceClient{}
+		dtcMock.On("GetTokenInfo", "84").Return(&dtclient.TokenInfo{Scopes: scopes, Expires: &expires}, nil)
+		dtcMock.On("GetTokenInfo", "69").Return(&dtclient.TokenInfo{Scopes: dtclient.TokenScopes{dtclient.TokenScopeMetricsIngest}}, nil)
+		recorder := record.NewFakeRecorder(10)
+
+		rec := &DynatraceClientReconciler{
+			Client:              c,
+			DynatraceClientFunc: StaticDynatraceClient(dtcMock),
+			Recorder:            recorder,
+			Now:                 reconcileNow,
+		}
+
+		_, _, err := rec.Reconcile(context.TODO(), dk)
+		require.NoError(t, err)
+
+		message := fmt.Sprintf("Tokens on secret dynatrace:dynakube must be rotated soon: apiToken expires at %s", expires.Format(time.RFC3339))
+		AssertCondition(t, dk, dynatracev1beta1.TokenExpiringConditionType, true, dynatracev1beta1.ReasonTokenExpiring, message)
+		assert.Equal(t, map[string]metav1.Time{dtclient.DynatraceApiToken: metav1.NewTime(expires)}, dk.Status.TokenExpirations)
+		require.Len(t, recorder.Events, 1)
+		<-recorder.Events
+
+		// only the data ingest token is probed, the expiring API token isn't forgotten
+		dk.Status.LastDataIngestTokenProbeTimestamp = nil
+		_, _, err = rec.Reconcile(context.TODO(), dk)
+		require.NoError(t, err)
+
+		AssertCondition(t, dk, dynatracev1beta1.TokenExpiringConditionType, true, dynatracev1beta1.ReasonTokenExpiring, message)
+		assert.Empty(t, recorder.Events)
+		dtcMock.AssertNumberOfCalls(t, "GetTokenInfo", 3)
+	})
+	t.Run("API token expired", func(t *testing.T) {
+		dk := base.DeepCopy()
+		expires := now.Add(-time.Hour).UTC()
+
+		dtcMock := &dtclient.MockDynatraceClient{}
+		dtcMock.On("GetTokenInfo", "84").Return(&dtclient.TokenInfo{Scopes: scopes, Expires: &expires}, nil)
+		recorder := record.NewFakeRecorder(10)
+
+		rec := &DynatraceClientReconciler{
+			Client:              c,
+			DynatraceClientFunc: StaticDynatraceClient(dtcMock),
+			Recorder:            recorder,
+			Now:                 now,
+		}
+
+		_, ucr, err := rec.Reconcile(context.TODO(), dk)
+		assert.True(t, ucr)
+		assert.NoError(t, err)
+		assert.False(t, rec.ValidTokens)
+
+		message := fmt.Sprintf("Token apiToken on secret dynatrace:dynakube expired at %s", expires.Format(time.RFC3339))
+		AssertCondition(t, dk, dynatracev1beta1.APITokenConditionType, false, dynatracev1beta1.ReasonTokenExpired, message)
+		require.Len(t, recorder.Events, 1)
+		assert.Equal(t, "Warning TokenExpired "+message, <-recorder.Events)
+	})
 }
 
 func AssertCondition(t *testing.T, dk *dynatracev1beta1.DynaKube, ct string, status bool, reason string, message string) {
@@ -0,0 +1,23 @@
+package dtevents
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/src/logger"
+)
+
+const (
+	eventSource = "Dynatrace Operator"
+
+	propertySource            = "source"
+	propertyDynakube          = "dynakube"
+	propertyNamespace         = "namespace"
+	propertyKind              = "kind"
+	propertyName              = "name"
+	propertyPreviousVersion   = "previousVersion"
+	propertyDeploymentName    = "dt.event.deployment.name"
+	propertyDeploymentVersion = "dt.event.deployment.version"
+	propertyOperatorVersion   = "operatorVersion"
+)
+
+var (
+	log = logger.NewDTLogger().WithName("dynakube-dtevents")
+)
@@ -0,0 +1,82 @@
+package dtevents
+
+import (
+	"context"
+	"fmt"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	"github.com/Dynatrace/dynatrace-operator/src/dtclient"
+	"github.com/Dynatrace/dynatrace-operator/src/version"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// Sender posts deployment events about changes done by the operator to the Kubernetes cluster entity in Dynatrace.
+// Events are best effort, failures are logged but never fail the reconciliation.
+type Sender struct {
+	dtc      dtclient.Client
+	dynakube *dynatracev1beta1.DynaKube
+}
+
+func NewSender(dtc dtclient.Client, dynakube *dynatracev1beta1.DynaKube) *Sender {
+	return &Sender{
+		dtc:      dtc,
+		dynakube: dynakube,
+	}
+}
+
+// SendRollout reports that the given workload (e.g. the OneAgent DaemonSet) was created or its template changed.
+func (sender *Sender) SendRollout(ctx context.Context, kind, name, deployedVersion string) {
+	properties := map[string]string{
+		propertyKind:              kind,
+		propertyName:              name,
+		propertyDeploymentName:    name,
+		propertyDeploymentVersion: deployedVersion,
+	}
+	sender.send(ctx, fmt.Sprintf("Dynatrace Operator rolled out %s %s", kind, name), properties)
+}
+
+// SendVersionChange reports that the version of the given component changed, an empty previous version is not reported
+// as that is the initial deployment.
+func (sender *Sender) SendVersionChange(ctx context.Context, component, previousVersion, currentVersion string) {
+	if previousVersion == "" || previousVersion == currentVersion {
+		return
+	}
+	properties := map[string]string{
+		propertyDeploymentName:    component,
+		propertyDeploymentVersion: currentVersion,
+		propertyPreviousVersion:   previousVersion,
+	}
+	sender.send(ctx, fmt.Sprintf("Dynatrace Operator updated %s from %s to %s", component, previousVersion, currentVersion), properties)
+}
+
+func (sender *Sender) send(ctx context.Context, title string, properties map[string]string) {
+	if sender.dtc == nil || sender.dynakube.FeatureDisableDynatraceEvents() {
+		return
+	}
+	// the API token has no events.ingest scope, the condition already reports that
+	if meta.IsStatusConditionFalse(sender.dynakube.Status.Conditions, dynatracev1beta1.DynatraceEventsConditionType) {
+		return
+	}
+	kubeSystemUUID := sender.dynakube.Status.KubeSystemUUID
+	if kubeSystemUUID == "" {
+		log.Info("kube-system UUID unknown, not sending event", "title", title)
+		return
+	}
+
+	properties[propertySource] = eventSource
+	properties[propertyDynakube] = sender.dynakube.Name
+	properties[propertyNamespace] = sender.dynakube.Namespace
+	properties[propertyOperatorVersion] = version.Version
+
+	err := sender.dtc.SendEventV2(ctx, &dtclient.EventDataV2{
+		EventType:      dtclient.CustomDeploymentEvent,
+		Title:          title,
+		EntitySelector: dtclient.KubernetesClusterEntitySelector(kubeSystemUUID),
+		Properties:     properties,
+	})
+	if err != nil {
+		log.Info("failed to send event to Dynatrace", "title", title, "error", err.Error())
+		return
+	}
+	log.Info("sent event to Dynatrace", "title", title)
+}
@@ -0,0 +1,88 @@
+package dtevents
+
+import (
+	"context"
+	"testing"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	"github.com/Dynatrace/dynatrace-operator/src/dtclient"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	testName           = "test-dynakube"
+	testNamespace      = "test-namespace"
+	testKubeSystemUUID = "test-uuid"
+)
+
+func newTestDynakube() *dynatracev1beta1.DynaKube {
+	dynakube := &dynatracev1beta1.DynaKube{}
+	dynakube.Name = testName
+	dynakube.Namespace = testNamespace
+	dynakube.Status.KubeSystemUUID = testKubeSystemUUID
+	return dynakube
+}
+
+func TestSendVersionChange(t *testing.T) {
+	t.Run(`sends deployment event to the cluster entity`, func(t *testing.T) {
+		dtc := &dtclient.MockDynatraceClient{}
+		dtc.On("SendEventV2", mock.AnythingOfType("*dtclient.EventDataV2")).Return(nil)
+
+		NewSender(dtc, newTestDynakube()).SendVersionChange(context.TODO(), "OneAgent", "1.0.0", "1.1.0")
+
+		dtc.AssertNumberOfCalls(t, "SendEventV2", 1)
+		event := dtc.Calls[0].Arguments.Get(0).(*dtclient.EventDataV2)
+		assert.Equal(t, dtclient.CustomDeploymentEvent, event.EventType)
+		assert.Equal(t, "Dynatrace Operator updated OneAgent from 1.0.0 to 1.1.0", event.Title)
+		assert.Equal(t, dtclient.KubernetesClusterEntitySelector(testKubeSystemUUID), event.EntitySelector)
+		assert.Equal(t, "1.1.0", event.Properties[propertyDeploymentVersion])
+		assert.Equal(t, "1.0.0", event.Properties[propertyPreviousVersion])
+		assert.Equal(t, testName, event.Properties[propertyDynakube])
+		assert.Equal(t, testNamespace, event.Properties[propertyNamespace])
+	})
+	t.Run(`ignores initial and unchanged versions`, func(t *testing.T) {
+		dtc := &dtclient.MockDynatraceClient{}
+		sender := NewSender(dtc, newTestDynakube())
+
+		sender.SendVersionChange(context.TODO(), "OneAgent", "", "1.1.0")
+		sender.SendVersionChange(context.TODO(), "OneAgent", "1.1.0", "1.1.0")
+
+		dtc.AssertNotCalled(t, "SendEventV2", mock.Anything)
+	})
+}
+
+func TestSendRollout(t *testing.T) {
+	t.Run(`failures do not panic`, func(t *testing.T) {
+		dtc := &dtclient.MockDynatraceClient{}
+		dtc.On("SendEventV2", mock.AnythingOfType("*dtclient.EventDataV2")).Return(errors.New("missing scope"))
+
+		NewSender(dtc, newTestDynakube()).SendRollout(context.TODO(), "DaemonSet", "test-oneagent", "1.0.0")
+
+		dtc.AssertNumberOfCalls(t, "SendEventV2", 1)
+	})
+	t.Run(`skipped without kube-system uuid`, func(t *testing.T) {
+		dtc := &dtclient.MockDynatraceClient{}
+		dynakube := newTestDynakube()
+		dynakube.Status.KubeSystemUUID = ""
+
+		NewSender(dtc, dynakube).SendRollout(context.TODO(), "DaemonSet", "test-oneagent", "1.0.0")
+
+		dtc.AssertNotCalled(t, "SendEventV2", mock.Anything)
+	})
+	t.Run(`skipped without events.ingest scope`, func(t *testing.T) {
+		dtc := &dtclient.MockDynatraceClient{}
+		dynakube := newTestDynakube()
+		dynakube.Status.Conditions = []metav1.Condition{{
+			Type:   dynatracev1beta1.DynatraceEventsConditionType,
+			Status: metav1.ConditionFalse,
+			Reason: dynatracev1beta1.ReasonTokenScopeMissing,
+		}}
+
+		NewSender(dtc, dynakube).SendRollout(context.TODO(), "DaemonSet", "test-oneagent", "1.0.0")
+
+		dtc.AssertNotCalled(t, "SendEventV2", mock.Anything)
+	})
+}
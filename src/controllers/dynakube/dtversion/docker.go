@@ -17,6 +17,10 @@ const (
 	VersionLabel = "com.dynatrace.build-version"
 	TmpCAPath    = "/tmp/dynatrace-operator"
 	TmpCAName    = "dynatraceCustomCA.crt"
+
+	// The registry client picks up *.cert and *.key files with the same base name in TmpCAPath as client certificate
+	TmpClientCertName = "dynatraceClient.cert"
+	TmpClientKeyName  = "dynatraceClient.key"
 )
 
 // ImageVersion includes information for a given image. Version can be empty if the corresponding label isn't set.
@@ -88,7 +92,7 @@ func MakeSystemContext(dockerReference reference.Named, dockerConfig *DockerConf
 	if dockerConfig.SkipCertCheck {
 		ctx.DockerInsecureSkipTLSVerify = types.OptionalBoolTrue
 	}
-	if dockerConfig.UseTrustedCerts {
+	if dockerConfig.UseTrustedCerts || dockerConfig.UseClientCertificate {
 		ctx.DockerCertPath = TmpCAPath
 	}
 
@@ -9,9 +9,10 @@ import (
 )
 
 type DockerConfig struct {
-	Auths           map[string]DockerAuth
-	SkipCertCheck   bool
-	UseTrustedCerts bool
+	Auths                map[string]DockerAuth
+	SkipCertCheck        bool
+	UseTrustedCerts      bool
+	UseClientCertificate bool
 }
 
 type DockerAuth struct {
@@ -33,6 +33,16 @@ func TestMakeSystemContext(t *testing.T) {
 		assert.Equal(t, testName, systemContext.DockerAuthConfig.Username)
 		assert.Equal(t, testString, systemContext.DockerAuthConfig.Password)
 	})
+	t.Run(`MakeSystemContext uses cert path for trusted CAs and client certificate`, func(t *testing.T) {
+		systemContext := MakeSystemContext(&mockDockerReference{}, &DockerConfig{})
+		assert.Empty(t, systemContext.DockerCertPath)
+
+		systemContext = MakeSystemContext(&mockDockerReference{}, &DockerConfig{UseTrustedCerts: true})
+		assert.Equal(t, TmpCAPath, systemContext.DockerCertPath)
+
+		systemContext = MakeSystemContext(&mockDockerReference{}, &DockerConfig{UseClientCertificate: true})
+		assert.Equal(t, TmpCAPath, systemContext.DockerCertPath)
+	})
 }
 
 type mockDockerReference struct{}
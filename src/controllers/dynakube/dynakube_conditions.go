@@ -0,0 +1,221 @@
+package dynakube
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	"github.com/Dynatrace/dynatrace-operator/src/controllers/activegate/capability"
+	dtcsi "github.com/Dynatrace/dynatrace-operator/src/controllers/csi"
+	"github.com/Dynatrace/dynatrace-operator/src/webhook"
+	appsv1 "k8s.io/api/apps/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// updateComponentConditions sets the conditions of the components needed by the DynaKube from the rollout status
+// of their workloads, and removes the conditions of components which are not needed anymore.
+func (controller *DynakubeController) updateComponentConditions(ctx context.Context, dynakube *dynatracev1beta1.DynaKube) bool {
+	var conditions []metav1.Condition
+
+	if dynakube.NeedsOneAgent() {
+		conditions = append(conditions, controller.oneAgentCondition(ctx, dynakube))
+	}
+
+	if dynakube.NeedsActiveGate() {
+		conditions = append(conditions, controller.activeGateConditions(ctx, dynakube)...)
+	}
+
+	if dynakube.NeedAppInjection() {
+		conditions = append(conditions, controller.codeModulesCondition(ctx, dynakube),
+			controller.workloadCondition(ctx, dynakube.Namespace,
+				dynatracev1beta1.WebhookReachableConditionType, &appsv1.Deployment{}, webhook.DeploymentName))
+	}
+
+	updated := removeStaleComponentConditions(&dynakube.Status, conditions)
+	for _, condition := range conditions {
+		updated = setCondition(dynakube, condition) || updated
+	}
+	return updated
+}
+
+// oneAgentCondition combines the rollout status of the default OneAgent DaemonSet and the ones of the node groups,
+// the first DaemonSet which is not ready determines the condition
+func (controller *DynakubeController) oneAgentCondition(ctx context.Context, dynakube *dynatracev1beta1.DynaKube) metav1.Condition {
+	var messages []string
+	for _, name := range dynakube.OneAgentDaemonsetNames() {
+		condition := controller.workloadCondition(ctx, dynakube.Namespace,
+			dynatracev1beta1.OneAgentReadyConditionType, &appsv1.DaemonSet{}, name)
+		if condition.Status != metav1.ConditionTrue {
+			return condition
+		}
+		messages = append(messages, condition.Message)
+	}
+
+	return metav1.Condition{
+		Type:    dynatracev1beta1.OneAgentReadyConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  dynatracev1beta1.ReasonRolloutComplete,
+		Message: strings.Join(messages, ", "),
+	}
+}
+
+// activeGateConditions returns one condition for each capability, with the rollout status of the StatefulSet providing it
+func (controller *DynakubeController) activeGateConditions(ctx context.Context, dynakube *dynatracev1beta1.DynaKube) []metav1.Condition {
+	var conditions []metav1.Condition
+
+	for _, activeGateCapability := range generateActiveGateCapabilities(dynakube) {
+		if !activeGateCapability.Enabled() {
+			continue
+		}
+		statefulSetName := capability.CalculateStatefulSetName(activeGateCapability, dynakube.Name)
+		condition := controller.workloadCondition(ctx, dynakube.Namespace, "", &appsv1.StatefulSet{}, statefulSetName)
+
+		for _, displayName := range providedCapabilities(dynakube, activeGateCapability) {
+			condition.Type = dynatracev1beta1.ActiveGateReadyConditionType(displayName)
+			conditions = append(conditions, condition)
+		}
+	}
+	return conditions
+}
+
+func providedCapabilities(dynakube *dynatracev1beta1.DynaKube, activeGateCapability capability.Capability) []dynatracev1beta1.CapabilityDisplayName {
+	switch activeGateCapability.(type) {
+	case *capability.KubeMonCapability:
+		return []dynatracev1beta1.CapabilityDisplayName{dynatracev1beta1.KubeMonCapability.DisplayName}
+	case *capability.RoutingCapability:
+		return []dynatracev1beta1.CapabilityDisplayName{dynatracev1beta1.RoutingCapability.DisplayName}
+	default:
+		return dynakube.Spec.ActiveGate.Capabilities
+	}
+}
+
+// codeModulesCondition checks the CSI driver providing the code modules, if it is not used the pods download them on their own
+func (controller *DynakubeController) codeModulesCondition(ctx context.Context, dynakube *dynatracev1beta1.DynaKube) metav1.Condition {
+	if !dynakube.NeedsCSIDriver() {
+		return metav1.Condition{
+			Type:    dynatracev1beta1.CodeModulesAvailableConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  dynatracev1beta1.ReasonInstallerDownload,
+			Message: "Code modules are downloaded by the init container of injected pods",
+		}
+	}
+	return controller.workloadCondition(ctx, dynakube.Namespace,
+		dynatracev1beta1.CodeModulesAvailableConditionType, &appsv1.DaemonSet{}, dtcsi.DaemonSetName)
+}
+
+func (controller *DynakubeController) workloadCondition(ctx context.Context, namespace, conditionType string, workload client.Object, name string) metav1.Condition {
+	condition := metav1.Condition{
+		Type:   conditionType,
+		Status: metav1.ConditionFalse,
+	}
+
+	err := controller.client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, workload)
+	if k8serrors.IsNotFound(err) {
+		condition.Reason = dynatracev1beta1.ReasonWorkloadNotFound
+		condition.Message = fmt.Sprintf("%s not found", name)
+		return condition
+	} else if err != nil {
+		condition.Reason = dynatracev1beta1.ReasonWorkloadError
+		condition.Message = fmt.Sprintf("%s could not be queried: %s", name, err.Error())
+		return condition
+	}
+
+	ready, message := rolloutStatus(workload)
+	condition.Reason = dynatracev1beta1.ReasonRolloutInProgress
+	condition.Message = name + ": " + message
+	if ready {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = dynatracev1beta1.ReasonRolloutComplete
+	}
+	return condition
+}
+
+// rolloutStatus is similar to "kubectl rollout status", a workload is ready when the current generation is rolled out
+// and all pods are updated and available
+func rolloutStatus(workload client.Object) (bool, string) {
+	var observedGeneration int64
+	var desired, updated, available int32
+
+	switch typed := workload.(type) {
+	case *appsv1.DaemonSet:
+		observedGeneration = typed.Status.ObservedGeneration
+		desired = typed.Status.DesiredNumberScheduled
+		updated = typed.Status.UpdatedNumberScheduled
+		available = typed.Status.NumberAvailable
+	case *appsv1.StatefulSet:
+		observedGeneration = typed.Status.ObservedGeneration
+		desired = 1
+		if typed.Spec.Replicas != nil {
+			desired = *typed.Spec.Replicas
+		}
+		updated = typed.Status.UpdatedReplicas
+		available = typed.Status.ReadyReplicas
+	case *appsv1.Deployment:
+		observedGeneration = typed.Status.ObservedGeneration
+		desired = 1
+		if typed.Spec.Replicas != nil {
+			desired = *typed.Spec.Replicas
+		}
+		updated = typed.Status.UpdatedReplicas
+		available = typed.Status.AvailableReplicas
+	}
+
+	switch {
+	case observedGeneration < workload.GetGeneration():
+		return false, "waiting for the rollout to start"
+	case updated < desired:
+		return false, fmt.Sprintf("%d of %d pods updated", updated, desired)
+	case available < desired:
+		return false, fmt.Sprintf("%d of %d pods available", available, desired)
+	}
+	return true, fmt.Sprintf("%d of %d pods available", available, desired)
+}
+
+func removeStaleComponentConditions(status *dynatracev1beta1.DynaKubeStatus, conditions []metav1.Condition) bool {
+	updated := false
+	for _, existing := range append([]metav1.Condition{}, status.Conditions...) {
+		if !isComponentConditionType(existing.Type) || meta.FindStatusCondition(conditions, existing.Type) != nil {
+			continue
+		}
+		meta.RemoveStatusCondition(&status.Conditions, existing.Type)
+		updated = true
+	}
+	return updated
+}
+
+func isComponentConditionType(conditionType string) bool {
+	switch conditionType {
+	case dynatracev1beta1.OneAgentReadyConditionType,
+		dynatracev1beta1.CodeModulesAvailableConditionType,
+		dynatracev1beta1.WebhookReachableConditionType:
+		return true
+	}
+	return dynatracev1beta1.IsActiveGateReadyConditionType(conditionType)
+}
+
+// setCondition sets the condition for the current generation of the DynaKube, returns true if it changed
+func setCondition(dynakube *dynatracev1beta1.DynaKube, condition metav1.Condition) bool {
+	condition.ObservedGeneration = dynakube.Generation
+
+	existing := meta.FindStatusCondition(dynakube.Status.Conditions, condition.Type)
+	if existing != nil && existing.Status == condition.Status && existing.Reason == condition.Reason &&
+		existing.Message == condition.Message && existing.ObservedGeneration == condition.ObservedGeneration {
+		return false
+	}
+
+	meta.SetStatusCondition(&dynakube.Status.Conditions, condition)
+	return true
+}
+
+func removeCondition(dynakube *dynatracev1beta1.DynaKube, conditionType string) bool {
+	if meta.FindStatusCondition(dynakube.Status.Conditions, conditionType) == nil {
+		return false
+	}
+	meta.RemoveStatusCondition(&dynakube.Status.Conditions, conditionType)
+	return true
+}
@@ -0,0 +1,172 @@
+package dynakube
+
+import (
+	"context"
+	"testing"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	"github.com/Dynatrace/dynatrace-operator/src/controllers/activegate/capability"
+	dtcsi "github.com/Dynatrace/dynatrace-operator/src/controllers/csi"
+	"github.com/Dynatrace/dynatrace-operator/src/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/src/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestUpdateComponentConditions(t *testing.T) {
+	newDaemonSet := func(name string, desired, updated, available int32) *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+			Status: appsv1.DaemonSetStatus{
+				DesiredNumberScheduled: desired,
+				UpdatedNumberScheduled: updated,
+				NumberAvailable:        available,
+			},
+		}
+	}
+	newController := func(objects ...client.Object) *DynakubeController {
+		return &DynakubeController{client: fake.NewClient(objects...)}
+	}
+	assertCondition := func(t *testing.T, dynakube *dynatracev1beta1.DynaKube, conditionType string, status metav1.ConditionStatus, reason string) {
+		condition := meta.FindStatusCondition(dynakube.Status.Conditions, conditionType)
+		require.NotNil(t, condition, conditionType)
+		assert.Equal(t, status, condition.Status, conditionType)
+		assert.Equal(t, reason, condition.Reason, conditionType)
+		assert.Equal(t, dynakube.Generation, condition.ObservedGeneration, conditionType)
+	}
+
+	t.Run(`oneagent daemonset rollout`, func(t *testing.T) {
+		dynakube := &dynatracev1beta1.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: testNamespace, Generation: 2},
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				OneAgent: dynatracev1beta1.OneAgentSpec{ClassicFullStack: &dynatracev1beta1.HostInjectSpec{}},
+			},
+		}
+
+		controller := newController(newDaemonSet(dynakube.OneAgentDaemonsetName(), 3, 2, 3))
+		assert.True(t, controller.updateComponentConditions(context.TODO(), dynakube))
+		assertCondition(t, dynakube, dynatracev1beta1.OneAgentReadyConditionType, metav1.ConditionFalse, dynatracev1beta1.ReasonRolloutInProgress)
+		assert.Equal(t, "test-name-oneagent: 2 of 3 pods updated",
+			meta.FindStatusCondition(dynakube.Status.Conditions, dynatracev1beta1.OneAgentReadyConditionType).Message)
+		assert.Nil(t, meta.FindStatusCondition(dynakube.Status.Conditions, dynatracev1beta1.WebhookReachableConditionType))
+
+		controller = newController(newDaemonSet(dynakube.OneAgentDaemonsetName(), 3, 3, 3))
+		assert.True(t, controller.updateComponentConditions(context.TODO(), dynakube))
+		assertCondition(t, dynakube, dynatracev1beta1.OneAgentReadyConditionType, metav1.ConditionTrue, dynatracev1beta1.ReasonRolloutComplete)
+		assert.False(t, controller.updateComponentConditions(context.TODO(), dynakube))
+	})
+	t.Run(`oneagent daemonsets of node groups`, func(t *testing.T) {
+		dynakube := &dynatracev1beta1.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: testNamespace},
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				OneAgent: dynatracev1beta1.OneAgentSpec{ClassicFullStack: &dynatracev1beta1.HostInjectSpec{
+					NodeGroups: []dynatracev1beta1.NodeGroupSpec{{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}}},
+				}},
+			},
+		}
+
+		controller := newController(newDaemonSet(dynakube.OneAgentDaemonsetName(), 3, 3, 3))
+		controller.updateComponentConditions(context.TODO(), dynakube)
+		assertCondition(t, dynakube, dynatracev1beta1.OneAgentReadyConditionType, metav1.ConditionFalse, dynatracev1beta1.ReasonWorkloadNotFound)
+
+		controller = newController(newDaemonSet(dynakube.OneAgentDaemonsetName(), 3, 3, 3),
+			newDaemonSet(dynakube.OneAgentNodeGroupDaemonsetName("gpu"), 2, 2, 2))
+		controller.updateComponentConditions(context.TODO(), dynakube)
+		assertCondition(t, dynakube, dynatracev1beta1.OneAgentReadyConditionType, metav1.ConditionTrue, dynatracev1beta1.ReasonRolloutComplete)
+		assert.Equal(t, "test-name-oneagent: 3 of 3 pods available, test-name-oneagent-gpu: 2 of 2 pods available",
+			meta.FindStatusCondition(dynakube.Status.Conditions, dynatracev1beta1.OneAgentReadyConditionType).Message)
+	})
+	t.Run(`injection components`, func(t *testing.T) {
+		dynakube := &dynatracev1beta1.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: testNamespace},
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				OneAgent: dynatracev1beta1.OneAgentSpec{CloudNativeFullStack: &dynatracev1beta1.CloudNativeFullStackSpec{}},
+			},
+		}
+		webhookDeployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: webhook.DeploymentName, Namespace: testNamespace},
+			Status:     appsv1.DeploymentStatus{UpdatedReplicas: 1, AvailableReplicas: 1},
+		}
+
+		controller := newController(webhookDeployment, newDaemonSet(dtcsi.DaemonSetName, 2, 2, 1))
+		assert.True(t, controller.updateComponentConditions(context.TODO(), dynakube))
+
+		assertCondition(t, dynakube, dynatracev1beta1.OneAgentReadyConditionType, metav1.ConditionFalse, dynatracev1beta1.ReasonWorkloadNotFound)
+		assertCondition(t, dynakube, dynatracev1beta1.CodeModulesAvailableConditionType, metav1.ConditionFalse, dynatracev1beta1.ReasonRolloutInProgress)
+		assertCondition(t, dynakube, dynatracev1beta1.WebhookReachableConditionType, metav1.ConditionTrue, dynatracev1beta1.ReasonRolloutComplete)
+	})
+	t.Run(`application monitoring without csi driver`, func(t *testing.T) {
+		dynakube := &dynatracev1beta1.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: testNamespace},
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				OneAgent: dynatracev1beta1.OneAgentSpec{ApplicationMonitoring: &dynatracev1beta1.ApplicationMonitoringSpec{}},
+			},
+		}
+
+		newController().updateComponentConditions(context.TODO(), dynakube)
+
+		assertCondition(t, dynakube, dynatracev1beta1.CodeModulesAvailableConditionType, metav1.ConditionTrue, dynatracev1beta1.ReasonInstallerDownload)
+		assert.Nil(t, meta.FindStatusCondition(dynakube.Status.Conditions, dynatracev1beta1.OneAgentReadyConditionType))
+	})
+	t.Run(`activegate condition per capability, stale conditions are removed`, func(t *testing.T) {
+		dynakube := &dynatracev1beta1.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: testNamespace},
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				ActiveGate: dynatracev1beta1.ActiveGateSpec{
+					Capabilities: []dynatracev1beta1.CapabilityDisplayName{
+						dynatracev1beta1.RoutingCapability.DisplayName,
+						dynatracev1beta1.MetricsIngestCapability.DisplayName,
+					},
+				},
+			},
+			Status: dynatracev1beta1.DynaKubeStatus{
+				Conditions: []metav1.Condition{
+					{Type: dynatracev1beta1.OneAgentReadyConditionType, Status: metav1.ConditionTrue},
+					{Type: dynatracev1beta1.ActiveGateReadyConditionType(dynatracev1beta1.KubeMonCapability.DisplayName), Status: metav1.ConditionTrue},
+					{Type: dynatracev1beta1.APITokenConditionType, Status: metav1.ConditionTrue},
+				},
+			},
+		}
+		replicas := int32(2)
+		statefulSet := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      capability.CalculateStatefulSetName(capability.NewMultiCapability(dynakube), testName),
+				Namespace: testNamespace,
+			},
+			Spec:   appsv1.StatefulSetSpec{Replicas: &replicas},
+			Status: appsv1.StatefulSetStatus{UpdatedReplicas: 2, ReadyReplicas: 2},
+		}
+
+		assert.True(t, newController(statefulSet).updateComponentConditions(context.TODO(), dynakube))
+
+		assertCondition(t, dynakube, "ActiveGateRoutingReady", metav1.ConditionTrue, dynatracev1beta1.ReasonRolloutComplete)
+		assertCondition(t, dynakube, "ActiveGateMetricsIngestReady", metav1.ConditionTrue, dynatracev1beta1.ReasonRolloutComplete)
+		assert.Nil(t, meta.FindStatusCondition(dynakube.Status.Conditions, "ActiveGateKubernetesMonitoringReady"))
+		assert.Nil(t, meta.FindStatusCondition(dynakube.Status.Conditions, dynatracev1beta1.OneAgentReadyConditionType))
+		assert.NotNil(t, meta.FindStatusCondition(dynakube.Status.Conditions, dynatracev1beta1.APITokenConditionType))
+	})
+}
+
+func TestRolloutStatus(t *testing.T) {
+	t.Run(`generation not observed yet`, func(t *testing.T) {
+		daemonSet := &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Status:     appsv1.DaemonSetStatus{ObservedGeneration: 1},
+		}
+
+		ready, message := rolloutStatus(daemonSet)
+
+		assert.False(t, ready)
+		assert.Equal(t, "waiting for the rollout to start", message)
+	})
+	t.Run(`statefulset replicas default to 1`, func(t *testing.T) {
+		ready, message := rolloutStatus(&appsv1.StatefulSet{Status: appsv1.StatefulSetStatus{UpdatedReplicas: 1}})
+
+		assert.False(t, ready)
+		assert.Equal(t, "0 of 1 pods available", message)
+	})
+}
@@ -13,28 +13,37 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/src/controllers/activegate/reconciler/automaticapimonitoring"
 	rcap "github.com/Dynatrace/dynatrace-operator/src/controllers/activegate/reconciler/capability"
 	"github.com/Dynatrace/dynatrace-operator/src/controllers/dynakube/activegate"
+	"github.com/Dynatrace/dynatrace-operator/src/controllers/dynakube/dtevents"
 	"github.com/Dynatrace/dynatrace-operator/src/controllers/dynakube/dtpullsecret"
 	"github.com/Dynatrace/dynatrace-operator/src/controllers/dynakube/dtversion"
 	"github.com/Dynatrace/dynatrace-operator/src/controllers/dynakube/istio"
+	"github.com/Dynatrace/dynatrace-operator/src/controllers/dynakube/maintenance"
 	"github.com/Dynatrace/dynatrace-operator/src/controllers/dynakube/oneagent"
 	"github.com/Dynatrace/dynatrace-operator/src/controllers/dynakube/oneagent/daemonset"
+	"github.com/Dynatrace/dynatrace-operator/src/controllers/dynakube/settings"
 	"github.com/Dynatrace/dynatrace-operator/src/controllers/dynakube/status"
 	"github.com/Dynatrace/dynatrace-operator/src/controllers/dynakube/updates"
 	"github.com/Dynatrace/dynatrace-operator/src/dtclient"
 	dtingestendpoint "github.com/Dynatrace/dynatrace-operator/src/ingestendpoint"
 	"github.com/Dynatrace/dynatrace-operator/src/initgeneration"
+	"github.com/Dynatrace/dynatrace-operator/src/kubeobjects"
 	"github.com/Dynatrace/dynatrace-operator/src/mapper"
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 const shortUpdateInterval = 30 * time.Second
@@ -53,6 +62,7 @@ func NewController(mgr manager.Manager) *DynakubeController {
 		config:            mgr.GetConfig(),
 		operatorPodName:   os.Getenv("POD_NAME"),
 		operatorNamespace: os.Getenv("POD_NAMESPACE"),
+		recorder:          mgr.GetEventRecorderFor("DynaKube"),
 	}
 }
 
@@ -61,9 +71,30 @@ func (controller *DynakubeController) SetupWithManager(mgr ctrl.Manager) error {
 		For(&dynatracev1beta1.DynaKube{}).
 		Owns(&appsv1.StatefulSet{}).
 		Owns(&appsv1.DaemonSet{}).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(controller.mapTokenSecretToDynaKubes)).
 		Complete(controller)
 }
 
+// mapTokenSecretToDynaKubes enqueues the DynaKubes using the given secret as tokens secret,
+// so rotated tokens are validated and propagated without waiting for the next periodic reconcile.
+func (controller *DynakubeController) mapTokenSecretToDynaKubes(secret client.Object) []reconcile.Request {
+	var dynakubeList dynatracev1beta1.DynaKubeList
+	if err := controller.client.List(context.TODO(), &dynakubeList, client.InNamespace(secret.GetNamespace())); err != nil {
+		log.Error(err, "failed to list DynaKubes for tokens secret", "secret", secret.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, dynakube := range dynakubeList.Items {
+		if dynakube.Tokens() == secret.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: dynakube.Name, Namespace: dynakube.Namespace},
+			})
+		}
+	}
+	return requests
+}
+
 func NewDynaKubeController(c client.Client, apiReader client.Reader, scheme *runtime.Scheme, dtcBuildFunc DynatraceClientFunc, config *rest.Config) *DynakubeController {
 	return &DynakubeController{
 		client:            c,
@@ -87,6 +118,7 @@ type DynakubeController struct {
 	config            *rest.Config
 	operatorPodName   string
 	operatorNamespace string
+	recorder          record.EventRecorder
 }
 
 type DynatraceClientFunc func(properties DynatraceClientProperties) (dtclient.Client, error)
@@ -117,6 +149,14 @@ func (controller *DynakubeController) Reconcile(ctx context.Context, request rec
 		// Error reading the object - requeue the request.
 		return reconcile.Result{}, err
 	}
+
+	if instance.DeletionTimestamp != nil {
+		return controller.cleanup(ctx, instance, &dkMapper)
+	}
+	if err := controller.addCleanupFinalizer(ctx, instance); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	dkState := status.NewDynakubeState(instance)
 	controller.reconcileDynaKube(ctx, dkState, &dkMapper)
 
@@ -147,13 +187,14 @@ func (controller *DynakubeController) Reconcile(ctx context.Context, request rec
 		}
 	}
 
-	return reconcile.Result{RequeueAfter: dkState.RequeueAfter}, nil
+	return reconcile.Result{RequeueAfter: requeueAfter(dkState)}, nil
 }
 
 func (controller *DynakubeController) reconcileDynaKube(ctx context.Context, dkState *status.DynakubeState, dkMapper *mapper.DynakubeMapper) {
 	dtcReconciler := DynatraceClientReconciler{
 		Client:              controller.client,
 		DynatraceClientFunc: controller.dtcBuildFunc,
+		Recorder:            controller.recorder,
 	}
 	dtc, upd, err := dtcReconciler.Reconcile(ctx, dkState.Instance)
 
@@ -169,24 +210,47 @@ func (controller *DynakubeController) reconcileDynaKube(ctx context.Context, dkS
 		return
 	}
 
-	err = status.SetDynakubeStatus(dkState.Instance, status.Options{
+	err = status.SetDynakubeStatus(ctx, dkState.Instance, status.Options{
 		Dtc:       dtc,
 		ApiClient: controller.apiReader,
+		Now:       dkState.Now,
 	})
 	if dkState.Error(err) {
 		log.Error(err, "could not set Dynakube status")
 		return
 	}
 
+	if dkState.Instance.Spec.Paused {
+		controller.reconcilePaused(ctx, dkState)
+		return
+	}
+	dkState.Update(removeCondition(dkState.Instance, dynatracev1beta1.PausedConditionType), "reconciliation resumed")
+
 	if dkState.Instance.Spec.EnableIstio {
 		if upd, err = istio.NewIstioReconciler(controller.config, controller.scheme).ReconcileIstio(dkState.Instance); err != nil {
 			// If there are errors log them, but move on.
 			log.Info("Istio: failed to reconcile objects", "error", err)
-		} else if upd {
-			dkState.Update(true, "Istio: objects updated")
-			dkState.RequeueAfter = shortUpdateInterval
-			return
+			dkState.Update(setCondition(dkState.Instance, metav1.Condition{
+				Type:    dynatracev1beta1.IstioConfiguredConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  dynatracev1beta1.ReasonIstioError,
+				Message: err.Error(),
+			}), "Istio: condition changed")
+		} else {
+			dkState.Update(setCondition(dkState.Instance, metav1.Condition{
+				Type:    dynatracev1beta1.IstioConfiguredConditionType,
+				Status:  metav1.ConditionTrue,
+				Reason:  dynatracev1beta1.ReasonIstioReconciled,
+				Message: "Istio objects allow access to the Dynatrace environment",
+			}), "Istio: condition changed")
+			if upd {
+				dkState.Update(true, "Istio: objects updated")
+				dkState.RequeueAfter = shortUpdateInterval
+				return
+			}
 		}
+	} else {
+		dkState.Update(removeCondition(dkState.Instance, dynatracev1beta1.IstioConfiguredConditionType), "Istio: condition removed")
 	}
 
 	err = dtpullsecret.
@@ -200,21 +264,39 @@ func (controller *DynakubeController) reconcileDynaKube(ctx context.Context, dkS
 	if !dkState.Instance.FeatureDisableActivegateRawImage() && dkState.Instance.NeedsActiveGate() {
 		err = activegate.
 			NewTenantSecretReconciler(controller.client, controller.apiReader, controller.scheme, dkState.Instance, dtcReconciler.ApiToken, dtc).
-			Reconcile()
+			Reconcile(ctx)
 		if dkState.Error(err) {
 			log.Error(err, "could not reconcile Dynatrace ActiveGate Tenant secrets")
 			return
 		}
 	}
 
+	controller.reconcileMaintenanceWindow(dkState)
+
+	eventSender := dtevents.NewSender(dtc, dkState.Instance)
+	previousOneAgentVersion := dkState.Instance.Status.OneAgent.Version
+	previousActiveGateVersion := dkState.Instance.Status.ActiveGate.Version
+
 	upd, err = updates.ReconcileVersions(ctx, dkState, controller.client, dtversion.GetImageVersion)
 	dkState.Update(upd, "Found updates")
 	dkState.Error(err)
 
+	eventSender.SendVersionChange(ctx, "OneAgent", previousOneAgentVersion, dkState.Instance.Status.OneAgent.Version)
+	eventSender.SendVersionChange(ctx, "ActiveGate", previousActiveGateVersion, dkState.Instance.Status.ActiveGate.Version)
+
 	if !controller.reconcileActiveGate(ctx, dkState, dtc) {
 		return
 	}
 
+	upd, err = settings.NewReconciler(dtc, dkState).Reconcile(ctx)
+	if err != nil {
+		log.Error(err, "could not sync settings objects")
+	}
+	dkState.Update(upd, "settings objects synced")
+
+	oneAgentDaemonSet := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: dkState.Instance.OneAgentDaemonsetName(), Namespace: dkState.Instance.Namespace}}
+	previousOneAgentTemplateHash := controller.getTemplateHash(ctx, oneAgentDaemonSet)
+
 	if dkState.Instance.HostMonitoringMode() {
 		upd, err = oneagent.NewOneAgentReconciler(
 			controller.client, controller.apiReader, controller.scheme, dkState.Instance, daemonset.DeploymentTypeHostMonitoring,
@@ -243,6 +325,10 @@ func (controller *DynakubeController) reconcileDynaKube(ctx context.Context, dkS
 		controller.removeOneAgentDaemonSet(dkState)
 	}
 
+	if dkState.Instance.NeedsOneAgent() {
+		controller.sendRolloutEventIfChanged(ctx, eventSender, oneAgentDaemonSet, previousOneAgentTemplateHash, dkState.Instance.Status.OneAgent.Version)
+	}
+
 	endpointSecretGenerator := dtingestendpoint.NewEndpointSecretGenerator(controller.client, controller.apiReader, dkState.Instance.Namespace)
 	if dkState.Instance.NeedAppInjection() {
 		if err := dkMapper.MapFromDynakube(); err != nil {
@@ -281,8 +367,79 @@ func (controller *DynakubeController) reconcileDynaKube(ctx context.Context, dkS
 		}
 	}
 
-	upd = controller.determineDynaKubePhase(dkState.Instance)
+	controller.updateObservedStatus(ctx, dkState)
+}
+
+// reconcilePaused only updates the status while the reconciliation is paused, nothing is changed in the cluster or the
+// Dynatrace environment
+func (controller *DynakubeController) reconcilePaused(ctx context.Context, dkState *status.DynakubeState) {
+	log.Info("reconciliation is paused", "dynakube", dkState.Instance.Name)
+	dkState.Update(setCondition(dkState.Instance, metav1.Condition{
+		Type:    dynatracev1beta1.PausedConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  dynatracev1beta1.ReasonReconciliationPaused,
+		Message: "The operator doesn't change any objects of the DynaKube until spec.paused is unset",
+	}), "reconciliation paused")
+
+	controller.reconcileMaintenanceWindow(dkState)
+	controller.updateObservedStatus(ctx, dkState)
+}
+
+// updateObservedStatus updates the phase and the component conditions from the deployed workloads
+func (controller *DynakubeController) updateObservedStatus(ctx context.Context, dkState *status.DynakubeState) {
+	upd := controller.determineDynaKubePhase(dkState.Instance)
 	dkState.Update(upd, "dynakube phase changed")
+
+	upd = controller.updateComponentConditions(ctx, dkState.Instance)
+	dkState.Update(upd, "component conditions changed")
+
+	if dkState.Instance.Status.ObservedGeneration != dkState.Instance.Generation {
+		dkState.Instance.Status.ObservedGeneration = dkState.Instance.Generation
+		dkState.Update(true, "observed generation changed")
+	}
+}
+
+// reconcileMaintenanceWindow updates the status of the maintenance windows, the updates themselves are postponed by the
+// reconcilers rolling them out
+func (controller *DynakubeController) reconcileMaintenanceWindow(dkState *status.DynakubeState) {
+	windows, err := maintenance.NewWindows(dkState.Instance)
+	if err != nil {
+		log.Error(err, "invalid maintenance windows, updates are postponed")
+	}
+
+	windowStatus := windows.Status(dkState.Now.Time)
+	if !equality.Semantic.DeepEqual(windowStatus, dkState.Instance.Status.MaintenanceWindow) {
+		dkState.Instance.Status.MaintenanceWindow = windowStatus
+		dkState.Update(true, "maintenance window status changed")
+	}
+}
+
+// requeueAfter returns the requeue interval, it is shortened while a staged OneAgent rollout is in progress, as the
+// controller doesn't watch the OneAgent pods
+func requeueAfter(dkState *status.DynakubeState) time.Duration {
+	requeue := requeueForMaintenanceWindow(dkState)
+	if dkState.Instance.Status.OneAgent.Rollout.InProgress() && shortUpdateInterval < requeue {
+		return shortUpdateInterval
+	}
+	return requeue
+}
+
+// requeueForMaintenanceWindow shortens the requeue interval, so postponed updates are rolled out as soon as the
+// maintenance window opens
+func requeueForMaintenanceWindow(dkState *status.DynakubeState) time.Duration {
+	windowStatus := dkState.Instance.Status.MaintenanceWindow
+	next := windowStatus.NextStart
+	if windowStatus.Active {
+		next = windowStatus.NextEnd
+	}
+	if next == nil {
+		return dkState.RequeueAfter
+	}
+
+	if untilNext := next.Sub(dkState.Now.Time); untilNext > 0 && untilNext < dkState.RequeueAfter {
+		return untilNext
+	}
+	return dkState.RequeueAfter
 }
 
 func updatePhaseIfChanged(instance *dynatracev1beta1.DynaKube, newPhase dynatracev1beta1.DynaKubePhaseType) bool {
@@ -298,6 +455,9 @@ func (controller *DynakubeController) removeOneAgentDaemonSet(dkState *status.Dy
 	if err := controller.ensureDeleted(&ds); dkState.Error(err) {
 		return
 	}
+	if err := oneagent.DeleteNodeGroupDaemonSets(context.TODO(), controller.client, dkState.Instance, nil); dkState.Error(err) {
+		return
+	}
 }
 
 func (controller *DynakubeController) ensureDeleted(obj client.Object) error {
@@ -311,7 +471,7 @@ func (controller *DynakubeController) reconcileActiveGate(ctx context.Context, d
 	if !controller.reconcileActiveGateProxySecret(ctx, dynakubeState) {
 		return false
 	}
-	return controller.reconcileActiveGateCapabilities(dynakubeState, dtc)
+	return controller.reconcileActiveGateCapabilities(ctx, dynakubeState, dtc)
 }
 
 func (controller *DynakubeController) reconcileActiveGateProxySecret(ctx context.Context, dynakubeState *status.DynakubeState) bool {
@@ -337,17 +497,27 @@ func generateActiveGateCapabilities(instance *dynatracev1beta1.DynaKube) []capab
 	}
 }
 
-func (controller *DynakubeController) reconcileActiveGateCapabilities(dynakubeState *status.DynakubeState, dtc dtclient.Client) bool {
+func (controller *DynakubeController) reconcileActiveGateCapabilities(ctx context.Context, dynakubeState *status.DynakubeState, dtc dtclient.Client) bool {
 	var caps = generateActiveGateCapabilities(dynakubeState.Instance)
 
 	for _, c := range caps {
 		if c.Enabled() {
+			sts := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      capability.CalculateStatefulSetName(c, dynakubeState.Instance.Name),
+					Namespace: dynakubeState.Instance.Namespace,
+				},
+			}
+			previousTemplateHash := controller.getTemplateHash(ctx, sts)
+
 			upd, err := rcap.NewReconciler(
 				c, controller.client, controller.apiReader, controller.scheme, dynakubeState.Instance).Reconcile()
 			if dynakubeState.Error(err) {
 				return false
 			}
 			dynakubeState.Update(upd, c.ShortName()+" reconciled")
+
+			controller.sendRolloutEventIfChanged(ctx, dtevents.NewSender(dtc, dynakubeState.Instance), sts, previousTemplateHash, dynakubeState.Instance.Status.ActiveGate.Version)
 			return true
 		} else {
 			sts := appsv1.StatefulSet{
@@ -379,7 +549,7 @@ func (controller *DynakubeController) reconcileActiveGateCapabilities(dynakubeSt
 		dynakubeState.Instance.FeatureAutomaticKubernetesApiMonitoring() &&
 		dynakubeState.Instance.KubernetesMonitoringMode() {
 		err := automaticapimonitoring.NewReconciler(dtc, dynakubeState.Instance.Name, dynakubeState.Instance.Status.KubeSystemUUID).
-			Reconcile()
+			Reconcile(ctx)
 		if err != nil {
 			log.Error(err, "could not create setting")
 		}
@@ -388,6 +558,30 @@ func (controller *DynakubeController) reconcileActiveGateCapabilities(dynakubeSt
 	return true
 }
 
+// getTemplateHash returns the template hash of the given DaemonSet or StatefulSet,
+// or an empty string if it does not exist (yet).
+func (controller *DynakubeController) getTemplateHash(ctx context.Context, workload client.Object) string {
+	current := workload.DeepCopyObject().(client.Object)
+	if err := controller.client.Get(ctx, client.ObjectKeyFromObject(workload), current); err != nil {
+		return ""
+	}
+	return current.GetAnnotations()[kubeobjects.AnnotationHash]
+}
+
+// sendRolloutEventIfChanged sends a deployment event to Dynatrace if the template of the workload changed during the reconciliation.
+func (controller *DynakubeController) sendRolloutEventIfChanged(ctx context.Context, eventSender *dtevents.Sender, workload client.Object, previousTemplateHash, deployedVersion string) {
+	currentTemplateHash := controller.getTemplateHash(ctx, workload)
+	if currentTemplateHash == "" || currentTemplateHash == previousTemplateHash {
+		return
+	}
+
+	kind := "DaemonSet"
+	if _, ok := workload.(*appsv1.StatefulSet); ok {
+		kind = "StatefulSet"
+	}
+	eventSender.SendRollout(ctx, kind, workload.GetName(), deployedVersion)
+}
+
 func (controller *DynakubeController) updateCR(ctx context.Context, instance *dynatracev1beta1.DynaKube) error {
 	instance.Status.UpdatedTimestamp = metav1.Now()
 	err := controller.client.Status().Update(ctx, instance)
@@ -14,12 +14,14 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/src/kubesystem"
 	"github.com/Dynatrace/dynatrace-operator/src/scheme"
 	"github.com/Dynatrace/dynatrace-operator/src/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/src/testing/fakedynatrace"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -55,8 +57,8 @@ func TestMonitoringModesDynakube_Reconcile(t *testing.T) {
 
 	for mode := range deploymentModes {
 		t.Run(fmt.Sprintf(`Reconcile dynakube with %s mode`, mode), func(t *testing.T) {
-			mockClient := createDTMockClient(dtclient.TokenScopes{dtclient.TokenScopeInstallerDownload},
-				dtclient.TokenScopes{dtclient.TokenScopeDataExport})
+			server := fakedynatrace.NewServer(fakedynatrace.Config{})
+			defer server.Close()
 
 			instance := &dynatracev1beta1.DynaKube{
 				ObjectMeta: metav1.ObjectMeta{
@@ -64,11 +66,10 @@ func TestMonitoringModesDynakube_Reconcile(t *testing.T) {
 					Namespace: testNamespace,
 				},
 				Spec: dynatracev1beta1.DynaKubeSpec{
-					APIURL:   testHost,
 					OneAgent: deploymentModes[mode],
 				},
 			}
-			controller := createFakeClientAndReconcile(mockClient, instance, testPaasToken, testAPIToken)
+			controller := createFakeClientAndReconcileWithTenant(server, instance)
 
 			result, err := controller.Reconcile(context.TODO(), reconcile.Request{
 				NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: testName},
@@ -84,6 +85,124 @@ func TestMonitoringModesDynakube_Reconcile(t *testing.T) {
 	}
 }
 
+func TestReconcile_SendsRolloutEvents(t *testing.T) {
+	newInstance := func(annotations map[string]string) *dynatracev1beta1.DynaKube {
+		return &dynatracev1beta1.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        testName,
+				Namespace:   testNamespace,
+				Annotations: annotations,
+			},
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				OneAgent: dynatracev1beta1.OneAgentSpec{HostMonitoring: &dynatracev1beta1.HostInjectSpec{}},
+			},
+		}
+	}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: testName}}
+
+	t.Run(`sends event once the OneAgent DaemonSet is rolled out`, func(t *testing.T) {
+		server := fakedynatrace.NewServer(fakedynatrace.Config{})
+		defer server.Close()
+		controller := createFakeClientAndReconcileWithTenant(server, newInstance(nil))
+
+		_, err := controller.Reconcile(context.TODO(), request)
+		require.NoError(t, err)
+		_, err = controller.Reconcile(context.TODO(), request)
+		require.NoError(t, err)
+
+		events := server.EventsV2()
+		require.Len(t, events, 1)
+		assert.Equal(t, dtclient.CustomDeploymentEvent, events[0].EventType)
+		assert.Equal(t, "DaemonSet", events[0].Properties["kind"])
+		assert.Equal(t, testName+"-oneagent", events[0].Properties["name"])
+		assert.Equal(t, dtclient.KubernetesClusterEntitySelector(testUID), events[0].EntitySelector)
+	})
+	t.Run(`sends no events without the events.ingest scope`, func(t *testing.T) {
+		server := fakedynatrace.NewServer(fakedynatrace.Config{
+			TokenScopes: map[string]dtclient.TokenScopes{
+				testAPIToken:  {dtclient.TokenScopeDataExport},
+				testPaasToken: {dtclient.TokenScopeInstallerDownload},
+			},
+			APIToken:  testAPIToken,
+			PaaSToken: testPaasToken,
+		})
+		defer server.Close()
+		instance := newInstance(nil)
+		controller := createFakeClientAndReconcileWithTenant(server, instance)
+
+		_, err := controller.Reconcile(context.TODO(), request)
+
+		require.NoError(t, err)
+		assert.Empty(t, server.EventsV2())
+		require.NoError(t, controller.client.Get(context.TODO(), request.NamespacedName, instance))
+		condition := meta.FindStatusCondition(instance.Status.Conditions, dynatracev1beta1.DynatraceEventsConditionType)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, dynatracev1beta1.ReasonTokenScopeMissing, condition.Reason)
+	})
+	t.Run(`sends no events if disabled`, func(t *testing.T) {
+		server := fakedynatrace.NewServer(fakedynatrace.Config{})
+		defer server.Close()
+		instance := newInstance(map[string]string{dynatracev1beta1.AnnotationFeatureDisableDynatraceEvents: "true"})
+		controller := createFakeClientAndReconcileWithTenant(server, instance)
+
+		_, err := controller.Reconcile(context.TODO(), request)
+		require.NoError(t, err)
+		_, err = controller.Reconcile(context.TODO(), request)
+		require.NoError(t, err)
+
+		assert.Empty(t, server.EventsV2())
+	})
+}
+
+func TestReconcile_Paused(t *testing.T) {
+	mockClient := createDTMockClient(dtclient.TokenScopes{dtclient.TokenScopeInstallerDownload},
+		dtclient.TokenScopes{dtclient.TokenScopeDataExport})
+	instance := &dynatracev1beta1.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testName,
+			Namespace: testNamespace,
+		},
+		Spec: dynatracev1beta1.DynaKubeSpec{
+			APIURL:   testHost,
+			OneAgent: dynatracev1beta1.OneAgentSpec{HostMonitoring: &dynatracev1beta1.HostInjectSpec{}},
+			Paused:   true,
+		},
+	}
+	controller := createFakeClientAndReconcile(mockClient, instance, testPaasToken, testAPIToken)
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: testName}}
+	daemonSetName := types.NamespacedName{Namespace: testNamespace, Name: testName + "-oneagent"}
+
+	t.Run(`nothing is deployed while paused`, func(t *testing.T) {
+		_, err := controller.Reconcile(context.TODO(), request)
+		require.NoError(t, err)
+
+		err = controller.client.Get(context.TODO(), daemonSetName, &appsv1.DaemonSet{})
+		assert.True(t, k8serrors.IsNotFound(err))
+		mockClient.AssertNotCalled(t, "CreateOrUpdateKubernetesSetting", mock.Anything, mock.Anything, mock.Anything)
+
+		var dynakube dynatracev1beta1.DynaKube
+		require.NoError(t, controller.client.Get(context.TODO(), request.NamespacedName, &dynakube))
+		condition := meta.FindStatusCondition(dynakube.Status.Conditions, dynatracev1beta1.PausedConditionType)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+		assert.Equal(t, dynatracev1beta1.ReasonReconciliationPaused, condition.Reason)
+	})
+	t.Run(`reconciliation continues once resumed`, func(t *testing.T) {
+		var dynakube dynatracev1beta1.DynaKube
+		require.NoError(t, controller.client.Get(context.TODO(), request.NamespacedName, &dynakube))
+		dynakube.Spec.Paused = false
+		require.NoError(t, controller.client.Update(context.TODO(), &dynakube))
+
+		_, err := controller.Reconcile(context.TODO(), request)
+		require.NoError(t, err)
+
+		assert.NoError(t, controller.client.Get(context.TODO(), daemonSetName, &appsv1.DaemonSet{}))
+		require.NoError(t, controller.client.Get(context.TODO(), request.NamespacedName, &dynakube))
+		assert.Nil(t, meta.FindStatusCondition(dynakube.Status.Conditions, dynatracev1beta1.PausedConditionType))
+	})
+}
+
 func TestReconcileActiveGate_Reconcile(t *testing.T) {
 	t.Run(`Reconcile works with minimal setup`, func(t *testing.T) {
 		controller := &DynakubeController{
@@ -231,6 +350,26 @@ func TestReconcileOnlyOneTokenProvided_Reconcile(t *testing.T) {
 	})
 }
 
+func TestMapTokenSecretToDynaKubes(t *testing.T) {
+	fakeClient := fake.NewClient(
+		&dynatracev1beta1.DynaKube{ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: testNamespace}},
+		&dynatracev1beta1.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{Name: "shared-tokens", Namespace: testNamespace},
+			Spec:       dynatracev1beta1.DynaKubeSpec{Tokens: testName},
+		},
+		&dynatracev1beta1.DynaKube{ObjectMeta: metav1.ObjectMeta{Name: "other-tokens", Namespace: testNamespace}},
+		&dynatracev1beta1.DynaKube{ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: "other-namespace"}},
+	)
+	controller := &DynakubeController{client: fakeClient}
+
+	requests := controller.mapTokenSecretToDynaKubes(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: testNamespace}})
+
+	assert.ElementsMatch(t, []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: testName, Namespace: testNamespace}},
+		{NamespacedName: types.NamespacedName{Name: "shared-tokens", Namespace: testNamespace}},
+	}, requests)
+}
+
 func TestRemoveOneAgentDaemonset(t *testing.T) {
 	t.Run(`Reconcile validates apiToken correctly if apiToken with "InstallerDownload"-scope is provided`, func(t *testing.T) {
 		mockClient := createDTMockClient(dtclient.TokenScopes{},
@@ -436,6 +575,7 @@ func TestReconcile_ActiveGateMultiCapability(t *testing.T) {
 func createDTMockClient(paasTokenScopes, apiTokenScopes dtclient.TokenScopes) *dtclient.MockDynatraceClient {
 	mockClient := &dtclient.MockDynatraceClient{}
 
+	mockClient.On("GetActiveURL").Return(testHost)
 	mockClient.On("GetCommunicationHostForClient").Return(dtclient.CommunicationHost{
 		Protocol: testProtocol,
 		Host:     testHost,
@@ -456,8 +596,8 @@ func createDTMockClient(paasTokenScopes, apiTokenScopes dtclient.TokenScopes) *d
 		},
 		TenantUUID: testUUID,
 	}, nil)
-	mockClient.On("GetTokenScopes", testPaasToken).Return(paasTokenScopes, nil)
-	mockClient.On("GetTokenScopes", testAPIToken).Return(apiTokenScopes, nil)
+	mockClient.On("GetTokenInfo", testPaasToken).Return(&dtclient.TokenInfo{Scopes: paasTokenScopes}, nil)
+	mockClient.On("GetTokenInfo", testAPIToken).Return(&dtclient.TokenInfo{Scopes: apiTokenScopes}, nil)
 	mockClient.On("GetConnectionInfo").Return(dtclient.ConnectionInfo{TenantUUID: "abc123456"}, nil)
 	mockClient.On("GetLatestAgentVersion", dtclient.OsUnix, dtclient.InstallerTypeDefault).Return(testVersion, nil)
 	mockClient.On("GetLatestAgentVersion", dtclient.OsUnix, dtclient.InstallerTypePaaS).Return(testVersion, nil)
@@ -469,6 +609,7 @@ func createDTMockClient(paasTokenScopes, apiTokenScopes dtclient.TokenScopes) *d
 		Return(testObjectID, nil)
 	mockClient.On("GetAgentTenantInfo").Return(&dtclient.AgentTenantInfo{}, nil)
 	mockClient.On("GetActiveGateTenantInfo").Return(&dtclient.ActiveGateTenantInfo{}, nil)
+	mockClient.On("SendEventV2", mock.AnythingOfType("*dtclient.EventDataV2")).Return(nil)
 
 	return mockClient
 }
@@ -509,6 +650,16 @@ func createFakeClientAndReconcile(mockClient dtclient.Client, instance *dynatrac
 	return controller
 }
 
+// createFakeClientAndReconcileWithTenant points the instance to the fake tenant, so the controller uses a real Dynatrace client
+func createFakeClientAndReconcileWithTenant(server *fakedynatrace.Server, instance *dynatracev1beta1.DynaKube) *DynakubeController {
+	config := server.TenantConfig()
+	instance.Spec.APIURL = server.APIURL()
+
+	controller := createFakeClientAndReconcile(nil, instance, config.PaaSToken, config.APIToken)
+	controller.dtcBuildFunc = BuildDynatraceClient
+	return controller
+}
+
 // generateStatefulSetForTesting prepares an ActiveGate StatefulSet after a Reconciliation of the Dynakube with a specific feature enabled
 func generateStatefulSetForTesting(name, namespace, feature, kubeSystemUUID string) *appsv1.StatefulSet {
 	labels := kubeobjects.CommonLabels(name, kubeobjects.ActiveGateComponentLabel)
@@ -47,14 +47,17 @@ func (controller *DynakubeController) determineDynaKubePhase(dynakube *dynatrace
 }
 
 func (controller *DynakubeController) numberOfMissingOneagentPods(dynakube *dynatracev1beta1.DynaKube) (int32, error) {
-	oneAgentDaemonSet := &appsv1.DaemonSet{}
-	instanceName := dynakube.OneAgentDaemonsetName()
-	err := controller.client.Get(context.TODO(), types.NamespacedName{Name: instanceName, Namespace: dynakube.Namespace}, oneAgentDaemonSet)
+	sum := int32(0)
+	for _, instanceName := range dynakube.OneAgentDaemonsetNames() {
+		oneAgentDaemonSet := &appsv1.DaemonSet{}
+		err := controller.client.Get(context.TODO(), types.NamespacedName{Name: instanceName, Namespace: dynakube.Namespace}, oneAgentDaemonSet)
 
-	if err != nil {
-		return 0, err
+		if err != nil {
+			return 0, err
+		}
+		sum += oneAgentDaemonSet.Status.CurrentNumberScheduled - oneAgentDaemonSet.Status.NumberReady
 	}
-	return oneAgentDaemonSet.Status.CurrentNumberScheduled - oneAgentDaemonSet.Status.NumberReady, nil
+	return sum, nil
 }
 
 func (controller *DynakubeController) numberOfMissingActiveGatePods(dynakube *dynatracev1beta1.DynaKube) (int32, error) {
@@ -42,10 +42,16 @@ func buildObjectMeta(name string, namespace string) metav1.ObjectMeta {
 }
 
 func buildIstioLabels(name, role string) map[string]string {
+	labels := buildInstanceIstioLabels(name)
+	labels["dynatrace-istio-role"] = role
+	return labels
+}
+
+// buildInstanceIstioLabels returns the labels the Istio objects of all roles of a DynaKube have in common
+func buildInstanceIstioLabels(name string) map[string]string {
 	return map[string]string{
-		"dynatrace":            "oneagent",
-		"oneagent":             name,
-		"dynatrace-istio-role": role,
+		"dynatrace": "oneagent",
+		"oneagent":  name,
 	}
 }
 
@@ -88,6 +88,37 @@ func (reconciler *IstioReconciler) ReconcileIstio(instance *dynatracev1beta1.Dyn
 	return false, nil
 }
 
+// RemoveIstioConfigurations removes the VirtualServices and ServiceEntries of all roles of the DynaKube,
+// used when the DynaKube is deleted
+func (reconciler *IstioReconciler) RemoveIstioConfigurations(instance *dynatracev1beta1.DynaKube) (removed bool, err error) {
+	enabled, err := CheckIstioEnabled(reconciler.config)
+	if err != nil {
+		return false, fmt.Errorf("istio: failed to verify Istio availability: %w", err)
+	}
+	if !enabled {
+		return false, nil
+	}
+
+	istioConfig := &istioConfiguration{
+		reconciler: reconciler,
+		listOps: &metav1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(buildInstanceIstioLabels(instance.GetName())).String(),
+		},
+		instance: instance,
+	}
+
+	vsUpd, err := removeIstioConfigurationForVirtualService(istioConfig, map[string]bool{})
+	if err != nil {
+		return false, err
+	}
+	seUpd, err := removeIstioConfigurationForServiceEntry(istioConfig, map[string]bool{})
+	if err != nil {
+		return false, err
+	}
+
+	return vsUpd || seUpd, nil
+}
+
 func (reconciler *IstioReconciler) reconcileIstioConfigurations(instance *dynatracev1beta1.DynaKube,
 	comHosts []dtclient.CommunicationHost, role string) (bool, error) {
 
@@ -96,6 +96,43 @@ func TestController_ReconcileIstio(t *testing.T) {
 	assert.False(t, updated)
 }
 
+func TestController_RemoveIstioConfigurations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(reconcileTestHandler))
+	defer server.Close()
+
+	instance := &dynatracev1beta1.DynaKube{ObjectMeta: metav1.ObjectMeta{Name: "dynakube", Namespace: DefaultTestNamespace}}
+	virtualService := buildVirtualService("dynakube-vs", DefaultTestNamespace, testVirtualServiceHost, testVirtualServiceProtocol, testVirtualServicePort)
+	virtualService.Labels = buildIstioLabels(instance.Name, "api-url")
+	serviceEntry := buildServiceEntry("dynakube-se", DefaultTestNamespace, testVirtualServiceHost, testVirtualServiceProtocol, testVirtualServicePort)
+	serviceEntry.Labels = buildIstioLabels(instance.Name, "communication-endpoint")
+	otherServiceEntry := buildServiceEntry("other-se", DefaultTestNamespace, testVirtualServiceHost, testVirtualServiceProtocol, testVirtualServicePort)
+	otherServiceEntry.Labels = buildIstioLabels("other", "communication-endpoint")
+
+	istioClient := fakeistio.NewSimpleClientset(virtualService, serviceEntry, otherServiceEntry)
+	reconciler := IstioReconciler{
+		istioClient: istioClient,
+		scheme:      scheme.Scheme,
+		config: &rest.Config{
+			Host:    server.URL,
+			APIPath: testApiPath,
+		},
+	}
+
+	removed, err := reconciler.RemoveIstioConfigurations(instance)
+
+	require.NoError(t, err)
+	assert.True(t, removed)
+
+	virtualServices, err := istioClient.NetworkingV1alpha3().VirtualServices(DefaultTestNamespace).List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, virtualServices.Items)
+
+	serviceEntries, err := istioClient.NetworkingV1alpha3().ServiceEntries(DefaultTestNamespace).List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, serviceEntries.Items, 1)
+	assert.Equal(t, "other-se", serviceEntries.Items[0].Name)
+}
+
 func reconcileTestHandler(w http.ResponseWriter, r *http.Request) {
 	if r.URL.Path == "/apis" {
 		sendApiGroupList(w)
@@ -0,0 +1,174 @@
+package maintenance
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSearchYears limits the search for the next matching time, e.g. "0 0 30 2 *" never matches
+const maxSearchYears = 5
+
+type field struct {
+	name     string
+	min, max uint
+}
+
+var (
+	minuteField     = field{name: "minute", min: 0, max: 59}
+	hourField       = field{name: "hour", min: 0, max: 23}
+	dayOfMonthField = field{name: "day of month", min: 1, max: 31}
+	monthField      = field{name: "month", min: 1, max: 12}
+	// dayOfWeekField allows 7 for Sunday like most cron implementations, it is mapped to 0
+	dayOfWeekField = field{name: "day of week", min: 0, max: 7}
+)
+
+// schedule is a parsed cron expression with the five standard fields "minute hour day-of-month month day-of-week",
+// every field is a bitset of the values it matches.
+type schedule struct {
+	minute, hour, dayOfMonth, month, dayOfWeek uint64
+
+	// like in cron, if both day fields are restricted a day matches if either of them matches
+	dayOfMonthRestricted, dayOfWeekRestricted bool
+}
+
+// parseSchedule parses a cron expression, every field supports "*", values, ranges "1-5", steps "*/15" or "1-5/2"
+// and lists of them "1,3,5-7"
+func parseSchedule(expression string) (*schedule, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields (minute hour day-of-month month day-of-week), got %d", len(fields))
+	}
+
+	var err error
+	parsed := &schedule{
+		dayOfMonthRestricted: fields[2] != "*",
+		dayOfWeekRestricted:  fields[4] != "*",
+	}
+
+	if parsed.minute, err = parseField(fields[0], minuteField); err != nil {
+		return nil, err
+	}
+	if parsed.hour, err = parseField(fields[1], hourField); err != nil {
+		return nil, err
+	}
+	if parsed.dayOfMonth, err = parseField(fields[2], dayOfMonthField); err != nil {
+		return nil, err
+	}
+	if parsed.month, err = parseField(fields[3], monthField); err != nil {
+		return nil, err
+	}
+	if parsed.dayOfWeek, err = parseField(fields[4], dayOfWeekField); err != nil {
+		return nil, err
+	}
+	if parsed.dayOfWeek&(1<<7) != 0 {
+		parsed.dayOfWeek |= 1
+	}
+	return parsed, nil
+}
+
+func parseField(expression string, f field) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(expression, ",") {
+		partBits, err := parseRange(part, f)
+		if err != nil {
+			return 0, err
+		}
+		bits |= partBits
+	}
+	return bits, nil
+}
+
+func parseRange(expression string, f field) (uint64, error) {
+	rangeExpression, stepExpression, hasStep := cut(expression, "/")
+	start, end := f.min, f.max
+	step := uint(1)
+
+	if rangeExpression != "*" {
+		startExpression, endExpression, isRange := cut(rangeExpression, "-")
+
+		var err error
+		if start, err = parseValue(startExpression, f); err != nil {
+			return 0, err
+		}
+		end = start
+		if isRange {
+			if end, err = parseValue(endExpression, f); err != nil {
+				return 0, err
+			}
+		} else if hasStep {
+			end = f.max
+		}
+		if start > end {
+			return 0, fmt.Errorf("invalid %s range %q", f.name, rangeExpression)
+		}
+	}
+
+	if hasStep {
+		parsedStep, err := strconv.ParseUint(stepExpression, 10, 8)
+		if err != nil || parsedStep == 0 {
+			return 0, fmt.Errorf("invalid %s step %q", f.name, stepExpression)
+		}
+		step = uint(parsedStep)
+	}
+
+	var bits uint64
+	for value := start; value <= end; value += step {
+		bits |= 1 << value
+	}
+	return bits, nil
+}
+
+func parseValue(expression string, f field) (uint, error) {
+	value, err := strconv.ParseUint(expression, 10, 8)
+	if err != nil || uint(value) < f.min || uint(value) > f.max {
+		return 0, fmt.Errorf("invalid %s %q, expected a value from %d to %d", f.name, expression, f.min, f.max)
+	}
+	return uint(value), nil
+}
+
+func cut(s, separator string) (string, string, bool) {
+	if i := strings.Index(s, separator); i >= 0 {
+		return s[:i], s[i+len(separator):], true
+	}
+	return s, "", false
+}
+
+// next returns the first time after t matching the schedule, in the location of t.
+// It returns the zero time if nothing matches within the next years.
+func (s *schedule) next(t time.Time) time.Time {
+	location := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	yearLimit := t.Year() + maxSearchYears
+
+	for t.Year() <= yearLimit {
+		switch {
+		case !matches(s.month, uint(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, location)
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, location)
+		case !matches(s.hour, uint(t.Hour())):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, location)
+		case !matches(s.minute, uint(t.Minute())):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s *schedule) matchesDay(t time.Time) bool {
+	dayOfMonth := matches(s.dayOfMonth, uint(t.Day()))
+	dayOfWeek := matches(s.dayOfWeek, uint(t.Weekday()))
+
+	if s.dayOfMonthRestricted && s.dayOfWeekRestricted {
+		return dayOfMonth || dayOfWeek
+	}
+	return dayOfMonth && dayOfWeek
+}
+
+func matches(bits uint64, value uint) bool {
+	return bits&(1<<value) != 0
+}
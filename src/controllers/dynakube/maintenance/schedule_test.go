@@ -0,0 +1,87 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule(t *testing.T) {
+	t.Run(`fields`, func(t *testing.T) {
+		parsed, err := parseSchedule("0,30 */6 1-10/3 * 7")
+
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1|1<<30), parsed.minute)
+		assert.Equal(t, uint64(1|1<<6|1<<12|1<<18), parsed.hour)
+		assert.Equal(t, uint64(1<<1|1<<4|1<<7|1<<10), parsed.dayOfMonth)
+		assert.Equal(t, uint64(0x1ffe), parsed.month)
+		assert.True(t, matches(parsed.dayOfWeek, uint(time.Sunday)))
+		assert.True(t, parsed.dayOfMonthRestricted)
+		assert.True(t, parsed.dayOfWeekRestricted)
+	})
+	t.Run(`invalid expressions`, func(t *testing.T) {
+		for _, expression := range []string{
+			"",
+			"* * * *",
+			"60 * * * *",
+			"* * 0 * *",
+			"* * * 13 *",
+			"* 5-1 * * *",
+			"*/0 * * * *",
+			"a * * * *",
+			"1,,2 * * * *",
+		} {
+			_, err := parseSchedule(expression)
+			assert.Error(t, err, expression)
+		}
+	})
+}
+
+func TestScheduleNext(t *testing.T) {
+	vienna, err := time.LoadLocation("Europe/Vienna")
+	require.NoError(t, err)
+
+	next := func(t *testing.T, expression string, after time.Time) time.Time {
+		parsed, err := parseSchedule(expression)
+		require.NoError(t, err)
+		return parsed.next(after)
+	}
+
+	t.Run(`next minute`, func(t *testing.T) {
+		after := time.Date(2022, 3, 14, 10, 15, 30, 0, time.UTC)
+		assert.Equal(t, time.Date(2022, 3, 14, 10, 16, 0, 0, time.UTC), next(t, "* * * * *", after))
+	})
+	t.Run(`strictly after`, func(t *testing.T) {
+		after := time.Date(2022, 3, 14, 22, 0, 0, 0, time.UTC)
+		assert.Equal(t, time.Date(2022, 3, 15, 22, 0, 0, 0, time.UTC), next(t, "0 22 * * *", after))
+	})
+	t.Run(`day of week`, func(t *testing.T) {
+		// 2022-03-14 is a Monday
+		after := time.Date(2022, 3, 14, 10, 0, 0, 0, time.UTC)
+		assert.Equal(t, time.Date(2022, 3, 19, 22, 0, 0, 0, time.UTC), next(t, "0 22 * * 6", after))
+	})
+	t.Run(`day of month or day of week if both are restricted`, func(t *testing.T) {
+		after := time.Date(2022, 3, 14, 10, 0, 0, 0, time.UTC)
+		assert.Equal(t, time.Date(2022, 3, 15, 0, 0, 0, 0, time.UTC), next(t, "0 0 15 * 5", after))
+		assert.Equal(t, time.Date(2022, 3, 18, 0, 0, 0, 0, time.UTC), next(t, "0 0 20 * 5", after))
+	})
+	t.Run(`end of year`, func(t *testing.T) {
+		after := time.Date(2022, 12, 31, 23, 59, 0, 0, time.UTC)
+		assert.Equal(t, time.Date(2023, 2, 1, 3, 0, 0, 0, time.UTC), next(t, "0 3 1 2 *", after))
+	})
+	t.Run(`time zone`, func(t *testing.T) {
+		after := time.Date(2022, 3, 14, 10, 0, 0, 0, vienna)
+		assert.Equal(t, time.Date(2022, 3, 14, 21, 0, 0, 0, time.UTC), next(t, "0 22 * * *", after).UTC())
+	})
+	t.Run(`daylight saving time gap`, func(t *testing.T) {
+		// clocks in Vienna jump from 02:00 to 03:00 on 2022-03-27
+		after := time.Date(2022, 3, 27, 1, 0, 0, 0, vienna)
+		assert.Equal(t, time.Date(2022, 3, 27, 3, 30, 0, 0, vienna), next(t, "30 2,3 * * *", after))
+	})
+	t.Run(`never matches`, func(t *testing.T) {
+		after := time.Date(2022, 3, 14, 10, 0, 0, 0, time.UTC)
+		assert.True(t, next(t, "0 0 30 2 *", after).IsZero())
+	})
+}
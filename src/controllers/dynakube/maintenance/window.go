@@ -0,0 +1,117 @@
+package maintenance
+
+import (
+	"fmt"
+	"time"
+
+	// the operator image doesn't contain the time zone database
+	_ "time/tzdata"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Window is a recurring maintenance window, it starts whenever the schedule matches and lasts for the duration
+type Window struct {
+	schedule *schedule
+	duration time.Duration
+	location *time.Location
+}
+
+func NewWindow(spec dynatracev1beta1.MaintenanceWindowSpec) (*Window, error) {
+	parsedSchedule, err := parseSchedule(spec.Schedule)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "invalid schedule %q", spec.Schedule)
+	}
+
+	if spec.Duration.Duration <= 0 {
+		return nil, fmt.Errorf("invalid duration %s, it must be positive", spec.Duration.Duration)
+	}
+
+	location, err := time.LoadLocation(spec.TimeZone)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "invalid time zone %q", spec.TimeZone)
+	}
+
+	return &Window{
+		schedule: parsedSchedule,
+		duration: spec.Duration.Duration,
+		location: location,
+	}, nil
+}
+
+// current returns the start of the window containing now, or false if the window is closed.
+// The window started within the last duration if the schedule matches between now-duration and now.
+func (window *Window) current(now time.Time) (time.Time, bool) {
+	start := window.schedule.next(now.In(window.location).Add(-window.duration))
+	if start.IsZero() || start.After(now) {
+		return time.Time{}, false
+	}
+	return start, true
+}
+
+// next returns the start of the next window after now, or the zero time if the schedule never matches
+func (window *Window) next(now time.Time) time.Time {
+	return window.schedule.next(now.In(window.location))
+}
+
+// Windows are the maintenance windows of a DynaKube, updates are allowed while any of them is open
+type Windows []*Window
+
+// NewWindows parses the maintenance windows of the DynaKube, an empty list means there are no restrictions
+func NewWindows(dynakube *dynatracev1beta1.DynaKube) (Windows, error) {
+	var windows Windows
+	for i, spec := range dynakube.Spec.MaintenanceWindows {
+		window, err := NewWindow(spec)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "maintenance window %d", i)
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+// Status returns whether a window is open at the given time, and the start and end of the open or next window
+func (windows Windows) Status(now time.Time) dynatracev1beta1.MaintenanceWindowStatus {
+	var status dynatracev1beta1.MaintenanceWindowStatus
+	var start, end time.Time
+
+	for _, window := range windows {
+		if currentStart, ok := window.current(now); ok {
+			currentEnd := currentStart.Add(window.duration)
+			if !status.Active || currentEnd.After(end) {
+				status.Active = true
+				start, end = currentStart, currentEnd
+			}
+			continue
+		}
+		if status.Active {
+			continue
+		}
+		if nextStart := window.next(now); !nextStart.IsZero() && (start.IsZero() || nextStart.Before(start)) {
+			start, end = nextStart, nextStart.Add(window.duration)
+		}
+	}
+
+	if !start.IsZero() {
+		status.NextStart = &metav1.Time{Time: start}
+		status.NextEnd = &metav1.Time{Time: end}
+	}
+	return status
+}
+
+// UpdatesAllowed returns true if the DynaKube has no maintenance windows or one of them is open.
+// If the windows can't be parsed no updates are allowed, the validation webhook rejects such DynaKubes anyway.
+func UpdatesAllowed(dynakube *dynatracev1beta1.DynaKube, now time.Time) bool {
+	if len(dynakube.Spec.MaintenanceWindows) == 0 {
+		return true
+	}
+
+	windows, err := NewWindows(dynakube)
+	if err != nil {
+		log.Error(err, "invalid maintenance windows, updates are postponed", "dynakube", dynakube.Name)
+		return false
+	}
+	return windows.Status(now).Active
+}
@@ -0,0 +1,72 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newDynakube(windows ...dynatracev1beta1.MaintenanceWindowSpec) *dynatracev1beta1.DynaKube {
+	return &dynatracev1beta1.DynaKube{
+		Spec: dynatracev1beta1.DynaKubeSpec{MaintenanceWindows: windows},
+	}
+}
+
+func newWindowSpec(schedule string, duration time.Duration, timeZone string) dynatracev1beta1.MaintenanceWindowSpec {
+	return dynatracev1beta1.MaintenanceWindowSpec{
+		Schedule: schedule,
+		Duration: metav1.Duration{Duration: duration},
+		TimeZone: timeZone,
+	}
+}
+
+func TestWindowsStatus(t *testing.T) {
+	// every Saturday 22:00 to Sunday 02:00 in Vienna (UTC+1 in March)
+	saturdayNight := newWindowSpec("0 22 * * 6", 4*time.Hour, "Europe/Vienna")
+	// every day 12:00 to 12:30 UTC
+	noon := newWindowSpec("0 12 * * *", 30*time.Minute, "")
+
+	windows, err := NewWindows(newDynakube(saturdayNight, noon))
+	require.NoError(t, err)
+
+	t.Run(`no open window`, func(t *testing.T) {
+		status := windows.Status(time.Date(2022, 3, 14, 13, 0, 0, 0, time.UTC))
+
+		assert.False(t, status.Active)
+		assert.Equal(t, time.Date(2022, 3, 15, 12, 0, 0, 0, time.UTC), status.NextStart.UTC())
+		assert.Equal(t, time.Date(2022, 3, 15, 12, 30, 0, 0, time.UTC), status.NextEnd.UTC())
+	})
+	t.Run(`open window`, func(t *testing.T) {
+		status := windows.Status(time.Date(2022, 3, 20, 0, 30, 0, 0, time.UTC))
+
+		assert.True(t, status.Active)
+		assert.Equal(t, time.Date(2022, 3, 19, 21, 0, 0, 0, time.UTC), status.NextStart.UTC())
+		assert.Equal(t, time.Date(2022, 3, 20, 1, 0, 0, 0, time.UTC), status.NextEnd.UTC())
+	})
+	t.Run(`window is closed at its end`, func(t *testing.T) {
+		status := windows.Status(time.Date(2022, 3, 14, 12, 30, 0, 0, time.UTC))
+
+		assert.False(t, status.Active)
+	})
+	t.Run(`window is open at its start`, func(t *testing.T) {
+		status := windows.Status(time.Date(2022, 3, 14, 12, 0, 0, 0, time.UTC))
+
+		assert.True(t, status.Active)
+	})
+	t.Run(`no windows`, func(t *testing.T) {
+		assert.Equal(t, dynatracev1beta1.MaintenanceWindowStatus{}, Windows(nil).Status(time.Now()))
+	})
+}
+
+func TestUpdatesAllowed(t *testing.T) {
+	now := time.Date(2022, 3, 14, 13, 0, 0, 0, time.UTC)
+
+	assert.True(t, UpdatesAllowed(newDynakube(), now))
+	assert.True(t, UpdatesAllowed(newDynakube(newWindowSpec("0 12 * * *", 2*time.Hour, "")), now))
+	assert.False(t, UpdatesAllowed(newDynakube(newWindowSpec("0 12 * * *", time.Hour, "")), now))
+	assert.False(t, UpdatesAllowed(newDynakube(newWindowSpec("invalid", 2*time.Hour, "")), now))
+}
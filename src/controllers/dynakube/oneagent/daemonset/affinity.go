@@ -20,7 +20,7 @@ func (dsInfo *builderInfo) affinityNodeSelectorTerms() []corev1.NodeSelectorTerm
 		kubernetesArchOsSelectorTerm(),
 	}
 
-	return nodeSelectorTerms
+	return dsInfo.excludeNodeGroups(nodeSelectorTerms)
 }
 
 func kubernetesArchOsSelectorTerm() corev1.NodeSelectorTerm {
@@ -16,7 +16,7 @@ const (
 
 func (dsInfo *builderInfo) arguments() []string {
 	metadata := deploymentmetadata.NewDeploymentMetadata(dsInfo.clusterId, dsInfo.deploymentType)
-	args := dsInfo.hostInjectSpec.Args
+	args := dsInfo.installerArgs()
 	args = dsInfo.appendProxyArg(args)
 	args = dsInfo.appendNetworkZoneArg(args)
 	args = appendOperatorVersionArg(args)
@@ -16,6 +16,9 @@ const (
 	annotationUnprivilegedValue = "unconfined"
 	annotationVersion           = dynatracev1beta1.InternalFlagPrefix + "version"
 
+	// AnnotationTemplateHash is set on the pod template during staged rollouts, to tell updated pods from outdated ones
+	AnnotationTemplateHash = dynatracev1beta1.InternalFlagPrefix + "template-hash"
+
 	defaultUnprivilegedServiceAccountName = "dynatrace-dynakube-oneagent-unprivileged"
 	// normal oneagent shutdown scenario with some extra time
 	defaultTerminationGracePeriod = 80
@@ -49,12 +52,15 @@ type ClassicFullStack struct {
 type builderInfo struct {
 	instance       *dynatracev1beta1.DynaKube
 	hostInjectSpec *dynatracev1beta1.HostInjectSpec
+	nodeGroup      *dynatracev1beta1.NodeGroupSpec
 	clusterId      string
 	deploymentType string
 }
 
 type Builder interface {
 	BuildDaemonSet() (*appsv1.DaemonSet, error)
+	// WithNodeGroup builds the DaemonSet of the node group instead of the default DaemonSet
+	WithNodeGroup(nodeGroup *dynatracev1beta1.NodeGroupSpec) Builder
 }
 
 func NewHostMonitoring(instance *dynatracev1beta1.DynaKube, clusterId string) Builder {
@@ -90,13 +96,23 @@ func NewClassicFullStack(instance *dynatracev1beta1.DynaKube, clusterId string)
 	}
 }
 
+func (dsInfo *HostMonitoring) WithNodeGroup(nodeGroup *dynatracev1beta1.NodeGroupSpec) Builder {
+	dsInfo.nodeGroup = nodeGroup
+	return dsInfo
+}
+
+func (dsInfo *ClassicFullStack) WithNodeGroup(nodeGroup *dynatracev1beta1.NodeGroupSpec) Builder {
+	dsInfo.nodeGroup = nodeGroup
+	return dsInfo
+}
+
 func (dsInfo *HostMonitoring) BuildDaemonSet() (*appsv1.DaemonSet, error) {
 	result, err := dsInfo.builderInfo.BuildDaemonSet()
 	if err != nil {
 		return nil, err
 	}
 
-	result.Name = dsInfo.instance.OneAgentDaemonsetName()
+	result.Name = dsInfo.daemonSetName()
 
 	if len(result.Spec.Template.Spec.Containers) > 0 {
 		appendHostIdArgument(result, inframonHostIdSource)
@@ -112,7 +128,7 @@ func (dsInfo *ClassicFullStack) BuildDaemonSet() (*appsv1.DaemonSet, error) {
 		return nil, err
 	}
 
-	result.Name = dsInfo.instance.OneAgentDaemonsetName()
+	result.Name = dsInfo.daemonSetName()
 
 	if len(result.Spec.Template.Spec.Containers) > 0 {
 		appendHostIdArgument(result, classicHostIdSource)
@@ -164,11 +180,23 @@ func (dsInfo *builderInfo) BuildDaemonSet() (*appsv1.DaemonSet, error) {
 		},
 	}
 
+	if dsInfo.hostInjectSpec.StagedRollout != nil {
+		// the operator deletes the outdated pods itself, node by node
+		result.Spec.UpdateStrategy = appsv1.DaemonSetUpdateStrategy{
+			Type: appsv1.OnDeleteDaemonSetStrategyType,
+		}
+	}
+
 	return result, nil
 }
 
+// GetVersion returns the OneAgent version a pod was created with
+func GetVersion(pod *corev1.Pod) string {
+	return pod.Annotations[annotationVersion]
+}
+
 func (dsInfo *builderInfo) buildLabels() map[string]string {
-	return BuildLabels(dsInfo.instance.Name, dsInfo.deploymentType)
+	return kubeobjects.MergeLabels(BuildLabels(dsInfo.instance.Name, dsInfo.deploymentType), dsInfo.nodeGroupLabels())
 }
 
 // buildMatchLabels produces a set of labels that
@@ -196,7 +224,7 @@ func (dsInfo *builderInfo) podSpec() corev1.PodSpec {
 		Containers: []corev1.Container{{
 			Args:            arguments,
 			Env:             environmentVariables,
-			Image:           dsInfo.instance.ImmutableOneAgentImage(),
+			Image:           dsInfo.image(),
 			ImagePullPolicy: corev1.PullAlways,
 			Name:            podName,
 			ReadinessProbe: &corev1.Probe{
@@ -219,10 +247,10 @@ func (dsInfo *builderInfo) podSpec() corev1.PodSpec {
 		HostNetwork:                   true,
 		HostPID:                       true,
 		HostIPC:                       false,
-		NodeSelector:                  dsInfo.hostInjectSpec.NodeSelector,
+		NodeSelector:                  dsInfo.nodeSelector(),
 		PriorityClassName:             dsInfo.hostInjectSpec.PriorityClassName,
 		ServiceAccountName:            defaultUnprivilegedServiceAccountName,
-		Tolerations:                   dsInfo.hostInjectSpec.Tolerations,
+		Tolerations:                   dsInfo.tolerations(),
 		DNSPolicy:                     dnsPolicy,
 		Volumes:                       volumes,
 		Affinity:                      affinity,
@@ -230,8 +258,15 @@ func (dsInfo *builderInfo) podSpec() corev1.PodSpec {
 	}
 }
 
+func (dsInfo *builderInfo) image() string {
+	if dsInfo.hostInjectSpec.StagedRollout != nil {
+		return dsInfo.instance.PinnedOneAgentImage()
+	}
+	return dsInfo.instance.ImmutableOneAgentImage()
+}
+
 func (dsInfo *builderInfo) resources() corev1.ResourceRequirements {
-	resources := dsInfo.hostInjectSpec.OneAgentResources
+	resources := dsInfo.mergeNodeGroupResources(dsInfo.hostInjectSpec.OneAgentResources)
 	if resources.Requests == nil {
 		resources.Requests = corev1.ResourceList{}
 	}
@@ -6,6 +6,7 @@ import (
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 )
@@ -53,6 +54,32 @@ func TestUseImmutableImage(t *testing.T) {
 	})
 }
 
+func TestStagedRollout(t *testing.T) {
+	instance := dynatracev1beta1.DynaKube{
+		Spec: dynatracev1beta1.DynaKubeSpec{
+			APIURL: testURL,
+			OneAgent: dynatracev1beta1.OneAgentSpec{
+				ClassicFullStack: &dynatracev1beta1.HostInjectSpec{
+					StagedRollout: &dynatracev1beta1.StagedRolloutSpec{},
+				},
+			},
+		},
+		Status: dynatracev1beta1.DynaKubeStatus{
+			OneAgent: dynatracev1beta1.OneAgentStatus{
+				VersionStatus: dynatracev1beta1.VersionStatus{Version: "1.200.0.20220101-000000"},
+			},
+		},
+	}
+	dsInfo := NewClassicFullStack(&instance, testClusterID)
+	ds, err := dsInfo.BuildDaemonSet()
+	require.NoError(t, err)
+
+	assert.Equal(t, appsv1.OnDeleteDaemonSetStrategyType, ds.Spec.UpdateStrategy.Type)
+	assert.Nil(t, ds.Spec.UpdateStrategy.RollingUpdate)
+	assert.Equal(t, instance.PinnedOneAgentImage(), ds.Spec.Template.Spec.Containers[0].Image)
+	assert.NotEqual(t, instance.ImmutableOneAgentImage(), ds.Spec.Template.Spec.Containers[0].Image)
+}
+
 func TestCustomPullSecret(t *testing.T) {
 	instance := dynatracev1beta1.DynaKube{
 		Spec: dynatracev1beta1.DynaKubeSpec{
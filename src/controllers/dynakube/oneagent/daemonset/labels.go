@@ -2,6 +2,9 @@ package daemonset
 
 import "github.com/Dynatrace/dynatrace-operator/src/kubeobjects"
 
+// NodeGroupLabel is set on the DaemonSets and pods of node groups, to tell them apart from the default DaemonSet
+const NodeGroupLabel = "component.dynatrace.com/node-group"
+
 func BuildLabels(name string, feature string) map[string]string {
 	labels := kubeobjects.CommonLabels(name, kubeobjects.OneAgentComponentLabel)
 	labels[kubeobjects.FeatureLabel] = feature
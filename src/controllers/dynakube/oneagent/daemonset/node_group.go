@@ -0,0 +1,158 @@
+package daemonset
+
+import (
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const hostGroupArgPrefix = "--set-host-group="
+
+func (dsInfo *builderInfo) daemonSetName() string {
+	if dsInfo.nodeGroup != nil {
+		return dsInfo.instance.OneAgentNodeGroupDaemonsetName(dsInfo.nodeGroup.Name)
+	}
+	return dsInfo.instance.OneAgentDaemonsetName()
+}
+
+func (dsInfo *builderInfo) nodeSelector() map[string]string {
+	if dsInfo.nodeGroup == nil {
+		return dsInfo.hostInjectSpec.NodeSelector
+	}
+
+	nodeSelector := map[string]string{}
+	for key, value := range dsInfo.hostInjectSpec.NodeSelector {
+		nodeSelector[key] = value
+	}
+	for key, value := range dsInfo.nodeGroup.NodeSelector {
+		nodeSelector[key] = value
+	}
+	return nodeSelector
+}
+
+func (dsInfo *builderInfo) tolerations() []corev1.Toleration {
+	if dsInfo.nodeGroup == nil || len(dsInfo.nodeGroup.Tolerations) == 0 {
+		return dsInfo.hostInjectSpec.Tolerations
+	}
+
+	tolerations := make([]corev1.Toleration, 0, len(dsInfo.hostInjectSpec.Tolerations)+len(dsInfo.nodeGroup.Tolerations))
+	tolerations = append(tolerations, dsInfo.hostInjectSpec.Tolerations...)
+	return append(tolerations, dsInfo.nodeGroup.Tolerations...)
+}
+
+// mergeNodeGroupResources overrides the requests and limits which are set for the node group
+func (dsInfo *builderInfo) mergeNodeGroupResources(resources corev1.ResourceRequirements) corev1.ResourceRequirements {
+	if dsInfo.nodeGroup == nil {
+		return resources
+	}
+
+	resources.Requests = mergeResourceList(resources.Requests, dsInfo.nodeGroup.OneAgentResources.Requests)
+	resources.Limits = mergeResourceList(resources.Limits, dsInfo.nodeGroup.OneAgentResources.Limits)
+	return resources
+}
+
+func mergeResourceList(base, override corev1.ResourceList) corev1.ResourceList {
+	if len(override) == 0 {
+		return base
+	}
+
+	merged := corev1.ResourceList{}
+	for name, quantity := range base {
+		merged[name] = quantity
+	}
+	for name, quantity := range override {
+		merged[name] = quantity
+	}
+	return merged
+}
+
+// installerArgs returns the installer arguments of the OneAgent spec, arguments of the node group replace the ones with
+// the same name
+func (dsInfo *builderInfo) installerArgs() []string {
+	args := append([]string{}, dsInfo.hostInjectSpec.Args...)
+	if dsInfo.nodeGroup == nil {
+		return args
+	}
+
+	groupArgs := append([]string{}, dsInfo.nodeGroup.Args...)
+	if dsInfo.nodeGroup.HostGroup != "" {
+		groupArgs = append(groupArgs, hostGroupArgPrefix+dsInfo.nodeGroup.HostGroup)
+	}
+
+	for _, groupArg := range groupArgs {
+		replaced := false
+		for i, arg := range args {
+			if argName(arg) == argName(groupArg) {
+				args[i] = groupArg
+				replaced = true
+			}
+		}
+		if !replaced {
+			args = append(args, groupArg)
+		}
+	}
+	return args
+}
+
+func argName(arg string) string {
+	return strings.SplitN(arg, "=", 2)[0]
+}
+
+func (dsInfo *builderInfo) mergeNodeGroupEnv(envVarMap map[string]corev1.EnvVar) map[string]corev1.EnvVar {
+	if dsInfo.nodeGroup == nil {
+		return envVarMap
+	}
+
+	for _, env := range dsInfo.nodeGroup.Env {
+		envVarMap[env.Name] = env
+	}
+	return envVarMap
+}
+
+// excludeNodeGroups restricts the node selector terms of the default DaemonSet to nodes which aren't part of a node group.
+// A node is not part of a node group if any label of the node group's node selector doesn't match, so each term is
+// split into one term per label.
+func (dsInfo *builderInfo) excludeNodeGroups(terms []corev1.NodeSelectorTerm) []corev1.NodeSelectorTerm {
+	if dsInfo.nodeGroup != nil || dsInfo.hostInjectSpec == nil {
+		return terms
+	}
+
+	for _, nodeGroup := range dsInfo.hostInjectSpec.NodeGroups {
+		keys := make([]string, 0, len(nodeGroup.NodeSelector))
+		for key := range nodeGroup.NodeSelector {
+			keys = append(keys, key)
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		sort.Strings(keys)
+
+		var excludingTerms []corev1.NodeSelectorTerm
+		for _, term := range terms {
+			for _, key := range keys {
+				excludingTerm := *term.DeepCopy()
+				excludingTerm.MatchExpressions = append(excludingTerm.MatchExpressions, corev1.NodeSelectorRequirement{
+					Key:      key,
+					Operator: corev1.NodeSelectorOpNotIn,
+					Values:   []string{nodeGroup.NodeSelector[key]},
+				})
+				excludingTerms = append(excludingTerms, excludingTerm)
+			}
+		}
+		terms = excludingTerms
+	}
+	return terms
+}
+
+func (dsInfo *builderInfo) nodeGroupLabels() map[string]string {
+	if dsInfo.nodeGroup == nil {
+		return nil
+	}
+	return map[string]string{NodeGroupLabel: dsInfo.nodeGroup.Name}
+}
+
+// NodeGroup returns the node group the pod belongs to, empty for pods of the default DaemonSet
+func NodeGroup(pod *corev1.Pod) string {
+	return pod.Labels[NodeGroupLabel]
+}
@@ -0,0 +1,100 @@
+package daemonset
+
+import (
+	"testing"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newNodeGroupDynakube() *dynatracev1beta1.DynaKube {
+	return &dynatracev1beta1.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{Name: testName},
+		Spec: dynatracev1beta1.DynaKubeSpec{
+			APIURL: testURL,
+			OneAgent: dynatracev1beta1.OneAgentSpec{
+				ClassicFullStack: &dynatracev1beta1.HostInjectSpec{
+					NodeSelector: map[string]string{"monitored": "true"},
+					Tolerations:  []corev1.Toleration{{Key: "default", Operator: corev1.TolerationOpExists}},
+					OneAgentResources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("1"),
+							corev1.ResourceMemory: resource.MustParse("1Gi"),
+						},
+					},
+					Args: []string{"--set-host-group=default", "--set-host-property=pool=default"},
+					Env:  []corev1.EnvVar{{Name: "ONEAGENT_INSTALLER_SCRIPT_URL", Value: "default"}},
+					NodeGroups: []dynatracev1beta1.NodeGroupSpec{
+						{
+							Name:         "gpu",
+							NodeSelector: map[string]string{"pool": "gpu", "accelerator": "nvidia"},
+							Tolerations:  []corev1.Toleration{{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists}},
+							OneAgentResources: corev1.ResourceRequirements{
+								Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+							},
+							Args:      []string{"--set-host-property=pool=gpu"},
+							Env:       []corev1.EnvVar{{Name: "ONEAGENT_INSTALLER_SCRIPT_URL", Value: "gpu"}},
+							HostGroup: "gpu-nodes",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestNodeGroup(t *testing.T) {
+	t.Run(`node group settings are merged with the oneagent spec`, func(t *testing.T) {
+		instance := newNodeGroupDynakube()
+
+		ds, err := NewClassicFullStack(instance, testClusterID).WithNodeGroup(&instance.Spec.OneAgent.ClassicFullStack.NodeGroups[0]).BuildDaemonSet()
+		require.NoError(t, err)
+
+		assert.Equal(t, "test-name-oneagent-gpu", ds.Name)
+		assert.Equal(t, "gpu", ds.Labels[NodeGroupLabel])
+		assert.Equal(t, "gpu", ds.Spec.Selector.MatchLabels[NodeGroupLabel])
+
+		podSpec := ds.Spec.Template.Spec
+		assert.Equal(t, map[string]string{"monitored": "true", "pool": "gpu", "accelerator": "nvidia"}, podSpec.NodeSelector)
+		assert.Len(t, podSpec.Tolerations, 2)
+
+		limits := podSpec.Containers[0].Resources.Limits
+		assert.Equal(t, "1", limits.Cpu().String())
+		assert.Equal(t, "4Gi", limits.Memory().String())
+
+		args := podSpec.Containers[0].Args
+		assert.Contains(t, args, "--set-host-group=gpu-nodes")
+		assert.Contains(t, args, "--set-host-property=pool=gpu")
+		assert.NotContains(t, args, "--set-host-group=default")
+		assert.NotContains(t, args, "--set-host-property=pool=default")
+
+		assert.Contains(t, podSpec.Containers[0].Env, corev1.EnvVar{Name: "ONEAGENT_INSTALLER_SCRIPT_URL", Value: "gpu"})
+
+		// the oneagent spec must not be modified
+		assert.Equal(t, []string{"--set-host-group=default", "--set-host-property=pool=default"}, instance.Spec.OneAgent.ClassicFullStack.Args)
+		assert.Len(t, instance.Spec.OneAgent.ClassicFullStack.Tolerations, 1)
+	})
+	t.Run(`default daemonset excludes the nodes of node groups`, func(t *testing.T) {
+		instance := newNodeGroupDynakube()
+
+		ds, err := NewClassicFullStack(instance, testClusterID).BuildDaemonSet()
+		require.NoError(t, err)
+
+		assert.Equal(t, "test-name-oneagent", ds.Name)
+		assert.NotContains(t, ds.Labels, NodeGroupLabel)
+		assert.Equal(t, map[string]string{"monitored": "true"}, ds.Spec.Template.Spec.NodeSelector)
+
+		terms := ds.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		require.Len(t, terms, 2)
+		assert.Contains(t, terms[0].MatchExpressions, corev1.NodeSelectorRequirement{
+			Key: "accelerator", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"nvidia"},
+		})
+		assert.Contains(t, terms[1].MatchExpressions, corev1.NodeSelectorRequirement{
+			Key: "pool", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"gpu"},
+		})
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	"github.com/Dynatrace/dynatrace-operator/src/controllers/dynakube/maintenance"
 	"github.com/Dynatrace/dynatrace-operator/src/controllers/dynakube/oneagent/daemonset"
 	"github.com/Dynatrace/dynatrace-operator/src/controllers/dynakube/status"
 	"github.com/Dynatrace/dynatrace-operator/src/kubeobjects"
@@ -17,7 +18,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -96,22 +99,35 @@ func (r *OneAgentReconciler) Reconcile(ctx context.Context, rec *status.Dynakube
 func (r *OneAgentReconciler) reconcileRollout(dkState *status.DynakubeState) (bool, error) {
 	updateCR := false
 
-	// Define a new DaemonSet object
-	dsDesired, err := r.getDesiredDaemonSet(dkState)
+	// Define the new DaemonSet objects, one for each node group in addition to the default one
+	daemonSets, err := r.getDesiredDaemonSets(dkState)
 	if err != nil {
 		log.Info("failed to get desired daemonset")
 		return false, err
 	}
 
-	// Set OneAgent instance as the owner and controller
-	if err := controllerutil.SetControllerReference(dkState.Instance, dsDesired, r.scheme); err != nil {
-		return false, err
-	}
+	for _, dsDesired := range daemonSets {
+		// Set OneAgent instance as the owner and controller
+		if err := controllerutil.SetControllerReference(dkState.Instance, dsDesired, r.scheme); err != nil {
+			return false, err
+		}
 
-	updateCR, err = kubeobjects.CreateOrUpdateDaemonSet(r.client, log, dsDesired)
-	if err != nil {
-		return updateCR, err
+		postponed, err := r.isRolloutPostponed(dkState, dsDesired)
+		if err != nil {
+			return false, err
+		} else if postponed {
+			log.Info("OneAgent rollout postponed until the next maintenance window", "name", dsDesired.Name,
+				"nextStart", dkState.Instance.Status.MaintenanceWindow.NextStart)
+			continue
+		}
+
+		upd, err := kubeobjects.CreateOrUpdateDaemonSet(r.client, log, dsDesired)
+		if err != nil {
+			return updateCR, err
+		}
+		updateCR = upd || updateCR
 	}
+
 	if updateCR {
 		// remove old daemonset with feature in name
 		oldClassicDaemonset := &appsv1.DaemonSet{
@@ -128,6 +144,21 @@ func (r *OneAgentReconciler) reconcileRollout(dkState *status.DynakubeState) (bo
 		}
 	}
 
+	if err := DeleteNodeGroupDaemonSets(context.TODO(), r.client, dkState.Instance, daemonSets); err != nil {
+		return updateCR, err
+	}
+
+	if dkState.Instance.StagedRollout() != nil {
+		rolloutUpdated, err := r.reconcileStagedRollout(context.TODO(), dkState, daemonSets)
+		if err != nil {
+			return updateCR, err
+		}
+		updateCR = rolloutUpdated || updateCR
+	} else if dkState.Instance.Status.OneAgent.Rollout != nil {
+		dkState.Instance.Status.OneAgent.Rollout = nil
+		updateCR = true
+	}
+
 	if dkState.Instance.Status.Tokens != dkState.Instance.Tokens() {
 		dkState.Instance.Status.Tokens = dkState.Instance.Tokens()
 		updateCR = true
@@ -136,17 +167,84 @@ func (r *OneAgentReconciler) reconcileRollout(dkState *status.DynakubeState) (bo
 	return updateCR, nil
 }
 
-func (r *OneAgentReconciler) getDesiredDaemonSet(dkState *status.DynakubeState) (*appsv1.DaemonSet, error) {
+// DeleteNodeGroupDaemonSets deletes the DaemonSets of node groups which are not desired anymore
+func DeleteNodeGroupDaemonSets(ctx context.Context, clt client.Client, dynakube *dynatracev1beta1.DynaKube, desired []*appsv1.DaemonSet) error {
+	hasNodeGroup, err := labels.NewRequirement(daemonset.NodeGroupLabel, selection.Exists, nil)
+	if err != nil {
+		return err
+	}
+	selector := labels.SelectorFromSet(labels.Set{
+		kubeobjects.AppCreatedByLabel: dynakube.Name,
+		kubeobjects.AppComponentLabel: string(kubeobjects.OneAgentComponentLabel),
+	}).Add(*hasNodeGroup)
+
+	daemonSetList := &appsv1.DaemonSetList{}
+	err = clt.List(ctx, daemonSetList, client.InNamespace(dynakube.Namespace), client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		return err
+	}
+
+	for i := range daemonSetList.Items {
+		daemonSet := &daemonSetList.Items[i]
+		if isDesiredDaemonSet(daemonSet, desired) {
+			continue
+		}
+		log.Info("removing daemonset of deleted node group", "name", daemonSet.Name)
+		if err := clt.Delete(ctx, daemonSet); err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func isDesiredDaemonSet(daemonSet *appsv1.DaemonSet, desired []*appsv1.DaemonSet) bool {
+	for _, desiredDaemonSet := range desired {
+		if desiredDaemonSet.Name == daemonSet.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// isRolloutPostponed returns true if the DaemonSet exists and changed while no maintenance window is open,
+// new DaemonSets are created right away.
+// Staged rollouts update the DaemonSet right away, the deletion of the outdated pods is postponed instead.
+func (r *OneAgentReconciler) isRolloutPostponed(dkState *status.DynakubeState, dsDesired *appsv1.DaemonSet) (bool, error) {
+	if dkState.Instance.StagedRollout() != nil || maintenance.UpdatesAllowed(dkState.Instance, dkState.Now.Time) {
+		return false, nil
+	}
+
+	dsCurrent := &appsv1.DaemonSet{}
+	err := r.client.Get(context.TODO(), client.ObjectKeyFromObject(dsDesired), dsCurrent)
+	if k8serrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return kubeobjects.HasChanged(dsCurrent, dsDesired), nil
+}
+
+func (r *OneAgentReconciler) getDesiredDaemonSets(dkState *status.DynakubeState) ([]*appsv1.DaemonSet, error) {
 	kubeSysUID, err := kubesystem.GetUID(r.apiReader)
 	if err != nil {
 		return nil, err
 	}
 
-	dsDesired, err := r.newDaemonSetForCR(dkState, string(kubeSysUID))
+	dsDesired, err := r.newDaemonSetForCR(dkState, string(kubeSysUID), nil)
 	if err != nil {
 		return nil, err
 	}
-	return dsDesired, nil
+	daemonSets := []*appsv1.DaemonSet{dsDesired}
+
+	nodeGroups := dkState.Instance.NodeGroups()
+	for i := range nodeGroups {
+		dsDesired, err = r.newDaemonSetForCR(dkState, string(kubeSysUID), &nodeGroups[i])
+		if err != nil {
+			return nil, err
+		}
+		daemonSets = append(daemonSets, dsDesired)
+	}
+	return daemonSets, nil
 }
 
 func (r *OneAgentReconciler) getPods(ctx context.Context, instance *dynatracev1beta1.DynaKube, feature string) ([]corev1.Pod, []client.ListOption, error) {
@@ -159,17 +257,20 @@ func (r *OneAgentReconciler) getPods(ctx context.Context, instance *dynatracev1b
 	return podList.Items, listOps, err
 }
 
-func (r *OneAgentReconciler) newDaemonSetForCR(dkState *status.DynakubeState, clusterID string) (*appsv1.DaemonSet, error) {
-	var ds *appsv1.DaemonSet
-	var err error
-
+func (r *OneAgentReconciler) newDaemonSetForCR(dkState *status.DynakubeState, clusterID string, nodeGroup *dynatracev1beta1.NodeGroupSpec) (*appsv1.DaemonSet, error) {
+	var builder daemonset.Builder
 	if r.feature == daemonset.DeploymentTypeFullStack {
-		ds, err = daemonset.NewClassicFullStack(dkState.Instance, clusterID).BuildDaemonSet()
+		builder = daemonset.NewClassicFullStack(dkState.Instance, clusterID)
 	} else if r.feature == daemonset.DeploymentTypeHostMonitoring {
-		ds, err = daemonset.NewHostMonitoring(dkState.Instance, clusterID).BuildDaemonSet()
+		builder = daemonset.NewHostMonitoring(dkState.Instance, clusterID)
 	} else if r.feature == daemonset.DeploymentTypeCloudNative {
-		ds, err = daemonset.NewCloudNativeFullStack(dkState.Instance, clusterID).BuildDaemonSet()
+		builder = daemonset.NewCloudNativeFullStack(dkState.Instance, clusterID)
 	}
+	if builder == nil {
+		return nil, fmt.Errorf("unknown OneAgent feature %s", r.feature)
+	}
+
+	ds, err := builder.WithNodeGroup(nodeGroup).BuildDaemonSet()
 	if err != nil {
 		return nil, err
 	}
@@ -179,6 +280,10 @@ func (r *OneAgentReconciler) newDaemonSetForCR(dkState *status.DynakubeState, cl
 		return nil, err
 	}
 	ds.Annotations[kubeobjects.AnnotationHash] = dsHash
+	if dkState.Instance.StagedRollout() != nil {
+		// staged rollouts compare the pods against the current template to find the outdated ones
+		ds.Spec.Template.Annotations[daemonset.AnnotationTemplateHash] = dsHash
+	}
 
 	return ds, nil
 }
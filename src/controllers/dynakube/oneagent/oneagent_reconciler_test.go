@@ -231,6 +231,102 @@ func TestReconcile_TokensSetCorrectly(t *testing.T) {
 	})
 }
 
+func TestReconcile_RolloutPostponedOutsideOfMaintenanceWindow(t *testing.T) {
+	namespace := "dynatrace"
+	dkName := "dynakube"
+	dk := &dynatracev1beta1.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{Name: dkName, Namespace: namespace},
+		Spec: dynatracev1beta1.DynaKubeSpec{
+			APIURL: "https://ENVIRONMENTID.live.dynatrace.com/api",
+			OneAgent: dynatracev1beta1.OneAgentSpec{
+				ClassicFullStack: &dynatracev1beta1.HostInjectSpec{},
+			},
+			MaintenanceWindows: []dynatracev1beta1.MaintenanceWindowSpec{
+				{Schedule: "0 22 * * 6", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+			},
+		},
+	}
+	c := fake.NewClient(sampleKubeSystemNS)
+	reconciler := &OneAgentReconciler{
+		client:    c,
+		apiReader: c,
+		scheme:    scheme.Scheme,
+		feature:   daemonset.DeploymentTypeFullStack,
+		instance:  dk,
+	}
+	dkState := status.DynakubeState{Instance: dk, Now: metav1.NewTime(time.Date(2022, 3, 14, 10, 0, 0, 0, time.UTC))}
+	getArgs := func() []string {
+		ds := &appsv1.DaemonSet{}
+		require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: dk.OneAgentDaemonsetName(), Namespace: namespace}, ds))
+		return ds.Spec.Template.Spec.Containers[0].Args
+	}
+
+	// new DaemonSets are created outside of maintenance windows
+	_, err := reconciler.reconcileRollout(&dkState)
+	require.NoError(t, err)
+	assert.NotContains(t, getArgs(), "--set-host-property=test=value")
+
+	dk.Spec.OneAgent.ClassicFullStack.Args = []string{"--set-host-property=test=value"}
+
+	updateCR, err := reconciler.reconcileRollout(&dkState)
+	require.NoError(t, err)
+	assert.False(t, updateCR)
+	assert.NotContains(t, getArgs(), "--set-host-property=test=value")
+
+	dkState.Now = metav1.NewTime(time.Date(2022, 3, 19, 23, 0, 0, 0, time.UTC))
+
+	updateCR, err = reconciler.reconcileRollout(&dkState)
+	require.NoError(t, err)
+	assert.True(t, updateCR)
+	assert.Contains(t, getArgs(), "--set-host-property=test=value")
+}
+
+func TestReconcile_NodeGroups(t *testing.T) {
+	namespace := "dynatrace"
+	dk := &dynatracev1beta1.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{Name: "dynakube", Namespace: namespace},
+		Spec: dynatracev1beta1.DynaKubeSpec{
+			APIURL: "https://ENVIRONMENTID.live.dynatrace.com/api",
+			OneAgent: dynatracev1beta1.OneAgentSpec{
+				HostMonitoring: &dynatracev1beta1.HostInjectSpec{
+					NodeGroups: []dynatracev1beta1.NodeGroupSpec{
+						{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}},
+						{Name: "arm", NodeSelector: map[string]string{"pool": "arm"}},
+					},
+				},
+			},
+		},
+	}
+	c := fake.NewClient(sampleKubeSystemNS)
+	reconciler := &OneAgentReconciler{
+		client:    c,
+		apiReader: c,
+		scheme:    scheme.Scheme,
+		feature:   daemonset.DeploymentTypeHostMonitoring,
+		instance:  dk,
+	}
+	dkState := status.DynakubeState{Instance: dk, Now: metav1.Now()}
+	daemonSetNames := func() []string {
+		daemonSetList := &appsv1.DaemonSetList{}
+		require.NoError(t, c.List(context.TODO(), daemonSetList))
+		var names []string
+		for _, ds := range daemonSetList.Items {
+			names = append(names, ds.Name)
+		}
+		return names
+	}
+
+	_, err := reconciler.reconcileRollout(&dkState)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"dynakube-oneagent", "dynakube-oneagent-gpu", "dynakube-oneagent-arm"}, daemonSetNames())
+
+	dk.Spec.OneAgent.HostMonitoring.NodeGroups = dk.Spec.OneAgent.HostMonitoring.NodeGroups[:1]
+
+	_, err = reconciler.reconcileRollout(&dkState)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"dynakube-oneagent", "dynakube-oneagent-gpu"}, daemonSetNames())
+}
+
 func TestReconcile_InstancesSet(t *testing.T) {
 	const (
 		namespace = "dynatrace"
@@ -254,8 +350,8 @@ func TestReconcile_InstancesSet(t *testing.T) {
 	oldVersion := "1.186"
 	hostIP := "1.2.3.4"
 	dtcMock.On("GetLatestAgentVersion", dtclient.OsUnix, dtclient.InstallerTypeDefault).Return(version, nil)
-	dtcMock.On("GetTokenScopes", "42").Return(dtclient.TokenScopes{dtclient.DynatracePaasToken}, nil)
-	dtcMock.On("GetTokenScopes", "84").Return(dtclient.TokenScopes{dtclient.DynatraceApiToken}, nil)
+	dtcMock.On("GetTokenInfo", "42").Return(&dtclient.TokenInfo{Scopes: dtclient.TokenScopes{dtclient.DynatracePaasToken}}, nil)
+	dtcMock.On("GetTokenInfo", "84").Return(&dtclient.TokenInfo{Scopes: dtclient.TokenScopes{dtclient.DynatraceApiToken}}, nil)
 
 	reconciler := &OneAgentReconciler{
 		client:    c,
@@ -358,7 +454,7 @@ func TestMigrationForDaemonSetWithoutAnnotation(t *testing.T) {
 		},
 	}
 
-	ds2, err := r.newDaemonSetForCR(dkState, "cluster1")
+	ds2, err := r.newDaemonSetForCR(dkState, "cluster1", nil)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, ds2.Annotations[kubeobjects.AnnotationHash])
 
@@ -393,11 +489,11 @@ func TestHasSpecChanged(t *testing.T) {
 			dkState := &status.DynakubeState{
 				Instance: &oldInstance,
 			}
-			ds1, err := r.newDaemonSetForCR(dkState, "cluster1")
+			ds1, err := r.newDaemonSetForCR(dkState, "cluster1", nil)
 			assert.NoError(t, err)
 
 			dkState.Instance = &newInstance
-			ds2, err := r.newDaemonSetForCR(dkState, "cluster1")
+			ds2, err := r.newDaemonSetForCR(dkState, "cluster1", nil)
 			assert.NoError(t, err)
 
 			assert.NotEmpty(t, ds1.Annotations[kubeobjects.AnnotationHash])
@@ -487,7 +583,7 @@ func TestNewDaemonset_Affinity(t *testing.T) {
 		}
 		versionProvider.On("Major").Return("1", nil)
 		versionProvider.On("Minor").Return("20+", nil)
-		ds, err := r.newDaemonSetForCR(dkState, "cluster1")
+		ds, err := r.newDaemonSetForCR(dkState, "cluster1", nil)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, ds)
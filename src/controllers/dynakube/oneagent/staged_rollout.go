@@ -0,0 +1,456 @@
+package oneagent
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	"github.com/Dynatrace/dynatrace-operator/src/controllers/dynakube/maintenance"
+	"github.com/Dynatrace/dynatrace-operator/src/controllers/dynakube/oneagent/daemonset"
+	"github.com/Dynatrace/dynatrace-operator/src/controllers/dynakube/status"
+	"github.com/Dynatrace/dynatrace-operator/src/kubeobjects"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultCanaryPercentage = 10
+	defaultReadyTimeout     = 10 * time.Minute
+	defaultSoakDuration     = 10 * time.Minute
+
+	crashLoopBackOffReason = "CrashLoopBackOff"
+)
+
+// stagedRollout updates the OneAgent pods of a DaemonSet with the OnDelete update strategy by deleting the outdated pods,
+// the DaemonSet recreates them with the current template.
+// The pods on the canary nodes are updated first, the remaining pods only after the canary pods stayed healthy for the
+// soak duration. If they don't, the rollout stops and the previous OneAgent version is restored.
+type stagedRollout struct {
+	client     client.Client
+	dkState    *status.DynakubeState
+	spec       *dynatracev1beta1.StagedRolloutSpec
+	daemonSets []*appsv1.DaemonSet
+	// templateHashes are the current template hashes of the DaemonSets by node group
+	templateHashes map[string]string
+	templateHash   string
+	pods           []corev1.Pod
+}
+
+// reconcileStagedRollout rolls out the DaemonSets of the default OneAgent DaemonSet and of the node groups together,
+// the first DaemonSet has to be the default one
+func (r *OneAgentReconciler) reconcileStagedRollout(ctx context.Context, dkState *status.DynakubeState, daemonSets []*appsv1.DaemonSet) (bool, error) {
+	// the selector of the default DaemonSet matches the pods of the node groups as well
+	podList := &corev1.PodList{}
+	err := r.client.List(ctx, podList,
+		client.InNamespace(daemonSets[0].Namespace),
+		client.MatchingLabels(daemonSets[0].Spec.Selector.MatchLabels))
+	if err != nil {
+		return false, err
+	}
+
+	templateHashes := map[string]string{}
+	for _, daemonSet := range daemonSets {
+		templateHashes[daemonSet.Labels[daemonset.NodeGroupLabel]] = daemonSet.Annotations[kubeobjects.AnnotationHash]
+	}
+	templateHash := daemonSets[0].Annotations[kubeobjects.AnnotationHash]
+	if len(daemonSets) > 1 {
+		if templateHash, err = kubeobjects.GenerateHash(templateHashes); err != nil {
+			return false, err
+		}
+	}
+
+	rollout := &stagedRollout{
+		client:         r.client,
+		dkState:        dkState,
+		spec:           dkState.Instance.StagedRollout(),
+		daemonSets:     daemonSets,
+		templateHashes: templateHashes,
+		templateHash:   templateHash,
+		pods:           podList.Items,
+	}
+	return rollout.reconcile(ctx)
+}
+
+func (rollout *stagedRollout) reconcile(ctx context.Context) (bool, error) {
+	updated := false
+	if rollout.status() == nil || rollout.status().TemplateHash != rollout.templateHash {
+		if err := rollout.start(ctx); err != nil {
+			return false, err
+		}
+		updated = true
+	}
+
+	var err error
+	var changed bool
+	switch rollout.status().Phase {
+	case dynatracev1beta1.RolloutCanary:
+		changed, err = rollout.reconcileCanary(ctx)
+	case dynatracev1beta1.RolloutSoaking:
+		changed = rollout.reconcileSoaking()
+	case dynatracev1beta1.RolloutProgressing:
+		changed, err = rollout.reconcileProgressing(ctx)
+	}
+	return updated || changed, err
+}
+
+func (rollout *stagedRollout) status() *dynatracev1beta1.OneAgentRolloutStatus {
+	return rollout.dkState.Instance.Status.OneAgent.Rollout
+}
+
+// start begins the rollout of a new pod template, a rollout which is still in progress is superseded
+func (rollout *stagedRollout) start(ctx context.Context) error {
+	dynakube := rollout.dkState.Instance
+	previous := rollout.status()
+	next := &dynatracev1beta1.OneAgentRolloutStatus{
+		Phase:          dynatracev1beta1.RolloutCanary,
+		PhaseTimestamp: rollout.dkState.Now.DeepCopy(),
+		TemplateHash:   rollout.templateHash,
+		Version:        dynakube.Status.OneAgent.Version,
+	}
+
+	outdatedPods := rollout.outdatedPods(rollout.pods)
+	switch {
+	case previous == nil && len(outdatedPods) > 0:
+		next.PreviousVersion = daemonset.GetVersion(&outdatedPods[0])
+	case previous != nil && previous.Phase == dynatracev1beta1.RolloutComplete:
+		next.PreviousVersion = previous.Version
+		next.FailedVersion = previous.FailedVersion
+	case previous != nil:
+		next.PreviousVersion = previous.PreviousVersion
+		next.FailedVersion = previous.FailedVersion
+	}
+	dynakube.Status.OneAgent.Rollout = next
+
+	if len(outdatedPods) == 0 {
+		rollout.setPhase(dynatracev1beta1.RolloutComplete, "all pods are up to date")
+		return nil
+	}
+
+	canaryNodes, err := rollout.selectCanaryNodes(ctx)
+	if err != nil {
+		return err
+	}
+	next.CanaryNodes = canaryNodes
+	next.Message = fmt.Sprintf("updating %d canary nodes", len(canaryNodes))
+	log.Info("starting staged OneAgent rollout", "version", next.Version, "previousVersion", next.PreviousVersion, "canaryNodes", canaryNodes)
+	return nil
+}
+
+// selectCanaryNodes returns the nodes running OneAgent which match the canary node selector,
+// or the configured percentage of them if no selector is set or no node matches it
+func (rollout *stagedRollout) selectCanaryNodes(ctx context.Context) ([]string, error) {
+	nodeNames := podNodeNames(rollout.pods)
+
+	if rollout.spec.CanaryNodeSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(rollout.spec.CanaryNodeSelector)
+		if err != nil {
+			return nil, err
+		}
+
+		nodeList := &corev1.NodeList{}
+		if err := rollout.client.List(ctx, nodeList, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, err
+		}
+
+		var canaryNodes []string
+		for _, node := range nodeList.Items {
+			if containsString(nodeNames, node.Name) {
+				canaryNodes = append(canaryNodes, node.Name)
+			}
+		}
+		if len(canaryNodes) > 0 {
+			sort.Strings(canaryNodes)
+			return canaryNodes, nil
+		}
+		log.Info("no OneAgent node matches the canary node selector, using a percentage of the nodes instead")
+	}
+
+	percentage := defaultCanaryPercentage
+	if rollout.spec.CanaryPercentage != nil {
+		percentage = int(*rollout.spec.CanaryPercentage)
+	}
+	count := int(math.Ceil(float64(len(nodeNames)*percentage) / 100))
+	if count < 1 {
+		count = 1
+	}
+	if count > len(nodeNames) {
+		count = len(nodeNames)
+	}
+	return nodeNames[:count], nil
+}
+
+// reconcileCanary deletes the outdated pods on the canary nodes and waits until their replacements are ready
+func (rollout *stagedRollout) reconcileCanary(ctx context.Context) (bool, error) {
+	rolloutStatus := rollout.status()
+	canaryPods := rollout.canaryPods()
+
+	deleted, postponed, err := rollout.deletePods(ctx, rollout.outdatedPods(canaryPods), len(canaryPods))
+	if err != nil {
+		return false, err
+	}
+	if postponed {
+		// the ready timeout starts once the canary pods have been deleted
+		rolloutStatus.PhaseTimestamp = rollout.dkState.Now.DeepCopy()
+		return rollout.setMessage("updating the canary nodes is postponed until the next maintenance window"), nil
+	}
+	if deleted > 0 {
+		return rollout.setMessage(fmt.Sprintf("deleted %d outdated canary pods", deleted)), nil
+	}
+
+	for _, pod := range canaryPods {
+		if failure := podFailure(&pod); failure != "" {
+			return rollout.fail(fmt.Sprintf("canary pod %s on node %s failed: %s", pod.Name, pod.Spec.NodeName, failure)), nil
+		}
+	}
+
+	readyNodes, err := rollout.countCanaryNodesReady(ctx, canaryPods)
+	if err != nil {
+		return false, err
+	}
+	if readyNodes.ready == readyNodes.existing {
+		return rollout.setPhase(dynatracev1beta1.RolloutSoaking, fmt.Sprintf("%d canary pods are ready", readyNodes.ready)), nil
+	}
+
+	if rollout.dkState.IsOutdated(rolloutStatus.PhaseTimestamp, rollout.readyTimeout()) {
+		return rollout.fail(fmt.Sprintf("only %d of %d canary pods became ready within %s",
+			readyNodes.ready, readyNodes.existing, rollout.readyTimeout())), nil
+	}
+	return rollout.setMessage(fmt.Sprintf("%d of %d canary pods are ready", readyNodes.ready, readyNodes.existing)), nil
+}
+
+type canaryNodeCount struct {
+	ready, existing int
+}
+
+// countCanaryNodesReady counts the canary nodes running an updated and ready pod, nodes which have been removed from
+// the cluster since the rollout started are ignored
+func (rollout *stagedRollout) countCanaryNodesReady(ctx context.Context, canaryPods []corev1.Pod) (canaryNodeCount, error) {
+	var count canaryNodeCount
+	for _, nodeName := range rollout.status().CanaryNodes {
+		err := rollout.client.Get(ctx, client.ObjectKey{Name: nodeName}, &corev1.Node{})
+		if k8serrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return count, err
+		}
+		count.existing++
+
+		for _, pod := range canaryPods {
+			if pod.Spec.NodeName == nodeName && !rollout.isOutdated(&pod) && isPodReady(&pod) {
+				count.ready++
+				break
+			}
+		}
+	}
+	return count, nil
+}
+
+// reconcileSoaking waits for the soak duration, the rollout fails if a canary pod becomes unready or restarts meanwhile
+func (rollout *stagedRollout) reconcileSoaking() bool {
+	for _, pod := range rollout.canaryPods() {
+		if rollout.isOutdated(&pod) {
+			continue
+		}
+		if failure := podFailure(&pod); failure != "" {
+			return rollout.fail(fmt.Sprintf("canary pod %s on node %s failed during the soak period: %s", pod.Name, pod.Spec.NodeName, failure))
+		} else if !isPodReady(&pod) {
+			return rollout.fail(fmt.Sprintf("canary pod %s on node %s became unready during the soak period", pod.Name, pod.Spec.NodeName))
+		}
+	}
+
+	if rollout.dkState.IsOutdated(rollout.status().PhaseTimestamp, rollout.soakDuration()) {
+		return rollout.setPhase(dynatracev1beta1.RolloutProgressing, "canary pods stayed healthy, updating the remaining nodes")
+	}
+	return false
+}
+
+// reconcileProgressing deletes the outdated pods on the remaining nodes, without exceeding the maximum number of unavailable pods
+func (rollout *stagedRollout) reconcileProgressing(ctx context.Context) (bool, error) {
+	outdatedPods := rollout.outdatedPods(rollout.pods)
+	if len(outdatedPods) == 0 {
+		return rollout.setPhase(dynatracev1beta1.RolloutComplete, "all pods are up to date"), nil
+	}
+
+	desiredPods := 0
+	for _, daemonSet := range rollout.daemonSets {
+		currentDaemonSet := &appsv1.DaemonSet{}
+		err := rollout.client.Get(ctx, client.ObjectKeyFromObject(daemonSet), currentDaemonSet)
+		if k8serrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return false, err
+		}
+		desiredPods += int(currentDaemonSet.Status.DesiredNumberScheduled)
+	}
+	readyPods := 0
+	for _, pod := range rollout.pods {
+		if isPodReady(&pod) {
+			readyPods++
+		}
+	}
+
+	maxUnavailable := rollout.dkState.Instance.FeatureOneAgentMaxUnavailable()
+	if maxUnavailable < 1 {
+		maxUnavailable = 1
+	}
+	unavailable := desiredPods - readyPods
+	if unavailable < 0 {
+		unavailable = 0
+	}
+
+	deleted, postponed, err := rollout.deletePods(ctx, outdatedPods, maxUnavailable-unavailable)
+	if err != nil {
+		return false, err
+	} else if postponed {
+		return rollout.setMessage("updating the remaining nodes is postponed until the next maintenance window"), nil
+	}
+	return rollout.setMessage(fmt.Sprintf("%d of %d pods are up to date, deleted %d outdated pods",
+		len(rollout.pods)-len(outdatedPods), len(rollout.pods), deleted)), nil
+}
+
+// deletePods deletes up to limit pods, outside of maintenance windows only pods running a failed version are deleted
+func (rollout *stagedRollout) deletePods(ctx context.Context, pods []corev1.Pod, limit int) (int, bool, error) {
+	updatesAllowed := maintenance.UpdatesAllowed(rollout.dkState.Instance, rollout.dkState.Now.Time)
+	failedVersion := rollout.status().FailedVersion
+
+	deleted := 0
+	postponed := false
+	for i := range pods {
+		if deleted >= limit {
+			break
+		}
+		pod := &pods[i]
+		if !updatesAllowed && (failedVersion == "" || daemonset.GetVersion(pod) != failedVersion) {
+			postponed = true
+			continue
+		}
+
+		log.Info("deleting outdated OneAgent pod", "pod", pod.Name, "node", pod.Spec.NodeName)
+		if err := rollout.client.Delete(ctx, pod); err != nil && !k8serrors.IsNotFound(err) {
+			return deleted, false, err
+		}
+		deleted++
+	}
+	return deleted, postponed && deleted == 0, nil
+}
+
+// fail stops the rollout and restores the previous version, unless only the configuration of the pods changed
+func (rollout *stagedRollout) fail(reason string) bool {
+	rolloutStatus := rollout.status()
+	oneAgentStatus := &rollout.dkState.Instance.Status.OneAgent
+
+	if rolloutStatus.PreviousVersion != "" && rolloutStatus.Version != rolloutStatus.PreviousVersion {
+		rolloutStatus.FailedVersion = rolloutStatus.Version
+		oneAgentStatus.Version = rolloutStatus.PreviousVersion
+		oneAgentStatus.ImageHash = ""
+		reason = fmt.Sprintf("%s, restoring version %s", reason, rolloutStatus.PreviousVersion)
+	}
+
+	log.Info("staged OneAgent rollout failed", "version", rolloutStatus.Version, "reason", reason)
+	return rollout.setPhase(dynatracev1beta1.RolloutFailed, reason)
+}
+
+func (rollout *stagedRollout) setPhase(phase dynatracev1beta1.OneAgentRolloutPhase, message string) bool {
+	rolloutStatus := rollout.status()
+	rolloutStatus.Phase = phase
+	rolloutStatus.PhaseTimestamp = rollout.dkState.Now.DeepCopy()
+	rolloutStatus.Message = message
+	return true
+}
+
+func (rollout *stagedRollout) setMessage(message string) bool {
+	if rollout.status().Message == message {
+		return false
+	}
+	rollout.status().Message = message
+	return true
+}
+
+func (rollout *stagedRollout) canaryPods() []corev1.Pod {
+	var canaryPods []corev1.Pod
+	for _, pod := range rollout.pods {
+		if containsString(rollout.status().CanaryNodes, pod.Spec.NodeName) {
+			canaryPods = append(canaryPods, pod)
+		}
+	}
+	return canaryPods
+}
+
+func (rollout *stagedRollout) outdatedPods(pods []corev1.Pod) []corev1.Pod {
+	var outdated []corev1.Pod
+	for _, pod := range pods {
+		if rollout.isOutdated(&pod) {
+			outdated = append(outdated, pod)
+		}
+	}
+	sort.Slice(outdated, func(i, j int) bool {
+		return outdated[i].Spec.NodeName < outdated[j].Spec.NodeName
+	})
+	return outdated
+}
+
+func (rollout *stagedRollout) isOutdated(pod *corev1.Pod) bool {
+	return pod.DeletionTimestamp == nil && pod.Annotations[daemonset.AnnotationTemplateHash] != rollout.templateHashes[daemonset.NodeGroup(pod)]
+}
+
+func (rollout *stagedRollout) readyTimeout() time.Duration {
+	if rollout.spec.ReadyTimeout != nil {
+		return rollout.spec.ReadyTimeout.Duration
+	}
+	return defaultReadyTimeout
+}
+
+func (rollout *stagedRollout) soakDuration() time.Duration {
+	if rollout.spec.SoakDuration != nil {
+		return rollout.spec.SoakDuration.Duration
+	}
+	return defaultSoakDuration
+}
+
+// podFailure returns why the pod is considered as failed, an updated pod must not restart at all
+func podFailure(pod *corev1.Pod) string {
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.State.Waiting != nil && containerStatus.State.Waiting.Reason == crashLoopBackOffReason {
+			return fmt.Sprintf("container %s is crashlooping", containerStatus.Name)
+		}
+		if containerStatus.RestartCount > 0 {
+			return fmt.Sprintf("container %s restarted %d times", containerStatus.Name, containerStatus.RestartCount)
+		}
+	}
+	return ""
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func podNodeNames(pods []corev1.Pod) []string {
+	var nodeNames []string
+	for _, pod := range pods {
+		if pod.Spec.NodeName != "" && !containsString(nodeNames, pod.Spec.NodeName) {
+			nodeNames = append(nodeNames, pod.Spec.NodeName)
+		}
+	}
+	sort.Strings(nodeNames)
+	return nodeNames
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
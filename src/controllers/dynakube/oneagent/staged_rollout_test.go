@@ -0,0 +1,259 @@
+package oneagent
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	"github.com/Dynatrace/dynatrace-operator/src/controllers/dynakube/oneagent/daemonset"
+	"github.com/Dynatrace/dynatrace-operator/src/controllers/dynakube/status"
+	"github.com/Dynatrace/dynatrace-operator/src/kubeobjects"
+	"github.com/Dynatrace/dynatrace-operator/src/scheme/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	testRolloutNamespace = "dynatrace"
+	testOldHash          = "old-hash"
+	testNewHash          = "new-hash"
+	testOldVersion       = "1.200.0.20220101-000000"
+	testNewVersion       = "1.210.0.20220301-000000"
+)
+
+var testRolloutLabels = map[string]string{"app": "oneagent"}
+
+type stagedRolloutTest struct {
+	client    client.Client
+	dkState   *status.DynakubeState
+	daemonSet *appsv1.DaemonSet
+}
+
+func newStagedRolloutTest(t *testing.T, nodeCount int, spec *dynatracev1beta1.StagedRolloutSpec) *stagedRolloutTest {
+	dynakube := &dynatracev1beta1.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{Name: "dynakube", Namespace: testRolloutNamespace},
+		Spec: dynatracev1beta1.DynaKubeSpec{
+			OneAgent: dynatracev1beta1.OneAgentSpec{
+				ClassicFullStack: &dynatracev1beta1.HostInjectSpec{StagedRollout: spec},
+			},
+		},
+	}
+	dynakube.Status.OneAgent.Version = testNewVersion
+
+	daemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        dynakube.OneAgentDaemonsetName(),
+			Namespace:   testRolloutNamespace,
+			Annotations: map[string]string{kubeobjects.AnnotationHash: testNewHash},
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: testRolloutLabels},
+		},
+		Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: int32(nodeCount)},
+	}
+
+	objects := []client.Object{daemonSet}
+	for i := 0; i < nodeCount; i++ {
+		nodeName := fmt.Sprintf("node-%d", i)
+		objects = append(objects,
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName, Labels: map[string]string{"index": fmt.Sprint(i)}}},
+			newRolloutPod(nodeName, testOldHash, testOldVersion, true))
+	}
+
+	return &stagedRolloutTest{
+		client:    fake.NewClient(objects...),
+		dkState:   &status.DynakubeState{Instance: dynakube, Now: metav1.NewTime(time.Date(2022, 3, 14, 10, 0, 0, 0, time.UTC))},
+		daemonSet: daemonSet,
+	}
+}
+
+func newRolloutPod(nodeName, templateHash, version string, ready bool) *corev1.Pod {
+	readyStatus := corev1.ConditionFalse
+	if ready {
+		readyStatus = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "oneagent-" + nodeName,
+			Namespace: testRolloutNamespace,
+			Labels:    testRolloutLabels,
+			Annotations: map[string]string{
+				daemonset.AnnotationTemplateHash:                templateHash,
+				dynatracev1beta1.InternalFlagPrefix + "version": version,
+			},
+		},
+		Spec: corev1.PodSpec{NodeName: nodeName},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: readyStatus}},
+		},
+	}
+}
+
+func (test *stagedRolloutTest) reconcile(t *testing.T) bool {
+	reconciler := &OneAgentReconciler{client: test.client}
+	updated, err := reconciler.reconcileStagedRollout(context.TODO(), test.dkState, []*appsv1.DaemonSet{test.daemonSet})
+	require.NoError(t, err)
+	return updated
+}
+
+func (test *stagedRolloutTest) rollout() *dynatracev1beta1.OneAgentRolloutStatus {
+	return test.dkState.Instance.Status.OneAgent.Rollout
+}
+
+func (test *stagedRolloutTest) advance(duration time.Duration) {
+	test.dkState.Now = metav1.NewTime(test.dkState.Now.Add(duration))
+}
+
+// replacePod simulates the DaemonSet controller recreating a deleted pod with the current template
+func (test *stagedRolloutTest) replacePod(t *testing.T, pod *corev1.Pod) {
+	err := test.client.Get(context.TODO(), client.ObjectKeyFromObject(pod), &corev1.Pod{})
+	require.True(t, k8serrors.IsNotFound(err), "pod %s should have been deleted", pod.Name)
+	require.NoError(t, test.client.Create(context.TODO(), pod))
+}
+
+func (test *stagedRolloutTest) podExists(t *testing.T, nodeName string) bool {
+	err := test.client.Get(context.TODO(), client.ObjectKey{Name: "oneagent-" + nodeName, Namespace: testRolloutNamespace}, &corev1.Pod{})
+	if k8serrors.IsNotFound(err) {
+		return false
+	}
+	require.NoError(t, err)
+	return true
+}
+
+func TestStagedRollout(t *testing.T) {
+	t.Run(`canary nodes are updated first, remaining nodes after soaking`, func(t *testing.T) {
+		test := newStagedRolloutTest(t, 4, &dynatracev1beta1.StagedRolloutSpec{})
+
+		assert.True(t, test.reconcile(t))
+		require.NotNil(t, test.rollout())
+		assert.Equal(t, dynatracev1beta1.RolloutCanary, test.rollout().Phase)
+		assert.Equal(t, testOldVersion, test.rollout().PreviousVersion)
+		assert.Equal(t, []string{"node-0"}, test.rollout().CanaryNodes)
+		assert.False(t, test.podExists(t, "node-0"))
+		assert.True(t, test.podExists(t, "node-1"))
+
+		test.replacePod(t, newRolloutPod("node-0", testNewHash, testNewVersion, true))
+		test.reconcile(t)
+		assert.Equal(t, dynatracev1beta1.RolloutSoaking, test.rollout().Phase)
+
+		test.advance(5 * time.Minute)
+		test.reconcile(t)
+		assert.Equal(t, dynatracev1beta1.RolloutSoaking, test.rollout().Phase)
+
+		test.advance(6 * time.Minute)
+		test.reconcile(t)
+		assert.Equal(t, dynatracev1beta1.RolloutProgressing, test.rollout().Phase)
+
+		// at most one pod is unavailable at a time
+		test.reconcile(t)
+		assert.False(t, test.podExists(t, "node-1"))
+		assert.True(t, test.podExists(t, "node-2"))
+
+		for _, nodeName := range []string{"node-1", "node-2", "node-3"} {
+			test.replacePod(t, newRolloutPod(nodeName, testNewHash, testNewVersion, true))
+			test.reconcile(t)
+		}
+		test.reconcile(t)
+		assert.Equal(t, dynatracev1beta1.RolloutComplete, test.rollout().Phase)
+		assert.False(t, test.rollout().InProgress())
+	})
+	t.Run(`canary node selector takes precedence over the percentage`, func(t *testing.T) {
+		test := newStagedRolloutTest(t, 4, &dynatracev1beta1.StagedRolloutSpec{
+			CanaryNodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"index": "2"}},
+		})
+
+		test.reconcile(t)
+
+		assert.Equal(t, []string{"node-2"}, test.rollout().CanaryNodes)
+		assert.True(t, test.podExists(t, "node-0"))
+		assert.False(t, test.podExists(t, "node-2"))
+	})
+	t.Run(`canary percentage is rounded up`, func(t *testing.T) {
+		canaryPercentage := int32(30)
+		test := newStagedRolloutTest(t, 4, &dynatracev1beta1.StagedRolloutSpec{CanaryPercentage: &canaryPercentage})
+
+		test.reconcile(t)
+
+		assert.Equal(t, []string{"node-0", "node-1"}, test.rollout().CanaryNodes)
+	})
+	t.Run(`rollout fails and restores the previous version if a canary pod restarts`, func(t *testing.T) {
+		test := newStagedRolloutTest(t, 2, &dynatracev1beta1.StagedRolloutSpec{})
+		test.dkState.Instance.Status.OneAgent.ImageHash = "new-image-hash"
+
+		test.reconcile(t)
+		crashingPod := newRolloutPod("node-0", testNewHash, testNewVersion, false)
+		crashingPod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name:         "dynatrace-oneagent",
+			RestartCount: 2,
+			State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: crashLoopBackOffReason}},
+		}}
+		test.replacePod(t, crashingPod)
+
+		assert.True(t, test.reconcile(t))
+
+		assert.Equal(t, dynatracev1beta1.RolloutFailed, test.rollout().Phase)
+		assert.Contains(t, test.rollout().Message, "crashlooping")
+		assert.Equal(t, testNewVersion, test.rollout().FailedVersion)
+		assert.Equal(t, testOldVersion, test.dkState.Instance.Status.OneAgent.Version)
+		assert.Empty(t, test.dkState.Instance.Status.OneAgent.ImageHash)
+		assert.True(t, test.podExists(t, "node-1"))
+	})
+	t.Run(`rollout fails if the canary pods don't become ready in time`, func(t *testing.T) {
+		readyTimeout := metav1.Duration{Duration: 5 * time.Minute}
+		test := newStagedRolloutTest(t, 2, &dynatracev1beta1.StagedRolloutSpec{ReadyTimeout: &readyTimeout})
+
+		test.reconcile(t)
+		test.replacePod(t, newRolloutPod("node-0", testNewHash, testNewVersion, false))
+		test.advance(4 * time.Minute)
+		test.reconcile(t)
+		assert.Equal(t, dynatracev1beta1.RolloutCanary, test.rollout().Phase)
+
+		test.advance(2 * time.Minute)
+		test.reconcile(t)
+		assert.Equal(t, dynatracev1beta1.RolloutFailed, test.rollout().Phase)
+	})
+	t.Run(`rollback rolls out the previous version to the canary nodes`, func(t *testing.T) {
+		test := newStagedRolloutTest(t, 2, &dynatracev1beta1.StagedRolloutSpec{})
+		test.reconcile(t)
+		test.replacePod(t, newRolloutPod("node-0", testNewHash, testNewVersion, false))
+		test.advance(11 * time.Minute)
+		test.reconcile(t)
+		require.Equal(t, dynatracev1beta1.RolloutFailed, test.rollout().Phase)
+
+		// the restored version results in a new template
+		test.daemonSet.Annotations[kubeobjects.AnnotationHash] = testOldHash
+		test.reconcile(t)
+
+		assert.Equal(t, dynatracev1beta1.RolloutCanary, test.rollout().Phase)
+		assert.Equal(t, testOldVersion, test.rollout().Version)
+		assert.Equal(t, testNewVersion, test.rollout().FailedVersion)
+		assert.False(t, test.podExists(t, "node-0"))
+	})
+	t.Run(`no outdated pods completes the rollout right away`, func(t *testing.T) {
+		test := newStagedRolloutTest(t, 2, &dynatracev1beta1.StagedRolloutSpec{})
+		test.daemonSet.Annotations[kubeobjects.AnnotationHash] = testOldHash
+
+		test.reconcile(t)
+
+		assert.Equal(t, dynatracev1beta1.RolloutComplete, test.rollout().Phase)
+	})
+	t.Run(`pods are not deleted outside of maintenance windows`, func(t *testing.T) {
+		test := newStagedRolloutTest(t, 2, &dynatracev1beta1.StagedRolloutSpec{})
+		test.dkState.Instance.Spec.MaintenanceWindows = []dynatracev1beta1.MaintenanceWindowSpec{
+			{Schedule: "0 22 * * 6", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+		}
+
+		test.reconcile(t)
+
+		assert.Equal(t, dynatracev1beta1.RolloutCanary, test.rollout().Phase)
+		assert.Contains(t, test.rollout().Message, "postponed")
+		assert.True(t, test.podExists(t, "node-0"))
+	})
+}
@@ -0,0 +1,19 @@
+package settings
+
+import (
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/src/logger"
+)
+
+const (
+	// SyncInterval is the time between comparisons of the settings objects with the environment, if the spec did not change.
+	SyncInterval = 15 * time.Minute
+
+	messageClusterScopeNotFound            = "the Kubernetes cluster entity does not exist yet, set a scope or enable Kubernetes monitoring"
+	messageSingleObjectSchemaDeclaredTwice = "the schema allows only one object per scope, but it is declared more than once for this scope"
+)
+
+var (
+	log = logger.NewDTLogger().WithName("dynakube-settings")
+)
@@ -0,0 +1,55 @@
+package settings
+
+import (
+	"reflect"
+	"sort"
+)
+
+// driftedProperties returns the paths of all properties in desired whose value differs in actual, sorted.
+// Properties which are only present in actual are ignored, as the tenant fills in defaults for them.
+func driftedProperties(desired, actual map[string]interface{}) []string {
+	var drifted []string
+	collectDriftedProperties("", desired, actual, &drifted)
+	sort.Strings(drifted)
+	return drifted
+}
+
+func collectDriftedProperties(prefix string, desired, actual map[string]interface{}, drifted *[]string) {
+	for key, desiredValue := range desired {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		actualValue, exists := actual[key]
+		desiredMap, desiredIsMap := desiredValue.(map[string]interface{})
+		actualMap, actualIsMap := actualValue.(map[string]interface{})
+		switch {
+		case !exists:
+			*drifted = append(*drifted, path)
+		case desiredIsMap && actualIsMap:
+			collectDriftedProperties(path, desiredMap, actualMap, drifted)
+		case !reflect.DeepEqual(desiredValue, actualValue):
+			*drifted = append(*drifted, path)
+		}
+	}
+}
+
+// mergeProperties returns a copy of actual with all properties of desired applied, so properties not managed by the
+// operator keep their value when the object is updated.
+func mergeProperties(desired, actual map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(actual))
+	for key, value := range actual {
+		merged[key] = value
+	}
+	for key, desiredValue := range desired {
+		desiredMap, desiredIsMap := desiredValue.(map[string]interface{})
+		actualMap, actualIsMap := merged[key].(map[string]interface{})
+		if desiredIsMap && actualIsMap {
+			merged[key] = mergeProperties(desiredMap, actualMap)
+		} else {
+			merged[key] = desiredValue
+		}
+	}
+	return merged
+}
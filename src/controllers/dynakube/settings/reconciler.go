@@ -0,0 +1,261 @@
+package settings
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	"github.com/Dynatrace/dynatrace-operator/src/controllers/dynakube/status"
+	"github.com/Dynatrace/dynatrace-operator/src/dtclient"
+	"github.com/Dynatrace/dynatrace-operator/src/kubeobjects"
+	"github.com/pkg/errors"
+)
+
+// Reconciler creates the settings objects listed in the DynaKube's spec and reverts changes made to them in the environment.
+// Objects removed from the spec are left in the environment.
+type Reconciler struct {
+	dtc     dtclient.Client
+	dkState *status.DynakubeState
+}
+
+func NewReconciler(dtc dtclient.Client, dkState *status.DynakubeState) *Reconciler {
+	return &Reconciler{
+		dtc:     dtc,
+		dkState: dkState,
+	}
+}
+
+// Reconcile syncs the settings objects if the spec changed or the SyncInterval passed since the last sync.
+// Returns true if the status was updated. Failures of single objects are reported in their status and retried with
+// the next reconcile.
+func (r *Reconciler) Reconcile(ctx context.Context) (bool, error) {
+	dk := r.dkState.Instance
+	settingsStatus := &dk.Status.Settings
+
+	if len(dk.Spec.Settings) == 0 {
+		if reflect.DeepEqual(*settingsStatus, dynatracev1beta1.SettingsStatus{}) {
+			return false, nil
+		}
+		*settingsStatus = dynatracev1beta1.SettingsStatus{}
+		return true, nil
+	}
+
+	specHash, err := kubeobjects.GenerateHash(dk.Spec.Settings)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	if specHash == settingsStatus.SpecHash && !r.dkState.IsOutdated(settingsStatus.LastSyncTimestamp, SyncInterval) {
+		return false, nil
+	}
+
+	clusterScope := r.findClusterScope(ctx)
+	scopes := make([]string, len(dk.Spec.Settings))
+	declarations := make(map[string]int)
+	for i, spec := range dk.Spec.Settings {
+		scopes[i] = spec.Scope
+		if scopes[i] == "" {
+			scopes[i] = clusterScope
+		}
+		declarations[spec.SchemaID+"/"+scopes[i]]++
+	}
+
+	allSynced := true
+	objects := make([]dynatracev1beta1.SettingsObjectStatus, 0, len(dk.Spec.Settings))
+	for i, spec := range dk.Spec.Settings {
+		scope := scopes[i]
+		var objectStatus dynatracev1beta1.SettingsObjectStatus
+		if declarations[spec.SchemaID+"/"+scope] > 1 {
+			objectStatus = r.syncObjectOfMultiObjectSchema(ctx, spec, scope, findObjectStatus(settingsStatus.Objects, spec.SchemaID, scope, spec.Key))
+		} else {
+			objectStatus = r.syncObject(ctx, spec, scope, findObjectStatus(settingsStatus.Objects, spec.SchemaID, scope, spec.Key))
+		}
+		if objectStatus.State == dynatracev1beta1.SettingsObjectFailed {
+			allSynced = false
+			log.Info("failed to sync settings object", "dynakube", dk.Name, "schema", spec.SchemaID, "scope", scope, "cause", objectStatus.Message)
+		}
+		objects = append(objects, objectStatus)
+	}
+
+	now := r.dkState.Now.DeepCopy()
+	*settingsStatus = dynatracev1beta1.SettingsStatus{
+		LastSyncTimestamp: now,
+		Objects:           objects,
+	}
+	// without the spec hash failed objects are retried with the next reconcile instead of after the SyncInterval
+	if allSynced {
+		settingsStatus.SpecHash = specHash
+	}
+	return true, nil
+}
+
+// findClusterScope returns the newest Kubernetes cluster entity of this cluster, or an empty string if there is none yet.
+func (r *Reconciler) findClusterScope(ctx context.Context) string {
+	dk := r.dkState.Instance
+	if dk.Status.KubeSystemUUID == "" || !needsClusterScope(dk.Spec.Settings) {
+		return ""
+	}
+
+	entities, err := r.dtc.GetMonitoredEntitiesForKubeSystemUUID(ctx, dk.Status.KubeSystemUUID)
+	if err != nil {
+		log.Info("failed to query Kubernetes cluster entity for settings scope", "dynakube", dk.Name, "cause", err.Error())
+		return ""
+	}
+
+	var newest dtclient.MonitoredEntity
+	for _, entity := range entities {
+		if entity.LastSeenTms > newest.LastSeenTms {
+			newest = entity
+		}
+	}
+	return newest.EntityId
+}
+
+// syncObjectOfMultiObjectSchema syncs an object of a schema which is declared more than once per scope, the
+// validation webhook can't tell whether the schema allows that, so objects of single-object schemas fail here.
+func (r *Reconciler) syncObjectOfMultiObjectSchema(ctx context.Context, spec dynatracev1beta1.SettingsObjectSpec, scope string, previous *dynatracev1beta1.SettingsObjectStatus) dynatracev1beta1.SettingsObjectStatus {
+	schema, err := r.dtc.GetSettingsSchema(ctx, spec.SchemaID)
+	if err != nil {
+		return failed(newObjectStatus(spec, scope, previous), err.Error())
+	}
+	if !schema.MultiObject {
+		return failed(newObjectStatus(spec, scope, previous), messageSingleObjectSchemaDeclaredTwice)
+	}
+	return r.syncObject(ctx, spec, scope, previous)
+}
+
+func newObjectStatus(spec dynatracev1beta1.SettingsObjectSpec, scope string, previous *dynatracev1beta1.SettingsObjectStatus) dynatracev1beta1.SettingsObjectStatus {
+	objectStatus := dynatracev1beta1.SettingsObjectStatus{
+		SchemaID: spec.SchemaID,
+		Scope:    scope,
+		Key:      spec.Key,
+	}
+	if previous != nil {
+		objectStatus.ObjectID = previous.ObjectID
+		objectStatus.LastDriftTimestamp = previous.LastDriftTimestamp
+	}
+	return objectStatus
+}
+
+func (r *Reconciler) syncObject(ctx context.Context, spec dynatracev1beta1.SettingsObjectSpec, scope string, previous *dynatracev1beta1.SettingsObjectStatus) dynatracev1beta1.SettingsObjectStatus {
+	objectStatus := newObjectStatus(spec, scope, previous)
+
+	if scope == "" {
+		return failed(objectStatus, messageClusterScopeNotFound)
+	}
+
+	var desired map[string]interface{}
+	if err := json.Unmarshal(spec.Value.Raw, &desired); err != nil {
+		return failed(objectStatus, fmt.Sprintf("value is not a JSON object: %s", err.Error()))
+	}
+
+	actual, err := r.findObject(ctx, spec.SchemaID, scope, objectStatus.ObjectID)
+	if err != nil {
+		return failed(objectStatus, err.Error())
+	}
+
+	if actual == nil {
+		objectID, err := r.dtc.CreateSettingsObject(ctx, dtclient.SettingsObject{
+			SchemaID:      spec.SchemaID,
+			SchemaVersion: spec.SchemaVersion,
+			Scope:         scope,
+			Value:         spec.Value.Raw,
+		})
+		if err != nil {
+			return failed(objectStatus, err.Error())
+		}
+		objectStatus.ObjectID = objectID
+		objectStatus.State = dynatracev1beta1.SettingsObjectCreated
+		return objectStatus
+	}
+	objectStatus.ObjectID = actual.ObjectID
+
+	var actualValue map[string]interface{}
+	if err := json.Unmarshal(actual.Value, &actualValue); err != nil {
+		return failed(objectStatus, fmt.Sprintf("failed to parse value of settings object: %s", err.Error()))
+	}
+
+	drifted := driftedProperties(desired, actualValue)
+	if len(drifted) == 0 {
+		objectStatus.State = dynatracev1beta1.SettingsObjectInSync
+		return objectStatus
+	}
+
+	mergedValue, err := json.Marshal(mergeProperties(desired, actualValue))
+	if err != nil {
+		return failed(objectStatus, err.Error())
+	}
+	err = r.dtc.UpdateSettingsObject(ctx, dtclient.SettingsObject{
+		ObjectID:      actual.ObjectID,
+		SchemaVersion: spec.SchemaVersion,
+		Value:         mergedValue,
+	})
+	if err != nil {
+		return failed(objectStatus, err.Error())
+	}
+
+	log.Info("reverted drifted settings object", "dynakube", r.dkState.Instance.Name, "schema", spec.SchemaID, "scope", scope, "properties", drifted)
+	objectStatus.State = dynatracev1beta1.SettingsObjectDriftCorrected
+	objectStatus.Message = fmt.Sprintf("reverted changed properties: %s", strings.Join(drifted, ", "))
+	objectStatus.LastDriftTimestamp = r.dkState.Now.DeepCopy()
+	return objectStatus
+}
+
+// findObject returns the settings object synced before, or else the object of a single-object schema in the given scope.
+// Objects of multi-object schemas which weren't created by the operator are never adopted, as they may have been created
+// by users. Returns nil if a new object has to be created.
+func (r *Reconciler) findObject(ctx context.Context, schemaID, scope, objectID string) (*dtclient.SettingsObject, error) {
+	if objectID != "" {
+		object, err := r.dtc.GetSettingsObject(ctx, objectID)
+		var serverErr dtclient.ServerError
+		if errors.As(err, &serverErr) && serverErr.Code == http.StatusNotFound {
+			log.Info("settings object was deleted in the environment, recreating it", "schema", schemaID, "objectId", objectID)
+			return nil, nil
+		}
+		return object, err
+	}
+
+	schema, err := r.dtc.GetSettingsSchema(ctx, schemaID)
+	if err != nil {
+		return nil, err
+	}
+	if schema.MultiObject {
+		return nil, nil
+	}
+
+	objects, err := r.dtc.ListSettingsObjects(ctx, schemaID, scope)
+	if err != nil {
+		return nil, err
+	}
+	if len(objects) == 1 {
+		return &objects[0], nil
+	}
+	return nil, nil
+}
+
+func findObjectStatus(objects []dynatracev1beta1.SettingsObjectStatus, schemaID, scope, key string) *dynatracev1beta1.SettingsObjectStatus {
+	for i := range objects {
+		if objects[i].SchemaID == schemaID && objects[i].Scope == scope && objects[i].Key == key {
+			return &objects[i]
+		}
+	}
+	return nil
+}
+
+func needsClusterScope(settings []dynatracev1beta1.SettingsObjectSpec) bool {
+	for _, spec := range settings {
+		if spec.Scope == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func failed(objectStatus dynatracev1beta1.SettingsObjectStatus, message string) dynatracev1beta1.SettingsObjectStatus {
+	objectStatus.State = dynatracev1beta1.SettingsObjectFailed
+	objectStatus.Message = message
+	return objectStatus
+}
@@ -0,0 +1,302 @@
+package settings
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	"github.com/Dynatrace/dynatrace-operator/src/controllers/dynakube/status"
+	"github.com/Dynatrace/dynatrace-operator/src/dtclient"
+	"github.com/Dynatrace/dynatrace-operator/src/testing/fakedynatrace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	testName           = "dynakube"
+	testKubeSystemUUID = "kube-system-uuid"
+	testSchemaID       = "builtin:anomaly-detection.kubernetes.cluster"
+	testScope          = "environment"
+)
+
+func newTestDynakubeState(settings ...dynatracev1beta1.SettingsObjectSpec) *status.DynakubeState {
+	dkState := status.NewDynakubeState(&dynatracev1beta1.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{Name: testName},
+		Spec:       dynatracev1beta1.DynaKubeSpec{Settings: settings},
+		Status:     dynatracev1beta1.DynaKubeStatus{KubeSystemUUID: testKubeSystemUUID},
+	})
+	return dkState
+}
+
+func newTestClient(t *testing.T, server *fakedynatrace.Server) dtclient.Client {
+	config := server.TenantConfig()
+	dtc, err := dtclient.NewClient(server.APIURL(), config.APIToken, config.PaaSToken)
+	require.NoError(t, err)
+	return dtc
+}
+
+func settingsObject(scope, value string) dynatracev1beta1.SettingsObjectSpec {
+	return dynatracev1beta1.SettingsObjectSpec{
+		SchemaID: testSchemaID,
+		Scope:    scope,
+		Value:    runtime.RawExtension{Raw: []byte(value)},
+	}
+}
+
+func TestReconciler(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run(`creates, keeps and corrects settings object`, func(t *testing.T) {
+		server := fakedynatrace.NewServer(fakedynatrace.Config{})
+		defer server.Close()
+		dtc := newTestClient(t, server)
+		dkState := newTestDynakubeState(settingsObject(testScope, `{"enabled": true, "threshold": {"value": 90}}`))
+
+		upd, err := NewReconciler(dtc, dkState).Reconcile(ctx)
+
+		require.NoError(t, err)
+		assert.True(t, upd)
+		objects := dkState.Instance.Status.Settings.Objects
+		require.Len(t, objects, 1)
+		assert.Equal(t, dynatracev1beta1.SettingsObjectCreated, objects[0].State)
+		require.Len(t, server.SettingsObjects(), 1)
+		objectID := server.SettingsObjects()[0].ObjectID
+		assert.Equal(t, objectID, objects[0].ObjectID)
+
+		// tenant adds defaults for properties not in the spec, that is no drift
+		server.SetSettingsObjectValue(objectID, json.RawMessage(`{"enabled": true, "threshold": {"value": 90, "unit": "percent"}, "samples": 5}`))
+		dkState.Now = metav1.NewTime(dkState.Now.Add(SyncInterval + time.Minute))
+
+		upd, err = NewReconciler(dtc, dkState).Reconcile(ctx)
+
+		require.NoError(t, err)
+		assert.True(t, upd)
+		assert.Equal(t, dynatracev1beta1.SettingsObjectInSync, dkState.Instance.Status.Settings.Objects[0].State)
+
+		server.SetSettingsObjectValue(objectID, json.RawMessage(`{"enabled": false, "threshold": {"value": 50, "unit": "percent"}, "samples": 5}`))
+		dkState.Now = metav1.NewTime(dkState.Now.Add(SyncInterval + time.Minute))
+
+		upd, err = NewReconciler(dtc, dkState).Reconcile(ctx)
+
+		require.NoError(t, err)
+		assert.True(t, upd)
+		objectStatus := dkState.Instance.Status.Settings.Objects[0]
+		assert.Equal(t, dynatracev1beta1.SettingsObjectDriftCorrected, objectStatus.State)
+		assert.Equal(t, "reverted changed properties: enabled, threshold.value", objectStatus.Message)
+		assert.NotNil(t, objectStatus.LastDriftTimestamp)
+		assert.JSONEq(t, `{"enabled": true, "threshold": {"value": 90, "unit": "percent"}, "samples": 5}`, string(server.SettingsObjects()[0].Value))
+		assert.Len(t, server.SettingsObjects(), 1)
+	})
+	t.Run(`adopts the object of a single-object schema`, func(t *testing.T) {
+		server := fakedynatrace.NewServer(fakedynatrace.Config{})
+		defer server.Close()
+		dtc := newTestClient(t, server)
+		objectID, err := dtc.CreateSettingsObject(ctx, dtclient.SettingsObject{SchemaID: testSchemaID, Scope: testScope, Value: json.RawMessage(`{"enabled": false}`)})
+		require.NoError(t, err)
+		dkState := newTestDynakubeState(settingsObject(testScope, `{"enabled": true}`))
+
+		_, err = NewReconciler(dtc, dkState).Reconcile(ctx)
+
+		require.NoError(t, err)
+		objectStatus := dkState.Instance.Status.Settings.Objects[0]
+		assert.Equal(t, dynatracev1beta1.SettingsObjectDriftCorrected, objectStatus.State)
+		assert.Equal(t, objectID, objectStatus.ObjectID)
+		assert.Len(t, server.SettingsObjects(), 1)
+	})
+	t.Run(`doesn't adopt objects of a multi-object schema`, func(t *testing.T) {
+		server := fakedynatrace.NewServer(fakedynatrace.Config{MultiObjectSchemas: []string{testSchemaID}})
+		defer server.Close()
+		dtc := newTestClient(t, server)
+		_, err := dtc.CreateSettingsObject(ctx, dtclient.SettingsObject{SchemaID: testSchemaID, Scope: testScope, Value: json.RawMessage(`{"enabled": false}`)})
+		require.NoError(t, err)
+		dkState := newTestDynakubeState(settingsObject(testScope, `{"enabled": true}`))
+
+		_, err = NewReconciler(dtc, dkState).Reconcile(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, dynatracev1beta1.SettingsObjectCreated, dkState.Instance.Status.Settings.Objects[0].State)
+		require.Len(t, server.SettingsObjects(), 2)
+		assert.JSONEq(t, `{"enabled": false}`, string(server.SettingsObjects()[0].Value))
+	})
+	t.Run(`syncs several objects of a multi-object schema by key`, func(t *testing.T) {
+		server := fakedynatrace.NewServer(fakedynatrace.Config{MultiObjectSchemas: []string{testSchemaID}})
+		defer server.Close()
+		dtc := newTestClient(t, server)
+		cpu, memory := settingsObject(testScope, `{"metric": "cpu"}`), settingsObject(testScope, `{"metric": "memory"}`)
+		cpu.Key, memory.Key = "cpu", "memory"
+		dkState := newTestDynakubeState(cpu, memory)
+
+		_, err := NewReconciler(dtc, dkState).Reconcile(ctx)
+
+		require.NoError(t, err)
+		objects := dkState.Instance.Status.Settings.Objects
+		require.Len(t, objects, 2)
+		assert.Equal(t, "cpu", objects[0].Key)
+		assert.Equal(t, dynatracev1beta1.SettingsObjectCreated, objects[0].State)
+		assert.Equal(t, "memory", objects[1].Key)
+		assert.Equal(t, dynatracev1beta1.SettingsObjectCreated, objects[1].State)
+
+		// the objects are found by key, even if the order in the spec changed
+		dkState.Instance.Spec.Settings = []dynatracev1beta1.SettingsObjectSpec{memory, cpu}
+		_, err = NewReconciler(dtc, dkState).Reconcile(ctx)
+
+		require.NoError(t, err)
+		objects = dkState.Instance.Status.Settings.Objects
+		assert.Equal(t, dynatracev1beta1.SettingsObjectInSync, objects[0].State)
+		assert.Equal(t, dynatracev1beta1.SettingsObjectInSync, objects[1].State)
+		assert.Len(t, server.SettingsObjects(), 2)
+	})
+	t.Run(`fails single-object schemas declared more than once per scope`, func(t *testing.T) {
+		server := fakedynatrace.NewServer(fakedynatrace.Config{})
+		defer server.Close()
+		dtc := newTestClient(t, server)
+		first, second := settingsObject(testScope, `{"enabled": true}`), settingsObject(testScope, `{"enabled": false}`)
+		first.Key, second.Key = "first", "second"
+		dkState := newTestDynakubeState(first, second)
+
+		_, err := NewReconciler(dtc, dkState).Reconcile(ctx)
+
+		require.NoError(t, err)
+		for _, objectStatus := range dkState.Instance.Status.Settings.Objects {
+			assert.Equal(t, dynatracev1beta1.SettingsObjectFailed, objectStatus.State)
+			assert.Equal(t, messageSingleObjectSchemaDeclaredTwice, objectStatus.Message)
+		}
+		assert.Empty(t, server.SettingsObjects())
+	})
+	t.Run(`waits for sync interval unless spec changed`, func(t *testing.T) {
+		server := fakedynatrace.NewServer(fakedynatrace.Config{})
+		defer server.Close()
+		dtc := newTestClient(t, server)
+		dkState := newTestDynakubeState(settingsObject(testScope, `{"enabled": true}`))
+
+		_, err := NewReconciler(dtc, dkState).Reconcile(ctx)
+		require.NoError(t, err)
+		upd, err := NewReconciler(dtc, dkState).Reconcile(ctx)
+		require.NoError(t, err)
+		assert.False(t, upd)
+
+		dkState.Instance.Spec.Settings[0] = settingsObject(testScope, `{"enabled": false}`)
+		upd, err = NewReconciler(dtc, dkState).Reconcile(ctx)
+
+		require.NoError(t, err)
+		assert.True(t, upd)
+		assert.Equal(t, dynatracev1beta1.SettingsObjectDriftCorrected, dkState.Instance.Status.Settings.Objects[0].State)
+		assert.JSONEq(t, `{"enabled": false}`, string(server.SettingsObjects()[0].Value))
+	})
+	t.Run(`recreates deleted object`, func(t *testing.T) {
+		server := fakedynatrace.NewServer(fakedynatrace.Config{})
+		defer server.Close()
+		dtc := newTestClient(t, server)
+		dkState := newTestDynakubeState(settingsObject(testScope, `{"enabled": true}`))
+
+		_, err := NewReconciler(dtc, dkState).Reconcile(ctx)
+		require.NoError(t, err)
+		require.NoError(t, dtc.DeleteSettingsObject(ctx, dkState.Instance.Status.Settings.Objects[0].ObjectID))
+		dkState.Now = metav1.NewTime(dkState.Now.Add(SyncInterval + time.Minute))
+
+		_, err = NewReconciler(dtc, dkState).Reconcile(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, dynatracev1beta1.SettingsObjectCreated, dkState.Instance.Status.Settings.Objects[0].State)
+		assert.Len(t, server.SettingsObjects(), 1)
+	})
+	t.Run(`defaults to cluster scope`, func(t *testing.T) {
+		server := fakedynatrace.NewServer(fakedynatrace.Config{})
+		defer server.Close()
+		dtc := newTestClient(t, server)
+		dkState := newTestDynakubeState(settingsObject("", `{"enabled": true}`))
+
+		_, err := NewReconciler(dtc, dkState).Reconcile(ctx)
+
+		require.NoError(t, err)
+		objectStatus := dkState.Instance.Status.Settings.Objects[0]
+		assert.Equal(t, dynatracev1beta1.SettingsObjectFailed, objectStatus.State)
+		assert.Equal(t, messageClusterScopeNotFound, objectStatus.Message)
+
+		entityID := server.AddKubernetesClusterEntity(testKubeSystemUUID)
+		dkState.Now = metav1.NewTime(dkState.Now.Add(SyncInterval + time.Minute))
+
+		_, err = NewReconciler(dtc, dkState).Reconcile(ctx)
+
+		require.NoError(t, err)
+		objectStatus = dkState.Instance.Status.Settings.Objects[0]
+		assert.Equal(t, dynatracev1beta1.SettingsObjectCreated, objectStatus.State)
+		assert.Equal(t, entityID, objectStatus.Scope)
+		assert.Equal(t, entityID, server.SettingsObjects()[0].Scope)
+	})
+	t.Run(`reports failures per object`, func(t *testing.T) {
+		server := fakedynatrace.NewServer(fakedynatrace.Config{})
+		defer server.Close()
+		server.AddFailure(fakedynatrace.Failure{PathPrefix: "/v2/settings/objects", StatusCode: 400, Times: 1})
+		dtc := newTestClient(t, server)
+		dkState := newTestDynakubeState(settingsObject(testScope, `{"enabled": true}`), settingsObject("other-scope", `{"enabled": true}`))
+
+		_, err := NewReconciler(dtc, dkState).Reconcile(ctx)
+
+		require.NoError(t, err)
+		objects := dkState.Instance.Status.Settings.Objects
+		require.Len(t, objects, 2)
+		assert.Equal(t, dynatracev1beta1.SettingsObjectFailed, objects[0].State)
+		assert.Equal(t, dynatracev1beta1.SettingsObjectCreated, objects[1].State)
+		assert.Empty(t, dkState.Instance.Status.Settings.SpecHash)
+
+		// failed objects are retried without waiting for the sync interval
+		_, err = NewReconciler(dtc, dkState).Reconcile(ctx)
+
+		require.NoError(t, err)
+		objects = dkState.Instance.Status.Settings.Objects
+		assert.Equal(t, dynatracev1beta1.SettingsObjectCreated, objects[0].State)
+		assert.Equal(t, dynatracev1beta1.SettingsObjectInSync, objects[1].State)
+		assert.NotEmpty(t, dkState.Instance.Status.Settings.SpecHash)
+		assert.Len(t, server.SettingsObjects(), 2)
+	})
+	t.Run(`clears status if spec is empty`, func(t *testing.T) {
+		dkState := newTestDynakubeState()
+		dkState.Instance.Status.Settings.SpecHash = "outdated"
+
+		upd, err := NewReconciler(nil, dkState).Reconcile(ctx)
+
+		require.NoError(t, err)
+		assert.True(t, upd)
+		assert.Empty(t, dkState.Instance.Status.Settings)
+
+		upd, err = NewReconciler(nil, dkState).Reconcile(ctx)
+
+		require.NoError(t, err)
+		assert.False(t, upd)
+	})
+}
+
+func TestDriftedProperties(t *testing.T) {
+	desired := map[string]interface{}{
+		"enabled": true,
+		"nested":  map[string]interface{}{"a": 1.0, "b": "x"},
+		"list":    []interface{}{"a"},
+	}
+
+	assert.Empty(t, driftedProperties(desired, map[string]interface{}{
+		"enabled": true,
+		"nested":  map[string]interface{}{"a": 1.0, "b": "x", "c": 2.0},
+		"list":    []interface{}{"a"},
+		"other":   "value",
+	}))
+	assert.Equal(t, []string{"list", "nested.b", "nested.c"}, driftedProperties(
+		map[string]interface{}{"list": []interface{}{"a"}, "nested": map[string]interface{}{"b": "x", "c": 1.0}},
+		map[string]interface{}{"list": []interface{}{"a", "b"}, "nested": map[string]interface{}{"b": "y"}},
+	))
+	assert.Equal(t, map[string]interface{}{
+		"enabled": true,
+		"nested":  map[string]interface{}{"a": 1.0, "b": "x", "c": 2.0},
+		"list":    []interface{}{"a"},
+		"other":   "value",
+	}, mergeProperties(desired, map[string]interface{}{
+		"enabled": false,
+		"nested":  map[string]interface{}{"a": 3.0, "c": 2.0},
+		"list":    []interface{}{"b"},
+		"other":   "value",
+	}))
+}
@@ -1,43 +1,52 @@
 package status
 
 import (
+	"context"
+
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	"github.com/Dynatrace/dynatrace-operator/src/controllers/dynakube/maintenance"
 	"github.com/Dynatrace/dynatrace-operator/src/dtclient"
 	"github.com/Dynatrace/dynatrace-operator/src/kubesystem"
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type Options struct {
 	Dtc       dtclient.Client
 	ApiClient client.Reader
+	Now       metav1.Time
 }
 
-func SetDynakubeStatus(instance *dynatracev1beta1.DynaKube, opts Options) error {
+func SetDynakubeStatus(ctx context.Context, instance *dynatracev1beta1.DynaKube, opts Options) error {
 	clt := opts.ApiClient
 	dtc := opts.Dtc
+	now := opts.Now
+	if now.IsZero() {
+		now = metav1.Now()
+	}
 
 	uid, err := kubesystem.GetUID(clt)
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
-	communicationHost, err := dtc.GetCommunicationHostForClient()
+	communicationHost, err := dtc.GetCommunicationHostForClient(ctx)
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
-	connectionInfo, err := dtc.GetConnectionInfo()
+	connectionInfo, err := dtc.GetConnectionInfo(ctx)
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
-	latestAgentVersionUnixDefault, err := dtc.GetLatestAgentVersion(dtclient.OsUnix, dtclient.InstallerTypeDefault)
+	latestAgentVersionUnixDefault, err := dtc.GetLatestAgentVersion(ctx, dtclient.OsUnix, dtclient.InstallerTypeDefault)
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
-	latestAgentVersionUnixPaas, err := dtc.GetLatestAgentVersion(dtclient.OsUnix, dtclient.InstallerTypePaaS)
+	latestAgentVersionUnixPaas, err := dtc.GetLatestAgentVersion(ctx, dtclient.OsUnix, dtclient.InstallerTypePaaS)
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -51,9 +60,14 @@ func SetDynakubeStatus(instance *dynatracev1beta1.DynaKube, opts Options) error
 
 	instance.Status.KubeSystemUUID = string(uid)
 	instance.Status.CommunicationHostForClient = communicationHostStatus
+	instance.Status.ActiveAPIURL = dtc.GetActiveURL()
 	instance.Status.ConnectionInfo = connectionInfoStatus
 	instance.Status.LatestAgentVersionUnixDefault = latestAgentVersionUnixDefault
-	instance.Status.LatestAgentVersionUnixPaas = latestAgentVersionUnixPaas
+
+	// the CSI driver switches the code modules of new pods to this version, so it is only changed during maintenance windows
+	if instance.Status.LatestAgentVersionUnixPaas == "" || maintenance.UpdatesAllowed(instance, now.Time) {
+		instance.Status.LatestAgentVersionUnixPaas = latestAgentVersionUnixPaas
+	}
 
 	return nil
 }
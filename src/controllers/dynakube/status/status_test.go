@@ -1,8 +1,10 @@
 package status
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
 	"github.com/Dynatrace/dynatrace-operator/src/dtclient"
@@ -24,6 +26,8 @@ const (
 	testAnotherPort     = uint32(5678)
 	testAnotherProtocol = "test-another-protocol"
 
+	testActiveURL = "https://failover.test/api"
+
 	testError       = "test-error"
 	testVersion     = "1.217.12345-678910"
 	testVersionPaas = "2.217.12345-678910"
@@ -77,8 +81,9 @@ func TestSetDynakubeStatus(t *testing.T) {
 		dtc.On("GetLatestAgentVersion", dtclient.OsUnix, dtclient.InstallerTypeDefault).Return(testVersion, nil)
 		dtc.On("GetLatestAgentVersion", dtclient.OsUnix, dtclient.InstallerTypePaaS).Return(testVersionPaas, nil)
 		dtc.On("GetAgentTenantInfo").Return(&dtclient.AgentTenantInfo{}, nil)
+		dtc.On("GetActiveURL").Return(testActiveURL)
 
-		err := SetDynakubeStatus(instance, options)
+		err := SetDynakubeStatus(context.TODO(), instance, options)
 
 		assert.NoError(t, err)
 		assert.Equal(t, testUUID, instance.Status.KubeSystemUUID)
@@ -104,6 +109,53 @@ func TestSetDynakubeStatus(t *testing.T) {
 		assert.NotNil(t, instance.Status.LatestAgentVersionUnixDefault)
 		assert.Equal(t, testVersion, instance.Status.LatestAgentVersionUnixDefault)
 		assert.Equal(t, testVersionPaas, instance.Status.LatestAgentVersionUnixPaas)
+		assert.Equal(t, testActiveURL, instance.Status.ActiveAPIURL)
+	})
+	t.Run(`code modules version is only switched during maintenance windows`, func(t *testing.T) {
+		instance := &dynatracev1beta1.DynaKube{
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				MaintenanceWindows: []dynatracev1beta1.MaintenanceWindowSpec{
+					{Schedule: "0 0 * * *", Duration: metav1.Duration{Duration: time.Hour}},
+				},
+			},
+		}
+		dtc := &dtclient.MockDynatraceClient{}
+		clt := fake.NewClient(&v1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: kubesystem.Namespace,
+				UID:  testUUID,
+			},
+		})
+		options := Options{
+			Dtc:       dtc,
+			ApiClient: clt,
+			// the window is closed at noon
+			Now: metav1.NewTime(time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)),
+		}
+
+		dtc.On("GetCommunicationHostForClient").Return(dtclient.CommunicationHost{}, nil)
+		dtc.On("GetConnectionInfo").Return(dtclient.ConnectionInfo{}, nil)
+		dtc.On("GetLatestAgentVersion", dtclient.OsUnix, dtclient.InstallerTypeDefault).Return(testVersion, nil)
+		dtc.On("GetLatestAgentVersion", dtclient.OsUnix, dtclient.InstallerTypePaaS).Return(testVersionPaas, nil)
+		dtc.On("GetActiveURL").Return(testActiveURL)
+
+		err := SetDynakubeStatus(context.TODO(), instance, options)
+
+		assert.NoError(t, err)
+		assert.Equal(t, testVersionPaas, instance.Status.LatestAgentVersionUnixPaas)
+
+		instance.Status.LatestAgentVersionUnixPaas = "1.0.0"
+		err = SetDynakubeStatus(context.TODO(), instance, options)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1.0.0", instance.Status.LatestAgentVersionUnixPaas)
+		assert.Equal(t, testVersion, instance.Status.LatestAgentVersionUnixDefault)
+
+		options.Now = metav1.NewTime(time.Date(2022, 1, 2, 0, 30, 0, 0, time.UTC))
+		err = SetDynakubeStatus(context.TODO(), instance, options)
+
+		assert.NoError(t, err)
+		assert.Equal(t, testVersionPaas, instance.Status.LatestAgentVersionUnixPaas)
 	})
 	t.Run(`error querying kube system uid`, func(t *testing.T) {
 		instance := &dynatracev1beta1.DynaKube{}
@@ -114,7 +166,7 @@ func TestSetDynakubeStatus(t *testing.T) {
 			ApiClient: clt,
 		}
 
-		err := SetDynakubeStatus(instance, options)
+		err := SetDynakubeStatus(context.TODO(), instance, options)
 		assert.EqualError(t, err, "namespaces \"kube-system\" not found")
 	})
 	t.Run(`error querying communication host for client`, func(t *testing.T) {
@@ -133,7 +185,7 @@ func TestSetDynakubeStatus(t *testing.T) {
 
 		dtc.On("GetCommunicationHostForClient").Return(dtclient.CommunicationHost{}, fmt.Errorf(testError))
 
-		err := SetDynakubeStatus(instance, options)
+		err := SetDynakubeStatus(context.TODO(), instance, options)
 		assert.EqualError(t, err, testError)
 	})
 	t.Run(`error querying connection info`, func(t *testing.T) {
@@ -158,7 +210,7 @@ func TestSetDynakubeStatus(t *testing.T) {
 
 		dtc.On("GetConnectionInfo").Return(dtclient.ConnectionInfo{}, fmt.Errorf(testError))
 
-		err := SetDynakubeStatus(instance, options)
+		err := SetDynakubeStatus(context.TODO(), instance, options)
 		assert.EqualError(t, err, testError)
 	})
 	t.Run(`error querying latest agent version for unix / default`, func(t *testing.T) {
@@ -199,7 +251,7 @@ func TestSetDynakubeStatus(t *testing.T) {
 
 		dtc.On("GetLatestAgentVersion", dtclient.OsUnix, dtclient.InstallerTypeDefault).Return("", fmt.Errorf(testError))
 
-		err := SetDynakubeStatus(instance, options)
+		err := SetDynakubeStatus(context.TODO(), instance, options)
 		assert.EqualError(t, err, testError)
 	})
 	t.Run(`error querying latest agent version for unix / paas`, func(t *testing.T) {
@@ -241,7 +293,7 @@ func TestSetDynakubeStatus(t *testing.T) {
 		dtc.On("GetLatestAgentVersion", dtclient.OsUnix, dtclient.InstallerTypeDefault).Return(testVersion, nil)
 		dtc.On("GetLatestAgentVersion", dtclient.OsUnix, dtclient.InstallerTypePaaS).Return("", fmt.Errorf(testError))
 
-		err := SetDynakubeStatus(instance, options)
+		err := SetDynakubeStatus(context.TODO(), instance, options)
 		assert.EqualError(t, err, testError)
 	})
 }
@@ -8,6 +8,7 @@ import (
 
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
 	"github.com/Dynatrace/dynatrace-operator/src/controllers/dynakube/dtversion"
+	"github.com/Dynatrace/dynatrace-operator/src/controllers/dynakube/maintenance"
 	"github.com/Dynatrace/dynatrace-operator/src/controllers/dynakube/status"
 	"github.com/Dynatrace/dynatrace-operator/src/dtclient"
 	"github.com/pkg/errors"
@@ -31,21 +32,28 @@ func ReconcileVersions(
 	upd := false
 	dk := dkState.Instance
 
+	// outside of the maintenance windows only missing versions are determined, e.g. for new DynaKubes
+	updatesAllowed := maintenance.UpdatesAllowed(dk, dkState.Now.Time)
+
 	needsOneAgentUpdate := dk.NeedsOneAgent() &&
 		dkState.IsOutdated(dk.Status.OneAgent.LastUpdateProbeTimestamp, ProbeThreshold) &&
-		dk.ShouldAutoUpdateOneAgent()
+		dk.ShouldAutoUpdateOneAgent() &&
+		(updatesAllowed || dk.Status.OneAgent.Version == "")
 
 	needsActiveGateUpdate := dk.NeedsActiveGate() &&
 		!dk.FeatureDisableActiveGateUpdates() &&
-		dkState.IsOutdated(dk.Status.ActiveGate.LastUpdateProbeTimestamp, ProbeThreshold)
+		dkState.IsOutdated(dk.Status.ActiveGate.LastUpdateProbeTimestamp, ProbeThreshold) &&
+		(updatesAllowed || dk.Status.ActiveGate.Version == "")
 
 	needsEecUpdate := dk.NeedsStatsd() &&
 		!dk.FeatureDisableActiveGateUpdates() &&
-		dkState.IsOutdated(dk.Status.ExtensionController.LastUpdateProbeTimestamp, ProbeThreshold)
+		dkState.IsOutdated(dk.Status.ExtensionController.LastUpdateProbeTimestamp, ProbeThreshold) &&
+		(updatesAllowed || dk.Status.ExtensionController.Version == "")
 
 	needsStatsdUpdate := dk.NeedsStatsd() &&
 		!dk.FeatureDisableActiveGateUpdates() &&
-		dkState.IsOutdated(dk.Status.Statsd.LastUpdateProbeTimestamp, ProbeThreshold)
+		dkState.IsOutdated(dk.Status.Statsd.LastUpdateProbeTimestamp, ProbeThreshold) &&
+		(updatesAllowed || dk.Status.Statsd.Version == "")
 
 	if !(needsActiveGateUpdate || needsOneAgentUpdate || needsEecUpdate || needsStatsdUpdate) {
 		return upd, nil
@@ -68,6 +76,13 @@ func ReconcileVersions(
 			_ = os.Remove(path.Join(dtversion.TmpCAPath, dtversion.TmpCAName))
 		}()
 	}
+	if dk.Spec.ClientCertificate != "" {
+		dockerCfg.UseClientCertificate = saveClientCertificate(cl, *dk)
+		defer func() {
+			_ = os.Remove(path.Join(dtversion.TmpCAPath, dtversion.TmpClientCertName))
+			_ = os.Remove(path.Join(dtversion.TmpCAPath, dtversion.TmpClientKeyName))
+		}()
+	}
 	upd = true // updateImageVersion() always updates the status
 
 	if needsActiveGateUpdate {
@@ -89,9 +104,18 @@ func ReconcileVersions(
 	}
 
 	if needsOneAgentUpdate {
+		previous := dk.Status.OneAgent.VersionStatus
 		if err := updateImageVersion(dkState, dk.ImmutableOneAgentImage(), &dk.Status.OneAgent.VersionStatus, &dockerCfg, verProvider, false); err != nil {
 			log.Error(err, "failed to update OneAgent image version")
 		}
+
+		// a version which failed the staged rollout is not rolled out again until a newer one is released
+		if rollout := dk.Status.OneAgent.Rollout; rollout != nil && rollout.FailedVersion != "" &&
+			previous.Version != "" && dk.Status.OneAgent.Version == rollout.FailedVersion {
+			log.Info("skipping OneAgent version which failed the staged rollout", "version", rollout.FailedVersion)
+			dk.Status.OneAgent.Version = previous.Version
+			dk.Status.OneAgent.ImageHash = previous.ImageHash
+		}
 	}
 
 	return upd, nil
@@ -115,6 +139,29 @@ func saveCustomCAs(cl client.Client, dk dynatracev1beta1.DynaKube) bool {
 	return true
 }
 
+func saveClientCertificate(cl client.Client, dk dynatracev1beta1.DynaKube) bool {
+	certSecret := &corev1.Secret{}
+	if err := cl.Get(context.TODO(), client.ObjectKey{Namespace: dk.Namespace, Name: dk.Spec.ClientCertificate}, certSecret); err != nil {
+		log.Error(err, "failed to load client certificate")
+		return false
+	}
+	cert, key := certSecret.Data[dtclient.ClientCertificateSecretCertKey], certSecret.Data[dtclient.ClientCertificateSecretKeyKey]
+	if len(cert) == 0 || len(key) == 0 {
+		log.Info("failed to extract client certificate secret fields: missing field tls.crt or tls.key")
+		return false
+	}
+	_ = os.MkdirAll(dtversion.TmpCAPath, 0755)
+	if err := os.WriteFile(path.Join(dtversion.TmpCAPath, dtversion.TmpClientCertName), cert, 0600); err != nil {
+		log.Error(err, "failed to save client certificate")
+		return false
+	}
+	if err := os.WriteFile(path.Join(dtversion.TmpCAPath, dtversion.TmpClientKeyName), key, 0600); err != nil {
+		log.Error(err, "failed to save client certificate key")
+		return false
+	}
+	return true
+}
+
 func updateImageVersion(
 	dkState *status.DynakubeState,
 	img string,
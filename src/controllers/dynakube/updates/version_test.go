@@ -157,6 +157,82 @@ func TestReconcile_UpdateImageVersion(t *testing.T) {
 			assertVersionStatusEquals(t, registry, statsdImagePath, now, &status.Statsd)
 		}
 	})
+
+	t.Run("updates are postponed until the maintenance window opens", func(t *testing.T) {
+		dk := dkTemplate.DeepCopy()
+		dk.Spec.MaintenanceWindows = []dynatracev1beta1.MaintenanceWindowSpec{
+			{Schedule: "0 22 * * 6", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+		}
+		dkState, fakeClient, _ := testInitDynakubeState(t, dk)
+		status := &dkState.Instance.Status
+		registry := newFakeRegistry(map[string]string{
+			agImagePath:       "1.0.0",
+			eecImagePath:      "1.0.0",
+			statsdImagePath:   "1.0.0",
+			oneAgentImagePath: "1.0.0",
+		})
+		initialRegistry := newFakeRegistry(registry.imageVersions)
+
+		// missing versions are determined outside of maintenance windows
+		dkState.Now = metav1.NewTime(time.Date(2022, 3, 14, 10, 0, 0, 0, time.UTC))
+		now := dkState.Now
+		{
+			upd, err := ReconcileVersions(ctx, dkState, fakeClient, registry.ImageVersionExt)
+			assert.NoError(t, err)
+			assert.True(t, upd)
+			assertVersionStatusEquals(t, registry, agImagePath, now, &status.ActiveGate)
+			assertVersionStatusEquals(t, registry, oneAgentImagePath, now, &status.OneAgent)
+		}
+
+		registry.SetVersion(agImagePath, "1.0.1").SetVersion(eecImagePath, "1.0.1")
+		testChangeTime(t, dkState, 15*time.Minute+1*time.Second)
+		{
+			upd, err := ReconcileVersions(ctx, dkState, fakeClient, registry.ImageVersionExt)
+			assert.NoError(t, err)
+			assert.False(t, upd)
+			assertVersionStatusEquals(t, initialRegistry, agImagePath, now, &status.ActiveGate)
+			assertVersionStatusEquals(t, initialRegistry, eecImagePath, now, &status.ExtensionController)
+		}
+
+		dkState.Now = metav1.NewTime(time.Date(2022, 3, 19, 22, 30, 0, 0, time.UTC))
+		now = dkState.Now
+		{
+			upd, err := ReconcileVersions(ctx, dkState, fakeClient, registry.ImageVersionExt)
+			assert.NoError(t, err)
+			assert.True(t, upd)
+			assertVersionStatusEquals(t, registry, agImagePath, now, &status.ActiveGate)
+			assertVersionStatusEquals(t, registry, eecImagePath, now, &status.ExtensionController)
+		}
+	})
+	t.Run("oneagent version which failed the staged rollout is skipped", func(t *testing.T) {
+		const (
+			previousVersion = "1.200.0.20220101-000000"
+			failedVersion   = "1.210.0.20220201-000000"
+			newerVersion    = "1.220.0.20220301-000000"
+		)
+		dk := dkTemplate.DeepCopy()
+		dkState, fakeClient, _ := testInitDynakubeState(t, dk)
+		status := &dkState.Instance.Status
+		status.OneAgent.Version = previousVersion
+		status.OneAgent.ImageHash = "previous-hash"
+		status.OneAgent.Rollout = &dynatracev1beta1.OneAgentRolloutStatus{
+			Phase:         dynatracev1beta1.RolloutFailed,
+			FailedVersion: failedVersion,
+		}
+		registry := newFakeRegistry(map[string]string{oneAgentImagePath: failedVersion})
+
+		_, err := ReconcileVersions(ctx, dkState, fakeClient, registry.ImageVersionExt)
+		assert.NoError(t, err)
+		assert.Equal(t, previousVersion, status.OneAgent.Version)
+		assert.Equal(t, "previous-hash", status.OneAgent.ImageHash)
+
+		registry.SetVersion(oneAgentImagePath, newerVersion)
+		testChangeTime(t, dkState, 15*time.Minute+1*time.Second)
+
+		_, err = ReconcileVersions(ctx, dkState, fakeClient, registry.ImageVersionExt)
+		assert.NoError(t, err)
+		assert.Equal(t, newerVersion, status.OneAgent.Version)
+	})
 }
 
 type fakeRegistry struct {
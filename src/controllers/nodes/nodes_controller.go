@@ -36,7 +36,7 @@ func nodeDeletionPredicate(controller *NodesController) predicate.Predicate {
 	return predicate.Funcs{
 		DeleteFunc: func(deleteEvent event.DeleteEvent) bool {
 			node := deleteEvent.Object.GetName()
-			err := controller.reconcileNodeDeletion(node)
+			err := controller.reconcileNodeDeletion(context.TODO(), node)
 			if err != nil {
 				log.Error(err, "error while deleting node", "node", node)
 			}
@@ -116,7 +116,7 @@ func (controller *NodesController) Reconcile(ctx context.Context, request reconc
 				nodeName:   nodeName,
 			}
 
-			if err := controller.markForTermination(dynakube, cachedNodeData); err != nil {
+			if err := controller.markForTermination(ctx, dynakube, cachedNodeData); err != nil {
 				return reconcile.Result{}, err
 			}
 		}
@@ -124,7 +124,7 @@ func (controller *NodesController) Reconcile(ctx context.Context, request reconc
 
 	// check node cache for outdated nodes and remove them, to keep cache clean
 	if nodeCache.IsCacheOutdated() {
-		if err := controller.handleOutdatedCache(nodeCache); err != nil {
+		if err := controller.handleOutdatedCache(ctx, nodeCache); err != nil {
 			return reconcile.Result{}, err
 		}
 		nodeCache.UpdateTimestamp()
@@ -132,7 +132,7 @@ func (controller *NodesController) Reconcile(ctx context.Context, request reconc
 	return reconcile.Result{}, controller.updateCache(nodeCache, ctx)
 }
 
-func (controller *NodesController) reconcileNodeDeletion(nodeName string) error {
+func (controller *NodesController) reconcileNodeDeletion(ctx context.Context, nodeName string) error {
 	nodeCache, err := controller.getCache()
 	if err != nil {
 		return err
@@ -159,13 +159,13 @@ func (controller *NodesController) reconcileNodeDeletion(nodeName string) error
 			nodeName:   nodeName,
 		}
 
-		if err := controller.markForTermination(dynakube, cachedNodeData); err != nil {
+		if err := controller.markForTermination(ctx, dynakube, cachedNodeData); err != nil {
 			return err
 		}
 	}
 
 	nodeCache.Delete(nodeName)
-	if err := controller.updateCache(nodeCache, context.TODO()); err != nil {
+	if err := controller.updateCache(nodeCache, ctx); err != nil {
 		return err
 	}
 	return nil
@@ -220,9 +220,9 @@ func (controller *NodesController) updateCache(nodeCache *Cache, ctx context.Con
 	return nil
 }
 
-func (controller *NodesController) handleOutdatedCache(nodeCache *Cache) error {
+func (controller *NodesController) handleOutdatedCache(ctx context.Context, nodeCache *Cache) error {
 	var nodeLst corev1.NodeList
-	if err := controller.client.List(context.TODO(), &nodeLst); err != nil {
+	if err := controller.client.List(ctx, &nodeLst); err != nil {
 		return err
 	}
 
@@ -245,7 +245,7 @@ func (controller *NodesController) handleOutdatedCache(nodeCache *Cache) error {
 		// if node is not in cluster -> probably deleted
 		if !cachedNodeInCluster {
 			log.Info("Removing unfound cached node from cluster", "node", cachedNodeName)
-			err := controller.reconcileNodeDeletion(cachedNodeName)
+			err := controller.reconcileNodeDeletion(ctx, cachedNodeName)
 			if err != nil {
 				return err
 			}
@@ -269,8 +269,8 @@ func (controller *NodesController) isNodeDeleteable(cachedNode CacheEntry) bool
 	return false
 }
 
-func (controller *NodesController) sendMarkedForTermination(dynakubeInstance *dynatracev1beta1.DynaKube, cachedNode CacheEntry) error {
-	dtp, err := dynakube.NewDynatraceClientProperties(context.TODO(), controller.client, *dynakubeInstance)
+func (controller *NodesController) sendMarkedForTermination(ctx context.Context, dynakubeInstance *dynatracev1beta1.DynaKube, cachedNode CacheEntry) error {
+	dtp, err := dynakube.NewDynatraceClientProperties(ctx, controller.client, *dynakubeInstance)
 	if err != nil {
 		log.Error(err, err.Error())
 	}
@@ -280,7 +280,7 @@ func (controller *NodesController) sendMarkedForTermination(dynakubeInstance *dy
 		return err
 	}
 
-	entityID, err := dtc.GetEntityIDForIP(cachedNode.IPAddress)
+	entityID, err := dtc.GetEntityIDForIP(ctx, cachedNode.IPAddress)
 	if err != nil {
 		log.Info("failed to send mark for termination event",
 			"reason", "failed to determine entity id", "dynakube", dynakubeInstance.Name, "nodeIP", cachedNode.IPAddress, "cause", err)
@@ -289,7 +289,7 @@ func (controller *NodesController) sendMarkedForTermination(dynakubeInstance *dy
 	}
 
 	ts := uint64(cachedNode.LastSeen.Add(-10*time.Minute).UnixNano()) / uint64(time.Millisecond)
-	return dtc.SendEvent(&dtclient.EventData{
+	return dtc.SendEvent(ctx, &dtclient.EventData{
 		EventType:     dtclient.MarkedForTerminationEvent,
 		Source:        "Dynatrace Operator",
 		Description:   "Kubernetes node cordoned. Node might be drained or terminated.",
@@ -301,7 +301,11 @@ func (controller *NodesController) sendMarkedForTermination(dynakubeInstance *dy
 	})
 }
 
-func (controller *NodesController) markForTermination(dynakube *dynatracev1beta1.DynaKube, cachedNodeData CachedNodeInfo) error {
+func (controller *NodesController) markForTermination(ctx context.Context, dynakube *dynatracev1beta1.DynaKube, cachedNodeData CachedNodeInfo) error {
+	if dynakube.Spec.Paused {
+		log.Info("reconciliation is paused, node is not marked for termination", "dynakube", dynakube.Name, "node", cachedNodeData.nodeName)
+		return nil
+	}
 	if !controller.isMarkableForTermination(&cachedNodeData.cachedNode) {
 		return nil
 	}
@@ -313,7 +317,7 @@ func (controller *NodesController) markForTermination(dynakube *dynatracev1beta1
 	log.Info("sending mark for termination event to dynatrace server", "dynakube", dynakube.Name, "ip", cachedNodeData.cachedNode.IPAddress,
 		"node", cachedNodeData.nodeName)
 
-	return controller.sendMarkedForTermination(dynakube, cachedNodeData.cachedNode)
+	return controller.sendMarkedForTermination(ctx, dynakube, cachedNodeData.cachedNode)
 }
 
 func (controller *NodesController) isUnschedulable(node *corev1.Node) bool {
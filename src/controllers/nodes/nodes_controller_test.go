@@ -54,7 +54,7 @@ func TestNodesReconciler_DeleteNode(t *testing.T) {
 	ctrl := createDefaultReconciler(fakeClient, dtClient)
 
 	reconcileAllNodes(t, ctrl, fakeClient)
-	assert.NoError(t, ctrl.reconcileNodeDeletion("node1"))
+	assert.NoError(t, ctrl.reconcileNodeDeletion(context.TODO(), "node1"))
 
 	var cm corev1.ConfigMap
 	require.NoError(t, fakeClient.Get(context.TODO(), testCacheKey, &cm))
@@ -83,7 +83,7 @@ func TestNodesReconciler_NodeNotFound(t *testing.T) {
 	require.NoError(t, fakeClient.Get(context.TODO(), client.ObjectKey{Name: "node2"}, &node2))
 	require.NoError(t, fakeClient.Delete(context.TODO(), &node2))
 
-	assert.NoError(t, ctrl.reconcileNodeDeletion("node2"))
+	assert.NoError(t, ctrl.reconcileNodeDeletion(context.TODO(), "node2"))
 
 	var cm corev1.ConfigMap
 	require.NoError(t, fakeClient.Get(context.TODO(), testCacheKey, &cm))
@@ -145,7 +145,7 @@ func Test_RemoveNode_ServerError(t *testing.T) {
 
 	reconcileAllNodes(t, ctrl, fakeClient)
 
-	assert.Error(t, ctrl.reconcileNodeDeletion("node1"))
+	assert.Error(t, ctrl.reconcileNodeDeletion(context.TODO(), "node1"))
 
 }
 
@@ -0,0 +1,16 @@
+package storageversion
+
+import (
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/src/logger"
+)
+
+const (
+	crdName       = "dynakubes.dynatrace.com"
+	retryInterval = time.Minute
+)
+
+var (
+	log = logger.NewDTLogger().WithName("storage-version-migration")
+)
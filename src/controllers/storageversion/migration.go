@@ -0,0 +1,139 @@
+package storageversion
+
+import (
+	"context"
+	"time"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	"github.com/pkg/errors"
+	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// Migration rewrites the DynaKubes stored in an older version of the CRD, e.g. v1beta1 after upgrading to the
+// v1beta2 storage version, and then drops the older versions from the stored versions in the CRD status,
+// so they can be removed from the CRD in a future release.
+// The operator only manages DynaKubes in its own namespace, so DynaKubes in other namespaces are not migrated and the
+// stored versions are kept as long as any of them exists.
+type Migration struct {
+	client    client.Client
+	apiReader client.Reader
+	namespace string
+}
+
+func Add(mgr manager.Manager, ns string) error {
+	return mgr.Add(NewMigration(mgr.GetClient(), mgr.GetAPIReader(), ns))
+}
+
+func NewMigration(clt client.Client, apiReader client.Reader, namespace string) *Migration {
+	return &Migration{
+		client:    clt,
+		apiReader: apiReader,
+		namespace: namespace,
+	}
+}
+
+// NeedLeaderElection makes sure only one operator instance migrates
+func (migration *Migration) NeedLeaderElection() bool {
+	return true
+}
+
+// Start retries the migration until it succeeds, e.g. the conversion webhook may not be available yet.
+// Errors are only logged, so they don't stop the manager.
+func (migration *Migration) Start(ctx context.Context) error {
+	ticker := time.NewTicker(retryInterval)
+	defer ticker.Stop()
+
+	for {
+		err := migration.Migrate(ctx)
+		if err == nil {
+			return nil
+		}
+		log.Info("storage version migration failed, retrying", "error", err.Error())
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (migration *Migration) Migrate(ctx context.Context) error {
+	var crd apiv1.CustomResourceDefinition
+	if err := migration.apiReader.Get(ctx, types.NamespacedName{Name: crdName}, &crd); err != nil {
+		return errors.WithStack(err)
+	}
+
+	storageVersion := getStorageVersion(crd)
+	if storageVersion == "" {
+		return errors.New("crd has no storage version")
+	}
+	if isMigrated(crd, storageVersion) {
+		log.Info("all dynakubes are stored in the storage version", "version", storageVersion)
+		return nil
+	}
+
+	foreignDynaKubes, err := migration.rewriteDynaKubes(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(foreignDynaKubes) > 0 {
+		log.Info("keeping the stored versions, as dynakubes outside of the operator namespace can't be migrated",
+			"storedVersions", crd.Status.StoredVersions, "dynakubes", foreignDynaKubes)
+		return nil
+	}
+
+	crd.Status.StoredVersions = []string{storageVersion}
+	if err := migration.client.Status().Update(ctx, &crd); err != nil {
+		return errors.WithStack(err)
+	}
+
+	log.Info("migrated dynakubes to the storage version", "version", storageVersion)
+	return nil
+}
+
+// rewriteDynaKubes updates every DynaKube in the operator namespace without changes, which makes the api server store
+// it in the current storage version. The DynaKubes are listed in the whole cluster, and the ones outside of the
+// operator namespace, e.g. left from an earlier installation, are returned. Fails if they can't be listed,
+// as dropping the stored versions could make them unreadable.
+func (migration *Migration) rewriteDynaKubes(ctx context.Context) ([]string, error) {
+	var dynakubeList dynatracev1beta1.DynaKubeList
+	if err := migration.apiReader.List(ctx, &dynakubeList); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var foreignDynaKubes []string
+	for i := range dynakubeList.Items {
+		dynakube := &dynakubeList.Items[i]
+		if dynakube.Namespace != migration.namespace {
+			foreignDynaKubes = append(foreignDynaKubes, dynakube.Namespace+"/"+dynakube.Name)
+			continue
+		}
+
+		err := migration.client.Update(ctx, dynakube)
+		// on conflicts the DynaKube has been written in the meantime, so it is stored in the current storage version
+		if err != nil && !k8serrors.IsConflict(err) && !k8serrors.IsNotFound(err) {
+			return nil, errors.WithStack(err)
+		}
+	}
+	return foreignDynaKubes, nil
+}
+
+func getStorageVersion(crd apiv1.CustomResourceDefinition) string {
+	for _, version := range crd.Spec.Versions {
+		if version.Storage {
+			return version.Name
+		}
+	}
+	return ""
+}
+
+func isMigrated(crd apiv1.CustomResourceDefinition, storageVersion string) bool {
+	storedVersions := crd.Status.StoredVersions
+	return len(storedVersions) == 1 && storedVersions[0] == storageVersion
+}
@@ -0,0 +1,117 @@
+package storageversion
+
+import (
+	"context"
+	"testing"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	"github.com/Dynatrace/dynatrace-operator/src/scheme/fake"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	testNamespace = "dynatrace"
+	testName      = "dynakube"
+)
+
+func newTestCRD(storedVersions ...string) *apiv1.CustomResourceDefinition {
+	return &apiv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: crdName,
+		},
+		Spec: apiv1.CustomResourceDefinitionSpec{
+			Versions: []apiv1.CustomResourceDefinitionVersion{
+				{Name: "v1beta1", Served: true},
+				{Name: "v1beta2", Served: true, Storage: true},
+			},
+		},
+		Status: apiv1.CustomResourceDefinitionStatus{
+			StoredVersions: storedVersions,
+		},
+	}
+}
+
+func getStoredVersions(t *testing.T, clt client.Client) []string {
+	var crd apiv1.CustomResourceDefinition
+	require.NoError(t, clt.Get(context.TODO(), types.NamespacedName{Name: crdName}, &crd))
+	return crd.Status.StoredVersions
+}
+
+// forbiddenListReader denies listing, like the api server does without cluster wide permissions
+type forbiddenListReader struct {
+	client.Reader
+}
+
+func (reader *forbiddenListReader) List(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+	return k8serrors.NewForbidden(schema.GroupResource{Group: "dynatrace.com", Resource: "dynakubes"}, "", errors.New("forbidden"))
+}
+
+func TestMigration_Migrate(t *testing.T) {
+	t.Run(`rewrites dynakubes and drops old stored versions`, func(t *testing.T) {
+		dynakube := &dynatracev1beta1.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: testNamespace},
+		}
+		clt := fake.NewClient(newTestCRD("v1beta1", "v1beta2"), dynakube)
+		var before dynatracev1beta1.DynaKube
+		require.NoError(t, clt.Get(context.TODO(), client.ObjectKeyFromObject(dynakube), &before))
+
+		err := NewMigration(clt, clt, testNamespace).Migrate(context.TODO())
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"v1beta2"}, getStoredVersions(t, clt))
+		var after dynatracev1beta1.DynaKube
+		require.NoError(t, clt.Get(context.TODO(), client.ObjectKeyFromObject(dynakube), &after))
+		assert.NotEqual(t, before.ResourceVersion, after.ResourceVersion)
+	})
+	t.Run(`keeps stored versions if dynakubes outside of the namespace exist`, func(t *testing.T) {
+		dynakube := &dynatracev1beta1.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: testNamespace},
+		}
+		foreignDynakube := &dynatracev1beta1.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: "other-namespace"},
+		}
+		clt := fake.NewClient(newTestCRD("v1beta1", "v1beta2"), dynakube, foreignDynakube)
+		var before dynatracev1beta1.DynaKube
+		require.NoError(t, clt.Get(context.TODO(), client.ObjectKeyFromObject(dynakube), &before))
+
+		err := NewMigration(clt, clt, testNamespace).Migrate(context.TODO())
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"v1beta1", "v1beta2"}, getStoredVersions(t, clt))
+		var after dynatracev1beta1.DynaKube
+		require.NoError(t, clt.Get(context.TODO(), client.ObjectKeyFromObject(dynakube), &after))
+		assert.NotEqual(t, before.ResourceVersion, after.ResourceVersion)
+	})
+	t.Run(`fails if dynakubes can't be listed`, func(t *testing.T) {
+		clt := fake.NewClient(newTestCRD("v1beta1", "v1beta2"))
+		apiReader := &forbiddenListReader{Reader: clt}
+
+		err := NewMigration(clt, apiReader, testNamespace).Migrate(context.TODO())
+
+		assert.True(t, k8serrors.IsForbidden(errors.Cause(err)))
+		assert.Equal(t, []string{"v1beta1", "v1beta2"}, getStoredVersions(t, clt))
+	})
+	t.Run(`nothing to do if already migrated`, func(t *testing.T) {
+		clt := fake.NewClient(newTestCRD("v1beta2"))
+
+		err := NewMigration(clt, clt, testNamespace).Migrate(context.TODO())
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"v1beta2"}, getStoredVersions(t, clt))
+	})
+	t.Run(`error if crd is missing`, func(t *testing.T) {
+		clt := fake.NewClient()
+
+		err := NewMigration(clt, clt, testNamespace).Migrate(context.TODO())
+
+		assert.Error(t, err)
+	})
+}
@@ -1,9 +1,8 @@
 package dtclient
 
 import (
+	"context"
 	"encoding/json"
-
-	"github.com/pkg/errors"
 )
 
 type ActiveGateTenantInfo struct {
@@ -11,26 +10,14 @@ type ActiveGateTenantInfo struct {
 	Endpoints string `json:"communicationEndpoints"`
 }
 
-func (dtc *dynatraceClient) GetActiveGateTenantInfo() (*ActiveGateTenantInfo, error) {
-	response, err := dtc.makeRequest(
+func (dtc *dynatraceClient) GetActiveGateTenantInfo(ctx context.Context) (*ActiveGateTenantInfo, error) {
+	data, err := dtc.makeCachedRequest(
+		ctx,
 		dtc.getActiveGateConnectionInfoUrl(),
 		dynatracePaaSToken,
 	)
-
 	if err != nil {
-		return nil, errors.WithStack(err)
-	}
-
-	defer func() {
-		err := response.Body.Close()
-		if err != nil {
-			log.Error(err, err.Error())
-		}
-	}()
-
-	data, err := dtc.getServerResponseData(response)
-	if err != nil {
-		return nil, dtc.handleErrorResponseFromAPI(data, response.StatusCode)
+		return nil, err
 	}
 
 	tenantInfo, err := dtc.readResponseForActiveGateTenantInfo(data)
@@ -1,6 +1,7 @@
 package dtclient
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -18,7 +19,7 @@ func TestGetActiveGateTenantInfoFaulty1(t *testing.T) {
 		dynatraceServer, dynatraceClient := createTestDynatraceClient(t, tenantServerHandler(activeGateConnectionInfoEndpoint, agTenantResponse), "")
 		defer dynatraceServer.Close()
 
-		tenantInfo, err := dynatraceClient.GetActiveGateTenantInfo()
+		tenantInfo, err := dynatraceClient.GetActiveGateTenantInfo(context.TODO())
 		assert.NoError(t, err)
 		assert.NotNil(t, tenantInfo)
 
@@ -28,7 +29,7 @@ func TestGetActiveGateTenantInfoFaulty1(t *testing.T) {
 		dynatraceServer, dynatraceClient := createTestDynatraceClient(t, tenantServerHandler(activeGateConnectionInfoEndpoint, agTenantResponse), "nz")
 		defer dynatraceServer.Close()
 
-		tenantInfo, err := dynatraceClient.GetActiveGateTenantInfo()
+		tenantInfo, err := dynatraceClient.GetActiveGateTenantInfo(context.TODO())
 		assert.NoError(t, err)
 		assert.NotNil(t, tenantInfo)
 
@@ -38,7 +39,7 @@ func TestGetActiveGateTenantInfoFaulty1(t *testing.T) {
 		dynatraceServer, dynatraceClient := createTestDynatraceClient(t, tenantServerHandler(activeGateConnectionInfoEndpoint, agTenantResponse), "")
 		defer dynatraceServer.Close()
 
-		tenantInfo, err := dynatraceClient.GetActiveGateTenantInfo()
+		tenantInfo, err := dynatraceClient.GetActiveGateTenantInfo(context.TODO())
 		assert.NoError(t, err)
 		assert.NotNil(t, tenantInfo)
 
@@ -48,7 +49,7 @@ func TestGetActiveGateTenantInfoFaulty1(t *testing.T) {
 		faultyDynatraceServer, faultyDynatraceClient := createTestDynatraceClient(t, tenantMalformedJson(activeGateConnectionInfoEndpoint), "")
 		defer faultyDynatraceServer.Close()
 
-		tenantInfo, err := faultyDynatraceClient.GetActiveGateTenantInfo()
+		tenantInfo, err := faultyDynatraceClient.GetActiveGateTenantInfo(context.TODO())
 		assert.Error(t, err)
 		assert.Nil(t, tenantInfo)
 
@@ -58,7 +59,7 @@ func TestGetActiveGateTenantInfoFaulty1(t *testing.T) {
 		faultyDynatraceServer, faultyDynatraceClient := createTestDynatraceClient(t, tenantInternalServerError(activeGateConnectionInfoEndpoint), "")
 		defer faultyDynatraceServer.Close()
 
-		tenantInfo, err := faultyDynatraceClient.GetActiveGateTenantInfo()
+		tenantInfo, err := faultyDynatraceClient.GetActiveGateTenantInfo(context.TODO())
 		assert.Error(t, err)
 		assert.Nil(t, tenantInfo)
 
@@ -0,0 +1,305 @@
+package dtclient
+
+import (
+	"bufio"
+	"context"
+	"crypto/md5" //nolint:gosec // md5 is what the installer endpoint provides
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	maxDownloadResumes  = 5
+	downloadBufferSize  = 32 * 1024
+	checksumSidecarExt  = ".sha256"
+	maxSidecarBodyBytes = 1024
+)
+
+// DownloadVerificationError is returned when a downloaded agent package does not match
+// the size or checksum announced by the server.
+type DownloadVerificationError struct {
+	Property string
+	Expected string
+	Actual   string
+}
+
+func (e *DownloadVerificationError) Error() string {
+	return fmt.Sprintf("verification of downloaded agent package failed: expected %s %s, got %s", e.Property, e.Expected, e.Actual)
+}
+
+// IsDownloadVerificationError returns true if err or any error it wraps is a DownloadVerificationError.
+func IsDownloadVerificationError(err error) bool {
+	var verificationErr *DownloadVerificationError
+	return errors.As(err, &verificationErr)
+}
+
+// interruptedDownloadError marks errors that happened while reading the response body, which can be resumed.
+type interruptedDownloadError struct {
+	cause error
+}
+
+func (e *interruptedDownloadError) Error() string {
+	return fmt.Sprintf("download interrupted: %s", e.cause.Error())
+}
+
+func (e *interruptedDownloadError) Unwrap() error {
+	return e.cause
+}
+
+// truncatableWriter is implemented by files, it allows restarting a download from scratch if the server ignores the Range header.
+type truncatableWriter interface {
+	io.Writer
+	io.Seeker
+	Truncate(size int64) error
+}
+
+type agentDownload struct {
+	writer  io.Writer
+	md5     hash.Hash
+	sha256  hash.Hash
+	written int64
+
+	validator    string
+	expectedSize int64
+	expectedMD5  string
+	expectedSHA  string
+}
+
+func newAgentDownload(writer io.Writer) *agentDownload {
+	return &agentDownload{
+		writer:       writer,
+		md5:          md5.New(), //nolint:gosec // see import
+		sha256:       sha256.New(),
+		expectedSize: -1,
+	}
+}
+
+func (download *agentDownload) write(p []byte) error {
+	if _, err := download.writer.Write(p); err != nil {
+		return err
+	}
+	_, _ = download.md5.Write(p)
+	_, _ = download.sha256.Write(p)
+	download.written += int64(len(p))
+	return nil
+}
+
+// restart discards what has been written so far, which is only possible for files.
+func (download *agentDownload) restart() error {
+	writer, ok := download.writer.(truncatableWriter)
+	if !ok {
+		return errors.New("server does not support resuming downloads and the download target can not be reset")
+	}
+	if err := writer.Truncate(0); err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err := writer.Seek(0, io.SeekStart); err != nil {
+		return errors.WithStack(err)
+	}
+	download.md5.Reset()
+	download.sha256.Reset()
+	download.written = 0
+	return nil
+}
+
+// readExpectations takes the size and checksums of the complete package from a 200 response.
+func (download *agentDownload) readExpectations(resp *http.Response) {
+	download.expectedSize = resp.ContentLength
+	download.validator = resp.Header.Get("ETag")
+	if download.validator == "" {
+		download.validator = resp.Header.Get("Last-Modified")
+	}
+	if contentMD5 := resp.Header.Get("Content-MD5"); contentMD5 != "" {
+		if decoded, err := base64.StdEncoding.DecodeString(contentMD5); err == nil {
+			download.expectedMD5 = hex.EncodeToString(decoded)
+		}
+	}
+	download.readDigest(resp.Header.Get("Digest"))
+}
+
+// readDigest parses an RFC 3230 Digest header, e.g. "sha-256=<base64>,md5=<base64>".
+func (download *agentDownload) readDigest(digest string) {
+	for _, entry := range strings.Split(digest, ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		algorithm, value := parts[0], parts[1]
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			continue
+		}
+		switch strings.ToLower(algorithm) {
+		case "sha-256":
+			download.expectedSHA = hex.EncodeToString(decoded)
+		case "md5":
+			download.expectedMD5 = hex.EncodeToString(decoded)
+		}
+	}
+}
+
+func (download *agentDownload) md5Sum() string {
+	return hex.EncodeToString(download.md5.Sum(nil))
+}
+
+func (download *agentDownload) sha256Sum() string {
+	return hex.EncodeToString(download.sha256.Sum(nil))
+}
+
+func (download *agentDownload) verify() error {
+	if download.expectedSize >= 0 && download.expectedSize != download.written {
+		return &DownloadVerificationError{
+			Property: "size",
+			Expected: strconv.FormatInt(download.expectedSize, 10),
+			Actual:   strconv.FormatInt(download.written, 10),
+		}
+	}
+	if download.expectedMD5 != "" && download.expectedMD5 != download.md5Sum() {
+		return &DownloadVerificationError{Property: "md5", Expected: download.expectedMD5, Actual: download.md5Sum()}
+	}
+	if download.expectedSHA != "" && download.expectedSHA != download.sha256Sum() {
+		return &DownloadVerificationError{Property: "sha256", Expected: download.expectedSHA, Actual: download.sha256Sum()}
+	}
+	return nil
+}
+
+// makeRequestForBinary downloads the given URL into writer and verifies it against the size and checksums sent by the server.
+// If the connection drops, the download is resumed with a Range request. Returns the md5 of the downloaded content.
+func (dtc *dynatraceClient) makeRequestForBinary(ctx context.Context, url string, token tokenType, writer io.Writer) (string, error) {
+	download, err := dtc.download(ctx, url, token, writer)
+	if err != nil {
+		return "", err
+	}
+	return download.md5Sum(), download.verify()
+}
+
+func (dtc *dynatraceClient) download(ctx context.Context, url string, token tokenType, writer io.Writer) (*agentDownload, error) {
+	download := newAgentDownload(writer)
+
+	for resumes := 0; ; resumes++ {
+		err := dtc.downloadRemaining(ctx, url, token, download)
+
+		var interruptedErr *interruptedDownloadError
+		if err == nil || !errors.As(err, &interruptedErr) || resumes >= maxDownloadResumes || ctx.Err() != nil {
+			return download, err
+		}
+		log.Info("agent download interrupted, resuming", "url", url, "offset", download.written, "cause", interruptedErr.cause.Error())
+	}
+}
+
+func (dtc *dynatraceClient) downloadRemaining(ctx context.Context, url string, token tokenType, download *agentDownload) error {
+	req, err := dtc.newRequest(ctx, url, token)
+	if err != nil {
+		return err
+	}
+	if download.written > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", download.written))
+		if download.validator != "" {
+			req.Header.Set("If-Range", download.validator)
+		}
+	}
+
+	resp, err := dtc.httpClient.Do(req)
+	if err != nil {
+		if download.written > 0 {
+			return &interruptedDownloadError{cause: err}
+		}
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if download.written > 0 {
+			log.Info("server does not support resuming, restarting download", "url", url)
+			if err := download.restart(); err != nil {
+				return err
+			}
+		}
+		download.readExpectations(resp)
+	case http.StatusPartialContent:
+		if err := checkContentRange(resp.Header.Get("Content-Range"), download.written); err != nil {
+			return err
+		}
+	default:
+		return readBinaryErrorResponse(resp)
+	}
+
+	return copyBody(resp.Body, download)
+}
+
+// checkContentRange makes sure a partial response continues exactly where the download stopped.
+func checkContentRange(contentRange string, offset int64) error {
+	var start, end, total int64
+	if _, err := fmt.Sscanf(contentRange, "bytes %d-%d/%d", &start, &end, &total); err != nil {
+		return errors.Errorf("unexpected Content-Range header in partial response: %q", contentRange)
+	}
+	if start != offset {
+		return errors.Errorf("partial response starts at %d instead of %d", start, offset)
+	}
+	return nil
+}
+
+func copyBody(body io.Reader, download *agentDownload) error {
+	buffer := make([]byte, downloadBufferSize)
+	for {
+		n, readErr := body.Read(buffer)
+		if n > 0 {
+			if err := download.write(buffer[:n]); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		if readErr == io.EOF {
+			return nil
+		}
+		if readErr != nil {
+			return &interruptedDownloadError{cause: readErr}
+		}
+	}
+}
+
+func readBinaryErrorResponse(resp *http.Response) error {
+	var errorResponse serverErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
+		return err
+	}
+	return fmt.Errorf("dynatrace server error %d: %s", errorResponse.ErrorMessage.Code, errorResponse.ErrorMessage.Message)
+}
+
+// fetchChecksumSidecar looks for a "<url>.sha256" file next to a user provided installer URL.
+// Returns an empty string if there is none.
+func (dtc *dynatraceClient) fetchChecksumSidecar(ctx context.Context, url string) string {
+	resp, err := dtc.makeRequest(ctx, url+checksumSidecarExt, installerUrlToken)
+	if err != nil {
+		log.Info("could not query checksum file for installer url", "url", url, "cause", err.Error())
+		return ""
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return ""
+	}
+
+	// sha256sum format: "<hex>  <filename>"
+	scanner := bufio.NewScanner(io.LimitReader(resp.Body, maxSidecarBodyBytes))
+	scanner.Split(bufio.ScanWords)
+	if !scanner.Scan() {
+		return ""
+	}
+	checksum := strings.ToLower(scanner.Text())
+	if _, err := hex.DecodeString(checksum); err != nil || len(checksum) != sha256.Size*2 {
+		log.Info("ignoring malformed checksum file for installer url", "url", url)
+		return ""
+	}
+	return checksum
+}
@@ -0,0 +1,192 @@
+package dtclient
+
+import (
+	"context"
+	"crypto/md5" //nolint:gosec // md5 is what the installer endpoint provides
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAgentPackage = "this-is-a-complete-agent-package"
+	testAgentETag    = `"agent-package-v1"`
+)
+
+// interruptingAgentHandler cuts off the first response halfway, further requests are answered by onRetry.
+type interruptingAgentHandler struct {
+	requests int
+	ranges   []string
+	onRetry  func(writer http.ResponseWriter, request *http.Request)
+}
+
+func (handler *interruptingAgentHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	handler.requests++
+	handler.ranges = append(handler.ranges, request.Header.Get("Range"))
+	if handler.requests > 1 {
+		handler.onRetry(writer, request)
+		return
+	}
+	writer.Header().Set("ETag", testAgentETag)
+	writer.Header().Set("Content-Length", strconv.Itoa(len(testAgentPackage)))
+	writer.WriteHeader(http.StatusOK)
+	_, _ = writer.Write([]byte(testAgentPackage[:len(testAgentPackage)/2]))
+}
+
+func writeFullAgentPackage(writer http.ResponseWriter, _ *http.Request) {
+	writer.Header().Set("Content-Length", strconv.Itoa(len(testAgentPackage)))
+	writer.WriteHeader(http.StatusOK)
+	_, _ = writer.Write([]byte(testAgentPackage))
+}
+
+func newDownloadTestClient(serverURL string) *dynatraceClient {
+	return &dynatraceClient{
+		url:        serverURL,
+		paasToken:  paasToken,
+		httpClient: http.DefaultClient,
+	}
+}
+
+func TestMakeRequestForBinary(t *testing.T) {
+	t.Run(`resumes interrupted download`, func(t *testing.T) {
+		handler := &interruptingAgentHandler{
+			onRetry: func(writer http.ResponseWriter, request *http.Request) {
+				assert.Equal(t, testAgentETag, request.Header.Get("If-Range"))
+				offset := len(testAgentPackage) / 2
+				writer.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, len(testAgentPackage)-1, len(testAgentPackage)))
+				writer.WriteHeader(http.StatusPartialContent)
+				_, _ = writer.Write([]byte(testAgentPackage[offset:]))
+			},
+		}
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		fs := afero.NewMemMapFs()
+		file, err := afero.TempFile(fs, "client", "installer")
+		require.NoError(t, err)
+
+		md5sum, err := newDownloadTestClient(server.URL).makeRequestForBinary(context.TODO(), server.URL, dynatracePaaSToken, file)
+		require.NoError(t, err)
+
+		content, err := afero.ReadFile(fs, file.Name())
+		require.NoError(t, err)
+		assert.Equal(t, testAgentPackage, string(content))
+		assert.Equal(t, md5Hex(testAgentPackage), md5sum)
+		assert.Equal(t, []string{"", fmt.Sprintf("bytes=%d-", len(testAgentPackage)/2)}, handler.ranges)
+	})
+	t.Run(`restarts if server ignores range`, func(t *testing.T) {
+		handler := &interruptingAgentHandler{onRetry: writeFullAgentPackage}
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		fs := afero.NewMemMapFs()
+		file, err := afero.TempFile(fs, "client", "installer")
+		require.NoError(t, err)
+
+		_, err = newDownloadTestClient(server.URL).makeRequestForBinary(context.TODO(), server.URL, dynatracePaaSToken, file)
+		require.NoError(t, err)
+
+		content, err := afero.ReadFile(fs, file.Name())
+		require.NoError(t, err)
+		assert.Equal(t, testAgentPackage, string(content))
+	})
+	t.Run(`can not restart non-file writer`, func(t *testing.T) {
+		handler := &interruptingAgentHandler{onRetry: writeFullAgentPackage}
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		writer := &memoryReadWriter{data: make([]byte, len(testAgentPackage))}
+		_, err := newDownloadTestClient(server.URL).makeRequestForBinary(context.TODO(), server.URL, dynatracePaaSToken, writer)
+
+		require.Error(t, err)
+		assert.False(t, IsDownloadVerificationError(err))
+	})
+	t.Run(`detects md5 mismatch`, func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			checksum := md5.Sum([]byte("something else")) //nolint:gosec // see import
+			writer.Header().Set("Content-MD5", base64.StdEncoding.EncodeToString(checksum[:]))
+			writeFullAgentPackage(writer, request)
+		}))
+		defer server.Close()
+
+		writer := &memoryReadWriter{data: make([]byte, len(testAgentPackage))}
+		_, err := newDownloadTestClient(server.URL).makeRequestForBinary(context.TODO(), server.URL, dynatracePaaSToken, writer)
+
+		require.Error(t, err)
+		assert.True(t, IsDownloadVerificationError(err))
+		assert.Contains(t, err.Error(), "md5")
+	})
+	t.Run(`verifies sha256 digest`, func(t *testing.T) {
+		checksum := sha256.Sum256([]byte(testAgentPackage))
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.Header().Set("Digest", "sha-256="+base64.StdEncoding.EncodeToString(checksum[:]))
+			writeFullAgentPackage(writer, request)
+		}))
+		defer server.Close()
+
+		writer := &memoryReadWriter{data: make([]byte, len(testAgentPackage))}
+		_, err := newDownloadTestClient(server.URL).makeRequestForBinary(context.TODO(), server.URL, dynatracePaaSToken, writer)
+
+		require.NoError(t, err)
+	})
+}
+
+func TestGetAgentViaInstallerUrl_ChecksumFile(t *testing.T) {
+	newInstallerServer := func(checksumFile string, status int) *httptest.Server {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/agent.zip", writeFullAgentPackage)
+		mux.HandleFunc("/agent.zip"+checksumSidecarExt, func(writer http.ResponseWriter, _ *http.Request) {
+			writer.WriteHeader(status)
+			_, _ = writer.Write([]byte(checksumFile))
+		})
+		return httptest.NewServer(mux)
+	}
+	correctChecksum := sha256.Sum256([]byte(testAgentPackage))
+
+	t.Run(`matching checksum file`, func(t *testing.T) {
+		server := newInstallerServer(hex.EncodeToString(correctChecksum[:])+"  agent.zip\n", http.StatusOK)
+		defer server.Close()
+
+		writer := &memoryReadWriter{data: make([]byte, len(testAgentPackage))}
+		err := newDownloadTestClient(server.URL).GetAgentViaInstallerUrl(context.TODO(), server.URL+"/agent.zip", writer)
+
+		require.NoError(t, err)
+	})
+	t.Run(`mismatching checksum file`, func(t *testing.T) {
+		wrongChecksum := sha256.Sum256([]byte("something else"))
+		server := newInstallerServer(hex.EncodeToString(wrongChecksum[:]), http.StatusOK)
+		defer server.Close()
+
+		writer := &memoryReadWriter{data: make([]byte, len(testAgentPackage))}
+		err := newDownloadTestClient(server.URL).GetAgentViaInstallerUrl(context.TODO(), server.URL+"/agent.zip", writer)
+
+		require.Error(t, err)
+		assert.True(t, IsDownloadVerificationError(err))
+	})
+	t.Run(`missing or malformed checksum file is ignored`, func(t *testing.T) {
+		for _, server := range []*httptest.Server{
+			newInstallerServer("", http.StatusNotFound),
+			newInstallerServer("not-a-checksum", http.StatusOK),
+		} {
+			writer := &memoryReadWriter{data: make([]byte, len(testAgentPackage))}
+			err := newDownloadTestClient(server.URL).GetAgentViaInstallerUrl(context.TODO(), server.URL+"/agent.zip", writer)
+			server.Close()
+
+			require.NoError(t, err)
+		}
+	})
+}
+
+func md5Hex(content string) string {
+	checksum := md5.Sum([]byte(content)) //nolint:gosec // see import
+	return hex.EncodeToString(checksum[:])
+}
@@ -1,6 +1,7 @@
 package dtclient
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 
@@ -13,26 +14,15 @@ type AgentTenantInfo struct {
 	CommunicationEndpoint string
 }
 
-func (dtc *dynatraceClient) GetAgentTenantInfo() (*AgentTenantInfo, error) {
-	response, err := dtc.makeRequest(
+func (dtc *dynatraceClient) GetAgentTenantInfo(ctx context.Context) (*AgentTenantInfo, error) {
+	data, err := dtc.makeCachedRequest(
+		ctx,
 		dtc.getOneAgentConnectionInfoUrl(),
 		dynatracePaaSToken,
 	)
-
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
-	defer func() {
-		err := response.Body.Close()
-		if err != nil {
-			log.Error(err, err.Error())
-		}
-	}()
-
-	data, err := dtc.getServerResponseData(response)
-	if err != nil {
-		return nil, errors.WithStack(dtc.handleErrorResponseFromAPI(data, response.StatusCode))
-	}
 
 	tenantInfo, err := dtc.readResponseForTenantInfo(data)
 	if err != nil {
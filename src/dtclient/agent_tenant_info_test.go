@@ -1,6 +1,7 @@
 package dtclient
 
 import (
+	"context"
 	"strings"
 	"testing"
 
@@ -24,7 +25,7 @@ func TestTenant(t *testing.T) {
 		dynatraceServer, dynatraceClient := createTestDynatraceClient(t, tenantServerHandler(agentConnectionInfoEndpoint, agentTenantResponse), "")
 		defer dynatraceServer.Close()
 
-		tenantInfo, err := dynatraceClient.GetAgentTenantInfo()
+		tenantInfo, err := dynatraceClient.GetAgentTenantInfo(context.TODO())
 		assert.NoError(t, err)
 		assert.NotNil(t, tenantInfo)
 
@@ -39,7 +40,7 @@ func TestTenant(t *testing.T) {
 		faultyDynatraceServer, faultyDynatraceClient := createTestDynatraceClient(t, tenantInternalServerError(agentConnectionInfoEndpoint), "")
 		defer faultyDynatraceServer.Close()
 
-		tenantInfo, err := faultyDynatraceClient.GetAgentTenantInfo()
+		tenantInfo, err := faultyDynatraceClient.GetAgentTenantInfo(context.TODO())
 		assert.Error(t, err)
 		assert.Nil(t, tenantInfo)
 
@@ -49,7 +50,7 @@ func TestTenant(t *testing.T) {
 		faultyDynatraceServer, faultyDynatraceClient := createTestDynatraceClient(t, tenantMalformedJson(agentConnectionInfoEndpoint), "")
 		defer faultyDynatraceServer.Close()
 
-		tenantInfo, err := faultyDynatraceClient.GetAgentTenantInfo()
+		tenantInfo, err := faultyDynatraceClient.GetAgentTenantInfo(context.TODO())
 		assert.Error(t, err)
 		assert.Nil(t, tenantInfo)
 
@@ -1,6 +1,7 @@
 package dtclient
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 
@@ -8,22 +9,13 @@ import (
 )
 
 // GetLatestAgentVersion gets the latest agent version for the given OS and installer type.
-func (dtc *dynatraceClient) GetLatestAgentVersion(os, installerType string) (string, error) {
+func (dtc *dynatraceClient) GetLatestAgentVersion(ctx context.Context, os, installerType string) (string, error) {
 	if len(os) == 0 || len(installerType) == 0 {
 		return "", errors.New("os or installerType is empty")
 	}
 
 	url := dtc.getLatestAgentVersionUrl(os, installerType)
-	resp, err := dtc.makeRequest(url, dynatracePaaSToken)
-	if err != nil {
-		return "", err
-	}
-	defer func() {
-		//Swallow error, nothing has to be done at this point
-		_ = resp.Body.Close()
-	}()
-
-	responseData, err := dtc.getServerResponseData(resp)
+	responseData, err := dtc.makeCachedRequest(ctx, url, dynatracePaaSToken)
 	if err != nil {
 		return "", err
 	}
@@ -31,12 +23,12 @@ func (dtc *dynatraceClient) GetLatestAgentVersion(os, installerType string) (str
 	return dtc.readResponseForLatestVersion(responseData)
 }
 
-func (dtc *dynatraceClient) GetEntityIDForIP(ip string) (string, error) {
+func (dtc *dynatraceClient) GetEntityIDForIP(ctx context.Context, ip string) (string, error) {
 	if len(ip) == 0 {
 		return "", errors.New("ip is invalid")
 	}
 
-	hostInfo, err := dtc.getHostInfoForIP(ip)
+	hostInfo, err := dtc.getHostInfoForIP(ctx, ip)
 	if err != nil {
 		return "", err
 	}
@@ -69,20 +61,20 @@ func (dtc *dynatraceClient) readResponseForLatestVersion(response []byte) (strin
 }
 
 // GetLatestAgent gets the latest agent package for the given OS and installer type.
-func (dtc *dynatraceClient) GetLatestAgent(os, installerType, flavor, arch string, technologies []string, writer io.Writer) error {
+func (dtc *dynatraceClient) GetLatestAgent(ctx context.Context, os, installerType, flavor, arch string, technologies []string, writer io.Writer) error {
 	if len(os) == 0 || len(installerType) == 0 {
 		return errors.New("os or installerType is empty")
 	}
 
 	url := dtc.getLatestAgentUrl(os, installerType, flavor, arch, technologies)
-	md5, err := dtc.makeRequestForBinary(url, dynatracePaaSToken, writer)
+	md5, err := dtc.makeRequestForBinary(ctx, url, dynatracePaaSToken, writer)
 	if err == nil {
 		log.Info("downloaded agent file", "os", os, "type", installerType, "flavor", flavor, "arch", arch, "technologies", technologies, "md5", md5)
 	}
 	return err
 }
 
-func (dtc *dynatraceClient) GetAgentVersions(os, installerType, flavor, arch string) ([]string, error) {
+func (dtc *dynatraceClient) GetAgentVersions(ctx context.Context, os, installerType, flavor, arch string) ([]string, error) {
 	response := struct {
 		AvailableVersions []string `json:"availableVersions"`
 	}{}
@@ -92,27 +84,36 @@ func (dtc *dynatraceClient) GetAgentVersions(os, installerType, flavor, arch str
 	}
 
 	url := dtc.getAgentVersionsUrl(os, installerType, flavor, arch)
-	err := dtc.makeRequestAndUnmarshal(url, dynatracePaaSToken, &response)
+	err := dtc.makeRequestAndUnmarshal(ctx, url, dynatracePaaSToken, &response)
 	return response.AvailableVersions, errors.WithStack(err)
 }
 
-func (dtc *dynatraceClient) GetAgent(os, installerType, flavor, arch, version string, technologies []string, writer io.Writer) error {
+func (dtc *dynatraceClient) GetAgent(ctx context.Context, os, installerType, flavor, arch, version string, technologies []string, writer io.Writer) error {
 	if len(os) == 0 || len(installerType) == 0 {
 		return errors.New("os or installerType is empty")
 	}
 
 	url := dtc.getAgentUrl(os, installerType, flavor, arch, version, technologies)
-	md5, err := dtc.makeRequestForBinary(url, dynatracePaaSToken, writer)
+	md5, err := dtc.makeRequestForBinary(ctx, url, dynatracePaaSToken, writer)
 	if err == nil {
 		log.Info("downloaded agent file", "os", os, "type", installerType, "flavor", flavor, "arch", arch, "technologies", technologies, "md5", md5)
 	}
 	return err
 }
 
-func (dtc *dynatraceClient) GetAgentViaInstallerUrl(url string, writer io.Writer) error {
-	md5, err := dtc.makeRequestForBinary(url, installerUrlToken, writer)
-	if err == nil {
-		log.Info("downloaded agent file using given url", "url", url, "md5", md5)
+// GetAgentViaInstallerUrl downloads the agent from the given url. If the server does not send a checksum,
+// a "<url>.sha256" file next to it is used for verification, if there is one.
+func (dtc *dynatraceClient) GetAgentViaInstallerUrl(ctx context.Context, url string, writer io.Writer) error {
+	download, err := dtc.download(ctx, url, installerUrlToken, writer)
+	if err != nil {
+		return err
 	}
-	return err
+	if download.expectedSHA == "" {
+		download.expectedSHA = dtc.fetchChecksumSidecar(ctx, url)
+	}
+	if err := download.verify(); err != nil {
+		return err
+	}
+	log.Info("downloaded agent file using given url", "url", url, "md5", download.md5Sum())
+	return nil
 }
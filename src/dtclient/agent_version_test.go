@@ -1,6 +1,7 @@
 package dtclient
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -112,12 +113,12 @@ func TestGetEntityIDForIP(t *testing.T) {
 		}
 	}
 ]`, time.Now().UTC().Unix()*1000))))
-	id, err := dtc.GetEntityIDForIP("1.1.1.1")
+	id, err := dtc.GetEntityIDForIP(context.TODO(), "1.1.1.1")
 	assert.NoError(t, err)
 	assert.NotEmpty(t, id)
 	assert.Equal(t, "HOST-42", id)
 
-	id, err = dtc.GetEntityIDForIP("2.2.2.2")
+	id, err = dtc.GetEntityIDForIP(context.TODO(), "2.2.2.2")
 
 	assert.Error(t, err)
 	assert.Empty(t, id)
@@ -144,7 +145,7 @@ func TestGetEntityIDForIP(t *testing.T) {
 	}
 ]`, time.Now().UTC().Unix()*1000))))
 
-	id, err = dtc.GetEntityIDForIP("1.1.1.1")
+	id, err = dtc.GetEntityIDForIP(context.TODO(), "1.1.1.1")
 
 	assert.Error(t, err)
 	assert.Empty(t, id)
@@ -152,17 +153,17 @@ func TestGetEntityIDForIP(t *testing.T) {
 
 func testAgentVersionGetLatestAgentVersion(t *testing.T, dynatraceClient Client) {
 	{
-		_, err := dynatraceClient.GetLatestAgentVersion("", InstallerTypeDefault)
+		_, err := dynatraceClient.GetLatestAgentVersion(context.TODO(), "", InstallerTypeDefault)
 
 		assert.Error(t, err, "empty OS")
 	}
 	{
-		_, err := dynatraceClient.GetLatestAgentVersion(OsUnix, "")
+		_, err := dynatraceClient.GetLatestAgentVersion(context.TODO(), OsUnix, "")
 
 		assert.Error(t, err, "empty installer type")
 	}
 	{
-		latestAgentVersion, err := dynatraceClient.GetLatestAgentVersion(OsUnix, InstallerTypeDefault)
+		latestAgentVersion, err := dynatraceClient.GetLatestAgentVersion(context.TODO(), OsUnix, InstallerTypeDefault)
 
 		assert.NoError(t, err)
 		assert.Equal(t, "17", latestAgentVersion, "latest agent version equals expected version")
@@ -186,7 +187,7 @@ func TestGetLatestAgent(t *testing.T) {
 		file, err := afero.TempFile(fs, "client", "installer")
 		require.NoError(t, err)
 
-		err = dtc.GetLatestAgent(OsUnix, InstallerTypePaaS, FlavorMultidistro, "arch", nil, file)
+		err = dtc.GetLatestAgent(context.TODO(), OsUnix, InstallerTypePaaS, FlavorMultidistro, "arch", nil, file)
 		require.NoError(t, err)
 
 		resp, err := afero.ReadFile(fs, file.Name())
@@ -198,7 +199,7 @@ func TestGetLatestAgent(t *testing.T) {
 		file, err := afero.TempFile(fs, "client", "installer")
 		require.NoError(t, err)
 
-		err = dtc.GetLatestAgent(OsUnix, InstallerTypePaaS, FlavorMultidistro, "invalid", nil, file)
+		err = dtc.GetLatestAgent(context.TODO(), OsUnix, InstallerTypePaaS, FlavorMultidistro, "invalid", nil, file)
 		require.Error(t, err)
 	})
 }
@@ -214,7 +215,7 @@ func TestDynatraceClient_GetAgent(t *testing.T) {
 			paasToken:  paasToken,
 		}
 		readWriter := &memoryReadWriter{data: make([]byte, len(versionedAgentResponse))}
-		err := dtc.GetAgent(OsUnix, InstallerTypePaaS, "", "", "", nil, readWriter)
+		err := dtc.GetAgent(context.TODO(), OsUnix, InstallerTypePaaS, "", "", "", nil, readWriter)
 
 		assert.NoError(t, err)
 		assert.Equal(t, versionedAgentResponse, string(readWriter.data))
@@ -229,7 +230,7 @@ func TestDynatraceClient_GetAgent(t *testing.T) {
 			paasToken:  paasToken,
 		}
 		readWriter := &memoryReadWriter{data: make([]byte, len(versionedAgentResponse))}
-		err := dtc.GetAgent(OsUnix, InstallerTypePaaS, "", "", "", nil, readWriter)
+		err := dtc.GetAgent(context.TODO(), OsUnix, InstallerTypePaaS, "", "", "", nil, readWriter)
 
 		assert.EqualError(t, err, "dynatrace server error 400: test-error")
 	})
@@ -245,7 +246,7 @@ func TestDynatraceClient_GetAgentVersions(t *testing.T) {
 			url:        dynatraceServer.URL,
 			paasToken:  paasToken,
 		}
-		availableVersions, err := dtc.GetAgentVersions(OsUnix, InstallerTypePaaS, "", "")
+		availableVersions, err := dtc.GetAgentVersions(context.TODO(), OsUnix, InstallerTypePaaS, "", "")
 
 		assert.NoError(t, err)
 		assert.Equal(t, 4, len(availableVersions))
@@ -263,7 +264,7 @@ func TestDynatraceClient_GetAgentVersions(t *testing.T) {
 			url:        dynatraceServer.URL,
 			paasToken:  paasToken,
 		}
-		availableVersions, err := dtc.GetAgentVersions(OsUnix, InstallerTypePaaS, "", "")
+		availableVersions, err := dtc.GetAgentVersions(context.TODO(), OsUnix, InstallerTypePaaS, "", "")
 
 		assert.EqualError(t, err, "dynatrace server error 400: test-error")
 		assert.Equal(t, 0, len(availableVersions))
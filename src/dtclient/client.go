@@ -1,12 +1,14 @@
 package dtclient
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -17,9 +19,12 @@ const (
 	DynatraceDataIngestToken       = "dataIngestToken"
 	CustomCertificatesConfigMapKey = "certs"
 	CustomProxySecretKey           = "proxy"
+	ClientCertificateSecretCertKey = "tls.crt"
+	ClientCertificateSecretKeyKey  = "tls.key"
 )
 
 // Client is the interface for the Dynatrace REST API client.
+// All methods abort the underlying requests once the given context is done.
 type Client interface {
 	// GetLatestAgentVersion gets the latest agent version for the given OS and installer type.
 	// Returns the version as received from the server on success.
@@ -29,55 +34,86 @@ type Client interface {
 	//  - IO error or unexpected response
 	//  - error response from the server (e.g. authentication failure)
 	//  - the agent version is not set or empty
-	GetLatestAgentVersion(os, installerType string) (string, error)
+	GetLatestAgentVersion(ctx context.Context, os, installerType string) (string, error)
 
 	// GetLatestAgent returns a reader with the contents of the download. Must be closed by caller.
-	GetLatestAgent(os, installerType, flavor, arch string, technologies []string, writer io.Writer) error
+	GetLatestAgent(ctx context.Context, os, installerType, flavor, arch string, technologies []string, writer io.Writer) error
 
 	// GetAgent downloads a specific agent version and writes it to the given io.Writer
-	GetAgent(os, installerType, flavor, arch, version string, technologies []string, writer io.Writer) error
+	GetAgent(ctx context.Context, os, installerType, flavor, arch, version string, technologies []string, writer io.Writer) error
 
 	// GetAgentViaInstallerUrl downloads the agent from the user specified URL and writes it to the given io.Writer
-	GetAgentViaInstallerUrl(url string, writer io.Writer) error
+	GetAgentViaInstallerUrl(ctx context.Context, url string, writer io.Writer) error
 
 	// GetAgentVersions on success returns an array of versions that can be used with GetAgent to
 	// download a specific agent version
-	GetAgentVersions(os, installerType, flavor, arch string) ([]string, error)
+	GetAgentVersions(ctx context.Context, os, installerType, flavor, arch string) ([]string, error)
 
-	GetConnectionInfo() (ConnectionInfo, error)
+	GetConnectionInfo(ctx context.Context) (ConnectionInfo, error)
 
-	GetProcessModuleConfig(prevRevision uint) (*ProcessModuleConfig, error)
+	GetProcessModuleConfig(ctx context.Context, prevRevision uint) (*ProcessModuleConfig, error)
 
 	// GetCommunicationHostForClient returns a CommunicationHost for the client's API URL. Or error, if failed to be parsed.
-	GetCommunicationHostForClient() (CommunicationHost, error)
+	GetCommunicationHostForClient(ctx context.Context) (CommunicationHost, error)
+
+	// GetActiveURL returns the API URL which answered last, it differs from the client's URL after a failover.
+	GetActiveURL() string
 
 	// SendEvent posts events to dynatrace API
-	SendEvent(eventData *EventData) error
+	SendEvent(ctx context.Context, eventData *EventData) error
+
+	// SendEventV2 posts events to the events API v2, which supports arbitrary properties and entity selectors.
+	//
+	// Returns an error if the server rejected the event, e.g. because the API token lacks the events.ingest scope.
+	SendEventV2(ctx context.Context, eventData *EventDataV2) error
 
 	// GetEntityIDForIP returns the entity id for a given IP address.
 	//
 	// Returns an error in case the lookup failed.
-	GetEntityIDForIP(ip string) (string, error)
+	GetEntityIDForIP(ctx context.Context, ip string) (string, error)
 
 	// GetTokenScopes returns the list of scopes assigned to a token if successful.
-	GetTokenScopes(token string) (TokenScopes, error)
+	GetTokenScopes(ctx context.Context, token string) (TokenScopes, error)
+
+	// GetTokenInfo returns the scopes and the expiration date of a token if successful.
+	GetTokenInfo(ctx context.Context, token string) (*TokenInfo, error)
 
 	// GetAgentTenantInfo returns AgentTenantInfo for OneAgents that holds UUID, Tenant Token and Endpoints
-	GetAgentTenantInfo() (*AgentTenantInfo, error)
+	GetAgentTenantInfo(ctx context.Context) (*AgentTenantInfo, error)
 
 	// GetActiveGateTenantInfo returns AgentTenantInfo for ActiveGate that holds UUID, Tenant Token and Endpoints
-	GetActiveGateTenantInfo() (*ActiveGateTenantInfo, error)
+	GetActiveGateTenantInfo(ctx context.Context) (*ActiveGateTenantInfo, error)
 
 	// CreateOrUpdateKubernetesSetting returns the object id of the created k8s settings if successful, or an api error otherwise
-	CreateOrUpdateKubernetesSetting(name, kubeSystemUUID, scope string) (string, error)
+	CreateOrUpdateKubernetesSetting(ctx context.Context, name, kubeSystemUUID, scope string) (string, error)
 
 	// GetMonitoredEntitiesForKubeSystemUUID returns a (possibly empty) list of k8s monitored entities for the given uuid,
 	// or an api error otherwise
-	GetMonitoredEntitiesForKubeSystemUUID(kubeSystemUUID string) ([]MonitoredEntity, error)
+	GetMonitoredEntitiesForKubeSystemUUID(ctx context.Context, kubeSystemUUID string) ([]MonitoredEntity, error)
 
 	// GetSettingsForMonitoredEntities returns the settings response with the number of settings objects,
 	// or an api error otherwise
-	GetSettingsForMonitoredEntities(monitoredEntities []MonitoredEntity) (GetSettingsResponse, error)
+	GetSettingsForMonitoredEntities(ctx context.Context, monitoredEntities []MonitoredEntity) (GetSettingsResponse, error)
+
+	// ListSettingsObjects returns all settings objects of the given schema, limited to the given scope if it is not empty.
+	ListSettingsObjects(ctx context.Context, schemaID, scope string) ([]SettingsObject, error)
+
+	// GetSettingsSchema returns the settings schema with the given id.
+	GetSettingsSchema(ctx context.Context, schemaID string) (*SettingsSchema, error)
+
+	// GetSettingsObject returns the settings object with the given id.
+	//
+	// Returns a ServerError with code 404 if the object does not exist.
+	GetSettingsObject(ctx context.Context, objectID string) (*SettingsObject, error)
+
+	// CreateSettingsObject creates the given settings object and returns its object id, the object id of the argument is ignored.
+	CreateSettingsObject(ctx context.Context, object SettingsObject) (string, error)
+
+	// UpdateSettingsObject replaces the value of the settings object with the object id of the argument.
+	UpdateSettingsObject(ctx context.Context, object SettingsObject) error
+
+	// DeleteSettingsObject deletes the settings object with the given id.
+	DeleteSettingsObject(ctx context.Context, objectID string) error
 }
 
 // Known OS values.
@@ -119,16 +155,21 @@ const (
 	TokenScopeEntitiesRead      = "entities.read"
 	TokenScopeSettingsRead      = "settings.read"
 	TokenScopeSettingsWrite     = "settings.write"
+	TokenScopeEventsIngest      = "events.ingest"
 )
 
 // NewClient creates a REST client for the given API base URL and authentication tokens.
 // Returns an error if a token or the URL is empty.
 //
 // The API base URL is different for managed and SaaS environments:
-//  - SaaS: https://{environment-id}.live.dynatrace.com/api
-//  - Managed: https://{domain}/e/{environment-id}/api
+//   - SaaS: https://{environment-id}.live.dynatrace.com/api
+//   - Managed: https://{domain}/e/{environment-id}/api
 //
 // opts can be used to customize the created client, entries must not be nil.
+//
+// All clients share one request budget, honor Retry-After responses and retry idempotent requests on
+// throttling or transient server errors. Every request is recorded in the dynatrace_api_client_* metrics.
+// If failover URLs are given, requests which can't reach the API URL are sent to them in order.
 func NewClient(url, apiToken, paasToken string, opts ...Option) (Client, error) {
 	if len(url) == 0 {
 		return nil, errors.New("url is empty")
@@ -144,7 +185,8 @@ func NewClient(url, apiToken, paasToken string, opts ...Option) (Client, error)
 		apiToken:  apiToken,
 		paasToken: paasToken,
 
-		hostCache: make(map[string]hostInfo),
+		hostCache:     make(map[string]hostInfo),
+		responseCache: sharedResponseCache,
 		httpClient: &http.Client{
 			Transport: http.DefaultTransport.(*http.Transport).Clone(),
 		},
@@ -154,6 +196,10 @@ func NewClient(url, apiToken, paasToken string, opts ...Option) (Client, error)
 		opt(dc)
 	}
 
+	// Wrapped after the options are applied, as they configure the underlying *http.Transport
+	dc.failover = newFailoverTransport(newMetricsTransport(dc.httpClient.Transport), append([]string{url}, dc.failoverURLs...))
+	dc.httpClient.Transport = newRetryTransport(dc.failover)
+
 	return dc, nil
 }
 
@@ -201,12 +247,49 @@ func Certs(certs []byte) Option {
 	}
 }
 
+// ClientCertificate creates an Option that presents the given PEM encoded certificate and key during the TLS handshake,
+// for gateways in front of the Dynatrace API which require mutual TLS.
+func ClientCertificate(certPEM, keyPEM []byte) Option {
+	return func(c *dynatraceClient) {
+		certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			log.Info("failed to parse client certificate!", "error", err.Error())
+			return
+		}
+
+		t := c.httpClient.Transport.(*http.Transport)
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		}
+		t.TLSClientConfig.Certificates = []tls.Certificate{certificate}
+	}
+}
+
 func NetworkZone(networkZone string) Option {
 	return func(c *dynatraceClient) {
 		c.networkZone = networkZone
 	}
 }
 
+// ResponseCacheTTL creates an Option that enables reusing tenant metadata responses (connection info, tenant info and
+// latest agent versions) across all clients with the same API URL and tokens for the given duration.
+// The default is 0, which disables caching.
+func ResponseCacheTTL(ttl time.Duration) Option {
+	return func(c *dynatraceClient) {
+		c.responseCacheTTL = ttl
+	}
+}
+
+// FailoverURLs creates an Option that adds API URLs of the same Dynatrace environment, which are used in the given
+// order if the API URL can't be reached. The URL which answered last is used first by all clients with the same URLs.
+func FailoverURLs(failoverURLs ...string) Option {
+	return func(c *dynatraceClient) {
+		for _, failoverURL := range failoverURLs {
+			c.failoverURLs = append(c.failoverURLs, strings.TrimSuffix(failoverURL, "/"))
+		}
+	}
+}
+
 func DisableHostsRequests(disabledHostsRequests bool) Option {
 	return func(c *dynatraceClient) {
 		c.disableHostsRequests = disabledHostsRequests
@@ -1,10 +1,22 @@
 package dtclient
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewClient(t *testing.T) {
@@ -83,3 +95,53 @@ func TestCerts(t *testing.T) {
 	certs(&dtc)
 	assert.NotNil(t, transport.TLSClientConfig.RootCAs)
 }
+
+func TestClientCertificate(t *testing.T) {
+	certPEM, keyPEM := generateTestClientCertificate(t)
+
+	t.Run(`presents client certificate`, func(t *testing.T) {
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if len(request.TLS.PeerCertificates) != 1 || request.TLS.PeerCertificates[0].Subject.CommonName != "dynatrace-operator" {
+				writer.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = writer.Write([]byte(`{"scopes": ["DataExport"]}`))
+		}))
+		server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+		server.StartTLS()
+		defer server.Close()
+
+		dtc, err := NewClient(server.URL, apiToken, paasToken, SkipCertificateValidation(true), ClientCertificate(certPEM, keyPEM))
+		require.NoError(t, err)
+
+		scopes, err := dtc.GetTokenScopes(context.TODO(), apiToken)
+		require.NoError(t, err)
+		assert.True(t, scopes.Contains(TokenScopeDataExport))
+	})
+	t.Run(`ignores invalid key pair`, func(t *testing.T) {
+		dtc := dynatraceClient{httpClient: &http.Client{Transport: &http.Transport{}}}
+
+		ClientCertificate(certPEM, []byte("not a key"))(&dtc)
+
+		assert.Nil(t, dtc.httpClient.Transport.(*http.Transport).TLSClientConfig)
+	})
+}
+
+func generateTestClientCertificate(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "dynatrace-operator"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
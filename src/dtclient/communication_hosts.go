@@ -1,6 +1,7 @@
 package dtclient
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/url"
@@ -20,21 +21,12 @@ type CommunicationHost struct {
 	Port     uint32
 }
 
-func (dtc *dynatraceClient) GetCommunicationHostForClient() (CommunicationHost, error) {
+func (dtc *dynatraceClient) GetCommunicationHostForClient(_ context.Context) (CommunicationHost, error) {
 	return dtc.parseEndpoint(dtc.url)
 }
 
-func (dtc *dynatraceClient) GetConnectionInfo() (ConnectionInfo, error) {
-	resp, err := dtc.makeRequest(dtc.getOneAgentConnectionInfoUrl(), dynatracePaaSToken)
-	if err != nil {
-		return ConnectionInfo{}, err
-	}
-	defer func() {
-		//Swallow error, nothing has to be done at this point
-		_ = resp.Body.Close()
-	}()
-
-	responseData, err := dtc.getServerResponseData(resp)
+func (dtc *dynatraceClient) GetConnectionInfo(ctx context.Context) (ConnectionInfo, error) {
+	responseData, err := dtc.makeCachedRequest(ctx, dtc.getOneAgentConnectionInfoUrl(), dynatracePaaSToken)
 	if err != nil {
 		return ConnectionInfo{}, err
 	}
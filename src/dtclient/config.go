@@ -2,8 +2,31 @@ package dtclient
 
 import (
 	"github.com/Dynatrace/dynatrace-operator/src/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
 var (
 	log = logger.NewDTLogger().WithName("dtclient")
+
+	apiRequestsMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dynatrace",
+		Subsystem: "api_client",
+		Name:      "requests_total",
+		Help:      "Number of requests sent to the Dynatrace API, including retries",
+	}, []string{endpointLabel, methodLabel, statusCodeLabel})
+
+	apiRequestDurationMetric = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "dynatrace",
+		Subsystem: "api_client",
+		Name:      "request_duration_seconds",
+		Help:      "Time until the Dynatrace API sent the response headers",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{endpointLabel, methodLabel})
 )
+
+// Registered on the controller-runtime registry, which is served by the operator, webhook and CSI driver managers.
+func init() {
+	metrics.Registry.MustRegister(apiRequestsMetric)
+	metrics.Registry.MustRegister(apiRequestDurationMetric)
+}
@@ -1,11 +1,9 @@
 package dtclient
 
 import (
-	"crypto/md5"
-	"encoding/hex"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"io/ioutil"
 	"net/http"
 	"time"
@@ -24,6 +22,9 @@ type dynatraceClient struct {
 	apiToken  string
 	paasToken string
 
+	failoverURLs []string
+	failover     *failoverTransport
+
 	networkZone string
 
 	disableHostsRequests bool
@@ -32,6 +33,9 @@ type dynatraceClient struct {
 
 	hostCache map[string]hostInfo
 
+	responseCache    *responseCache
+	responseCacheTTL time.Duration
+
 	// Set for testing purposes, leave the default zero value to use the current time.
 	now time.Time
 }
@@ -46,8 +50,18 @@ const (
 
 // makeRequest does an HTTP request by formatting the URL from the given arguments and returns the response.
 // The response body must be closed by the caller when no longer used.
-func (dtc *dynatraceClient) makeRequest(url string, tokenType tokenType) (*http.Response, error) {
-	req, err := http.NewRequest("GET", url, nil)
+func (dtc *dynatraceClient) makeRequest(ctx context.Context, url string, tokenType tokenType) (*http.Response, error) {
+	req, err := dtc.newRequest(ctx, url, tokenType)
+	if err != nil {
+		return nil, err
+	}
+
+	return dtc.httpClient.Do(req)
+}
+
+// newRequest creates a GET request for the given URL with the Authorization header matching the token type.
+func (dtc *dynatraceClient) newRequest(ctx context.Context, url string, tokenType tokenType) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error initializing http request: %s", err.Error())
 	}
@@ -66,14 +80,14 @@ func (dtc *dynatraceClient) makeRequest(url string, tokenType tokenType) (*http.
 		}
 		authHeader = fmt.Sprintf("Api-Token %s", dtc.paasToken)
 	case installerUrlToken:
-		return dtc.httpClient.Do(req)
+		return req, nil
 	default:
 		return nil, errors.New("unable to determine token to set in headers")
 	}
 
 	req.Header.Add("Authorization", authHeader)
 
-	return dtc.httpClient.Do(req)
+	return req, nil
 }
 
 func (dtc *dynatraceClient) getServerResponseData(response *http.Response) ([]byte, error) {
@@ -90,8 +104,8 @@ func (dtc *dynatraceClient) getServerResponseData(response *http.Response) ([]by
 	return responseData, nil
 }
 
-func (dtc *dynatraceClient) makeRequestAndUnmarshal(url string, token tokenType, response interface{}) error {
-	resp, err := dtc.makeRequest(url, token)
+func (dtc *dynatraceClient) makeRequestAndUnmarshal(ctx context.Context, url string, token tokenType, response interface{}) error {
+	resp, err := dtc.makeRequest(ctx, url, token)
 	if err != nil {
 		return err
 	}
@@ -105,27 +119,6 @@ func (dtc *dynatraceClient) makeRequestAndUnmarshal(url string, token tokenType,
 	return json.Unmarshal(responseData, &response)
 }
 
-func (dtc *dynatraceClient) makeRequestForBinary(url string, token tokenType, writer io.Writer) (string, error) {
-	resp, err := dtc.makeRequest(url, token)
-	if err != nil {
-		return "", err
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK {
-		var errorResponse serverErrorResponse
-		err = json.NewDecoder(resp.Body).Decode(&errorResponse)
-		if err != nil {
-			return "", err
-		}
-		return "", fmt.Errorf("dynatrace server error %d: %s", errorResponse.ErrorMessage.Code, errorResponse.ErrorMessage.Message)
-	}
-
-	hash := md5.New()
-	_, err = io.Copy(writer, io.TeeReader(resp.Body, hash))
-	return hex.EncodeToString(hash.Sum(nil)), err
-}
-
 func (dtc *dynatraceClient) handleErrorResponseFromAPI(response []byte, statusCode int) error {
 	se := serverErrorResponse{}
 	if err := json.Unmarshal(response, &se); err != nil {
@@ -135,9 +128,9 @@ func (dtc *dynatraceClient) handleErrorResponseFromAPI(response []byte, statusCo
 	return se.ErrorMessage
 }
 
-func (dtc *dynatraceClient) getHostInfoForIP(ip string) (*hostInfo, error) {
+func (dtc *dynatraceClient) getHostInfoForIP(ctx context.Context, ip string) (*hostInfo, error) {
 	if len(dtc.hostCache) == 0 {
-		err := dtc.buildHostCache()
+		err := dtc.buildHostCache(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("error building hostcache from dynatrace cluster: %w", err)
 		}
@@ -151,12 +144,12 @@ func (dtc *dynatraceClient) getHostInfoForIP(ip string) (*hostInfo, error) {
 	}
 }
 
-func (dtc *dynatraceClient) buildHostCache() error {
+func (dtc *dynatraceClient) buildHostCache(ctx context.Context) error {
 	if dtc.disableHostsRequests {
 		return nil
 	}
 
-	resp, err := dtc.makeRequest(dtc.getHostsUrl(), dynatraceApiToken)
+	resp, err := dtc.makeRequest(ctx, dtc.getHostsUrl(), dynatraceApiToken)
 	if err != nil {
 		return errors.WithStack(err)
 	}
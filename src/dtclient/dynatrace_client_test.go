@@ -1,6 +1,7 @@
 package dtclient
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -29,17 +30,40 @@ func TestMakeRequest(t *testing.T) {
 
 	{
 		url := fmt.Sprintf("%s/v1/deployment/installer/agent/connectioninfo", dc.url)
-		resp, err := dc.makeRequest(url, dynatraceApiToken)
+		resp, err := dc.makeRequest(context.TODO(), url, dynatraceApiToken)
 		assert.NoError(t, err)
 		assert.NotNil(t, resp)
 	}
 	{
-		resp, err := dc.makeRequest("%s/v1/deployment/installer/agent/connectioninfo", dynatraceApiToken)
+		resp, err := dc.makeRequest(context.TODO(), "%s/v1/deployment/installer/agent/connectioninfo", dynatraceApiToken)
 		assert.Error(t, err, "unsupported protocol scheme")
 		assert.Nil(t, resp)
 	}
 }
 
+func TestMakeRequest_CanceledContext(t *testing.T) {
+	dynatraceServer := httptest.NewServer(dynatraceServerHandler())
+	defer dynatraceServer.Close()
+
+	dc := &dynatraceClient{
+		url:       dynatraceServer.URL,
+		apiToken:  apiToken,
+		paasToken: paasToken,
+
+		hostCache:  make(map[string]hostInfo),
+		httpClient: http.DefaultClient,
+	}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+
+	url := fmt.Sprintf("%s/v1/deployment/installer/agent/connectioninfo", dc.url)
+	resp, err := dc.makeRequest(ctx, url, dynatraceApiToken)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, resp)
+}
+
 func TestGetResponseOrServerError(t *testing.T) {
 	dynatraceServer := httptest.NewServer(dynatraceServerHandler())
 	defer dynatraceServer.Close()
@@ -57,7 +81,7 @@ func TestGetResponseOrServerError(t *testing.T) {
 
 	reqURL := fmt.Sprintf("%s/v1/deployment/installer/agent/connectioninfo", dc.url)
 	{
-		resp, err := dc.makeRequest(reqURL, dynatraceApiToken)
+		resp, err := dc.makeRequest(context.TODO(), reqURL, dynatraceApiToken)
 		assert.NoError(t, err)
 		assert.NotNil(t, resp)
 
@@ -83,13 +107,13 @@ func TestBuildHostCache(t *testing.T) {
 	require.NotNil(t, dc)
 
 	{
-		err := dc.buildHostCache()
+		err := dc.buildHostCache(context.TODO())
 		assert.Error(t, err, "error querying dynatrace server")
 		assert.Empty(t, dc.hostCache)
 	}
 	{
 		dc.apiToken = apiToken
-		err := dc.buildHostCache()
+		err := dc.buildHostCache(context.TODO())
 		assert.NoError(t, err)
 		assert.NotZero(t, len(dc.hostCache))
 		assert.ObjectsAreEqualValues(dc.hostCache, map[string]hostInfo{
@@ -220,7 +244,7 @@ func TestIgnoreNonCurrentlySeenHosts(t *testing.T) {
 	}
 ]`)))
 
-	info, err := c.getHostInfoForIP("1.1.1.1")
+	info, err := c.getHostInfoForIP(context.TODO(), "1.1.1.1")
 	require.NoError(t, err)
 	require.Equal(t, "HOST-42", info.entityID)
 	require.Equal(t, "1.195.0.20200515-045253", info.version)
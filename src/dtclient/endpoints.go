@@ -1,6 +1,9 @@
 package dtclient
 
-import "fmt"
+import (
+	"fmt"
+	"net/url"
+)
 
 func (dtc *dynatraceClient) getAgentUrl(os, installerType, flavor, arch, version string, technologies []string) string {
 	url := fmt.Sprintf("%s/v1/deployment/installer/agent/%s/%s/version/%s?flavor=%s&arch=%s&bitness=64",
@@ -47,6 +50,14 @@ func (dtc *dynatraceClient) getSettingsUrl(validate bool) string {
 	return fmt.Sprintf("%s/v2/settings/objects%s", dtc.url, validationQuery)
 }
 
+func (dtc *dynatraceClient) getSettingsObjectUrl(objectID string) string {
+	return fmt.Sprintf("%s/v2/settings/objects/%s", dtc.url, url.PathEscape(objectID))
+}
+
+func (dtc *dynatraceClient) getSettingsSchemaUrl(schemaID string) string {
+	return fmt.Sprintf("%s/v2/settings/schemas/%s", dtc.url, url.PathEscape(schemaID))
+}
+
 func (dtc *dynatraceClient) getProcessModuleConfigUrl() string {
 	return fmt.Sprintf("%s/v1/deployment/installer/agent/processmoduleconfig", dtc.url)
 }
@@ -55,6 +66,10 @@ func (dtc *dynatraceClient) getEventsUrl() string {
 	return fmt.Sprintf("%s/v1/events", dtc.url)
 }
 
+func (dtc *dynatraceClient) getEventsV2Url() string {
+	return fmt.Sprintf("%s/v2/events/ingest", dtc.url)
+}
+
 func (dtc *dynatraceClient) getTokensLookupUrl() string {
 	return fmt.Sprintf("%s/v1/tokens/lookup", dtc.url)
 }
@@ -0,0 +1,171 @@
+package dtclient
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// primaryURLRetryInterval is how long requests are sent to a failover API URL before the primary API URL is tried again
+const primaryURLRetryInterval = 15 * time.Minute
+
+// sharedActiveURLs is used by every client created via NewClient, so the clients which are created for every
+// reconciliation start with the API URL that was reachable last, instead of trying the unreachable ones again.
+var sharedActiveURLs = newActiveURLs()
+
+// activeURLs remembers the index of the reachable API URL per list of API URLs. A failover is forgotten after
+// primaryURLRetryInterval, so the primary API URL is used again once it is reachable again.
+type activeURLs struct {
+	mutex   sync.Mutex
+	indices map[string]activeURL
+	now     func() time.Time
+}
+
+type activeURL struct {
+	index int
+	since time.Time
+}
+
+func newActiveURLs() *activeURLs {
+	return &activeURLs{indices: make(map[string]activeURL), now: time.Now}
+}
+
+func (active *activeURLs) get(apiURLs []string) int {
+	active.mutex.Lock()
+	defer active.mutex.Unlock()
+
+	key := activeURLsKey(apiURLs)
+	entry := active.indices[key]
+	if entry.index != 0 && active.now().Sub(entry.since) >= primaryURLRetryInterval {
+		delete(active.indices, key)
+		return 0
+	}
+	return entry.index
+}
+
+func (active *activeURLs) set(apiURLs []string, index int) {
+	active.mutex.Lock()
+	defer active.mutex.Unlock()
+
+	active.indices[activeURLsKey(apiURLs)] = activeURL{index: index, since: active.now()}
+}
+
+func activeURLsKey(apiURLs []string) string {
+	return strings.Join(apiURLs, " ")
+}
+
+// failoverTransport sends the requests for the primary API URL to the first API URL which can be reached.
+// It starts with the API URL which answered last, on connection errors the following ones are tried in order.
+// After primaryURLRetryInterval it starts with the primary API URL again.
+// Requests to other URLs, e.g. custom installer URLs, are passed through.
+type failoverTransport struct {
+	next    http.RoundTripper
+	apiURLs []string
+	parsed  []*url.URL
+	active  *activeURLs
+}
+
+func newFailoverTransport(next http.RoundTripper, apiURLs []string) *failoverTransport {
+	transport := &failoverTransport{
+		next:   next,
+		active: sharedActiveURLs,
+	}
+	for _, apiURL := range apiURLs {
+		parsed, err := url.Parse(apiURL)
+		if err != nil {
+			log.Info("could not parse failover API URL, it is ignored", "url", apiURL, "error", err.Error())
+			continue
+		}
+		transport.apiURLs = append(transport.apiURLs, apiURL)
+		transport.parsed = append(transport.parsed, parsed)
+	}
+	return transport
+}
+
+// activeURL returns the API URL which answered last.
+func (transport *failoverTransport) activeURL() string {
+	return transport.apiURLs[transport.active.get(transport.apiURLs)%len(transport.apiURLs)]
+}
+
+func (transport *failoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(transport.parsed) < 2 || !hasBaseURL(req.URL, transport.parsed[0]) || (req.Body != nil && req.GetBody == nil) {
+		return transport.next.RoundTrip(req)
+	}
+
+	start := transport.active.get(transport.apiURLs) % len(transport.parsed)
+	var err error
+	for i := range transport.parsed {
+		index := (start + i) % len(transport.parsed)
+		attempt, rewriteErr := transport.rewrite(req, index, i > 0)
+		if rewriteErr != nil {
+			return nil, rewriteErr
+		}
+
+		var resp *http.Response
+		resp, err = transport.next.RoundTrip(attempt)
+		if err == nil {
+			if index != start {
+				log.Info("failed over to the next dynatrace api url", "url", transport.apiURLs[index])
+				transport.active.set(transport.apiURLs, index)
+			}
+			return resp, nil
+		}
+
+		if req.Context().Err() != nil || !canFailOver(req, err) {
+			return nil, err
+		}
+		log.Info("dynatrace api url not reachable", "url", transport.apiURLs[index], "error", err.Error())
+	}
+	return nil, err
+}
+
+// rewrite returns a copy of the request which is sent to the API URL with the given index.
+func (transport *failoverTransport) rewrite(req *http.Request, index int, resendBody bool) (*http.Request, error) {
+	if index == 0 && !resendBody {
+		return req, nil
+	}
+
+	attempt := req.Clone(req.Context())
+	target := transport.parsed[index]
+	attempt.URL.Scheme = target.Scheme
+	attempt.URL.Host = target.Host
+	attempt.URL.Path = target.Path + strings.TrimPrefix(req.URL.Path, transport.parsed[0].Path)
+	attempt.URL.RawPath = ""
+	attempt.Host = target.Host
+
+	if resendBody && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		attempt.Body = body
+	}
+	return attempt, nil
+}
+
+func hasBaseURL(requestURL, baseURL *url.URL) bool {
+	return requestURL.Scheme == baseURL.Scheme &&
+		requestURL.Host == baseURL.Host &&
+		strings.HasPrefix(requestURL.Path, baseURL.Path)
+}
+
+// canFailOver reports whether the request can be sent to the next API URL. Requests which may have reached the
+// server are only repeated if they are idempotent.
+func canFailOver(req *http.Request, err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	return isIdempotent(req)
+}
+
+func (dtc *dynatraceClient) GetActiveURL() string {
+	if dtc.failover == nil || len(dtc.failover.apiURLs) == 0 {
+		return dtc.url
+	}
+	return dtc.failover.activeURL()
+}
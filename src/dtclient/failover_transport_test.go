@@ -0,0 +1,119 @@
+package dtclient
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFailoverTransport(apiURLs ...string) *failoverTransport {
+	transport := newFailoverTransport(http.DefaultTransport, apiURLs)
+	transport.active = newActiveURLs()
+	return transport
+}
+
+func newUnreachableURL() string {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	return server.URL
+}
+
+func TestFailoverTransport(t *testing.T) {
+	t.Run(`fails over to the next reachable url and keeps using it`, func(t *testing.T) {
+		var paths []string
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			paths = append(paths, request.URL.Path)
+			writer.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		unreachableURL := newUnreachableURL()
+		transport := newTestFailoverTransport(unreachableURL+"/e/primary/api", server.URL+"/e/failover/api")
+		client := &http.Client{Transport: transport}
+
+		for i := 0; i < 2; i++ {
+			resp, err := client.Get(unreachableURL + "/e/primary/api/v1/deployment/installer/agent/connectioninfo")
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		}
+
+		assert.Equal(t, []string{
+			"/e/failover/api/v1/deployment/installer/agent/connectioninfo",
+			"/e/failover/api/v1/deployment/installer/agent/connectioninfo",
+		}, paths)
+		assert.Equal(t, server.URL+"/e/failover/api", transport.activeURL())
+	})
+	t.Run(`resends the body of POST requests which could not connect`, func(t *testing.T) {
+		var body string
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			content, _ := ioutil.ReadAll(request.Body)
+			body = string(content)
+			writer.WriteHeader(http.StatusCreated)
+		}))
+		defer server.Close()
+
+		unreachableURL := newUnreachableURL()
+		client := &http.Client{Transport: newTestFailoverTransport(unreachableURL+"/api", server.URL+"/api")}
+
+		resp, err := client.Post(unreachableURL+"/api/v2/settings/objects", "application/json", strings.NewReader(`{"value":1}`))
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, `{"value":1}`, body)
+	})
+	t.Run(`passes through requests to other urls`, func(t *testing.T) {
+		var requests int
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+			requests++
+			writer.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		unreachableURL := newUnreachableURL()
+		transport := newTestFailoverTransport(server.URL+"/api", server.URL+"/failover/api")
+		client := &http.Client{Transport: transport}
+
+		_, err := client.Get(unreachableURL + "/installer")
+		require.Error(t, err)
+		assert.Zero(t, requests)
+		assert.Equal(t, server.URL+"/api", transport.activeURL())
+	})
+	t.Run(`returns the error if no url is reachable`, func(t *testing.T) {
+		unreachableURL := newUnreachableURL()
+		client := &http.Client{Transport: newTestFailoverTransport(unreachableURL+"/api", newUnreachableURL()+"/api")}
+
+		_, err := client.Get(unreachableURL + "/api/v1/time")
+		require.Error(t, err)
+	})
+	t.Run(`tries the primary url again after the retry interval`, func(t *testing.T) {
+		var paths []string
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			paths = append(paths, request.URL.Path)
+			writer.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		now := time.Now()
+		transport := newTestFailoverTransport(server.URL+"/primary/api", server.URL+"/failover/api")
+		transport.active.now = func() time.Time { return now }
+		transport.active.set(transport.apiURLs, 1)
+		client := &http.Client{Transport: transport}
+
+		for _, elapsed := range []time.Duration{primaryURLRetryInterval - time.Second, primaryURLRetryInterval} {
+			now = now.Add(elapsed)
+			resp, err := client.Get(server.URL + "/primary/api/v1/time")
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+		}
+
+		assert.Equal(t, []string{"/failover/api/v1/time", "/primary/api/v1/time"}, paths)
+		assert.Equal(t, server.URL+"/primary/api", transport.activeURL())
+	})
+}
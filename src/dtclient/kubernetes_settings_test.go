@@ -1,6 +1,7 @@
 package dtclient
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -33,7 +34,7 @@ func TestDynatraceClient_GetMonitoredEntitiesForKubeSystemUUID(t *testing.T) {
 		require.NotNil(t, dtc)
 
 		// act
-		actual, err := dtc.(*dynatraceClient).GetMonitoredEntitiesForKubeSystemUUID(testUID)
+		actual, err := dtc.(*dynatraceClient).GetMonitoredEntitiesForKubeSystemUUID(context.TODO(), testUID)
 
 		// assert
 		assert.NotNil(t, actual)
@@ -55,7 +56,7 @@ func TestDynatraceClient_GetMonitoredEntitiesForKubeSystemUUID(t *testing.T) {
 		require.NotNil(t, dtc)
 
 		// act
-		actual, err := dtc.(*dynatraceClient).GetMonitoredEntitiesForKubeSystemUUID(testUID)
+		actual, err := dtc.(*dynatraceClient).GetMonitoredEntitiesForKubeSystemUUID(context.TODO(), testUID)
 
 		// assert
 		assert.NotNil(t, actual)
@@ -77,7 +78,7 @@ func TestDynatraceClient_GetMonitoredEntitiesForKubeSystemUUID(t *testing.T) {
 		require.NotNil(t, dtc)
 
 		// act
-		actual, err := dtc.(*dynatraceClient).GetMonitoredEntitiesForKubeSystemUUID("")
+		actual, err := dtc.(*dynatraceClient).GetMonitoredEntitiesForKubeSystemUUID(context.TODO(), "")
 
 		// assert
 		assert.Nil(t, actual)
@@ -98,7 +99,7 @@ func TestDynatraceClient_GetMonitoredEntitiesForKubeSystemUUID(t *testing.T) {
 		require.NotNil(t, dtc)
 
 		// act
-		actual, err := dtc.(*dynatraceClient).GetMonitoredEntitiesForKubeSystemUUID(testUID)
+		actual, err := dtc.(*dynatraceClient).GetMonitoredEntitiesForKubeSystemUUID(context.TODO(), testUID)
 
 		// assert
 		assert.Nil(t, actual)
@@ -122,7 +123,7 @@ func TestDynatraceClient_GetSettingsForMonitoredEntities(t *testing.T) {
 		require.NotNil(t, dtc)
 
 		// act
-		actual, err := dtc.(*dynatraceClient).GetSettingsForMonitoredEntities(expected)
+		actual, err := dtc.(*dynatraceClient).GetSettingsForMonitoredEntities(context.TODO(), expected)
 
 		// assert
 		assert.NoError(t, err)
@@ -145,7 +146,7 @@ func TestDynatraceClient_GetSettingsForMonitoredEntities(t *testing.T) {
 		require.NotNil(t, dtc)
 
 		// act
-		actual, err := dtc.(*dynatraceClient).GetSettingsForMonitoredEntities(expected)
+		actual, err := dtc.(*dynatraceClient).GetSettingsForMonitoredEntities(context.TODO(), expected)
 
 		// assert
 		assert.NoError(t, err)
@@ -169,7 +170,7 @@ func TestDynatraceClient_GetSettingsForMonitoredEntities(t *testing.T) {
 		require.NotNil(t, dtc)
 
 		// act
-		actual, err := dtc.(*dynatraceClient).GetSettingsForMonitoredEntities(entities)
+		actual, err := dtc.(*dynatraceClient).GetSettingsForMonitoredEntities(context.TODO(), entities)
 
 		// assert
 		assert.NoError(t, err)
@@ -192,7 +193,7 @@ func TestDynatraceClient_GetSettingsForMonitoredEntities(t *testing.T) {
 		require.NotNil(t, dtc)
 
 		// act
-		actual, err := dtc.(*dynatraceClient).GetSettingsForMonitoredEntities(entities)
+		actual, err := dtc.(*dynatraceClient).GetSettingsForMonitoredEntities(context.TODO(), entities)
 
 		// assert
 		assert.Error(t, err)
@@ -212,7 +213,7 @@ func TestDynatraceClient_CreateOrUpdateKubernetesSetting(t *testing.T) {
 		require.NotNil(t, dtc)
 
 		// act
-		actual, err := dtc.(*dynatraceClient).CreateOrUpdateKubernetesSetting(testName, testUID, testScope)
+		actual, err := dtc.(*dynatraceClient).CreateOrUpdateKubernetesSetting(context.TODO(), testName, testUID, testScope)
 
 		// assert
 		assert.NotNil(t, actual)
@@ -232,7 +233,7 @@ func TestDynatraceClient_CreateOrUpdateKubernetesSetting(t *testing.T) {
 		require.NotNil(t, dtc)
 
 		// act
-		actual, err := dtc.(*dynatraceClient).CreateOrUpdateKubernetesSetting(testName, "", testScope)
+		actual, err := dtc.(*dynatraceClient).CreateOrUpdateKubernetesSetting(context.TODO(), testName, "", testScope)
 
 		// assert
 		assert.Error(t, err)
@@ -250,7 +251,7 @@ func TestDynatraceClient_CreateOrUpdateKubernetesSetting(t *testing.T) {
 		require.NotNil(t, dtc)
 
 		// act
-		actual, err := dtc.(*dynatraceClient).CreateOrUpdateKubernetesSetting(testName, testUID, testScope)
+		actual, err := dtc.(*dynatraceClient).CreateOrUpdateKubernetesSetting(context.TODO(), testName, testUID, testScope)
 
 		// assert
 		assert.Error(t, err)
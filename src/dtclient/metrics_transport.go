@@ -0,0 +1,75 @@
+package dtclient
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const (
+	endpointLabel   = "endpoint"
+	methodLabel     = "method"
+	statusCodeLabel = "status_code"
+
+	// statusCodeError is used as status code label if no response was received, e.g. on timeouts.
+	statusCodeError = "error"
+	// endpointOther is used for URLs outside the known API, e.g. custom installer URLs.
+	endpointOther = "other"
+)
+
+// apiEndpoints maps request paths to the endpoint label, the path is matched without the environment's base path,
+// so the label has a bounded number of values. See endpoints.go for the URLs.
+var apiEndpoints = []struct {
+	name    string
+	pattern *regexp.Regexp
+}{
+	{name: "agent_connection_info", pattern: regexp.MustCompile(`/v1/deployment/installer/agent/connectioninfo$`)},
+	{name: "activegate_connection_info", pattern: regexp.MustCompile(`/v1/deployment/installer/gateway/connectioninfo$`)},
+	{name: "process_module_config", pattern: regexp.MustCompile(`/v1/deployment/installer/agent/processmoduleconfig$`)},
+	{name: "agent_versions", pattern: regexp.MustCompile(`/v1/deployment/installer/agent/versions/[^/]+/[^/]+$`)},
+	{name: "latest_agent_version", pattern: regexp.MustCompile(`/v1/deployment/installer/agent/[^/]+/[^/]+/latest/metainfo$`)},
+	{name: "agent_download", pattern: regexp.MustCompile(`/v1/deployment/installer/agent/[^/]+/[^/]+/(latest|version/[^/]+)$`)},
+	{name: "hosts", pattern: regexp.MustCompile(`/v1/entity/infrastructure/hosts$`)},
+	{name: "entities", pattern: regexp.MustCompile(`/v2/entities$`)},
+	{name: "settings_objects", pattern: regexp.MustCompile(`/v2/settings/objects$`)},
+	{name: "settings_object", pattern: regexp.MustCompile(`/v2/settings/objects/[^/]+$`)},
+	{name: "events", pattern: regexp.MustCompile(`/v1/events$`)},
+	{name: "events_v2", pattern: regexp.MustCompile(`/v2/events/ingest$`)},
+	{name: "tokens_lookup", pattern: regexp.MustCompile(`/v1/tokens/lookup$`)},
+}
+
+// metricsTransport records the count, status code and latency of every request it sends.
+// It is wrapped by the retryTransport, so each attempt is counted and throttling shows up as 429s.
+type metricsTransport struct {
+	next http.RoundTripper
+}
+
+func newMetricsTransport(next http.RoundTripper) *metricsTransport {
+	return &metricsTransport{next: next}
+}
+
+func (transport *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	endpoint := endpointName(req.URL.Path)
+	start := time.Now()
+
+	resp, err := transport.next.RoundTrip(req)
+
+	apiRequestDurationMetric.WithLabelValues(endpoint, req.Method).Observe(time.Since(start).Seconds())
+	statusCode := statusCodeError
+	if err == nil {
+		statusCode = strconv.Itoa(resp.StatusCode)
+	}
+	apiRequestsMetric.WithLabelValues(endpoint, req.Method, statusCode).Inc()
+
+	return resp, err
+}
+
+func endpointName(path string) string {
+	for _, endpoint := range apiEndpoints {
+		if endpoint.pattern.MatchString(path) {
+			return endpoint.name
+		}
+	}
+	return endpointOther
+}
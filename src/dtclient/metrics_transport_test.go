@@ -0,0 +1,67 @@
+package dtclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsTransport(t *testing.T) {
+	t.Run(`counts requests per endpoint and status code`, func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if request.URL.Path == "/api/v1/tokens/lookup" {
+				writer.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			writer.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+		client := &http.Client{Transport: newMetricsTransport(http.DefaultTransport)}
+		unauthorized := apiRequestsMetric.WithLabelValues("tokens_lookup", http.MethodPost, "401")
+		ok := apiRequestsMetric.WithLabelValues("agent_connection_info", http.MethodGet, "200")
+		unauthorizedBefore, okBefore := testutil.ToFloat64(unauthorized), testutil.ToFloat64(ok)
+
+		resp, err := client.Post(server.URL+"/api/v1/tokens/lookup", "application/json", nil)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		resp, err = client.Get(server.URL + "/api/v1/deployment/installer/agent/connectioninfo")
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+
+		assert.Equal(t, unauthorizedBefore+1, testutil.ToFloat64(unauthorized))
+		assert.Equal(t, okBefore+1, testutil.ToFloat64(ok))
+	})
+	t.Run(`counts transport errors`, func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+		client := &http.Client{Transport: newMetricsTransport(http.DefaultTransport)}
+		failed := apiRequestsMetric.WithLabelValues("hosts", http.MethodGet, statusCodeError)
+		before := testutil.ToFloat64(failed)
+
+		_, err := client.Get(server.URL + "/e/tenant/api/v1/entity/infrastructure/hosts?includeDetails=false")
+
+		require.Error(t, err)
+		assert.Equal(t, before+1, testutil.ToFloat64(failed))
+	})
+}
+
+func TestEndpointName(t *testing.T) {
+	for path, expected := range map[string]string{
+		"/api/v1/deployment/installer/agent/unix/paas/latest":            "agent_download",
+		"/api/v1/deployment/installer/agent/unix/paas/version/1.2.3.4-5": "agent_download",
+		"/api/v1/deployment/installer/agent/unix/paas/latest/metainfo":   "latest_agent_version",
+		"/api/v1/deployment/installer/agent/versions/unix/paas":          "agent_versions",
+		"/api/v1/deployment/installer/agent/processmoduleconfig":         "process_module_config",
+		"/api/v1/deployment/installer/gateway/connectioninfo":            "activegate_connection_info",
+		"/e/abc/api/v2/settings/objects":                                 "settings_objects",
+		"/api/v2/events/ingest":                                          "events_v2",
+		"/api/v2/entities":                                               "entities",
+		"/installer/agent.zip":                                           endpointOther,
+	} {
+		assert.Equal(t, expected, endpointName(path), path)
+	}
+}
@@ -1,92 +1,139 @@
 package dtclient
 
 import (
+	"context"
 	"io"
 
 	"github.com/stretchr/testify/mock"
 )
 
 // MockDynatraceClient implements a Dynatrace REST API Client mock
+// The context is not passed on to the expectations, so they match regardless of it.
 type MockDynatraceClient struct {
 	mock.Mock
 }
 
-func (o *MockDynatraceClient) GetAgentTenantInfo() (*AgentTenantInfo, error) {
+func (o *MockDynatraceClient) GetAgentTenantInfo(_ context.Context) (*AgentTenantInfo, error) {
 	args := o.Called()
 	return args.Get(0).(*AgentTenantInfo), args.Error(1)
 }
 
-func (o *MockDynatraceClient) GetActiveGateTenantInfo() (*ActiveGateTenantInfo, error) {
+func (o *MockDynatraceClient) GetActiveGateTenantInfo(_ context.Context) (*ActiveGateTenantInfo, error) {
 	args := o.Called()
 	return args.Get(0).(*ActiveGateTenantInfo), args.Error(1)
 }
 
-func (o *MockDynatraceClient) GetLatestAgentVersion(os, installerType string) (string, error) {
+func (o *MockDynatraceClient) GetLatestAgentVersion(_ context.Context, os, installerType string) (string, error) {
 	args := o.Called(os, installerType)
 	return args.String(0), args.Error(1)
 }
 
-func (o *MockDynatraceClient) GetLatestAgent(os, installerType, flavor, arch string, technologies []string, writer io.Writer) error {
+func (o *MockDynatraceClient) GetLatestAgent(_ context.Context, os, installerType, flavor, arch string, technologies []string, writer io.Writer) error {
 	args := o.Called(os, installerType, flavor, arch, technologies, writer)
 	return args.Error(0)
 }
 
-func (o *MockDynatraceClient) GetAgent(os, installerType, flavor, arch, version string, technologies []string, writer io.Writer) error {
+func (o *MockDynatraceClient) GetAgent(_ context.Context, os, installerType, flavor, arch, version string, technologies []string, writer io.Writer) error {
 	args := o.Called(os, installerType, flavor, arch, version, technologies, writer)
 	return args.Error(0)
 }
 
-func (o *MockDynatraceClient) GetAgentViaInstallerUrl(url string, writer io.Writer) error {
+func (o *MockDynatraceClient) GetAgentViaInstallerUrl(_ context.Context, url string, writer io.Writer) error {
 	args := o.Called(url, writer)
 	return args.Error(0)
 }
 
-func (o *MockDynatraceClient) GetAgentVersions(os, installerType, flavor, arch string) ([]string, error) {
+func (o *MockDynatraceClient) GetAgentVersions(_ context.Context, os, installerType, flavor, arch string) ([]string, error) {
 	args := o.Called(os, installerType, flavor, arch)
 	return args.Get(0).([]string), args.Error(1)
 }
 
-func (o *MockDynatraceClient) GetConnectionInfo() (ConnectionInfo, error) {
+func (o *MockDynatraceClient) GetConnectionInfo(_ context.Context) (ConnectionInfo, error) {
 	args := o.Called()
 	return args.Get(0).(ConnectionInfo), args.Error(1)
 }
 
-func (o *MockDynatraceClient) GetCommunicationHostForClient() (CommunicationHost, error) {
+func (o *MockDynatraceClient) GetCommunicationHostForClient(_ context.Context) (CommunicationHost, error) {
 	args := o.Called()
 	return args.Get(0).(CommunicationHost), args.Error(1)
 }
 
-func (o *MockDynatraceClient) GetProcessModuleConfig(prevRevision uint) (*ProcessModuleConfig, error) {
+func (o *MockDynatraceClient) GetActiveURL() string {
+	args := o.Called()
+	return args.String(0)
+}
+
+func (o *MockDynatraceClient) GetProcessModuleConfig(_ context.Context, prevRevision uint) (*ProcessModuleConfig, error) {
 	args := o.Called(prevRevision)
 	return args.Get(0).(*ProcessModuleConfig), args.Error(1)
 }
 
-func (o *MockDynatraceClient) SendEvent(event *EventData) error {
+func (o *MockDynatraceClient) SendEvent(_ context.Context, event *EventData) error {
+	args := o.Called(event)
+	return args.Error(0)
+}
+
+func (o *MockDynatraceClient) SendEventV2(_ context.Context, event *EventDataV2) error {
 	args := o.Called(event)
 	return args.Error(0)
 }
 
-func (o *MockDynatraceClient) GetEntityIDForIP(ip string) (string, error) {
+func (o *MockDynatraceClient) GetEntityIDForIP(_ context.Context, ip string) (string, error) {
 	args := o.Called(ip)
 	return args.String(0), args.Error(1)
 }
 
-func (o *MockDynatraceClient) GetTokenScopes(token string) (TokenScopes, error) {
+func (o *MockDynatraceClient) GetTokenScopes(_ context.Context, token string) (TokenScopes, error) {
 	args := o.Called(token)
 	return args.Get(0).(TokenScopes), args.Error(1)
 }
 
-func (o *MockDynatraceClient) CreateOrUpdateKubernetesSetting(name string, kubeSystemUUID string, scope string) (string, error) {
+func (o *MockDynatraceClient) GetTokenInfo(_ context.Context, token string) (*TokenInfo, error) {
+	args := o.Called(token)
+	return args.Get(0).(*TokenInfo), args.Error(1)
+}
+
+func (o *MockDynatraceClient) CreateOrUpdateKubernetesSetting(_ context.Context, name string, kubeSystemUUID string, scope string) (string, error) {
 	args := o.Called(name, kubeSystemUUID, scope)
 	return args.String(0), args.Error(1)
 }
 
-func (o *MockDynatraceClient) GetMonitoredEntitiesForKubeSystemUUID(kubeSystemUUID string) ([]MonitoredEntity, error) {
+func (o *MockDynatraceClient) GetMonitoredEntitiesForKubeSystemUUID(_ context.Context, kubeSystemUUID string) ([]MonitoredEntity, error) {
 	args := o.Called(kubeSystemUUID)
 	return args.Get(0).([]MonitoredEntity), args.Error(1)
 }
 
-func (o *MockDynatraceClient) GetSettingsForMonitoredEntities(monitoredEntities []MonitoredEntity) (GetSettingsResponse, error) {
+func (o *MockDynatraceClient) GetSettingsForMonitoredEntities(_ context.Context, monitoredEntities []MonitoredEntity) (GetSettingsResponse, error) {
 	args := o.Called(monitoredEntities)
 	return args.Get(0).(GetSettingsResponse), args.Error(1)
 }
+
+func (o *MockDynatraceClient) ListSettingsObjects(_ context.Context, schemaID, scope string) ([]SettingsObject, error) {
+	args := o.Called(schemaID, scope)
+	return args.Get(0).([]SettingsObject), args.Error(1)
+}
+
+func (o *MockDynatraceClient) GetSettingsSchema(_ context.Context, schemaID string) (*SettingsSchema, error) {
+	args := o.Called(schemaID)
+	return args.Get(0).(*SettingsSchema), args.Error(1)
+}
+
+func (o *MockDynatraceClient) GetSettingsObject(_ context.Context, objectID string) (*SettingsObject, error) {
+	args := o.Called(objectID)
+	return args.Get(0).(*SettingsObject), args.Error(1)
+}
+
+func (o *MockDynatraceClient) CreateSettingsObject(_ context.Context, object SettingsObject) (string, error) {
+	args := o.Called(object)
+	return args.String(0), args.Error(1)
+}
+
+func (o *MockDynatraceClient) UpdateSettingsObject(_ context.Context, object SettingsObject) error {
+	args := o.Called(object)
+	return args.Error(0)
+}
+
+func (o *MockDynatraceClient) DeleteSettingsObject(_ context.Context, objectID string) error {
+	args := o.Called(objectID)
+	return args.Error(0)
+}
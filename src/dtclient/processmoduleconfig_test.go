@@ -1,6 +1,7 @@
 package dtclient
 
 import (
+	"context"
 	"net/http"
 	"testing"
 
@@ -32,7 +33,7 @@ func TestCreateProcessModuleConfigRequest(t *testing.T) {
 	}
 	require.NotNil(t, dc)
 
-	req, err := dc.createProcessModuleConfigRequest(0)
+	req, err := dc.createProcessModuleConfigRequest(context.TODO(), 0)
 	require.Nil(t, err)
 	assert.Equal(t, "0", req.URL.Query().Get("revision"))
 	assert.Contains(t, req.Header.Get("Authorization"), dc.paasToken)
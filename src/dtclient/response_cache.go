@@ -0,0 +1,144 @@
+package dtclient
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const maxResponseCacheEntries = 256
+
+// sharedResponseCache is used by every client, so clients built for the same tenant and tokens
+// (e.g. by different controllers reconciling the same DynaKube) reuse each other's responses.
+var sharedResponseCache = newResponseCache()
+
+type cachedResponse struct {
+	data      []byte
+	etag      string
+	expiresAt time.Time
+}
+
+type responseCache struct {
+	mutex   sync.Mutex
+	entries map[string]cachedResponse
+}
+
+func newResponseCache() *responseCache {
+	return &responseCache{
+		entries: make(map[string]cachedResponse),
+	}
+}
+
+func (cache *responseCache) get(key string) (cachedResponse, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	entry, ok := cache.entries[key]
+	return entry, ok
+}
+
+func (cache *responseCache) set(key string, entry cachedResponse, now time.Time) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if _, ok := cache.entries[key]; !ok && len(cache.entries) >= maxResponseCacheEntries {
+		cache.prune(now)
+	}
+	cache.entries[key] = entry
+}
+
+// prune drops expired entries, or everything if none have expired, to keep the cache bounded.
+func (cache *responseCache) prune(now time.Time) {
+	for key, entry := range cache.entries {
+		if now.After(entry.expiresAt) {
+			delete(cache.entries, key)
+		}
+	}
+	if len(cache.entries) >= maxResponseCacheEntries {
+		cache.entries = make(map[string]cachedResponse)
+	}
+}
+
+// makeCachedRequest behaves like makeRequest followed by getServerResponseData, but reuses successful responses
+// for the configured TTL. Once expired, the entry is revalidated with If-None-Match if the server sent an ETag.
+func (dtc *dynatraceClient) makeCachedRequest(ctx context.Context, url string, tokenType tokenType) ([]byte, error) {
+	if dtc.responseCacheTTL <= 0 {
+		return dtc.makeUncachedRequest(ctx, url, tokenType)
+	}
+
+	now := dtc.now
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+
+	key := dtc.responseCacheKey(url, tokenType)
+	entry, found := dtc.responseCache.get(key)
+	if found && now.Before(entry.expiresAt) {
+		return entry.data, nil
+	}
+
+	req, err := dtc.newRequest(ctx, url, tokenType)
+	if err != nil {
+		return nil, err
+	}
+	if found && entry.etag != "" {
+		req.Header.Set("If-None-Match", entry.etag)
+	}
+
+	resp, err := dtc.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		//Swallow error, nothing has to be done at this point
+		_ = resp.Body.Close()
+	}()
+
+	if found && resp.StatusCode == http.StatusNotModified {
+		entry.expiresAt = now.Add(dtc.responseCacheTTL)
+		dtc.responseCache.set(key, entry, now)
+		return entry.data, nil
+	}
+
+	data, err := dtc.getServerResponseData(resp)
+	if err != nil {
+		return data, err
+	}
+
+	dtc.responseCache.set(key, cachedResponse{
+		data:      data,
+		etag:      resp.Header.Get("ETag"),
+		expiresAt: now.Add(dtc.responseCacheTTL),
+	}, now)
+	return data, nil
+}
+
+func (dtc *dynatraceClient) makeUncachedRequest(ctx context.Context, url string, tokenType tokenType) ([]byte, error) {
+	resp, err := dtc.makeRequest(ctx, url, tokenType)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		//Swallow error, nothing has to be done at this point
+		_ = resp.Body.Close()
+	}()
+
+	return dtc.getServerResponseData(resp)
+}
+
+// responseCacheKey identifies a response by URL and a hash of the token used, so rotated tokens never see stale entries.
+func (dtc *dynatraceClient) responseCacheKey(url string, tokenType tokenType) string {
+	var token string
+	switch tokenType {
+	case dynatraceApiToken:
+		token = dtc.apiToken
+	case dynatracePaaSToken:
+		token = dtc.paasToken
+	}
+
+	tokenHash := sha256.Sum256([]byte(token))
+	return url + "|" + hex.EncodeToString(tokenHash[:])
+}
@@ -0,0 +1,130 @@
+package dtclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testETag = `"connection-info-v1"`
+
+type countingConnectionInfoHandler struct {
+	requests            int
+	conditionalRequests int
+}
+
+func (handler *countingConnectionInfoHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	handler.requests++
+	if request.Header.Get("If-None-Match") == testETag {
+		handler.conditionalRequests++
+		writer.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writer.Header().Set("ETag", testETag)
+	writer.WriteHeader(http.StatusOK)
+	_, _ = writer.Write([]byte(goodCommunicationEndpointsResponse))
+}
+
+func newCachingTestClient(serverURL, paasToken string, ttl time.Duration) *dynatraceClient {
+	return &dynatraceClient{
+		url:              serverURL,
+		apiToken:         apiToken,
+		paasToken:        paasToken,
+		hostCache:        make(map[string]hostInfo),
+		httpClient:       http.DefaultClient,
+		responseCache:    newResponseCache(),
+		responseCacheTTL: ttl,
+	}
+}
+
+func TestMakeCachedRequest(t *testing.T) {
+	t.Run(`reuses response within ttl`, func(t *testing.T) {
+		handler := &countingConnectionInfoHandler{}
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		dtc := newCachingTestClient(server.URL, paasToken, time.Minute)
+
+		first, err := dtc.GetConnectionInfo(context.TODO())
+		require.NoError(t, err)
+		second, err := dtc.GetConnectionInfo(context.TODO())
+		require.NoError(t, err)
+		_, err = dtc.GetAgentTenantInfo(context.TODO())
+		require.NoError(t, err)
+
+		assert.Equal(t, first, second)
+		assert.Equal(t, 1, handler.requests)
+	})
+	t.Run(`revalidates expired entry with etag`, func(t *testing.T) {
+		handler := &countingConnectionInfoHandler{}
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		dtc := newCachingTestClient(server.URL, paasToken, time.Minute)
+		dtc.now = time.Now().UTC()
+
+		_, err := dtc.GetConnectionInfo(context.TODO())
+		require.NoError(t, err)
+
+		dtc.now = dtc.now.Add(2 * time.Minute)
+		connectionInfo, err := dtc.GetConnectionInfo(context.TODO())
+		require.NoError(t, err)
+
+		assert.Equal(t, "aabb", connectionInfo.TenantUUID)
+		assert.Equal(t, 2, handler.requests)
+		assert.Equal(t, 1, handler.conditionalRequests)
+	})
+	t.Run(`different tokens do not share entries`, func(t *testing.T) {
+		handler := &countingConnectionInfoHandler{}
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		dtc := newCachingTestClient(server.URL, paasToken, time.Minute)
+		rotated := newCachingTestClient(server.URL, "rotated-token", time.Minute)
+		rotated.responseCache = dtc.responseCache
+
+		_, err := dtc.GetConnectionInfo(context.TODO())
+		require.NoError(t, err)
+		_, err = rotated.GetConnectionInfo(context.TODO())
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, handler.requests)
+	})
+	t.Run(`errors are not cached`, func(t *testing.T) {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			requests++
+			writeError(writer, http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		dtc := newCachingTestClient(server.URL, paasToken, time.Minute)
+
+		_, err := dtc.GetConnectionInfo(context.TODO())
+		assert.Error(t, err)
+		_, err = dtc.GetConnectionInfo(context.TODO())
+		assert.Error(t, err)
+
+		assert.Equal(t, 2, requests)
+	})
+	t.Run(`disabled without ttl`, func(t *testing.T) {
+		handler := &countingConnectionInfoHandler{}
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		dtc := newCachingTestClient(server.URL, paasToken, 0)
+
+		_, err := dtc.GetConnectionInfo(context.TODO())
+		require.NoError(t, err)
+		_, err = dtc.GetConnectionInfo(context.TODO())
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, handler.requests)
+		assert.Equal(t, 0, handler.conditionalRequests)
+	})
+}
@@ -0,0 +1,205 @@
+package dtclient
+
+import (
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultMaxRetries     = 3
+	defaultBaseRetryDelay = 500 * time.Millisecond
+	defaultMaxRetryDelay  = 30 * time.Second
+	defaultRequestsPerSec = 10
+	defaultRequestBurst   = 20
+	maxHonoredRetryAfter  = 5 * time.Minute
+	retryJitterFactor     = 0.5
+	drainedBodyLimit      = 4096
+
+	retryAfterHeader = "Retry-After"
+)
+
+// sharedBudget is used by every client created via NewClient, so that all DynaKubes and CSI nodes handled by
+// the same process draw from one request budget and a Retry-After from the tenant pauses all of them.
+var sharedBudget = newRequestBudget(defaultRequestsPerSec, defaultRequestBurst)
+
+// requestBudget is a token bucket that can additionally be put on hold until a point in time.
+type requestBudget struct {
+	limiter *rate.Limiter
+
+	mutex        sync.Mutex
+	blockedUntil time.Time
+}
+
+func newRequestBudget(requestsPerSecond float64, burst int) *requestBudget {
+	return &requestBudget{
+		limiter: rate.NewLimiter(rate.Limit(requestsPerSecond), burst),
+	}
+}
+
+// wait blocks until the hold is over and a token is available, or the request's context is done.
+func (budget *requestBudget) wait(req *http.Request) error {
+	if delay := time.Until(budget.heldUntil()); delay > 0 {
+		if err := sleep(req, delay); err != nil {
+			return err
+		}
+	}
+	return budget.limiter.Wait(req.Context())
+}
+
+func (budget *requestBudget) holdUntil(until time.Time) {
+	budget.mutex.Lock()
+	defer budget.mutex.Unlock()
+
+	if until.After(budget.blockedUntil) {
+		budget.blockedUntil = until
+	}
+}
+
+func (budget *requestBudget) heldUntil() time.Time {
+	budget.mutex.Lock()
+	defer budget.mutex.Unlock()
+
+	return budget.blockedUntil
+}
+
+// retryTransport wraps the client's transport, draws every request from the shared budget,
+// honors Retry-After and retries idempotent requests with jittered exponential backoff.
+type retryTransport struct {
+	next   http.RoundTripper
+	budget *requestBudget
+
+	maxRetries int
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+}
+
+func newRetryTransport(next http.RoundTripper) *retryTransport {
+	return &retryTransport{
+		next:       next,
+		budget:     sharedBudget,
+		maxRetries: defaultMaxRetries,
+		baseDelay:  defaultBaseRetryDelay,
+		maxDelay:   defaultMaxRetryDelay,
+	}
+}
+
+func (transport *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if err := transport.budget.wait(req); err != nil {
+			return nil, err
+		}
+
+		resp, err := transport.next.RoundTrip(req)
+
+		retryAfter, hasRetryAfter := parseRetryAfter(resp)
+		if hasRetryAfter {
+			transport.budget.holdUntil(time.Now().Add(retryAfter))
+		}
+
+		if attempt >= transport.maxRetries || !isIdempotent(req) || !isRetryable(resp, err) {
+			return resp, err
+		}
+
+		delay := transport.backoff(attempt)
+		if hasRetryAfter && retryAfter > delay {
+			delay = retryAfter
+		}
+
+		log.Info("retrying request to dynatrace api", "method", req.Method, "url", req.URL.Path, "attempt", attempt+1, "delay", delay.String(), "cause", retryCause(resp, err))
+		if resp != nil {
+			drainAndClose(resp)
+		}
+
+		if err := sleep(req, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// backoff returns the exponential delay for the given attempt, with up to retryJitterFactor of it randomized.
+func (transport *retryTransport) backoff(attempt int) time.Duration {
+	delay := float64(transport.baseDelay) * math.Pow(2, float64(attempt))
+	if delay > float64(transport.maxDelay) {
+		delay = float64(transport.maxDelay)
+	}
+	jitter := delay * retryJitterFactor * rand.Float64() //nolint:gosec // jitter does not need a secure source
+	return time.Duration(delay - jitter)
+}
+
+func isIdempotent(req *http.Request) bool {
+	return req.Method == http.MethodGet || req.Method == http.MethodHead
+}
+
+// isRetryable reports whether the failure is transient. Plain 500s are not retried,
+// as the API uses them for errors that won't go away by themselves.
+func isRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter reads the Retry-After header of 429 and 503 responses, which is either a number of seconds or an HTTP date.
+func parseRetryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil || (resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable) {
+		return 0, false
+	}
+
+	value := resp.Header.Get(retryAfterHeader)
+	if value == "" {
+		return 0, false
+	}
+
+	var retryAfter time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		retryAfter = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		retryAfter = time.Until(date)
+	} else {
+		return 0, false
+	}
+
+	if retryAfter < 0 {
+		retryAfter = 0
+	}
+	if retryAfter > maxHonoredRetryAfter {
+		retryAfter = maxHonoredRetryAfter
+	}
+	return retryAfter, true
+}
+
+func retryCause(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return resp.Status
+}
+
+// drainAndClose reads a bit of the body before closing it, so the connection can be reused for the next attempt.
+func drainAndClose(resp *http.Response) {
+	buffer := make([]byte, drainedBodyLimit)
+	_, _ = resp.Body.Read(buffer)
+	_ = resp.Body.Close()
+}
+
+func sleep(req *http.Request, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-req.Context().Done():
+		return req.Context().Err()
+	case <-timer.C:
+		return nil
+	}
+}
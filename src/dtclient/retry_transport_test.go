@@ -0,0 +1,158 @@
+package dtclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRetryTransport(maxRetries int) *retryTransport {
+	return &retryTransport{
+		next:       http.DefaultTransport,
+		budget:     newRequestBudget(1000, 1000),
+		maxRetries: maxRetries,
+		baseDelay:  time.Millisecond,
+		maxDelay:   10 * time.Millisecond,
+	}
+}
+
+func failingHandler(failures int32, status int, requests *int32) http.HandlerFunc {
+	return func(writer http.ResponseWriter, _ *http.Request) {
+		if atomic.AddInt32(requests, 1) <= failures {
+			writer.WriteHeader(status)
+			return
+		}
+		writer.WriteHeader(http.StatusOK)
+	}
+}
+
+func TestRetryTransport(t *testing.T) {
+	t.Run(`retries GET on transient errors`, func(t *testing.T) {
+		var requests int32
+		server := httptest.NewServer(failingHandler(2, http.StatusServiceUnavailable, &requests))
+		defer server.Close()
+
+		client := &http.Client{Transport: newTestRetryTransport(3)}
+		resp, err := client.Get(server.URL)
+
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+	})
+	t.Run(`gives up after max retries`, func(t *testing.T) {
+		var requests int32
+		server := httptest.NewServer(failingHandler(10, http.StatusTooManyRequests, &requests))
+		defer server.Close()
+
+		client := &http.Client{Transport: newTestRetryTransport(2)}
+		resp, err := client.Get(server.URL)
+
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+	})
+	t.Run(`does not retry POST`, func(t *testing.T) {
+		var requests int32
+		server := httptest.NewServer(failingHandler(1, http.StatusServiceUnavailable, &requests))
+		defer server.Close()
+
+		client := &http.Client{Transport: newTestRetryTransport(3)}
+		resp, err := client.Post(server.URL, "application/json", nil)
+
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	})
+	t.Run(`does not retry internal server errors`, func(t *testing.T) {
+		var requests int32
+		server := httptest.NewServer(failingHandler(1, http.StatusInternalServerError, &requests))
+		defer server.Close()
+
+		client := &http.Client{Transport: newTestRetryTransport(3)}
+		resp, err := client.Get(server.URL)
+
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	})
+	t.Run(`Retry-After puts shared budget on hold`, func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+			writer.Header().Set(retryAfterHeader, "120")
+			writer.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		transport := newTestRetryTransport(0)
+		client := &http.Client{Transport: transport}
+		resp, err := client.Get(server.URL)
+
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.WithinDuration(t, time.Now().Add(120*time.Second), transport.budget.heldUntil(), 5*time.Second)
+	})
+	t.Run(`waiting on hold respects context`, func(t *testing.T) {
+		transport := newTestRetryTransport(0)
+		transport.budget.holdUntil(time.Now().Add(time.Hour))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost", nil)
+		require.NoError(t, err)
+
+		_, err = transport.RoundTrip(req)
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	newResponse := func(status int, retryAfter string) *http.Response {
+		resp := &http.Response{StatusCode: status, Header: http.Header{}}
+		resp.Header.Set(retryAfterHeader, retryAfter)
+		return resp
+	}
+
+	retryAfter, ok := parseRetryAfter(newResponse(http.StatusTooManyRequests, "7"))
+	assert.True(t, ok)
+	assert.Equal(t, 7*time.Second, retryAfter)
+
+	retryAfter, ok = parseRetryAfter(newResponse(http.StatusServiceUnavailable, time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)))
+	assert.True(t, ok)
+	assert.InDelta(t, time.Minute, retryAfter, float64(2*time.Second))
+
+	retryAfter, ok = parseRetryAfter(newResponse(http.StatusTooManyRequests, "86400"))
+	assert.True(t, ok)
+	assert.Equal(t, maxHonoredRetryAfter, retryAfter)
+
+	_, ok = parseRetryAfter(newResponse(http.StatusTooManyRequests, "soon"))
+	assert.False(t, ok)
+
+	_, ok = parseRetryAfter(newResponse(http.StatusOK, "7"))
+	assert.False(t, ok)
+
+	_, ok = parseRetryAfter(nil)
+	assert.False(t, ok)
+}
+
+func TestBackoff(t *testing.T) {
+	transport := newTestRetryTransport(3)
+	transport.baseDelay = 100 * time.Millisecond
+	transport.maxDelay = time.Second
+
+	for attempt := 0; attempt < 10; attempt++ {
+		delay := transport.backoff(attempt)
+		assert.LessOrEqual(t, delay, time.Second)
+		assert.Greater(t, delay, time.Duration(0))
+	}
+	assert.GreaterOrEqual(t, transport.backoff(2), 200*time.Millisecond)
+}
@@ -0,0 +1,102 @@
+package dtclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// Event types of the events API v2, see https://www.dynatrace.com/support/help/dynatrace-api/environment-api/events-v2
+const (
+	CustomDeploymentEvent = "CUSTOM_DEPLOYMENT"
+)
+
+const eventIngestStatusOK = "OK"
+
+// EventDataV2 defines the payload of an event ingested via the events API v2
+type EventDataV2 struct {
+	EventType string `json:"eventType"`
+	Title     string `json:"title"`
+	// StartTime and EndTime are unix timestamps in milliseconds, the server uses the current time if they are not set
+	StartTime int64 `json:"startTime,omitempty"`
+	EndTime   int64 `json:"endTime,omitempty"`
+	// Timeout in minutes, only used if EndTime is not set
+	Timeout        int               `json:"timeout,omitempty"`
+	EntitySelector string            `json:"entitySelector,omitempty"`
+	Properties     map[string]string `json:"properties,omitempty"`
+}
+
+type eventIngestResponse struct {
+	ReportCount        int `json:"reportCount"`
+	EventIngestResults []struct {
+		CorrelationID string `json:"correlationId"`
+		Status        string `json:"status"`
+	} `json:"eventIngestResults"`
+}
+
+// KubernetesClusterEntitySelector returns an entity selector matching the Kubernetes cluster entity
+// with the given kube-system namespace UUID.
+func KubernetesClusterEntitySelector(kubeSystemUUID string) string {
+	return fmt.Sprintf("type(KUBERNETES_CLUSTER),kubernetesClusterId(%s)", kubeSystemUUID)
+}
+
+func (dtc *dynatraceClient) SendEventV2(ctx context.Context, eventData *EventDataV2) error {
+	if eventData == nil {
+		return errors.New("no data found in eventData payload")
+	}
+
+	if eventData.EventType == "" {
+		return errors.New("no key set for eventType in eventData payload")
+	}
+
+	if eventData.Title == "" {
+		return errors.New("no key set for title in eventData payload")
+	}
+
+	jsonStr, err := json.Marshal(eventData)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dtc.getEventsV2Url(), bytes.NewBuffer(jsonStr))
+	if err != nil {
+		return fmt.Errorf("error initializing http request: %s", err.Error())
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Authorization", fmt.Sprintf("Api-Token %s", dtc.apiToken))
+
+	response, err := dtc.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error making post request to dynatrace api: %s", err.Error())
+	}
+	defer func() {
+		//Swallow error, nothing has to be done at this point
+		_ = response.Body.Close()
+	}()
+
+	responseData, err := dtc.getServerResponseData(response)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return checkEventIngestResponse(responseData)
+}
+
+// checkEventIngestResponse returns an error if the server did not accept the event, e.g. because of an invalid entity selector.
+func checkEventIngestResponse(responseData []byte) error {
+	var ingestResponse eventIngestResponse
+	if err := json.Unmarshal(responseData, &ingestResponse); err != nil {
+		return errors.WithMessage(err, "error parsing event ingest response")
+	}
+
+	for _, result := range ingestResponse.EventIngestResults {
+		if result.Status != eventIngestStatusOK {
+			return errors.Errorf("event was not ingested, status: %s", result.Status)
+		}
+	}
+	return nil
+}
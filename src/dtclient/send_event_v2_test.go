@@ -0,0 +1,76 @@
+package dtclient
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendEventV2(t *testing.T) {
+	deploymentEvent := EventDataV2{
+		EventType:      CustomDeploymentEvent,
+		Title:          "OneAgent rolled out",
+		EntitySelector: KubernetesClusterEntitySelector("kube-system-uuid"),
+		Properties: map[string]string{
+			"dt.event.deployment.version": "1.2.3",
+		},
+	}
+
+	t.Run("SendEventV2 posts event", func(t *testing.T) {
+		var received EventDataV2
+		dynatraceServer, dynatraceClient := createTestDynatraceClient(t, http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			assert.Equal(t, "/v2/events/ingest", request.URL.Path)
+			assert.Equal(t, http.MethodPost, request.Method)
+			assert.Equal(t, "Api-Token "+apiToken, request.Header.Get("Authorization"))
+
+			body, err := ioutil.ReadAll(request.Body)
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(body, &received))
+
+			writer.WriteHeader(http.StatusCreated)
+			_, _ = writer.Write([]byte(`{"reportCount":1,"eventIngestResults":[{"correlationId":"abc","status":"OK"}]}`))
+		}), "")
+		defer dynatraceServer.Close()
+
+		err := dynatraceClient.SendEventV2(context.TODO(), &deploymentEvent)
+
+		require.NoError(t, err)
+		assert.Equal(t, deploymentEvent, received)
+		assert.Equal(t, "type(KUBERNETES_CLUSTER),kubernetesClusterId(kube-system-uuid)", received.EntitySelector)
+	})
+	t.Run("SendEventV2 incomplete event data", func(t *testing.T) {
+		dynatraceServer, dynatraceClient := createTestDynatraceClient(t, sendEventHandlerStub(), "")
+		defer dynatraceServer.Close()
+
+		err := dynatraceClient.SendEventV2(context.TODO(), nil)
+		assert.EqualError(t, err, "no data found in eventData payload")
+
+		err = dynatraceClient.SendEventV2(context.TODO(), &EventDataV2{Title: "title"})
+		assert.EqualError(t, err, "no key set for eventType in eventData payload")
+
+		err = dynatraceClient.SendEventV2(context.TODO(), &EventDataV2{EventType: CustomDeploymentEvent})
+		assert.EqualError(t, err, "no key set for title in eventData payload")
+	})
+	t.Run("SendEventV2 event not ingested", func(t *testing.T) {
+		dynatraceServer, dynatraceClient := createTestDynatraceClient(t, http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.WriteHeader(http.StatusCreated)
+			_, _ = writer.Write([]byte(`{"reportCount":0,"eventIngestResults":[{"status":"INVALID_ENTITY_TYPE"}]}`))
+		}), "")
+		defer dynatraceServer.Close()
+
+		err := dynatraceClient.SendEventV2(context.TODO(), &deploymentEvent)
+		assert.EqualError(t, err, "event was not ingested, status: INVALID_ENTITY_TYPE")
+	})
+	t.Run("SendEventV2 server error", func(t *testing.T) {
+		dynatraceServer, dynatraceClient := createTestDynatraceClient(t, sendEventHandlerError(), "")
+		defer dynatraceServer.Close()
+
+		err := dynatraceClient.SendEventV2(context.TODO(), &deploymentEvent)
+		assert.EqualError(t, err, "dynatrace server error 500: error received from server")
+	})
+}
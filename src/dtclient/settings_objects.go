@@ -0,0 +1,223 @@
+package dtclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	settingsObjectsPageSize = "500"
+	settingsObjectsFields   = "objectId,value,schemaId,schemaVersion,scope"
+)
+
+// SettingsObject is a Settings 2.0 object. The value is kept as raw JSON, as its structure is defined by the schema.
+type SettingsObject struct {
+	ObjectID      string          `json:"objectId,omitempty"`
+	SchemaID      string          `json:"schemaId,omitempty"`
+	SchemaVersion string          `json:"schemaVersion,omitempty"`
+	Scope         string          `json:"scope,omitempty"`
+	Value         json.RawMessage `json:"value,omitempty"`
+}
+
+// SettingsSchema is the part of a Settings 2.0 schema needed to manage its objects.
+// Schemas which aren't multi-object allow only one object per scope.
+type SettingsSchema struct {
+	SchemaID    string `json:"schemaId"`
+	MultiObject bool   `json:"multiObject"`
+}
+
+type listSettingsObjectsResponse struct {
+	Items       []SettingsObject `json:"items"`
+	TotalCount  int              `json:"totalCount"`
+	NextPageKey string           `json:"nextPageKey,omitempty"`
+}
+
+type updateSettingsObjectBody struct {
+	SchemaVersion string          `json:"schemaVersion,omitempty"`
+	Value         json.RawMessage `json:"value"`
+}
+
+func (dtc *dynatraceClient) ListSettingsObjects(ctx context.Context, schemaID, scope string) ([]SettingsObject, error) {
+	if schemaID == "" {
+		return nil, errors.New("no schema id given")
+	}
+
+	var objects []SettingsObject
+	nextPageKey := ""
+	for {
+		req, err := createBaseRequest(ctx, dtc.getSettingsUrl(true), http.MethodGet, dtc.apiToken, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		// follow-up pages must only be queried with the page key
+		q := req.URL.Query()
+		if nextPageKey != "" {
+			q.Add("nextPageKey", nextPageKey)
+		} else {
+			q.Add("schemaIds", schemaID)
+			if scope != "" {
+				q.Add("scopes", scope)
+			}
+			q.Add("fields", settingsObjectsFields)
+			q.Add("pageSize", settingsObjectsPageSize)
+		}
+		req.URL.RawQuery = q.Encode()
+
+		var page listSettingsObjectsResponse
+		if err := dtc.doSettingsRequest(req, &page); err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, page.Items...)
+		if page.NextPageKey == "" {
+			return objects, nil
+		}
+		nextPageKey = page.NextPageKey
+	}
+}
+
+func (dtc *dynatraceClient) GetSettingsSchema(ctx context.Context, schemaID string) (*SettingsSchema, error) {
+	if schemaID == "" {
+		return nil, errors.New("no schema id given")
+	}
+
+	req, err := createBaseRequest(ctx, dtc.getSettingsSchemaUrl(schemaID), http.MethodGet, dtc.apiToken, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var schema SettingsSchema
+	if err := dtc.doSettingsRequest(req, &schema); err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+func (dtc *dynatraceClient) GetSettingsObject(ctx context.Context, objectID string) (*SettingsObject, error) {
+	if objectID == "" {
+		return nil, errors.New("no object id given")
+	}
+
+	req, err := createBaseRequest(ctx, dtc.getSettingsObjectUrl(objectID), http.MethodGet, dtc.apiToken, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var object SettingsObject
+	if err := dtc.doSettingsRequest(req, &object); err != nil {
+		return nil, err
+	}
+	return &object, nil
+}
+
+func (dtc *dynatraceClient) CreateSettingsObject(ctx context.Context, object SettingsObject) (string, error) {
+	if object.SchemaID == "" {
+		return "", errors.New("no schema id given")
+	}
+	object.ObjectID = ""
+
+	bodyData, err := json.Marshal([]SettingsObject{object})
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	req, err := createBaseRequest(ctx, dtc.getSettingsUrl(false), http.MethodPost, dtc.apiToken, bytes.NewReader(bodyData))
+	if err != nil {
+		return "", err
+	}
+
+	res, err := dtc.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error making post request to dynatrace api: %s", err.Error())
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	resData, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", fmt.Errorf("error reading response: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK &&
+		res.StatusCode != http.StatusCreated {
+		return "", handleErrorArrayResponseFromAPI(resData, res.StatusCode)
+	}
+
+	var resDataJson []postSettingsResponse
+	if err := json.Unmarshal(resData, &resDataJson); err != nil {
+		return "", err
+	}
+	if len(resDataJson) != 1 {
+		return "", fmt.Errorf("response is not containing exactly one entry %s", resData)
+	}
+
+	return resDataJson[0].ObjectId, nil
+}
+
+func (dtc *dynatraceClient) UpdateSettingsObject(ctx context.Context, object SettingsObject) error {
+	if object.ObjectID == "" {
+		return errors.New("no object id given")
+	}
+
+	bodyData, err := json.Marshal(updateSettingsObjectBody{
+		SchemaVersion: object.SchemaVersion,
+		Value:         object.Value,
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	req, err := createBaseRequest(ctx, dtc.getSettingsObjectUrl(object.ObjectID), http.MethodPut, dtc.apiToken, bytes.NewReader(bodyData))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	return dtc.doSettingsRequest(req, nil)
+}
+
+func (dtc *dynatraceClient) DeleteSettingsObject(ctx context.Context, objectID string) error {
+	if objectID == "" {
+		return errors.New("no object id given")
+	}
+
+	req, err := createBaseRequest(ctx, dtc.getSettingsObjectUrl(objectID), http.MethodDelete, dtc.apiToken, nil)
+	if err != nil {
+		return err
+	}
+
+	return dtc.doSettingsRequest(req, nil)
+}
+
+// doSettingsRequest sends the request and unmarshals the response into result, if given.
+// Errors reported by the server are returned as ServerError, so callers can check e.g. for 404.
+func (dtc *dynatraceClient) doSettingsRequest(req *http.Request, result interface{}) error {
+	res, err := dtc.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error making %s request to dynatrace api: %s", req.Method, err.Error())
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	resData, err := dtc.getServerResponseData(res)
+	if err != nil {
+		return err
+	}
+
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(resData, result); err != nil {
+		return fmt.Errorf("error parsing response body: %s", err.Error())
+	}
+	return nil
+}
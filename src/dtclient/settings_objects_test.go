@@ -0,0 +1,137 @@
+package dtclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSchemaID = "builtin:test.schema"
+
+func TestDynatraceClient_ListSettingsObjects(t *testing.T) {
+	t.Run("follows pages", func(t *testing.T) {
+		var queries []string
+		dynatraceServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			queries = append(queries, request.URL.RawQuery)
+			if request.URL.Query().Get("nextPageKey") == "" {
+				writeTestJSON(t, writer, listSettingsObjectsResponse{
+					Items:       []SettingsObject{{ObjectID: "object-1", Value: json.RawMessage(`{"enabled":true}`)}},
+					NextPageKey: "page-2",
+				})
+				return
+			}
+			writeTestJSON(t, writer, listSettingsObjectsResponse{Items: []SettingsObject{{ObjectID: "object-2"}}})
+		}))
+		defer dynatraceServer.Close()
+
+		dtc, err := NewClient(dynatraceServer.URL, apiToken, paasToken)
+		require.NoError(t, err)
+
+		objects, err := dtc.ListSettingsObjects(context.TODO(), testSchemaID, testScope)
+
+		require.NoError(t, err)
+		require.Len(t, objects, 2)
+		assert.Equal(t, "object-1", objects[0].ObjectID)
+		assert.JSONEq(t, `{"enabled":true}`, string(objects[0].Value))
+		assert.Equal(t, "object-2", objects[1].ObjectID)
+		assert.Contains(t, queries[0], "schemaIds=builtin%3Atest.schema")
+		assert.Contains(t, queries[0], "scopes="+testScope)
+		assert.Equal(t, "nextPageKey=page-2", queries[1])
+	})
+	t.Run("requires schema id", func(t *testing.T) {
+		dtc, err := NewClient("http://localhost", apiToken, paasToken)
+		require.NoError(t, err)
+
+		_, err = dtc.ListSettingsObjects(context.TODO(), "", testScope)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestDynatraceClient_GetSettingsSchema(t *testing.T) {
+	var path string
+	dynatraceServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		path = request.URL.Path
+		_, _ = writer.Write([]byte(`{"schemaId": "builtin:test.schema", "multiObject": true, "displayName": "Test"}`))
+	}))
+	defer dynatraceServer.Close()
+
+	dtc, err := NewClient(dynatraceServer.URL, apiToken, paasToken)
+	require.NoError(t, err)
+
+	schema, err := dtc.GetSettingsSchema(context.TODO(), testSchemaID)
+
+	require.NoError(t, err)
+	assert.Equal(t, "/v2/settings/schemas/"+testSchemaID, path)
+	assert.Equal(t, testSchemaID, schema.SchemaID)
+	assert.True(t, schema.MultiObject)
+}
+
+func TestDynatraceClient_SettingsObjectLifecycle(t *testing.T) {
+	var requests []string
+	var body []byte
+	dynatraceServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requests = append(requests, request.Method+" "+request.URL.Path)
+		body, _ = ioutil.ReadAll(request.Body)
+
+		switch {
+		case request.Method == http.MethodPost:
+			writeTestJSON(t, writer, []postSettingsResponse{{ObjectId: testObjectID}})
+		case request.Method == http.MethodGet && request.URL.Path == "/v2/settings/objects/missing":
+			writer.WriteHeader(http.StatusNotFound)
+			_, _ = writer.Write([]byte(`{"error": {"code": 404, "message": "Settings not found"}}`))
+		case request.Method == http.MethodGet:
+			writeTestJSON(t, writer, SettingsObject{ObjectID: testObjectID, SchemaID: testSchemaID, Value: json.RawMessage(`{}`)})
+		case request.Method == http.MethodPut:
+			writeTestJSON(t, writer, map[string]string{"code": "200"})
+		case request.Method == http.MethodDelete:
+			writer.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer dynatraceServer.Close()
+
+	dtc, err := NewClient(dynatraceServer.URL, apiToken, paasToken)
+	require.NoError(t, err)
+	ctx := context.TODO()
+
+	objectID, err := dtc.CreateSettingsObject(ctx, SettingsObject{SchemaID: testSchemaID, Scope: testScope, Value: json.RawMessage(`{"enabled":true}`)})
+	require.NoError(t, err)
+	assert.Equal(t, testObjectID, objectID)
+	assert.JSONEq(t, `[{"schemaId":"builtin:test.schema","scope":"test-scope","value":{"enabled":true}}]`, string(body))
+
+	object, err := dtc.GetSettingsObject(ctx, objectID)
+	require.NoError(t, err)
+	assert.Equal(t, testSchemaID, object.SchemaID)
+
+	_, err = dtc.GetSettingsObject(ctx, "missing")
+	var serverErr ServerError
+	require.True(t, errors.As(err, &serverErr))
+	assert.Equal(t, http.StatusNotFound, serverErr.Code)
+
+	err = dtc.UpdateSettingsObject(ctx, SettingsObject{ObjectID: objectID, SchemaVersion: "1.0", Value: json.RawMessage(`{"enabled":false}`)})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"schemaVersion":"1.0","value":{"enabled":false}}`, string(body))
+
+	err = dtc.DeleteSettingsObject(ctx, objectID)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"POST /v2/settings/objects",
+		"GET /v2/settings/objects/" + testObjectID,
+		"GET /v2/settings/objects/missing",
+		"PUT /v2/settings/objects/" + testObjectID,
+		"DELETE /v2/settings/objects/" + testObjectID,
+	}, requests)
+}
+
+func writeTestJSON(t *testing.T, writer http.ResponseWriter, body interface{}) {
+	writer.Header().Set("Content-Type", "application/json")
+	require.NoError(t, json.NewEncoder(writer).Encode(body))
+}
@@ -2,9 +2,11 @@ package dtclient
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -22,7 +24,23 @@ func (s TokenScopes) Contains(scope string) bool {
 	return false
 }
 
-func (dtc *dynatraceClient) GetTokenScopes(token string) (TokenScopes, error) {
+// TokenInfo is the metadata of a token returned by the tokens lookup
+type TokenInfo struct {
+	Scopes TokenScopes
+
+	// Expires is nil if the token never expires
+	Expires *time.Time
+}
+
+func (dtc *dynatraceClient) GetTokenScopes(ctx context.Context, token string) (TokenScopes, error) {
+	tokenInfo, err := dtc.GetTokenInfo(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return tokenInfo.Scopes, nil
+}
+
+func (dtc *dynatraceClient) GetTokenInfo(ctx context.Context, token string) (*TokenInfo, error) {
 	var model struct {
 		Token string `json:"token"`
 	}
@@ -33,7 +51,7 @@ func (dtc *dynatraceClient) GetTokenScopes(token string) (TokenScopes, error) {
 		return nil, errors.WithStack(err)
 	}
 
-	req, err := http.NewRequest("POST", dtc.getTokensLookupUrl(), bytes.NewBuffer(jsonStr))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dtc.getTokensLookupUrl(), bytes.NewBuffer(jsonStr))
 	if err != nil {
 		return nil, fmt.Errorf("error initializing http request: %w", err)
 	}
@@ -54,12 +72,14 @@ func (dtc *dynatraceClient) GetTokenScopes(token string) (TokenScopes, error) {
 		return nil, errors.WithStack(err)
 	}
 
-	return dtc.readResponseForTokenScopes(data)
+	return dtc.readResponseForTokenInfo(data)
 }
 
-func (dtc *dynatraceClient) readResponseForTokenScopes(response []byte) (TokenScopes, error) {
+func (dtc *dynatraceClient) readResponseForTokenInfo(response []byte) (*TokenInfo, error) {
 	var jr struct {
 		Scopes []string `json:"scopes"`
+		// Expires is the expiration date in Unix timestamp format (milliseconds)
+		Expires int64 `json:"expires"`
 	}
 
 	if err := json.Unmarshal(response, &jr); err != nil {
@@ -67,5 +87,10 @@ func (dtc *dynatraceClient) readResponseForTokenScopes(response []byte) (TokenSc
 		return nil, err
 	}
 
-	return jr.Scopes, nil
+	tokenInfo := &TokenInfo{Scopes: jr.Scopes}
+	if jr.Expires > 0 {
+		expires := time.Unix(0, jr.Expires*int64(time.Millisecond))
+		tokenInfo.Expires = &expires
+	}
+	return tokenInfo, nil
 }
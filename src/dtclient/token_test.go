@@ -1,10 +1,12 @@
 package dtclient
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -22,16 +24,29 @@ func TestToken_Contains(t *testing.T) {
 
 func testGetTokenScopes(t *testing.T, dynatraceClient Client) {
 	{
-		scopes, err := dynatraceClient.GetTokenScopes("good-token")
+		scopes, err := dynatraceClient.GetTokenScopes(context.TODO(), "good-token")
 		assert.NoError(t, err)
 		assert.ElementsMatch(t, []string{"DataExport", "LogExport"}, scopes)
 	}
 	{
-		scopes, err := dynatraceClient.GetTokenScopes("bad-token")
+		scopes, err := dynatraceClient.GetTokenScopes(context.TODO(), "bad-token")
 		assert.Nil(t, scopes)
 		assert.Error(t, err)
 		assert.Exactly(t, ServerError{Code: 401, Message: "error received from server"}, errors.Cause(err))
 	}
+	{
+		tokenInfo, err := dynatraceClient.GetTokenInfo(context.TODO(), "good-token")
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"DataExport", "LogExport"}, tokenInfo.Scopes)
+		assert.Nil(t, tokenInfo.Expires)
+	}
+	{
+		tokenInfo, err := dynatraceClient.GetTokenInfo(context.TODO(), "expiring-token")
+		assert.NoError(t, err)
+		if assert.NotNil(t, tokenInfo.Expires) {
+			assert.Equal(t, time.Date(2022, 4, 1, 12, 0, 0, 0, time.UTC), tokenInfo.Expires.UTC())
+		}
+	}
 }
 
 func handleTokenScopes(request *http.Request, writer http.ResponseWriter) {
@@ -67,6 +82,17 @@ func handleTokenScopes(request *http.Request, writer http.ResponseWriter) {
 				"LogExport"
 			]
 		}`))
+	case "expiring-token":
+		writer.WriteHeader(http.StatusOK)
+		_, _ = writer.Write([]byte(`{
+			"id": "a1cfd9b3-2b5e-4a4c-9a69-2f7e1e5e8b01",
+			"name": "the-expiring-token",
+			"userId": "the-user",
+			"expires": 1648814400000,
+			"scopes": [
+				"DataExport"
+			]
+		}`))
 	default:
 		writeError(writer, http.StatusUnauthorized)
 	}
@@ -12,4 +12,7 @@ const (
 	trustedCAKey = "certs"
 	proxyKey     = "proxy"
 	tlsCertKey   = "server.crt"
+
+	clientCertKey = "tls.crt"
+	clientKeyKey  = "tls.key"
 )
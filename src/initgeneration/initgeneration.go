@@ -132,6 +132,16 @@ func (g *InitGenerator) prepareSecretConfigForDynaKube(dk *dynatracev1beta1.Dyna
 		trustedCAs = []byte(cam.Data[trustedCAKey])
 	}
 
+	var clientCert, clientKey []byte
+	if dk.Spec.ClientCertificate != "" {
+		var certSecret corev1.Secret
+		if err := g.client.Get(context.TODO(), client.ObjectKey{Name: dk.Spec.ClientCertificate, Namespace: g.namespace}, &certSecret); err != nil {
+			return nil, fmt.Errorf("failed to query client certificate: %w", err)
+		}
+		clientCert = certSecret.Data[clientCertKey]
+		clientKey = certSecret.Data[clientKeyKey]
+	}
+
 	var tlsCert string
 	if dk.HasActiveGateCaCert() {
 		var tlsSecret corev1.Secret
@@ -143,11 +153,14 @@ func (g *InitGenerator) prepareSecretConfigForDynaKube(dk *dynatracev1beta1.Dyna
 
 	return &standalone.SecretConfig{
 		ApiUrl:          dk.Spec.APIURL,
+		ApiUrls:         dk.APIURLs(),
 		ApiToken:        getAPIToken(tokens),
 		PaasToken:       getPaasToken(tokens),
 		Proxy:           proxy,
 		NetworkZone:     dk.Spec.NetworkZone,
 		TrustedCAs:      string(trustedCAs),
+		ClientCert:      string(clientCert),
+		ClientKey:       string(clientKey),
 		SkipCertCheck:   dk.Spec.SkipCertCheck,
 		TenantUUID:      dk.Status.ConnectionInfo.TenantUUID,
 		HasHost:         dk.CloudNativeFullstackMode(),
@@ -27,6 +27,9 @@ var (
 	testProxy                = "testproxy.com"
 	testtrustCAsCM           = "testtrustedCAsConfigMap"
 	testCAValue              = "somecertificate"
+	testClientCertSecret     = "testclientcertificate"
+	testClientCert           = "someclientcertificate"
+	testClientKey            = "someclientkey"
 	testTenantUUID           = "abc12345"
 	kubesystemNamespace      = "kube-system"
 	kubesystemUID            = types.UID("42")
@@ -38,10 +41,11 @@ var (
 	testDynakubeComplex = &dynatracev1beta1.DynaKube{
 		ObjectMeta: metav1.ObjectMeta{Name: testDynakubeComplexName, Namespace: operatorNamespace},
 		Spec: dynatracev1beta1.DynaKubeSpec{
-			APIURL:     testApiUrl,
-			Proxy:      &dynatracev1beta1.DynaKubeProxy{Value: testProxy},
-			TrustedCAs: testtrustCAsCM,
-			Tokens:     testTokensName,
+			APIURL:            testApiUrl,
+			Proxy:             &dynatracev1beta1.DynaKubeProxy{Value: testProxy},
+			TrustedCAs:        testtrustCAsCM,
+			Tokens:            testTokensName,
+			ClientCertificate: testClientCertSecret,
 			OneAgent: dynatracev1beta1.OneAgentSpec{
 				CloudNativeFullStack: &dynatracev1beta1.CloudNativeFullStackSpec{
 					HostInjectSpec: dynatracev1beta1.HostInjectSpec{
@@ -134,6 +138,11 @@ var (
 		Data:       map[string][]byte{tlsCertKey: []byte("testing")},
 	}
 
+	testClientCertSecretDynakubeComplex = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: testClientCertSecret, Namespace: operatorNamespace},
+		Data:       map[string][]byte{clientCertKey: []byte(testClientCert), clientKeyKey: []byte(testClientKey)},
+	}
+
 	testSecretDynakubeSimple = &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{Name: testDynakubeSimpleName, Namespace: operatorNamespace},
 		Data:       map[string][]byte{"paasToken": []byte("42"), "apiToken": []byte("84")},
@@ -167,7 +176,7 @@ func TestGenerateForNamespace(t *testing.T) {
 				Labels: map[string]string{mapper.InstanceLabel: testDynakubeComplex.Name},
 			},
 		}
-		clt := fake.NewClient(testDynakubeComplex, &testNamespace, testSecretDynakubeComplex, kubeNamespace, caConfigMap, testTlsSecretDynakubeComplex, testNode1, testNode2)
+		clt := fake.NewClient(testDynakubeComplex, &testNamespace, testSecretDynakubeComplex, kubeNamespace, caConfigMap, testTlsSecretDynakubeComplex, testClientCertSecretDynakubeComplex, testNode1, testNode2)
 		ig := NewInitGenerator(clt, clt, operatorNamespace)
 
 		_, err := ig.GenerateForNamespace(context.TODO(), *testDynakubeComplex, testNamespace.Name)
@@ -213,7 +222,7 @@ func TestGenerateForDynakube(t *testing.T) {
 				Labels: map[string]string{mapper.InstanceLabel: testDynakubeComplex.Name},
 			},
 		}
-		clt := fake.NewClient(&testNamespace, testSecretDynakubeComplex, kubeNamespace, caConfigMap, testTlsSecretDynakubeComplex, testNode1, testNode2)
+		clt := fake.NewClient(&testNamespace, testSecretDynakubeComplex, kubeNamespace, caConfigMap, testTlsSecretDynakubeComplex, testClientCertSecretDynakubeComplex, testNode1, testNode2)
 		ig := NewInitGenerator(clt, clt, operatorNamespace)
 
 		updated, err := ig.GenerateForDynakube(context.TODO(), dk)
@@ -337,17 +346,20 @@ func testForCorrectContent(t *testing.T, secret *corev1.Secret) {
 			Labels: map[string]string{mapper.InstanceLabel: testDynakubeComplex.Name},
 		},
 	}
-	clt := fake.NewClient(&testNamespace, secret, caConfigMap, testTlsSecretDynakubeComplex)
+	clt := fake.NewClient(&testNamespace, secret, caConfigMap, testTlsSecretDynakubeComplex, testClientCertSecretDynakubeComplex)
 	ig := NewInitGenerator(clt, clt, operatorNamespace)
 	imNodes := map[string]string{testNode1Name: testTenantUUID, testNode2Name: testTenantUUID}
 	secretConfig, err := ig.prepareSecretConfigForDynaKube(dk, kubesystemUID, imNodes)
 	assert.NoError(t, err)
 	expectedConfig := standalone.SecretConfig{
 		ApiUrl:          dk.Spec.APIURL,
+		ApiUrls:         dk.APIURLs(),
 		ApiToken:        string(secret.Data["apiToken"]),
 		SkipCertCheck:   dk.Spec.SkipCertCheck,
 		Proxy:           testProxy,
 		TrustedCAs:      testCAValue,
+		ClientCert:      testClientCert,
+		ClientKey:       testClientKey,
 		ClusterID:       string(kubesystemUID),
 		TenantUUID:      dk.Status.ConnectionInfo.TenantUUID,
 		MonitoringNodes: imNodes,
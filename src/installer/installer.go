@@ -1,6 +1,7 @@
 package installer
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -40,7 +41,7 @@ func (props *InstallerProperties) fillEmptyWithDefaults() {
 }
 
 type Installer interface {
-	InstallAgent(targetDir string) error
+	InstallAgent(ctx context.Context, targetDir string) error
 	UpdateProcessModuleConfig(targetDir string, processModuleConfig *dtclient.ProcessModuleConfig) error
 	SetVersion(version string)
 }
@@ -65,10 +66,10 @@ func NewOneAgentInstaller(
 	}
 }
 
-func (installer *OneAgentInstaller) InstallAgent(targetDir string) error {
+func (installer *OneAgentInstaller) InstallAgent(ctx context.Context, targetDir string) error {
 	log.Info("installing agent", "target dir", targetDir)
 	installer.props.fillEmptyWithDefaults()
-	if err := installer.installAgent(targetDir); err != nil {
+	if err := installer.installAgent(ctx, targetDir); err != nil {
 		_ = installer.fs.RemoveAll(targetDir)
 
 		return fmt.Errorf("failed to install agent: %w", err)
@@ -81,7 +82,7 @@ func (installer *OneAgentInstaller) SetVersion(version string) {
 	installer.props.Version = version
 }
 
-func (installer *OneAgentInstaller) installAgent(targetDir string) error {
+func (installer *OneAgentInstaller) installAgent(ctx context.Context, targetDir string) error {
 	fs := installer.fs
 	tmpFile, err := afero.TempFile(fs, "", "download")
 	if err != nil {
@@ -95,15 +96,15 @@ func (installer *OneAgentInstaller) installAgent(targetDir string) error {
 	}()
 
 	if installer.props.Url != "" {
-		if err := installer.downloadOneAgentViaInstallerUrl(tmpFile); err != nil {
+		if err := installer.downloadOneAgentViaInstallerUrl(ctx, tmpFile); err != nil {
 			return err
 		}
 	} else if installer.props.Version == VersionLatest {
-		if err := installer.downloadLatestOneAgent(tmpFile); err != nil {
+		if err := installer.downloadLatestOneAgent(ctx, tmpFile); err != nil {
 			return err
 		}
 	} else {
-		if err := installer.downloadOneAgentWithVersion(tmpFile); err != nil {
+		if err := installer.downloadOneAgentWithVersion(ctx, tmpFile); err != nil {
 			return err
 		}
 	}
@@ -127,9 +128,10 @@ func (installer *OneAgentInstaller) installAgent(targetDir string) error {
 	return nil
 }
 
-func (installer *OneAgentInstaller) downloadLatestOneAgent(tmpFile afero.File) error {
+func (installer *OneAgentInstaller) downloadLatestOneAgent(ctx context.Context, tmpFile afero.File) error {
 	log.Info("downloading latest OneAgent package", "props", installer.props)
 	return installer.dtc.GetLatestAgent(
+		ctx,
 		installer.props.Os,
 		installer.props.Type,
 		installer.props.Flavor,
@@ -139,9 +141,10 @@ func (installer *OneAgentInstaller) downloadLatestOneAgent(tmpFile afero.File) e
 	)
 }
 
-func (installer *OneAgentInstaller) downloadOneAgentWithVersion(tmpFile afero.File) error {
+func (installer *OneAgentInstaller) downloadOneAgentWithVersion(ctx context.Context, tmpFile afero.File) error {
 	log.Info("downloading specific OneAgent package", "version", installer.props.Version)
 	err := installer.dtc.GetAgent(
+		ctx,
 		installer.props.Os,
 		installer.props.Type,
 		installer.props.Flavor,
@@ -151,8 +154,11 @@ func (installer *OneAgentInstaller) downloadOneAgentWithVersion(tmpFile afero.Fi
 		tmpFile,
 	)
 
-	if err != nil {
+	if dtclient.IsDownloadVerificationError(err) {
+		return err
+	} else if err != nil {
 		availableVersions, getVersionsError := installer.dtc.GetAgentVersions(
+			ctx,
 			installer.props.Os,
 			installer.props.Type,
 			installer.props.Flavor,
@@ -166,9 +172,9 @@ func (installer *OneAgentInstaller) downloadOneAgentWithVersion(tmpFile afero.Fi
 	return nil
 }
 
-func (installer *OneAgentInstaller) downloadOneAgentViaInstallerUrl(tmpFile afero.File) error {
+func (installer *OneAgentInstaller) downloadOneAgentViaInstallerUrl(ctx context.Context, tmpFile afero.File) error {
 	log.Info("downloading OneAgent package using provided url, all other properties are ignored", "url", installer.props.Url)
-	return installer.dtc.GetAgentViaInstallerUrl(installer.props.Url, tmpFile)
+	return installer.dtc.GetAgentViaInstallerUrl(ctx, installer.props.Url, tmpFile)
 }
 
 func (installer *OneAgentInstaller) unzip(file afero.File, targetDir string) error {
@@ -1,6 +1,7 @@
 package installer
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 	"io"
@@ -57,7 +58,7 @@ func TestInstallAgent(t *testing.T) {
 			fs: fs,
 		}
 
-		err := installer.installAgent("")
+		err := installer.installAgent(context.TODO(), "")
 		assert.EqualError(t, err, "failed to create temporary file for download: "+testErrorMessage)
 	})
 	t.Run(`error when downloading latest agent`, func(t *testing.T) {
@@ -80,7 +81,7 @@ func TestInstallAgent(t *testing.T) {
 			},
 		}
 
-		err := installer.installAgent("")
+		err := installer.installAgent(context.TODO(), "")
 		assert.EqualError(t, err, "failed to fetch OneAgent version: "+testErrorMessage)
 	})
 	t.Run(`error unzipping file`, func(t *testing.T) {
@@ -110,7 +111,7 @@ func TestInstallAgent(t *testing.T) {
 			},
 		}
 
-		err := installer.installAgent("")
+		err := installer.installAgent(context.TODO(), "")
 		assert.Error(t, err)
 	})
 	t.Run(`downloading and unzipping agent via version`, func(t *testing.T) {
@@ -140,7 +141,7 @@ func TestInstallAgent(t *testing.T) {
 			},
 		}
 
-		err := installer.installAgent(testDir)
+		err := installer.installAgent(context.TODO(), testDir)
 		require.NoError(t, err)
 
 		info, err := fs.Stat(filepath.Join(testDir, testFilename))
@@ -176,7 +177,7 @@ func TestInstallAgent(t *testing.T) {
 			},
 		}
 
-		err := installer.installAgent(testDir)
+		err := installer.installAgent(context.TODO(), testDir)
 		require.NoError(t, err)
 
 		info, err := fs.Stat(filepath.Join(testDir, testFilename))
@@ -208,7 +209,7 @@ func TestInstallAgent(t *testing.T) {
 			},
 		}
 
-		err := installer.installAgent(testDir)
+		err := installer.installAgent(context.TODO(), testDir)
 		require.NoError(t, err)
 
 		info, err := fs.Stat(filepath.Join(testDir, testFilename))
@@ -1,6 +1,8 @@
 package installer
 
 import (
+	"context"
+
 	"github.com/Dynatrace/dynatrace-operator/src/dtclient"
 	"github.com/stretchr/testify/mock"
 )
@@ -11,7 +13,7 @@ type InstallerMock struct {
 
 var _ Installer = &InstallerMock{}
 
-func (mock *InstallerMock) InstallAgent(targetDir string) error {
+func (mock *InstallerMock) InstallAgent(_ context.Context, targetDir string) error {
 	args := mock.Called(targetDir)
 	return args.Error(0)
 }
@@ -23,14 +23,17 @@ func NewDynakubeMapper(ctx context.Context, clt client.Client, apiReader client.
 }
 
 // MapFromDynakube checks all the namespaces to all the dynakubes
-// updates the labels on the namespaces if necessary,
+// updates the labels on the namespaces and the status of their InjectionConfigs if necessary,
 // finds confliction dynakubes (2 dynakube with codeModules on the same namespace)
 func (dm DynakubeMapper) MapFromDynakube() error {
 	modifiedNs, err := dm.MatchingNamespaces()
 	if err != nil {
 		return errors.Cause(err)
 	}
-	return dm.updateNamespaces(modifiedNs)
+	if err := dm.updateNamespaces(modifiedNs); err != nil {
+		return err
+	}
+	return updateInjectionConfigs(dm.ctx, dm.client, dm.apiReader)
 }
 
 func (dm DynakubeMapper) MatchingNamespaces() ([]*corev1.Namespace, error) {
@@ -60,7 +63,7 @@ func (dm DynakubeMapper) UnmapFromDynaKube() error {
 			return errors.WithMessagef(err, "failed to remove label %s from namespace %s", InstanceLabel, ns.Name)
 		}
 	}
-	return nil
+	return updateInjectionConfigs(dm.ctx, dm.client, dm.apiReader)
 }
 
 func (dm DynakubeMapper) mapFromDynakube(nsList *corev1.NamespaceList, dkList *dynatracev1beta1.DynaKubeList) ([]*corev1.Namespace, error) {
@@ -0,0 +1,47 @@
+package mapper
+
+import (
+	"context"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// updateInjectionConfigs records the DynaKube the namespace is assigned to in the status of every InjectionConfig,
+// so the webhook only has to read them. If InjectionConfigs can't be listed because the CRD or the permissions are missing,
+// e.g. during an upgrade, they are skipped.
+func updateInjectionConfigs(ctx context.Context, clt client.Client, apiReader client.Reader) error {
+	var injectionConfigs dynatracev1beta1.InjectionConfigList
+	err := apiReader.List(ctx, &injectionConfigs)
+	if meta.IsNoMatchError(err) || k8serrors.IsNotFound(err) || k8serrors.IsForbidden(err) {
+		log.Info("InjectionConfigs are unavailable, skipping the update of their status", "error", err.Error())
+		return nil
+	} else if err != nil {
+		return errors.WithStack(err)
+	}
+
+	for i := range injectionConfigs.Items {
+		injectionConfig := &injectionConfigs.Items[i]
+		var ns corev1.Namespace
+		if err := apiReader.Get(ctx, client.ObjectKey{Name: injectionConfig.Namespace}, &ns); k8serrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return errors.WithStack(err)
+		}
+
+		dkName := ns.Labels[InstanceLabel]
+		if injectionConfig.Status.DynaKube == dkName && injectionConfig.Status.ObservedGeneration == injectionConfig.Generation {
+			continue
+		}
+		injectionConfig.Status.DynaKube = dkName
+		injectionConfig.Status.ObservedGeneration = injectionConfig.Generation
+		if err := clt.Status().Update(ctx, injectionConfig); err != nil {
+			return errors.WithMessagef(err, "failed to update the status of InjectionConfig %s in namespace %s", injectionConfig.Name, injectionConfig.Namespace)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package mapper
+
+import (
+	"context"
+	"testing"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	"github.com/Dynatrace/dynatrace-operator/src/scheme/fake"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestUpdateInjectionConfigs(t *testing.T) {
+	labels := map[string]string{"test": "selector"}
+	dk := createTestDynakubeWithAppInject("dk-test", labels, nil)
+	newInjectionConfig := func(namespace string, status dynatracev1beta1.InjectionConfigStatus) *dynatracev1beta1.InjectionConfig {
+		return &dynatracev1beta1.InjectionConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "defaults", Namespace: namespace, Generation: 2},
+			Status:     status,
+		}
+	}
+	getStatus := func(t *testing.T, clt client.Client, namespace string) dynatracev1beta1.InjectionConfigStatus {
+		var injectionConfig dynatracev1beta1.InjectionConfig
+		require.NoError(t, clt.Get(context.TODO(), client.ObjectKey{Name: "defaults", Namespace: namespace}, &injectionConfig))
+		return injectionConfig.Status
+	}
+
+	t.Run(`status is set when the namespace is mapped`, func(t *testing.T) {
+		clt := fake.NewClient(dk, createNamespace("test-namespace", labels),
+			newInjectionConfig("test-namespace", dynatracev1beta1.InjectionConfigStatus{}))
+		dm := NewDynakubeMapper(context.TODO(), clt, clt, "dynatrace", dk)
+
+		require.NoError(t, dm.MapFromDynakube())
+
+		assert.Equal(t, dynatracev1beta1.InjectionConfigStatus{DynaKube: dk.Name, ObservedGeneration: 2}, getStatus(t, clt, "test-namespace"))
+	})
+	t.Run(`status is cleared when the namespace is unmapped`, func(t *testing.T) {
+		clt := fake.NewClient(dk, createNamespace("test-namespace", map[string]string{InstanceLabel: dk.Name}),
+			newInjectionConfig("test-namespace", dynatracev1beta1.InjectionConfigStatus{DynaKube: dk.Name, ObservedGeneration: 2}))
+		dm := NewDynakubeMapper(context.TODO(), clt, clt, "dynatrace", dk)
+
+		require.NoError(t, dm.UnmapFromDynaKube())
+
+		assert.Equal(t, dynatracev1beta1.InjectionConfigStatus{ObservedGeneration: 2}, getStatus(t, clt, "test-namespace"))
+	})
+	t.Run(`status of namespaces of other dynakubes is kept up to date`, func(t *testing.T) {
+		clt := fake.NewClient(dk, createNamespace("test-namespace", labels),
+			createNamespace("other-namespace", map[string]string{InstanceLabel: "other-dk"}),
+			newInjectionConfig("other-namespace", dynatracev1beta1.InjectionConfigStatus{DynaKube: "other-dk", ObservedGeneration: 1}))
+		dm := NewDynakubeMapper(context.TODO(), clt, clt, "dynatrace", dk)
+
+		require.NoError(t, dm.MapFromDynakube())
+
+		assert.Equal(t, dynatracev1beta1.InjectionConfigStatus{DynaKube: "other-dk", ObservedGeneration: 2}, getStatus(t, clt, "other-namespace"))
+	})
+	t.Run(`unavailable injection configs are skipped`, func(t *testing.T) {
+		clt := fake.NewClient(dk, createNamespace("test-namespace", labels))
+		forbidden := k8serrors.NewForbidden(schema.GroupResource{Group: "dynatrace.com", Resource: "injectionconfigs"}, "", errors.New("forbidden"))
+
+		err := updateInjectionConfigs(context.TODO(), clt, &failingListReader{Reader: clt, err: forbidden})
+
+		assert.NoError(t, err)
+	})
+}
+
+// failingListReader returns the error for every list of InjectionConfigs, like the api server does without the CRD or permissions
+type failingListReader struct {
+	client.Reader
+	err error
+}
+
+func (reader *failingListReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if _, ok := list.(*dynatracev1beta1.InjectionConfigList); ok {
+		return reader.err
+	}
+	return reader.Reader.List(ctx, list, opts...)
+}
@@ -115,7 +115,8 @@ func updateLabels(matches bool, dynakube *dynatracev1beta1.DynaKube, namespace *
 		namespace.Labels = make(map[string]string)
 	}
 	oldDkName, ok := namespace.Labels[InstanceLabel]
-	if matches && dynakube.NeedAppInjection() {
+	// namespaces are no longer mapped to a DynaKube which is being deleted, as its finalizer removes the labels
+	if matches && dynakube.NeedAppInjection() && dynakube.DeletionTimestamp == nil {
 		if !ok || oldDkName != dynakube.Name {
 			updated = true
 			addNamespaceInjectLabel(dynakube.Name, namespace)
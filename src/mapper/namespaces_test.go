@@ -3,10 +3,12 @@ package mapper
 import (
 	"context"
 	"testing"
+	"time"
 
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
 	"github.com/Dynatrace/dynatrace-operator/src/scheme/fake"
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestMatchForNamespaceNothingEverything(t *testing.T) {
@@ -72,6 +74,25 @@ func TestMapFromNamespace(t *testing.T) {
 		assert.Equal(t, 0, len(nm.targetNs.Labels))
 	})
 
+	t.Run("Remove namespace entry of deleted dynakube", func(t *testing.T) {
+		labels := map[string]string{
+			"test":        "selector",
+			InstanceLabel: dk.Name,
+		}
+		namespace := createNamespace("test-namespace", labels)
+		deletedDk := dk.DeepCopy()
+		deletedDk.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		deletedDk.Finalizers = []string{"dynatrace.com/cleanup"}
+		clt := fake.NewClient(deletedDk)
+		nm := NewNamespaceMapper(context.TODO(), clt, clt, "dynatrace", namespace)
+
+		updated, err := nm.MapFromNamespace()
+
+		assert.NoError(t, err)
+		assert.True(t, updated)
+		assert.NotContains(t, nm.targetNs.Labels, InstanceLabel)
+	})
+
 	t.Run("Ignore kube namespaces", func(t *testing.T) {
 		dk := createTestDynakubeWithMultipleFeatures("appMonitoring", nil, nil)
 		namespace := createNamespace("kube-something", nil)
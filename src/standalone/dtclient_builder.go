@@ -34,9 +34,11 @@ func (builder *dtclientBuilder) createClient() (dtclient.Client, error) {
 
 func (builder *dtclientBuilder) setOptions() {
 	builder.addCertCheck()
+	builder.addFailoverURLs()
 	builder.addProxy()
 	builder.addNetworkZone()
 	builder.addTrustedCerts()
+	builder.addClientCertificate()
 }
 
 func (builder *dtclientBuilder) addCertCheck() {
@@ -46,6 +48,13 @@ func (builder *dtclientBuilder) addCertCheck() {
 	}
 }
 
+func (builder *dtclientBuilder) addFailoverURLs() {
+	if len(builder.config.ApiUrls) > 1 {
+		log.Info("using the following failover api urls", "urls", builder.config.ApiUrls[1:])
+		builder.options = append(builder.options, dtclient.FailoverURLs(builder.config.ApiUrls[1:]...))
+	}
+}
+
 func (builder *dtclientBuilder) addProxy() {
 	if builder.config.Proxy != "" {
 		log.Info("using the following proxy", "proxy", builder.config.Proxy)
@@ -65,3 +74,10 @@ func (builder *dtclientBuilder) addTrustedCerts() {
 		builder.options = append(builder.options, dtclient.Certs([]byte(builder.config.TrustedCAs)))
 	}
 }
+
+func (builder *dtclientBuilder) addClientCertificate() {
+	if builder.config.ClientCert != "" && builder.config.ClientKey != "" {
+		log.Info("using client certificate, check the secret for more details")
+		builder.options = append(builder.options, dtclient.ClientCertificate([]byte(builder.config.ClientCert), []byte(builder.config.ClientKey)))
+	}
+}
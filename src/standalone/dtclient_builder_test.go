@@ -30,9 +30,22 @@ func TestCreateClient(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, client)
 
-		assert.Len(t, builder.options, 3)
+		assert.Len(t, builder.options, 4)
 
 	})
+
+	t.Run(`failover api urls`, func(t *testing.T) {
+		config := basicTestSecretConfigForClient()
+		config.ApiUrls = []string{testApiUrl, testFailoverApiUrl}
+		builder := newDTClientBuilder(config)
+
+		client, err := builder.createClient()
+
+		require.NoError(t, err)
+		require.NotNil(t, client)
+
+		assert.Len(t, builder.options, 1)
+	})
 }
 
 func basicTestSecretConfigForClient() *SecretConfig {
@@ -51,5 +64,7 @@ func complexTestSecretConfigForClient() *SecretConfig {
 		Proxy:       testProxy,
 		NetworkZone: testNetworkZone,
 		TrustedCAs:  testTrustedCA,
+		ClientCert:  testClientCert,
+		ClientKey:   testClientKey,
 	}
 }
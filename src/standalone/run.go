@@ -1,6 +1,7 @@
 package standalone
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 
@@ -56,7 +57,7 @@ func NewRunner(fs afero.Fs) (*Runner, error) {
 	}, nil
 }
 
-func (runner *Runner) Run() error {
+func (runner *Runner) Run(ctx context.Context) error {
 	log.Info("standalone agent init started")
 	var err error
 	defer runner.consumeErrorIfNecessary(&err)
@@ -66,12 +67,12 @@ func (runner *Runner) Run() error {
 	}
 
 	if runner.env.Mode == InstallerMode && runner.env.OneAgentInjected {
-		if err = runner.installOneAgent(); err != nil {
+		if err = runner.installOneAgent(ctx); err != nil {
 			return err
 		}
 		log.Info("OneAgent download finished")
 	}
-	err = runner.configureInstallation()
+	err = runner.configureInstallation(ctx)
 	if err == nil {
 		log.Info("standalone agent init completed")
 	}
@@ -99,12 +100,16 @@ func (runner *Runner) setHostTenant() error {
 	return nil
 }
 
-func (runner *Runner) installOneAgent() error {
+func (runner *Runner) installOneAgent(ctx context.Context) error {
 	log.Info("downloading OneAgent")
-	return runner.installer.InstallAgent(BinDirMount)
+	err := runner.installer.InstallAgent(ctx, BinDirMount)
+	if dtclient.IsDownloadVerificationError(err) {
+		log.Info("downloaded OneAgent package is corrupted, the installation was removed")
+	}
+	return err
 }
 
-func (runner *Runner) configureInstallation() error {
+func (runner *Runner) configureInstallation(ctx context.Context) error {
 	log.Info("configuring standalone OneAgent")
 
 	if runner.env.OneAgentInjected {
@@ -122,7 +127,7 @@ func (runner *Runner) configureInstallation() error {
 				return err
 			}
 		}
-		processModuleConfig, err := runner.dtclient.GetProcessModuleConfig(0)
+		processModuleConfig, err := runner.dtclient.GetProcessModuleConfig(ctx, 0)
 		if err != nil {
 			return err
 		}
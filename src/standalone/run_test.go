@@ -1,6 +1,7 @@
 package standalone
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"testing"
@@ -89,7 +90,7 @@ func TestInstallOneAgent(t *testing.T) {
 			On("InstallAgent", BinDirMount).
 			Return(nil)
 
-		err := runner.installOneAgent()
+		err := runner.installOneAgent(context.TODO())
 
 		require.NoError(t, err)
 	})
@@ -99,7 +100,7 @@ func TestInstallOneAgent(t *testing.T) {
 			On("InstallAgent", BinDirMount).
 			Return(fmt.Errorf("BOOM"))
 
-		err := runner.installOneAgent()
+		err := runner.installOneAgent(context.TODO())
 
 		require.Error(t, err)
 	})
@@ -120,7 +121,7 @@ func TestRun(t *testing.T) {
 		runner.fs = afero.NewMemMapFs()
 		runner.env.Mode = CsiMode
 
-		err := runner.Run()
+		err := runner.Run(context.TODO())
 
 		require.NoError(t, err)
 		assertIfAgentFilesExists(t, *runner)
@@ -134,7 +135,7 @@ func TestRun(t *testing.T) {
 		runner.fs = afero.NewMemMapFs()
 		runner.env.Mode = InstallerMode
 
-		err := runner.Run()
+		err := runner.Run(context.TODO())
 
 		require.NoError(t, err)
 		assertIfAgentFilesExists(t, *runner)
@@ -158,7 +159,7 @@ func TestConfigureInstallation(t *testing.T) {
 		runner.env.OneAgentInjected = true
 		runner.env.DataIngestInjected = true
 
-		err := runner.configureInstallation()
+		err := runner.configureInstallation(context.TODO())
 
 		require.NoError(t, err)
 		assertIfAgentFilesExists(t, *runner)
@@ -170,7 +171,7 @@ func TestConfigureInstallation(t *testing.T) {
 		runner.env.OneAgentInjected = true
 		runner.env.DataIngestInjected = false
 
-		err := runner.configureInstallation()
+		err := runner.configureInstallation(context.TODO())
 
 		require.NoError(t, err)
 		assertIfAgentFilesExists(t, *runner)
@@ -182,7 +183,7 @@ func TestConfigureInstallation(t *testing.T) {
 		runner.env.OneAgentInjected = false
 		runner.env.DataIngestInjected = true
 
-		err := runner.configureInstallation()
+		err := runner.configureInstallation(context.TODO())
 
 		require.NoError(t, err)
 		assertIfAgentFilesNotExists(t, *runner)
@@ -12,13 +12,16 @@ var ()
 
 type SecretConfig struct {
 	// For the client
-	ApiUrl        string `json:"apiUrl"`
-	ApiToken      string `json:"apiToken"`
-	PaasToken     string `json:"paasToken"`
-	Proxy         string `json:"proxy"`
-	NetworkZone   string `json:"networkZone"`
-	TrustedCAs    string `json:"trustedCAs"`
-	SkipCertCheck bool   `json:"skipCertCheck"`
+	ApiUrl        string   `json:"apiUrl"`
+	ApiUrls       []string `json:"apiUrls,omitempty"`
+	ApiToken      string   `json:"apiToken"`
+	PaasToken     string   `json:"paasToken"`
+	Proxy         string   `json:"proxy"`
+	NetworkZone   string   `json:"networkZone"`
+	TrustedCAs    string   `json:"trustedCAs"`
+	ClientCert    string   `json:"clientCert"`
+	ClientKey     string   `json:"clientKey"`
+	SkipCertCheck bool     `json:"skipCertCheck"`
 
 	// For the injection
 	TenantUUID      string            `json:"tenantUUID"`
@@ -41,6 +44,12 @@ func (secret SecretConfig) logContent() {
 	if secret.TrustedCAs != "" {
 		secret.TrustedCAs = "***"
 	}
+	if secret.ClientCert != "" {
+		secret.ClientCert = "***"
+	}
+	if secret.ClientKey != "" {
+		secret.ClientKey = "***"
+	}
 	if secret.TlsCert != "" {
 		secret.TlsCert = "***"
 	}
@@ -12,13 +12,16 @@ import (
 )
 
 const (
-	testApiUrl    = "test.com"
-	testApiToken  = "testy"
-	testPaasToken = "testz"
+	testApiUrl         = "test.com"
+	testFailoverApiUrl = "failover.test.com"
+	testApiToken       = "testy"
+	testPaasToken      = "testz"
 
 	testProxy       = "proxy"
 	testNetworkZone = "zone"
 	testTrustedCA   = "secret"
+	testClientCert  = "cert"
+	testClientKey   = "key"
 
 	testTenantUUID = "test"
 	testNodeName   = "node1"
@@ -34,6 +37,8 @@ var testSecretConfig = SecretConfig{
 	Proxy:         testProxy,
 	NetworkZone:   testNetworkZone,
 	TrustedCAs:    testTrustedCA,
+	ClientCert:    testClientCert,
+	ClientKey:     testClientKey,
 	SkipCertCheck: true,
 	TenantUUID:    testTenantUUID,
 	HasHost:       true,
@@ -60,6 +65,8 @@ func TestNewSecretConfigViaFs(t *testing.T) {
 		assert.Equal(t, testProxy, config.Proxy)
 		assert.Equal(t, testNetworkZone, config.NetworkZone)
 		assert.Equal(t, testTrustedCA, config.TrustedCAs)
+		assert.Equal(t, testClientCert, config.ClientCert)
+		assert.Equal(t, testClientKey, config.ClientKey)
 		assert.True(t, config.SkipCertCheck)
 		assert.Equal(t, testTenantUUID, config.TenantUUID)
 		assert.True(t, config.HasHost)
@@ -0,0 +1,44 @@
+package fakedynatrace
+
+import (
+	"bytes"
+	"path/filepath"
+
+	"github.com/klauspost/compress/zip"
+)
+
+// agentPackageFiles are the files of a OneAgent package the operator relies on.
+var agentPackageFiles = map[string]string{
+	filepath.Join("agent", "conf", "ruxitagentproc.conf"): "[general]\nkey value\n",
+	filepath.Join("agent", "conf", "standalone.conf"):     "",
+	filepath.Join("agent", "lib64", "liboneagentproc.so"): "fake",
+	"manifest.json": "{}",
+}
+
+// buildAgentPackage returns a zip containing a minimal OneAgent package for the given version.
+func buildAgentPackage(version string) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+
+	files := map[string]string{
+		filepath.Join("agent", "installer.version"): version,
+	}
+	for name, content := range agentPackageFiles {
+		files[name] = content
+	}
+
+	for name, content := range files {
+		file, err := writer.Create(name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := file.Write([]byte(content)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
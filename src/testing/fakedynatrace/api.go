@@ -0,0 +1,403 @@
+package fakedynatrace
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/src/dtclient"
+)
+
+const (
+	agentInstallerPath           = "/v1/deployment/installer/agent/"
+	agentConnectionInfoPath      = agentInstallerPath + "connectioninfo"
+	processModuleConfigPath      = agentInstallerPath + "processmoduleconfig"
+	agentVersionsPath            = agentInstallerPath + "versions/"
+	activeGateConnectionInfoPath = "/v1/deployment/installer/gateway/connectioninfo"
+	hostsPath                    = "/v1/entity/infrastructure/hosts"
+	entitiesPath                 = "/v2/entities"
+	settingsObjectsPath          = "/v2/settings/objects"
+	settingsSchemasPath          = "/v2/settings/schemas/"
+	eventsPath                   = "/v1/events"
+	eventsV2Path                 = "/v2/events/ingest"
+	tokensLookupPath             = "/v1/tokens/lookup"
+
+	installerDownloadPath = "/installer/agent.zip"
+	checksumFileExtension = ".sha256"
+)
+
+var kubernetesClusterIDSelector = regexp.MustCompile(`kubernetesClusterId\(([^)]*)\)`)
+
+func (tenant *Tenant) serveAPI(writer http.ResponseWriter, request *http.Request, path string) {
+	switch {
+	case path == agentConnectionInfoPath:
+		tenant.serveAgentConnectionInfo(writer)
+	case path == activeGateConnectionInfoPath:
+		tenant.serveActiveGateConnectionInfo(writer)
+	case path == processModuleConfigPath:
+		tenant.serveProcessModuleConfig(writer, request)
+	case strings.HasPrefix(path, agentVersionsPath):
+		writeJSON(writer, http.StatusOK, map[string]interface{}{"availableVersions": tenant.config.AgentVersions})
+	case strings.HasPrefix(path, agentInstallerPath):
+		tenant.serveAgentInstaller(writer, request, strings.TrimPrefix(path, agentInstallerPath))
+	case path == hostsPath:
+		tenant.serveHosts(writer)
+	case path == entitiesPath:
+		tenant.serveEntities(writer, request)
+	case path == settingsObjectsPath && request.Method == http.MethodGet:
+		tenant.serveGetSettingsObjects(writer, request)
+	case path == settingsObjectsPath && request.Method == http.MethodPost:
+		tenant.servePostSettingsObjects(writer, request)
+	case strings.HasPrefix(path, settingsSchemasPath):
+		tenant.serveSettingsSchema(writer, strings.TrimPrefix(path, settingsSchemasPath))
+	case strings.HasPrefix(path, settingsObjectsPath+"/"):
+		tenant.serveSettingsObject(writer, request, strings.TrimPrefix(path, settingsObjectsPath+"/"))
+	case path == eventsPath && request.Method == http.MethodPost:
+		tenant.serveEvent(writer, request)
+	case path == eventsV2Path && request.Method == http.MethodPost:
+		tenant.serveEventV2(writer, request)
+	default:
+		writeError(writer, http.StatusNotFound, "Not found: "+path)
+	}
+}
+
+func (tenant *Tenant) serveAgentConnectionInfo(writer http.ResponseWriter) {
+	writeJSON(writer, http.StatusOK, map[string]interface{}{
+		"tenantUUID":                      tenant.config.TenantUUID,
+		"tenantToken":                     tenant.config.TenantToken,
+		"communicationEndpoints":          tenant.config.CommunicationEndpoints,
+		"formattedCommunicationEndpoints": strings.Join(tenant.config.CommunicationEndpoints, ","),
+	})
+}
+
+func (tenant *Tenant) serveActiveGateConnectionInfo(writer http.ResponseWriter) {
+	writeJSON(writer, http.StatusOK, map[string]interface{}{
+		"tenantUUID":             tenant.config.TenantUUID,
+		"tenantToken":            tenant.config.TenantToken,
+		"communicationEndpoints": strings.Join(tenant.config.CommunicationEndpoints, ","),
+	})
+}
+
+func (tenant *Tenant) serveProcessModuleConfig(writer http.ResponseWriter, request *http.Request) {
+	revision := request.URL.Query().Get("revision")
+	if revision != "" && revision == strconv.FormatUint(uint64(tenant.config.ProcessModuleConfig.Revision), 10) {
+		writer.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeJSON(writer, http.StatusOK, tenant.config.ProcessModuleConfig)
+}
+
+// serveAgentInstaller handles "{os}/{type}/latest", "{os}/{type}/latest/metainfo" and "{os}/{type}/version/{version}".
+func (tenant *Tenant) serveAgentInstaller(writer http.ResponseWriter, request *http.Request, subPath string) {
+	latestVersion := tenant.config.AgentVersions[len(tenant.config.AgentVersions)-1]
+	segments := strings.Split(subPath, "/")
+
+	switch {
+	case len(segments) == 4 && segments[2] == "latest" && segments[3] == "metainfo":
+		writeJSON(writer, http.StatusOK, map[string]string{"latestAgentVersion": latestVersion})
+	case len(segments) == 3 && segments[2] == "latest":
+		tenant.serveAgentPackage(writer, request, latestVersion)
+	case len(segments) == 4 && segments[2] == "version":
+		if !tenant.hasAgentVersion(segments[3]) {
+			writeError(writer, http.StatusNotFound, "Agent version not found: "+segments[3])
+			return
+		}
+		tenant.serveAgentPackage(writer, request, segments[3])
+	default:
+		writeError(writer, http.StatusNotFound, "Not found: "+subPath)
+	}
+}
+
+func (tenant *Tenant) hasAgentVersion(version string) bool {
+	for _, available := range tenant.config.AgentVersions {
+		if available == version {
+			return true
+		}
+	}
+	return false
+}
+
+// serveAgentPackage supports range requests, so resuming downloads can be tested.
+func (tenant *Tenant) serveAgentPackage(writer http.ResponseWriter, request *http.Request, version string) {
+	agentPackage, err := tenant.getAgentPackage(version)
+	if err != nil {
+		writeError(writer, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writer.Header().Set("ETag", fmt.Sprintf("%q", version))
+	writer.Header().Set("Content-Type", "application/octet-stream")
+	http.ServeContent(writer, request, "agent.zip", time.Time{}, bytes.NewReader(agentPackage))
+}
+
+func (tenant *Tenant) getAgentPackage(version string) ([]byte, error) {
+	tenant.mutex.Lock()
+	defer tenant.mutex.Unlock()
+
+	if agentPackage, ok := tenant.agentPackages[version]; ok {
+		return agentPackage, nil
+	}
+	agentPackage, err := buildAgentPackage(version)
+	if err != nil {
+		return nil, err
+	}
+	tenant.agentPackages[version] = agentPackage
+	return agentPackage, nil
+}
+
+func (tenant *Tenant) serveInstallerDownload(writer http.ResponseWriter, request *http.Request) {
+	latestVersion := tenant.config.AgentVersions[len(tenant.config.AgentVersions)-1]
+	if !strings.HasSuffix(request.URL.Path, checksumFileExtension) {
+		tenant.serveAgentPackage(writer, request, latestVersion)
+		return
+	}
+
+	agentPackage, err := tenant.getAgentPackage(latestVersion)
+	if err != nil {
+		writeError(writer, http.StatusInternalServerError, err.Error())
+		return
+	}
+	checksum := sha256.Sum256(agentPackage)
+	_, _ = fmt.Fprintf(writer, "%s  agent.zip\n", hex.EncodeToString(checksum[:]))
+}
+
+func (tenant *Tenant) serveHosts(writer http.ResponseWriter) {
+	type hostResponse struct {
+		EntityID          string   `json:"entityId"`
+		IPAddresses       []string `json:"ipAddresses"`
+		NetworkZoneID     string   `json:"networkZoneId,omitempty"`
+		LastSeenTimestamp int64    `json:"lastSeenTimestamp"`
+	}
+
+	tenant.mutex.Lock()
+	defer tenant.mutex.Unlock()
+
+	response := make([]hostResponse, 0, len(tenant.hosts))
+	for _, host := range tenant.hosts {
+		response = append(response, hostResponse{
+			EntityID:          host.EntityID,
+			IPAddresses:       host.IPAddresses,
+			NetworkZoneID:     host.NetworkZoneID,
+			LastSeenTimestamp: time.Now().UnixNano() / int64(time.Millisecond),
+		})
+	}
+	writeJSON(writer, http.StatusOK, response)
+}
+
+func (tenant *Tenant) serveEntities(writer http.ResponseWriter, request *http.Request) {
+	var kubeSystemUUID string
+	if match := kubernetesClusterIDSelector.FindStringSubmatch(request.URL.Query().Get("entitySelector")); match != nil {
+		kubeSystemUUID = match[1]
+	}
+
+	tenant.mutex.Lock()
+	defer tenant.mutex.Unlock()
+
+	entities := append([]dtclient.MonitoredEntity{}, tenant.entities[kubeSystemUUID]...)
+	writeJSON(writer, http.StatusOK, map[string]interface{}{
+		"totalCount": len(entities),
+		"pageSize":   500,
+		"entities":   entities,
+	})
+}
+
+func (tenant *Tenant) serveSettingsSchema(writer http.ResponseWriter, schemaID string) {
+	multiObject := false
+	for _, multiObjectSchema := range tenant.config.MultiObjectSchemas {
+		multiObject = multiObject || multiObjectSchema == schemaID
+	}
+	writeJSON(writer, http.StatusOK, map[string]interface{}{
+		"schemaId":    schemaID,
+		"multiObject": multiObject,
+	})
+}
+
+func (tenant *Tenant) serveGetSettingsObjects(writer http.ResponseWriter, request *http.Request) {
+	query := request.URL.Query()
+	schemaIDs := splitNonEmpty(query.Get("schemaIds"))
+	scopes := splitNonEmpty(query.Get("scopes"))
+
+	tenant.mutex.Lock()
+	defer tenant.mutex.Unlock()
+
+	items := []SettingsObject{}
+	for _, object := range tenant.settingsObjects {
+		if containsOrEmpty(schemaIDs, object.SchemaID) && containsOrEmpty(scopes, object.Scope) {
+			items = append(items, object)
+		}
+	}
+	writeJSON(writer, http.StatusOK, map[string]interface{}{
+		"items":      items,
+		"totalCount": len(items),
+		"pageSize":   500,
+	})
+}
+
+func (tenant *Tenant) servePostSettingsObjects(writer http.ResponseWriter, request *http.Request) {
+	var objects []SettingsObject
+	if err := json.NewDecoder(request.Body).Decode(&objects); err != nil {
+		writeError(writer, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tenant.mutex.Lock()
+	defer tenant.mutex.Unlock()
+
+	response := make([]map[string]interface{}, 0, len(objects))
+	for _, object := range objects {
+		tenant.settingsObjectCount++
+		object.ObjectID = fmt.Sprintf("fake-object-%d", tenant.settingsObjectCount)
+		tenant.settingsObjects = append(tenant.settingsObjects, object)
+		tenant.registerKubernetesCluster(object)
+
+		response = append(response, map[string]interface{}{
+			"code":     http.StatusOK,
+			"objectId": object.ObjectID,
+		})
+	}
+	writeJSON(writer, http.StatusOK, response)
+}
+
+func (tenant *Tenant) serveSettingsObject(writer http.ResponseWriter, request *http.Request, objectID string) {
+	tenant.mutex.Lock()
+	defer tenant.mutex.Unlock()
+
+	index := tenant.findSettingsObject(objectID)
+	if index < 0 {
+		writeError(writer, http.StatusNotFound, "Settings not found")
+		return
+	}
+
+	switch request.Method {
+	case http.MethodGet:
+		writeJSON(writer, http.StatusOK, tenant.settingsObjects[index])
+	case http.MethodPut:
+		var update SettingsObject
+		if err := json.NewDecoder(request.Body).Decode(&update); err != nil {
+			writeError(writer, http.StatusBadRequest, err.Error())
+			return
+		}
+		tenant.settingsObjects[index].Value = update.Value
+		if update.SchemaVersion != "" {
+			tenant.settingsObjects[index].SchemaVersion = update.SchemaVersion
+		}
+		writeJSON(writer, http.StatusOK, map[string]interface{}{"code": http.StatusOK, "objectId": objectID})
+	case http.MethodDelete:
+		tenant.settingsObjects = append(tenant.settingsObjects[:index], tenant.settingsObjects[index+1:]...)
+		writer.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(writer, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (tenant *Tenant) findSettingsObject(objectID string) int {
+	for i, object := range tenant.settingsObjects {
+		if object.ObjectID == objectID {
+			return i
+		}
+	}
+	return -1
+}
+
+// registerKubernetesCluster creates the cluster entity once a Kubernetes connection setting exists, like the real tenant does
+// once the ActiveGate reports data for the cluster.
+func (tenant *Tenant) registerKubernetesCluster(object SettingsObject) {
+	if object.SchemaID != "builtin:cloud.kubernetes" {
+		return
+	}
+	var value struct {
+		ClusterID string `json:"clusterId"`
+	}
+	if err := json.Unmarshal(object.Value, &value); err != nil || value.ClusterID == "" {
+		return
+	}
+	if len(tenant.entities[value.ClusterID]) == 0 {
+		tenant.addKubernetesClusterEntity(value.ClusterID)
+	}
+}
+
+func (tenant *Tenant) serveEvent(writer http.ResponseWriter, request *http.Request) {
+	var event dtclient.EventData
+	if err := json.NewDecoder(request.Body).Decode(&event); err != nil {
+		writeError(writer, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tenant.mutex.Lock()
+	tenant.events = append(tenant.events, event)
+	eventID := len(tenant.events)
+	tenant.mutex.Unlock()
+
+	writeJSON(writer, http.StatusOK, map[string]interface{}{
+		"storedEventIds": []int{eventID},
+	})
+}
+
+func (tenant *Tenant) serveEventV2(writer http.ResponseWriter, request *http.Request) {
+	var event dtclient.EventDataV2
+	if err := json.NewDecoder(request.Body).Decode(&event); err != nil {
+		writeError(writer, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tenant.mutex.Lock()
+	tenant.eventsV2 = append(tenant.eventsV2, event)
+	correlationID := fmt.Sprintf("fake-event-%d", len(tenant.eventsV2))
+	tenant.mutex.Unlock()
+
+	writeJSON(writer, http.StatusCreated, map[string]interface{}{
+		"reportCount": 1,
+		"eventIngestResults": []map[string]string{
+			{"correlationId": correlationID, "status": "OK"},
+		},
+	})
+}
+
+func (tenant *Tenant) serveTokensLookup(writer http.ResponseWriter, request *http.Request) {
+	var lookup struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(request.Body).Decode(&lookup); err != nil {
+		writeError(writer, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	scopes, ok := tenant.config.TokenScopes[lookup.Token]
+	if !ok {
+		writeError(writer, http.StatusUnauthorized, "Token Authentication failed")
+		return
+	}
+	response := map[string]interface{}{
+		"id":     "fake-token-id",
+		"scopes": scopes,
+	}
+	if expires, ok := tenant.config.TokenExpirations[lookup.Token]; ok {
+		response["expires"] = expires.UnixNano() / int64(time.Millisecond)
+	}
+	writeJSON(writer, http.StatusOK, response)
+}
+
+func splitNonEmpty(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+func containsOrEmpty(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
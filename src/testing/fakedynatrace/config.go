@@ -0,0 +1,94 @@
+package fakedynatrace
+
+import (
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/src/dtclient"
+	"github.com/Dynatrace/dynatrace-operator/src/logger"
+)
+
+const (
+	defaultTenantUUID   = "abc12345"
+	defaultTenantToken  = "fake-tenant-token"
+	defaultAgentVersion = "1.239.0.20220301-120000"
+	defaultAPIToken     = "fake-api-token"
+	defaultPaaSToken    = "fake-paas-token"
+
+	// APIPath is the path the API is served under, matching SaaS and Managed tenants.
+	APIPath = "/api"
+)
+
+var (
+	log = logger.NewDTLogger().WithName("fake-dynatrace")
+
+	// DefaultAPITokenScopes are enough for every feature of the operator.
+	DefaultAPITokenScopes = dtclient.TokenScopes{
+		dtclient.TokenScopeDataExport,
+		dtclient.TokenScopeInstallerDownload,
+		dtclient.TokenScopeMetricsIngest,
+		dtclient.TokenScopeEntitiesRead,
+		dtclient.TokenScopeSettingsRead,
+		dtclient.TokenScopeSettingsWrite,
+		dtclient.TokenScopeEventsIngest,
+	}
+)
+
+// Config describes the tenant served by the fake, empty fields are filled with defaults.
+type Config struct {
+	TenantUUID  string
+	TenantToken string
+
+	// APIToken and PaaSToken are accepted for authentication, TokenScopes are returned by the tokens lookup.
+	APIToken    string
+	PaaSToken   string
+	TokenScopes map[string]dtclient.TokenScopes
+	// TokenExpirations are returned by the tokens lookup, tokens not listed never expire.
+	TokenExpirations map[string]time.Time
+
+	// AgentVersions are the available OneAgent versions, the last one is the latest.
+	AgentVersions []string
+
+	// CommunicationEndpoints default to an endpoint containing the tenant UUID.
+	CommunicationEndpoints []string
+
+	ProcessModuleConfig dtclient.ProcessModuleConfig
+
+	// MultiObjectSchemas allow several settings objects per scope, all other schemas allow only one.
+	MultiObjectSchemas []string
+}
+
+func (config Config) withDefaults() Config {
+	if config.TenantUUID == "" {
+		config.TenantUUID = defaultTenantUUID
+	}
+	if config.TenantToken == "" {
+		config.TenantToken = defaultTenantToken
+	}
+	if config.APIToken == "" {
+		config.APIToken = defaultAPIToken
+	}
+	if config.PaaSToken == "" {
+		config.PaaSToken = defaultPaaSToken
+	}
+	if config.TokenScopes == nil {
+		config.TokenScopes = map[string]dtclient.TokenScopes{
+			config.APIToken:  DefaultAPITokenScopes,
+			config.PaaSToken: {dtclient.TokenScopeInstallerDownload},
+		}
+	}
+	if len(config.AgentVersions) == 0 {
+		config.AgentVersions = []string{defaultAgentVersion}
+	}
+	if len(config.ProcessModuleConfig.Properties) == 0 {
+		config.ProcessModuleConfig = dtclient.ProcessModuleConfig{
+			Revision: 1,
+			Properties: []dtclient.ProcessModuleProperty{
+				{Section: "general", Key: "tenant", Value: config.TenantUUID},
+			},
+		}
+	}
+	if len(config.CommunicationEndpoints) == 0 {
+		config.CommunicationEndpoints = []string{"https://" + config.TenantUUID + ".dev.dynatracelabs.com:443/communication"}
+	}
+	return config
+}
@@ -0,0 +1,60 @@
+package fakedynatrace
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+const (
+	controlPath         = "/fake/"
+	controlFailuresPath = controlPath + "failures"
+	controlRequestsPath = controlPath + "requests"
+	controlEventsPath   = controlPath + "events"
+)
+
+// controlFailure is the JSON representation of a Failure for the control API, the delay is a duration string like "5s".
+type controlFailure struct {
+	Failure
+	Delay string `json:"delay,omitempty"`
+}
+
+// serveControl allows scripting the fake from outside the process, e.g. from kuttl or e2e tests:
+//   - POST /fake/failures adds a failure, e.g. {"pathPrefix": "/v1/deployment", "statusCode": 429, "retryAfter": 5, "times": 3}
+//   - DELETE /fake/failures removes all failures
+//   - GET /fake/requests lists the received requests
+//   - GET /fake/events lists the received events
+func (tenant *Tenant) serveControl(writer http.ResponseWriter, request *http.Request) {
+	switch {
+	case request.URL.Path == controlFailuresPath && request.Method == http.MethodPost:
+		var failure controlFailure
+		if err := json.NewDecoder(request.Body).Decode(&failure); err != nil {
+			writeError(writer, http.StatusBadRequest, err.Error())
+			return
+		}
+		if failure.Delay != "" {
+			delay, err := time.ParseDuration(failure.Delay)
+			if err != nil {
+				writeError(writer, http.StatusBadRequest, err.Error())
+				return
+			}
+			failure.Failure.Delay = delay
+		}
+		tenant.AddFailure(failure.Failure)
+		log.Info("added failure", "failure", failure)
+		writer.WriteHeader(http.StatusNoContent)
+	case request.URL.Path == controlFailuresPath && request.Method == http.MethodDelete:
+		tenant.ClearFailures()
+		log.Info("cleared failures")
+		writer.WriteHeader(http.StatusNoContent)
+	case request.URL.Path == controlRequestsPath && request.Method == http.MethodGet:
+		writeJSON(writer, http.StatusOK, tenant.Requests())
+	case request.URL.Path == controlEventsPath && request.Method == http.MethodGet:
+		writeJSON(writer, http.StatusOK, map[string]interface{}{
+			"v1": tenant.Events(),
+			"v2": tenant.EventsV2(),
+		})
+	default:
+		writeError(writer, http.StatusNotFound, "Not found: "+request.URL.Path)
+	}
+}
@@ -0,0 +1,97 @@
+package fakedynatrace
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Failure makes the fake respond to matching requests with an error status and/or a delay.
+type Failure struct {
+	// PathPrefix of the API path (without APIPath), e.g. "/v1/deployment", matches every request if empty.
+	PathPrefix string `json:"pathPrefix,omitempty"`
+	// StatusCode is sent instead of the regular response, 0 only delays the regular response.
+	StatusCode int `json:"statusCode,omitempty"`
+	// RetryAfter is sent as Retry-After header in seconds, if set.
+	RetryAfter int `json:"retryAfter,omitempty"`
+	// Delay is applied before responding, e.g. to simulate slow responses or timeouts.
+	Delay time.Duration `json:"-"`
+	// Times limits how many requests fail, 0 means all requests.
+	Times int `json:"times,omitempty"`
+}
+
+// Unauthorized fails all requests like a tenant that does not accept the tokens.
+func Unauthorized() Failure {
+	return Failure{StatusCode: http.StatusUnauthorized}
+}
+
+// TooManyRequests throttles the given number of requests for the path prefix.
+func TooManyRequests(pathPrefix string, times, retryAfterSeconds int) Failure {
+	return Failure{PathPrefix: pathPrefix, StatusCode: http.StatusTooManyRequests, RetryAfter: retryAfterSeconds, Times: times}
+}
+
+// Slow delays all responses for the path prefix.
+func Slow(pathPrefix string, delay time.Duration) Failure {
+	return Failure{PathPrefix: pathPrefix, Delay: delay}
+}
+
+func (failure *Failure) matches(path string) bool {
+	return strings.HasPrefix(path, failure.PathPrefix)
+}
+
+// apply delays the request and writes the error response, returns true if the regular response must not be sent.
+func (failure *Failure) apply(writer http.ResponseWriter, request *http.Request) bool {
+	if failure.Delay > 0 {
+		select {
+		case <-time.After(failure.Delay):
+		case <-request.Context().Done():
+			return true
+		}
+	}
+	if failure.StatusCode == 0 {
+		return false
+	}
+	if failure.RetryAfter > 0 {
+		writer.Header().Set("Retry-After", strconv.Itoa(failure.RetryAfter))
+	}
+	writeError(writer, failure.StatusCode, http.StatusText(failure.StatusCode))
+	return true
+}
+
+// AddFailure scripts a failure, failures are evaluated in the order they were added.
+func (tenant *Tenant) AddFailure(failure Failure) {
+	tenant.mutex.Lock()
+	defer tenant.mutex.Unlock()
+
+	tenant.failures = append(tenant.failures, &failure)
+}
+
+// ClearFailures removes all scripted failures.
+func (tenant *Tenant) ClearFailures() {
+	tenant.mutex.Lock()
+	defer tenant.mutex.Unlock()
+
+	tenant.failures = nil
+}
+
+// nextFailure returns the first failure matching the path and counts it as used.
+func (tenant *Tenant) nextFailure(path string) *Failure {
+	tenant.mutex.Lock()
+	defer tenant.mutex.Unlock()
+
+	for i, failure := range tenant.failures {
+		if !failure.matches(path) {
+			continue
+		}
+		if failure.Times > 0 {
+			failure.Times--
+			if failure.Times == 0 {
+				tenant.failures = append(tenant.failures[:i], tenant.failures[i+1:]...)
+			}
+		}
+		current := *failure
+		return &current
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package fakedynatrace
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/src/dtclient"
+)
+
+// Tenant is an in-memory fake of the parts of the Dynatrace API used by the operator, see dtclient/endpoints.go.
+// It is an http.Handler, so it can be served by httptest (see NewServer) or a regular http.Server (see cmd/fakedynatrace).
+type Tenant struct {
+	config Config
+
+	mutex           sync.Mutex
+	failures        []*Failure
+	requests        []Request
+	hosts           []Host
+	entities        map[string][]dtclient.MonitoredEntity
+	settingsObjects []SettingsObject
+	// settingsObjectCount is used to generate unique object ids, as objects can be deleted
+	settingsObjectCount int
+	events              []dtclient.EventData
+	eventsV2            []dtclient.EventDataV2
+	agentPackages       map[string][]byte
+}
+
+// Request is a request received by the fake, with the path relative to APIPath.
+type Request struct {
+	Method string
+	Path   string
+}
+
+// Host is a host known to the tenant, returned by the hosts API.
+type Host struct {
+	EntityID      string
+	IPAddresses   []string
+	NetworkZoneID string
+}
+
+// SettingsObject is a Settings 2.0 object created through the fake.
+type SettingsObject struct {
+	ObjectID      string          `json:"objectId"`
+	SchemaID      string          `json:"schemaId"`
+	SchemaVersion string          `json:"schemaVersion,omitempty"`
+	Scope         string          `json:"scope,omitempty"`
+	Value         json.RawMessage `json:"value"`
+}
+
+func NewTenant(config Config) *Tenant {
+	return &Tenant{
+		config:        config.withDefaults(),
+		entities:      make(map[string][]dtclient.MonitoredEntity),
+		agentPackages: make(map[string][]byte),
+	}
+}
+
+// Server is a Tenant served by httptest.
+type Server struct {
+	*httptest.Server
+	*Tenant
+}
+
+// NewServer starts serving a new fake tenant, it must be closed by the caller.
+func NewServer(config Config) *Server {
+	tenant := NewTenant(config)
+	return &Server{
+		Server: httptest.NewServer(tenant),
+		Tenant: tenant,
+	}
+}
+
+// APIURL returns the URL to configure as DynaKube apiUrl or to pass to dtclient.NewClient.
+func (server *Server) APIURL() string {
+	return server.URL + APIPath
+}
+
+// InstallerURL returns an URL to download the latest agent package without authentication, like a custom installer URL.
+// A sha256 checksum file is available at the same URL with ".sha256" appended.
+func (server *Server) InstallerURL() string {
+	return server.URL + installerDownloadPath
+}
+
+// TenantConfig returns the configuration including defaults, e.g. to get the accepted tokens.
+func (tenant *Tenant) TenantConfig() Config {
+	return tenant.config
+}
+
+// AddHost makes the host known to the hosts API, it is always reported as recently seen.
+func (tenant *Tenant) AddHost(host Host) {
+	tenant.mutex.Lock()
+	defer tenant.mutex.Unlock()
+
+	tenant.hosts = append(tenant.hosts, host)
+}
+
+// AddKubernetesClusterEntity makes the entities API return a KUBERNETES_CLUSTER entity for the kube-system UUID.
+func (tenant *Tenant) AddKubernetesClusterEntity(kubeSystemUUID string) string {
+	tenant.mutex.Lock()
+	defer tenant.mutex.Unlock()
+
+	return tenant.addKubernetesClusterEntity(kubeSystemUUID)
+}
+
+func (tenant *Tenant) addKubernetesClusterEntity(kubeSystemUUID string) string {
+	entityID := fmt.Sprintf("KUBERNETES_CLUSTER-%016X", len(tenant.entities)+1)
+	tenant.entities[kubeSystemUUID] = append(tenant.entities[kubeSystemUUID], dtclient.MonitoredEntity{
+		EntityId:    entityID,
+		DisplayName: kubeSystemUUID,
+		LastSeenTms: time.Now().UnixNano() / int64(time.Millisecond),
+	})
+	return entityID
+}
+
+// Requests returns all requests received so far.
+func (tenant *Tenant) Requests() []Request {
+	tenant.mutex.Lock()
+	defer tenant.mutex.Unlock()
+
+	return append([]Request{}, tenant.requests...)
+}
+
+// CountRequests returns the number of requests received for paths starting with pathPrefix.
+func (tenant *Tenant) CountRequests(pathPrefix string) int {
+	count := 0
+	for _, request := range tenant.Requests() {
+		if strings.HasPrefix(request.Path, pathPrefix) {
+			count++
+		}
+	}
+	return count
+}
+
+// SettingsObjects returns the Settings 2.0 objects created so far.
+func (tenant *Tenant) SettingsObjects() []SettingsObject {
+	tenant.mutex.Lock()
+	defer tenant.mutex.Unlock()
+
+	return append([]SettingsObject{}, tenant.settingsObjects...)
+}
+
+// SetSettingsObjectValue replaces the value of an existing settings object, e.g. to simulate a change made in the web UI.
+// Returns false if there is no object with the given id.
+func (tenant *Tenant) SetSettingsObjectValue(objectID string, value json.RawMessage) bool {
+	tenant.mutex.Lock()
+	defer tenant.mutex.Unlock()
+
+	index := tenant.findSettingsObject(objectID)
+	if index < 0 {
+		return false
+	}
+	tenant.settingsObjects[index].Value = value
+	return true
+}
+
+// Events returns the events received via the events API v1.
+func (tenant *Tenant) Events() []dtclient.EventData {
+	tenant.mutex.Lock()
+	defer tenant.mutex.Unlock()
+
+	return append([]dtclient.EventData{}, tenant.events...)
+}
+
+// EventsV2 returns the events received via the events API v2.
+func (tenant *Tenant) EventsV2() []dtclient.EventDataV2 {
+	tenant.mutex.Lock()
+	defer tenant.mutex.Unlock()
+
+	return append([]dtclient.EventDataV2{}, tenant.eventsV2...)
+}
+
+func (tenant *Tenant) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if strings.HasPrefix(request.URL.Path, controlPath) {
+		tenant.serveControl(writer, request)
+		return
+	}
+	if strings.HasPrefix(request.URL.Path, installerDownloadPath) {
+		tenant.serveInstallerDownload(writer, request)
+		return
+	}
+
+	path := strings.TrimPrefix(request.URL.Path, APIPath)
+	tenant.recordRequest(request.Method, path)
+
+	if failure := tenant.nextFailure(path); failure != nil && failure.apply(writer, request) {
+		return
+	}
+
+	// the tokens lookup is authenticated with the token that is looked up
+	if path == tokensLookupPath {
+		tenant.serveTokensLookup(writer, request)
+		return
+	}
+
+	if !tenant.isAuthorized(request) {
+		writeError(writer, http.StatusUnauthorized, "Token Authentication failed")
+		return
+	}
+
+	tenant.serveAPI(writer, request, path)
+}
+
+func (tenant *Tenant) recordRequest(method, path string) {
+	tenant.mutex.Lock()
+	defer tenant.mutex.Unlock()
+
+	tenant.requests = append(tenant.requests, Request{Method: method, Path: path})
+}
+
+func (tenant *Tenant) isAuthorized(request *http.Request) bool {
+	token := strings.TrimPrefix(request.Header.Get("Authorization"), "Api-Token ")
+	return token == tenant.config.APIToken || token == tenant.config.PaaSToken
+}
+
+func writeJSON(writer http.ResponseWriter, statusCode int, body interface{}) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(statusCode)
+	if err := json.NewEncoder(writer).Encode(body); err != nil {
+		log.Error(err, "failed to write response")
+	}
+}
+
+func writeError(writer http.ResponseWriter, statusCode int, message string) {
+	writeJSON(writer, statusCode, map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    statusCode,
+			"message": message,
+		},
+	})
+}
@@ -0,0 +1,186 @@
+package fakedynatrace
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/src/dtclient"
+	"github.com/klauspost/compress/zip"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testKubeSystemUUID = "kube-system-uuid"
+
+func newTestClient(t *testing.T, server *Server) dtclient.Client {
+	config := server.TenantConfig()
+	dtc, err := dtclient.NewClient(server.APIURL(), config.APIToken, config.PaaSToken)
+	require.NoError(t, err)
+	return dtc
+}
+
+func TestTenant(t *testing.T) {
+	server := NewServer(Config{AgentVersions: []string{"1.0.0.20220101-000000", "1.1.0.20220201-000000"}})
+	defer server.Close()
+	dtc := newTestClient(t, server)
+	ctx := context.TODO()
+
+	t.Run(`tenant metadata`, func(t *testing.T) {
+		connectionInfo, err := dtc.GetConnectionInfo(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, defaultTenantUUID, connectionInfo.TenantUUID)
+		assert.Len(t, connectionInfo.CommunicationHosts, 1)
+
+		agentTenantInfo, err := dtc.GetAgentTenantInfo(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, defaultTenantToken, agentTenantInfo.Token)
+
+		activeGateTenantInfo, err := dtc.GetActiveGateTenantInfo(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, defaultTenantUUID, activeGateTenantInfo.UUID)
+		assert.NotEmpty(t, activeGateTenantInfo.Endpoints)
+
+		processModuleConfig, err := dtc.GetProcessModuleConfig(ctx, 0)
+		require.NoError(t, err)
+		assert.False(t, processModuleConfig.IsEmpty())
+
+		processModuleConfig, err = dtc.GetProcessModuleConfig(ctx, processModuleConfig.Revision)
+		require.NoError(t, err)
+		assert.True(t, processModuleConfig.IsEmpty())
+	})
+	t.Run(`agent versions and download`, func(t *testing.T) {
+		latest, err := dtc.GetLatestAgentVersion(ctx, dtclient.OsUnix, dtclient.InstallerTypePaaS)
+		require.NoError(t, err)
+		assert.Equal(t, "1.1.0.20220201-000000", latest)
+
+		versions, err := dtc.GetAgentVersions(ctx, dtclient.OsUnix, dtclient.InstallerTypePaaS, dtclient.FlavorDefault, dtclient.ArchX86)
+		require.NoError(t, err)
+		assert.Len(t, versions, 2)
+
+		var buffer bytes.Buffer
+		err = dtc.GetAgent(ctx, dtclient.OsUnix, dtclient.InstallerTypePaaS, dtclient.FlavorDefault, dtclient.ArchX86, "1.0.0.20220101-000000", nil, &buffer)
+		require.NoError(t, err)
+		reader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+		require.NoError(t, err)
+		assert.NotEmpty(t, reader.File)
+
+		err = dtc.GetAgent(ctx, dtclient.OsUnix, dtclient.InstallerTypePaaS, dtclient.FlavorDefault, dtclient.ArchX86, "0.0.1", nil, &bytes.Buffer{})
+		assert.Error(t, err)
+
+		err = dtc.GetAgentViaInstallerUrl(ctx, server.InstallerURL(), &bytes.Buffer{})
+		assert.NoError(t, err)
+	})
+	t.Run(`tokens lookup`, func(t *testing.T) {
+		scopes, err := dtc.GetTokenScopes(ctx, server.TenantConfig().APIToken)
+		require.NoError(t, err)
+		assert.True(t, scopes.Contains(dtclient.TokenScopeEventsIngest))
+
+		_, err = dtc.GetTokenScopes(ctx, "unknown-token")
+		assert.Error(t, err)
+	})
+	t.Run(`tokens expiration`, func(t *testing.T) {
+		expires := time.Date(2022, 4, 1, 12, 0, 0, 0, time.UTC)
+		server := NewServer(Config{TokenExpirations: map[string]time.Time{defaultAPIToken: expires}})
+		defer server.Close()
+
+		tokenInfo, err := newTestClient(t, server).GetTokenInfo(ctx, defaultAPIToken)
+		require.NoError(t, err)
+		require.NotNil(t, tokenInfo.Expires)
+		assert.True(t, expires.Equal(*tokenInfo.Expires))
+	})
+	t.Run(`hosts`, func(t *testing.T) {
+		server.AddHost(Host{EntityID: "HOST-42", IPAddresses: []string{"1.2.3.4"}})
+
+		entityID, err := dtc.GetEntityIDForIP(ctx, "1.2.3.4")
+		require.NoError(t, err)
+		assert.Equal(t, "HOST-42", entityID)
+	})
+	t.Run(`kubernetes settings and entities`, func(t *testing.T) {
+		entities, err := dtc.GetMonitoredEntitiesForKubeSystemUUID(ctx, testKubeSystemUUID)
+		require.NoError(t, err)
+		assert.Empty(t, entities)
+
+		objectID, err := dtc.CreateOrUpdateKubernetesSetting(ctx, "cluster", testKubeSystemUUID, "")
+		require.NoError(t, err)
+		assert.NotEmpty(t, objectID)
+
+		entities, err = dtc.GetMonitoredEntitiesForKubeSystemUUID(ctx, testKubeSystemUUID)
+		require.NoError(t, err)
+		require.Len(t, entities, 1)
+
+		settings, err := dtc.GetSettingsForMonitoredEntities(ctx, entities)
+		require.NoError(t, err)
+		assert.Equal(t, 0, settings.TotalCount)
+		assert.Len(t, server.SettingsObjects(), 1)
+	})
+	t.Run(`events`, func(t *testing.T) {
+		err := dtc.SendEvent(ctx, &dtclient.EventData{EventType: dtclient.MarkedForTerminationEvent})
+		require.NoError(t, err)
+
+		err = dtc.SendEventV2(ctx, &dtclient.EventDataV2{EventType: dtclient.CustomDeploymentEvent, Title: "rollout"})
+		require.NoError(t, err)
+
+		assert.Len(t, server.Events(), 1)
+		require.Len(t, server.EventsV2(), 1)
+		assert.Equal(t, "rollout", server.EventsV2()[0].Title)
+	})
+}
+
+func TestTenant_Failures(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run(`unauthorized`, func(t *testing.T) {
+		server := NewServer(Config{})
+		defer server.Close()
+		dtc, err := dtclient.NewClient(server.APIURL(), "wrong", "wrong")
+		require.NoError(t, err)
+
+		_, err = dtc.GetConnectionInfo(ctx)
+		assert.EqualError(t, err, "dynatrace server error 401: Token Authentication failed")
+
+		server.AddFailure(Unauthorized())
+		_, err = newTestClient(t, server).GetConnectionInfo(ctx)
+		assert.Error(t, err)
+	})
+	t.Run(`too many requests are retried`, func(t *testing.T) {
+		server := NewServer(Config{})
+		defer server.Close()
+		server.AddFailure(TooManyRequests(agentConnectionInfoPath, 1, 0))
+
+		_, err := newTestClient(t, server).GetConnectionInfo(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, server.CountRequests(agentConnectionInfoPath))
+	})
+	t.Run(`slow responses`, func(t *testing.T) {
+		server := NewServer(Config{})
+		defer server.Close()
+		server.AddFailure(Slow(agentInstallerPath, time.Minute))
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		_, err := newTestClient(t, server).GetConnectionInfo(timeoutCtx)
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+	t.Run(`scripted via control api`, func(t *testing.T) {
+		server := NewServer(Config{})
+		defer server.Close()
+
+		response, err := http.Post(server.URL+controlFailuresPath, "application/json",
+			strings.NewReader(`{"pathPrefix": "/v1/tokens", "statusCode": 400, "times": 1}`))
+		require.NoError(t, err)
+		_ = response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+
+		dtc := newTestClient(t, server)
+		_, err = dtc.GetTokenScopes(ctx, server.TenantConfig().APIToken)
+		assert.Error(t, err)
+		_, err = dtc.GetTokenScopes(ctx, server.TenantConfig().APIToken)
+		assert.NoError(t, err)
+	})
+}
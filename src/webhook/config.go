@@ -7,6 +7,10 @@ const (
 	// AnnotationDynatraceInjected is set to "true" by the webhook to Pods to indicate that it has been injected.
 	AnnotationDynatraceInjected = "dynakube.dynatrace.com/injected"
 
+	// AnnotationDryRunSummary is set by the webhook in dry-run mode to Pods instead of injecting them, it contains a JSON
+	// summary of the changes the injection would have made.
+	AnnotationDryRunSummary = "dynakube.dynatrace.com/dry-run-summary"
+
 	// AnnotationOneAgentInject can be set at pod level to enable/disable OneAgent injection.
 	OneAgentPrefix           = "oneagent"
 	AnnotationOneAgentInject = OneAgentPrefix + ".dynatrace.com/inject"
@@ -15,6 +19,14 @@ const (
 	DataIngestPrefix           = "data-ingest"
 	AnnotationDataIngestInject = DataIngestPrefix + ".dynatrace.com/inject"
 
+	// AnnotationIncludeContainers can be set on a Pod to only instrument the listed containers, a comma separated list
+	// of container names. Takes precedence over the container selection of the DynaKube.
+	AnnotationIncludeContainers = "oneagent.dynatrace.com/include-containers"
+
+	// AnnotationExcludeContainers can be set on a Pod to not instrument the listed containers, a comma separated list
+	// of container names. Takes precedence over AnnotationIncludeContainers and the container selection of the DynaKube.
+	AnnotationExcludeContainers = "oneagent.dynatrace.com/exclude-containers"
+
 	// AnnotationFlavor can be set on a Pod to configure which code modules flavor to download. It's set to "default"
 	// if not set.
 	AnnotationFlavor = "oneagent.dynatrace.com/flavor"
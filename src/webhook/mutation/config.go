@@ -2,12 +2,16 @@ package mutation
 
 import (
 	"github.com/Dynatrace/dynatrace-operator/src/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
 const (
 	injectEvent          = "Inject"
 	updatePodEvent       = "UpdatePod"
 	missingDynakubeEvent = "MissingDynakube"
+	dryRunInjectEvent    = "DryRunInject"
+	injectionFailedEvent = "InjectionFailed"
 
 	dataIngestInjectedEnvVarName = "DATA_INGEST_INJECTED"
 	oneAgentInjectedEnvVarName   = "ONEAGENT_INJECTED"
@@ -29,6 +33,51 @@ const (
 	installerVolumeMode   = "installer"
 )
 
+const (
+	dynakubeLabel  = "dynakube"
+	namespaceLabel = "namespace"
+	kindLabel      = "kind"
+	sourceLabel    = "source"
+	modeLabel      = "mode"
+	resultLabel    = "result"
+	reasonLabel    = "reason"
+)
+
 var (
 	log = logger.NewDTLogger().WithName("mutation-webhook")
+
+	dryRunInjectionsMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dynatrace",
+		Subsystem: "webhook",
+		Name:      "dry_run_injections_total",
+		Help:      "Number of pods which would have been injected if the dry run was disabled",
+	}, []string{dynakubeLabel, namespaceLabel})
+
+	podInjectionsMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dynatrace",
+		Subsystem: "webhook",
+		Name:      "pod_injections_total",
+		Help:      "Number of pods admitted by the pod mutator by injection mode, result (injected, skipped or failed) and reason",
+	}, []string{namespaceLabel, dynakubeLabel, modeLabel, resultLabel, reasonLabel})
+
+	lookupDurationMetric = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "dynatrace",
+		Subsystem: "webhook",
+		Name:      "lookup_duration_seconds",
+		Help:      "Duration of the lookups of the pod mutator by kind and source, either the webhook cache or the API server",
+		Buckets:   prometheus.ExponentialBuckets(0.0001, 4, 10),
+	}, []string{kindLabel, sourceLabel})
+
+	admissionDurationMetric = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "dynatrace",
+		Subsystem: "webhook",
+		Name:      "pod_admission_duration_seconds",
+		Help:      "Duration of the admission of pods by the pod mutator",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 2, 14),
+	})
 )
+
+// Registered on the controller-runtime registry, which is served by the webhook manager.
+func init() {
+	metrics.Registry.MustRegister(dryRunInjectionsMetric, podInjectionsMetric, lookupDurationMetric, admissionDurationMetric)
+}
@@ -0,0 +1,151 @@
+package mutation
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/src/webhook"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// containerMatcher is a compiled ContainerSelector, nil patterns aren't set
+type containerMatcher struct {
+	name  *regexp.Regexp
+	image *regexp.Regexp
+}
+
+// containerSelection is the compiled container selection of a DynaKube
+type containerSelection struct {
+	include []containerMatcher
+	exclude []containerMatcher
+}
+
+// compileContainerSelection compiles the rules of the DynaKube, invalid rules are rejected by the validation webhook
+// and never match
+func compileContainerSelection(spec dynatracev1beta1.ContainerSelectionSpec) containerSelection {
+	return containerSelection{
+		include: compileContainerSelectors(spec.Include),
+		exclude: compileContainerSelectors(spec.Exclude),
+	}
+}
+
+func compileContainerSelectors(selectors []dynatracev1beta1.ContainerSelector) []containerMatcher {
+	var matchers []containerMatcher
+	for _, selector := range selectors {
+		matcher, err := compileContainerSelector(selector)
+		if err != nil {
+			podLog.Info("ignoring invalid container selector", "name", selector.Name, "image", selector.Image, "error", err.Error())
+			continue
+		}
+		matchers = append(matchers, matcher)
+	}
+	return matchers
+}
+
+func compileContainerSelector(selector dynatracev1beta1.ContainerSelector) (containerMatcher, error) {
+	var matcher containerMatcher
+	var err error
+	if selector.Name == "" && selector.Image == "" {
+		return matcher, errors.New("neither name nor image is set")
+	}
+	if selector.Name != "" {
+		if matcher.name, err = dynatracev1beta1.CompileContainerPattern(selector.Name); err != nil {
+			return matcher, err
+		}
+	}
+	if selector.Image != "" {
+		if matcher.image, err = dynatracev1beta1.CompileContainerPattern(selector.Image); err != nil {
+			return matcher, err
+		}
+	}
+	return matcher, nil
+}
+
+// containerSelectionCache keeps the compiled container selection per DynaKube until its spec changes,
+// a nil cache compiles the selection on every call
+type containerSelectionCache struct {
+	selections sync.Map
+}
+
+type cachedContainerSelection struct {
+	generation int64
+	selection  containerSelection
+}
+
+func newContainerSelectionCache() *containerSelectionCache {
+	return &containerSelectionCache{}
+}
+
+func (cache *containerSelectionCache) get(dk *dynatracev1beta1.DynaKube) containerSelection {
+	if cache == nil {
+		return compileContainerSelection(dk.ContainerSelection())
+	}
+	if cached, ok := cache.selections.Load(dk.UID); ok && cached.(cachedContainerSelection).generation == dk.Generation {
+		return cached.(cachedContainerSelection).selection
+	}
+	selection := compileContainerSelection(dk.ContainerSelection())
+	cache.selections.Store(dk.UID, cachedContainerSelection{generation: dk.Generation, selection: selection})
+	return selection
+}
+
+// selectContainers returns the containers of the pod which are instrumented. The annotations of the pod take
+// precedence over the container selection of the DynaKube, exclusions take precedence over inclusions.
+func selectContainers(pod *corev1.Pod, selection containerSelection) []*corev1.Container {
+	var selected []*corev1.Container
+	for i := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[i]
+		if isContainerSelected(pod, container, selection) {
+			selected = append(selected, container)
+		} else {
+			podLog.Info("container is not selected for instrumentation", "containerName", container.Name)
+		}
+	}
+	return selected
+}
+
+func isContainerSelected(pod *corev1.Pod, container *corev1.Container, selection containerSelection) bool {
+	if containerNamesOfAnnotation(pod, dtwebhook.AnnotationExcludeContainers)[container.Name] {
+		return false
+	}
+	if included := containerNamesOfAnnotation(pod, dtwebhook.AnnotationIncludeContainers); len(included) > 0 {
+		return included[container.Name]
+	}
+
+	if matchesAnyContainer(container, selection.exclude) {
+		return false
+	}
+	return len(selection.include) == 0 || matchesAnyContainer(container, selection.include)
+}
+
+func containerNamesOfAnnotation(pod *corev1.Pod, annotation string) map[string]bool {
+	names := make(map[string]bool)
+	for _, name := range strings.Split(pod.Annotations[annotation], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+func matchesAnyContainer(container *corev1.Container, matchers []containerMatcher) bool {
+	for _, matcher := range matchers {
+		if matcher.matches(container) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches returns true if the whole name and image of the container match the patterns which are set
+func (matcher containerMatcher) matches(container *corev1.Container) bool {
+	if matcher.name != nil && !matcher.name.MatchString(container.Name) {
+		return false
+	}
+	if matcher.image != nil && !matcher.image.MatchString(container.Image) {
+		return false
+	}
+	return true
+}
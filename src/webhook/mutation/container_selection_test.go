@@ -0,0 +1,248 @@
+package mutation
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	"github.com/Dynatrace/dynatrace-operator/src/standalone"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/src/webhook"
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/json"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestIsContainerSelected(t *testing.T) {
+	app := &corev1.Container{Name: "app", Image: "registry.example.com/app:1.0"}
+	sidecar := &corev1.Container{Name: "istio-proxy", Image: "docker.io/istio/proxyv2:1.14.1"}
+	newPod := func(annotations map[string]string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}}
+	}
+
+	t.Run(`all containers are selected by default`, func(t *testing.T) {
+		pod := newPod(nil)
+		assert.True(t, isContainerSelected(pod, app, containerSelection{}))
+		assert.True(t, isContainerSelected(pod, sidecar, containerSelection{}))
+	})
+	t.Run(`dynakube rules select by name and image`, func(t *testing.T) {
+		pod := newPod(nil)
+		selection := compileContainerSelection(dynatracev1beta1.ContainerSelectionSpec{
+			Exclude: []dynatracev1beta1.ContainerSelector{{Image: ".*/istio/proxyv2:.*"}},
+		})
+		assert.True(t, isContainerSelected(pod, app, selection))
+		assert.False(t, isContainerSelected(pod, sidecar, selection))
+
+		selection = compileContainerSelection(dynatracev1beta1.ContainerSelectionSpec{
+			Include: []dynatracev1beta1.ContainerSelector{{Name: "app", Image: "registry.example.com/.*"}},
+		})
+		assert.True(t, isContainerSelected(pod, app, selection))
+		assert.False(t, isContainerSelected(pod, sidecar, selection))
+	})
+	t.Run(`dynakube rules match the whole name and image`, func(t *testing.T) {
+		selection := compileContainerSelection(dynatracev1beta1.ContainerSelectionSpec{
+			Exclude: []dynatracev1beta1.ContainerSelector{{Name: "proxy"}, {Image: "istio"}},
+		})
+		assert.True(t, isContainerSelected(newPod(nil), sidecar, selection))
+
+		selection = compileContainerSelection(dynatracev1beta1.ContainerSelectionSpec{
+			Exclude: []dynatracev1beta1.ContainerSelector{{Name: "app|.*-proxy"}},
+		})
+		assert.False(t, isContainerSelected(newPod(nil), app, selection))
+		assert.False(t, isContainerSelected(newPod(nil), sidecar, selection))
+	})
+	t.Run(`invalid dynakube rules never match`, func(t *testing.T) {
+		selection := compileContainerSelection(dynatracev1beta1.ContainerSelectionSpec{
+			Include: []dynatracev1beta1.ContainerSelector{{Name: "app"}},
+			Exclude: []dynatracev1beta1.ContainerSelector{{Name: "("}, {}},
+		})
+		assert.Empty(t, selection.exclude)
+		assert.True(t, isContainerSelected(newPod(nil), app, selection))
+	})
+	t.Run(`exclude rules take precedence over include rules`, func(t *testing.T) {
+		selection := compileContainerSelection(dynatracev1beta1.ContainerSelectionSpec{
+			Include: []dynatracev1beta1.ContainerSelector{{Name: ".*"}},
+			Exclude: []dynatracev1beta1.ContainerSelector{{Name: "istio-proxy"}},
+		})
+		assert.True(t, isContainerSelected(newPod(nil), app, selection))
+		assert.False(t, isContainerSelected(newPod(nil), sidecar, selection))
+	})
+	t.Run(`pod annotations take precedence over dynakube rules`, func(t *testing.T) {
+		selection := compileContainerSelection(dynatracev1beta1.ContainerSelectionSpec{
+			Exclude: []dynatracev1beta1.ContainerSelector{{Name: "istio-proxy"}},
+		})
+		pod := newPod(map[string]string{dtwebhook.AnnotationIncludeContainers: "istio-proxy"})
+		assert.False(t, isContainerSelected(pod, app, selection))
+		assert.True(t, isContainerSelected(pod, sidecar, selection))
+
+		pod = newPod(map[string]string{
+			dtwebhook.AnnotationIncludeContainers: "app, istio-proxy",
+			dtwebhook.AnnotationExcludeContainers: "istio-proxy",
+		})
+		assert.True(t, isContainerSelected(pod, app, containerSelection{}))
+		assert.False(t, isContainerSelected(pod, sidecar, containerSelection{}))
+	})
+}
+
+func TestContainerSelection(t *testing.T) {
+	t.Run(`excluded containers are not instrumented`, func(t *testing.T) {
+		inj := createInjectionConfigPodInjector(t)
+
+		updPod := injectContainerSelectionPod(t, inj, map[string]string{dtwebhook.AnnotationExcludeContainers: "istio-proxy"})
+
+		assertContainersSelected(t, updPod, "app")
+		assertContainersEnriched(t, updPod, "app", "istio-proxy")
+	})
+	t.Run(`dynakube rules exclude containers`, func(t *testing.T) {
+		inj := createInjectionConfigPodInjector(t)
+		var dynakube dynatracev1beta1.DynaKube
+		require.NoError(t, inj.client.Get(context.TODO(), client.ObjectKey{Name: dynakubeName, Namespace: "dynatrace"}, &dynakube))
+		dynakube.Spec.OneAgent.CloudNativeFullStack.ContainerSelection.Exclude = []dynatracev1beta1.ContainerSelector{{Image: ".*/istio/proxyv2:.*"}}
+		require.NoError(t, inj.client.Update(context.TODO(), &dynakube))
+
+		updPod := injectContainerSelectionPod(t, inj, nil)
+
+		assertContainersSelected(t, updPod, "app")
+	})
+	t.Run(`containers are numbered consecutively`, func(t *testing.T) {
+		inj := createInjectionConfigPodInjector(t)
+
+		updPod := injectContainerSelectionPod(t, inj, map[string]string{dtwebhook.AnnotationExcludeContainers: "app"})
+
+		assertContainersSelected(t, updPod, "istio-proxy")
+	})
+	t.Run(`oneagent is skipped if all containers are excluded`, func(t *testing.T) {
+		inj := createInjectionConfigPodInjector(t)
+		skippedBefore := podInjections(dtwebhook.OneAgentPrefix, injectionResultSkipped, reasonNoSelectedContainers)
+
+		updPod := injectContainerSelectionPod(t, inj, map[string]string{dtwebhook.AnnotationExcludeContainers: "app,istio-proxy"})
+
+		assertContainersSelected(t, updPod)
+		assertContainersEnriched(t, updPod, "app", "istio-proxy")
+		for _, volume := range updPod.Spec.Volumes {
+			assert.NotEqual(t, oneAgentBinVolumeName, volume.Name)
+			assert.NotEqual(t, oneAgentShareVolumeName, volume.Name)
+		}
+		assert.Equal(t, skippedBefore+1, podInjections(dtwebhook.OneAgentPrefix, injectionResultSkipped, reasonNoSelectedContainers))
+	})
+	t.Run(`pod is skipped if all containers are excluded without metadata enrichment`, func(t *testing.T) {
+		inj := createInjectionConfigPodInjector(t)
+		skippedBefore := podInjections(dtwebhook.OneAgentPrefix, injectionResultSkipped, reasonNoSelectedContainers)
+
+		resp := handleReportedPod(t, inj, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod-12345",
+			Namespace: "test-namespace",
+			Annotations: map[string]string{
+				dtwebhook.AnnotationExcludeContainers: "test-container",
+				dtwebhook.AnnotationDataIngestInject:  "false",
+			},
+		}})
+
+		assert.Empty(t, resp.Patches)
+		assert.Equal(t, skippedBefore+1, podInjections(dtwebhook.OneAgentPrefix, injectionResultSkipped, reasonNoSelectedContainers))
+	})
+}
+
+func injectContainerSelectionPod(t *testing.T, inj *podMutator, annotations map[string]string) corev1.Pod {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pod-12345", Namespace: "test-namespace", Annotations: annotations},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "app", Image: "registry.example.com/app:1.0"},
+				{Name: "istio-proxy", Image: "docker.io/istio/proxyv2:1.14.1"},
+			},
+		},
+	}
+	podBytes, err := json.Marshal(&pod)
+	require.NoError(t, err)
+
+	req := admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Object:    runtime.RawExtension{Raw: podBytes},
+			Namespace: "test-namespace",
+		},
+	}
+	resp := inj.Handle(context.TODO(), req)
+	require.NoError(t, resp.Complete(req))
+	require.True(t, resp.Allowed)
+
+	patch, err := jsonpatch.DecodePatch(resp.Patch)
+	require.NoError(t, err)
+	updPodBytes, err := patch.Apply(podBytes)
+	require.NoError(t, err)
+
+	var updPod corev1.Pod
+	require.NoError(t, json.Unmarshal(updPodBytes, &updPod))
+	return updPod
+}
+
+// assertContainersSelected checks that only the given containers are passed to the install container and preloaded
+func assertContainersSelected(t *testing.T, pod corev1.Pod, names ...string) {
+	require.Len(t, pod.Spec.InitContainers, 1)
+	env := make(map[string]string)
+	for _, envVar := range pod.Spec.InitContainers[0].Env {
+		env[envVar.Name] = envVar.Value
+	}
+	assert.Equal(t, strconv.Itoa(len(names)), env[standalone.ContainerCountEnv])
+
+	var configured []string
+	for i := 1; i <= len(names); i++ {
+		configured = append(configured, env[fmt.Sprintf("CONTAINER_%d_NAME", i)])
+	}
+	assert.Equal(t, names, configured)
+
+	var preloaded []string
+	for _, container := range pod.Spec.Containers {
+		for _, envVar := range container.Env {
+			if envVar.Name == "LD_PRELOAD" {
+				preloaded = append(preloaded, container.Name)
+			}
+		}
+	}
+	assert.Equal(t, names, preloaded)
+}
+
+// assertContainersEnriched checks that the metadata enrichment isn't restricted by the container selection
+func assertContainersEnriched(t *testing.T, pod corev1.Pod, names ...string) {
+	var enriched []string
+	for _, container := range pod.Spec.Containers {
+		for _, volumeMount := range container.VolumeMounts {
+			if volumeMount.Name == dataIngestEndpointVolumeName {
+				enriched = append(enriched, container.Name)
+			}
+		}
+	}
+	assert.Equal(t, names, enriched)
+}
+
+func TestContainerSelectionCache(t *testing.T) {
+	dynakube := &dynatracev1beta1.DynaKube{ObjectMeta: metav1.ObjectMeta{UID: "test-uid", Generation: 1}}
+	dynakube.Spec.OneAgent.CloudNativeFullStack = &dynatracev1beta1.CloudNativeFullStackSpec{}
+	dynakube.Spec.OneAgent.CloudNativeFullStack.ContainerSelection.Exclude = []dynatracev1beta1.ContainerSelector{{Name: "istio-proxy"}}
+	cache := newContainerSelectionCache()
+
+	t.Run(`keeps the compiled selection of a dynakube`, func(t *testing.T) {
+		selection := cache.get(dynakube)
+		require.Len(t, selection.exclude, 1)
+		assert.Same(t, selection.exclude[0].name, cache.get(dynakube).exclude[0].name)
+	})
+	t.Run(`recompiles the selection once the dynakube changed`, func(t *testing.T) {
+		changed := dynakube.DeepCopy()
+		changed.Generation = 2
+		changed.Spec.OneAgent.CloudNativeFullStack.ContainerSelection.Exclude = nil
+
+		assert.Empty(t, cache.get(changed).exclude)
+	})
+	t.Run(`nil cache compiles the selection`, func(t *testing.T) {
+		var cache *containerSelectionCache
+		assert.Len(t, cache.get(dynakube).exclude, 1)
+	})
+}
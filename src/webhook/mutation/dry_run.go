@@ -0,0 +1,155 @@
+package mutation
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/src/webhook"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// dryRunSummary describes the changes the injection would have made to a pod
+type dryRunSummary struct {
+	OneAgent       bool                     `json:"oneAgent"`
+	DataIngest     bool                     `json:"dataIngest"`
+	InitContainers []string                 `json:"initContainers,omitempty"`
+	Volumes        []string                 `json:"volumes,omitempty"`
+	Containers     []containerDryRunSummary `json:"containers,omitempty"`
+}
+
+// containerDryRunSummary lists the env vars and mount paths the injection would have added to a container
+type containerDryRunSummary struct {
+	Name         string   `json:"name"`
+	Env          []string `json:"env,omitempty"`
+	VolumeMounts []string `json:"volumeMounts,omitempty"`
+}
+
+// isDryRun returns true if the pods of the namespace are only annotated with a summary of the injection, either
+// because of the DynaKube or the InjectionConfig of the namespace
+func isDryRun(dk dynatracev1beta1.DynaKube, injectionConfig *dynatracev1beta1.InjectionConfig) bool {
+	return dk.FeatureInjectionDryRun() || (injectionConfig != nil && injectionConfig.Spec.DryRun)
+}
+
+func newDryRunSummary(original *corev1.Pod, injected *corev1.Pod, injectionInfo *InjectionInfo) dryRunSummary {
+	summary := dryRunSummary{
+		OneAgent:       injectionInfo.enabled(OneAgent),
+		DataIngest:     injectionInfo.enabled(DataIngest),
+		InitContainers: addedInitContainers(original, injected),
+		Volumes:        addedVolumes(original, injected),
+	}
+
+	for i := range injected.Spec.Containers {
+		container := &injected.Spec.Containers[i]
+		var originalContainer corev1.Container
+		if i < len(original.Spec.Containers) {
+			originalContainer = original.Spec.Containers[i]
+		}
+
+		containerSummary := containerDryRunSummary{
+			Name:         container.Name,
+			Env:          addedEnvVars(&originalContainer, container),
+			VolumeMounts: addedMountPaths(&originalContainer, container),
+		}
+		if len(containerSummary.Env) > 0 || len(containerSummary.VolumeMounts) > 0 {
+			summary.Containers = append(summary.Containers, containerSummary)
+		}
+	}
+	return summary
+}
+
+func (summary dryRunSummary) String() string {
+	changes := []string{
+		fmt.Sprintf("init containers %v", summary.InitContainers),
+		fmt.Sprintf("volumes %v", summary.Volumes),
+	}
+	for _, container := range summary.Containers {
+		changes = append(changes, fmt.Sprintf("env vars %v and mounts %v to container %s", container.Env, container.VolumeMounts, container.Name))
+	}
+	return strings.Join(changes, ", ")
+}
+
+// handleDryRun annotates the original pod with the summary of the injection instead of applying it
+func (m *podMutator) handleDryRun(original *corev1.Pod, injected *corev1.Pod, dk dynatracev1beta1.DynaKube, injectionInfo *InjectionInfo, req admission.Request) admission.Response {
+	summary := newDryRunSummary(original, injected, injectionInfo)
+	summaryJson, err := json.Marshal(summary)
+	if err != nil {
+		return silentErrorResponse(m.currentPodName, err)
+	}
+
+	if original.Annotations == nil {
+		original.Annotations = make(map[string]string)
+	}
+	original.Annotations[dtwebhook.AnnotationDryRunSummary] = string(summaryJson)
+
+	podLog.Info("dry run, not injecting into Pod", "name", original.Name, "generatedName", original.GenerateName, "namespace", req.Namespace)
+	dryRunInjectionsMetric.WithLabelValues(dk.Name, req.Namespace).Inc()
+	m.recorder.Eventf(&dk,
+		corev1.EventTypeNormal,
+		dryRunInjectEvent,
+		"Dry run, would add %s to pod %s in namespace %s", summary, getBasePodName(original), req.Namespace)
+
+	return getResponseForPod(original, &req)
+}
+
+func addedInitContainers(original *corev1.Pod, injected *corev1.Pod) []string {
+	existing := make(map[string]bool)
+	for _, container := range original.Spec.InitContainers {
+		existing[container.Name] = true
+	}
+
+	var added []string
+	for _, container := range injected.Spec.InitContainers {
+		if !existing[container.Name] {
+			added = append(added, container.Name)
+		}
+	}
+	return added
+}
+
+func addedVolumes(original *corev1.Pod, injected *corev1.Pod) []string {
+	existing := make(map[string]bool)
+	for _, volume := range original.Spec.Volumes {
+		existing[volume.Name] = true
+	}
+
+	var added []string
+	for _, volume := range injected.Spec.Volumes {
+		if !existing[volume.Name] {
+			added = append(added, volume.Name)
+		}
+	}
+	return added
+}
+
+func addedEnvVars(original *corev1.Container, injected *corev1.Container) []string {
+	existing := make(map[string]bool)
+	for _, envVar := range original.Env {
+		existing[envVar.Name] = true
+	}
+
+	var added []string
+	for _, envVar := range injected.Env {
+		if !existing[envVar.Name] {
+			added = append(added, envVar.Name)
+		}
+	}
+	return added
+}
+
+func addedMountPaths(original *corev1.Container, injected *corev1.Container) []string {
+	existing := make(map[string]bool)
+	for _, volumeMount := range original.VolumeMounts {
+		existing[volumeMount.MountPath] = true
+	}
+
+	var added []string
+	for _, volumeMount := range injected.VolumeMounts {
+		if !existing[volumeMount.MountPath] {
+			added = append(added, volumeMount.MountPath)
+		}
+	}
+	return added
+}
@@ -0,0 +1,104 @@
+package mutation
+
+import (
+	"context"
+	"testing"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	t_utils "github.com/Dynatrace/dynatrace-operator/src/testing"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/src/webhook"
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestDryRun(t *testing.T) {
+	t.Run(`dynakube dry run only annotates the pod`, func(t *testing.T) {
+		inj := createInjectionConfigPodInjector(t)
+		var dynakube dynatracev1beta1.DynaKube
+		require.NoError(t, inj.client.Get(context.TODO(), client.ObjectKey{Name: dynakubeName, Namespace: "dynatrace"}, &dynakube))
+		dynakube.Annotations = map[string]string{dynatracev1beta1.AnnotationFeatureInjectionDryRun: "true"}
+		require.NoError(t, inj.client.Update(context.TODO(), &dynakube))
+		injectionsBefore := testutil.ToFloat64(dryRunInjectionsMetric.WithLabelValues(dynakubeName, "test-namespace"))
+
+		updPod := injectDryRunPod(t, inj)
+
+		assert.Empty(t, updPod.Spec.InitContainers)
+		assert.Empty(t, updPod.Spec.Volumes)
+		assert.Empty(t, updPod.Spec.Containers[0].Env)
+		assert.NotContains(t, updPod.Annotations, dtwebhook.AnnotationDynatraceInjected)
+
+		var summary dryRunSummary
+		require.NoError(t, json.Unmarshal([]byte(updPod.Annotations[dtwebhook.AnnotationDryRunSummary]), &summary))
+		assert.True(t, summary.OneAgent)
+		assert.True(t, summary.DataIngest)
+		assert.Equal(t, []string{dtwebhook.InstallContainerName}, summary.InitContainers)
+		assert.ElementsMatch(t, []string{injectionConfigVolumeName, oneAgentBinVolumeName, oneAgentShareVolumeName,
+			dataIngestVolumeName, dataIngestEndpointVolumeName}, summary.Volumes)
+		require.Len(t, summary.Containers, 1)
+		assert.Equal(t, "test-container", summary.Containers[0].Name)
+		assert.Contains(t, summary.Containers[0].Env, "LD_PRELOAD")
+		assert.Contains(t, summary.Containers[0].VolumeMounts, dtwebhook.DefaultInstallPath)
+
+		assert.Equal(t, injectionsBefore+1, testutil.ToFloat64(dryRunInjectionsMetric.WithLabelValues(dynakubeName, "test-namespace")))
+		t_utils.AssertEvents(t,
+			inj.recorder.(*record.FakeRecorder).Events,
+			t_utils.Events{
+				t_utils.Event{
+					EventType: corev1.EventTypeNormal,
+					Reason:    dryRunInjectEvent,
+				},
+			},
+		)
+	})
+	t.Run(`injection config enables dry run for its namespace`, func(t *testing.T) {
+		inj := createInjectionConfigPodInjector(t, &dynatracev1beta1.InjectionConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "dry-run", Namespace: "test-namespace"},
+			Spec:       dynatracev1beta1.InjectionConfigSpec{DryRun: true},
+		})
+
+		updPod := injectDryRunPod(t, inj)
+
+		assert.Empty(t, updPod.Spec.InitContainers)
+		assert.Contains(t, updPod.Annotations, dtwebhook.AnnotationDryRunSummary)
+	})
+}
+
+func injectDryRunPod(t *testing.T, inj *podMutator) corev1.Pod {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pod-12345", Namespace: "test-namespace"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "test-container", Image: "alpine"}},
+		},
+	}
+	podBytes, err := json.Marshal(&pod)
+	require.NoError(t, err)
+
+	req := admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Object:    runtime.RawExtension{Raw: podBytes},
+			Namespace: "test-namespace",
+		},
+	}
+	resp := inj.Handle(context.TODO(), req)
+	require.NoError(t, resp.Complete(req))
+	require.True(t, resp.Allowed)
+
+	patch, err := jsonpatch.DecodePatch(resp.Patch)
+	require.NoError(t, err)
+	updPodBytes, err := patch.Apply(podBytes)
+	require.NoError(t, err)
+
+	var updPod corev1.Pod
+	require.NoError(t, json.Unmarshal(updPodBytes, &updPod))
+	return updPod
+}
@@ -0,0 +1,73 @@
+package mutation
+
+import (
+	"context"
+	"sort"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	"github.com/Dynatrace/dynatrace-operator/src/dtclient"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/src/webhook"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// getInjectionConfig returns the InjectionConfig of the namespace, if there are several the first one by name is used.
+// If InjectionConfigs can't be listed because the CRD or the permissions are missing, e.g. during an upgrade,
+// pods are injected without them.
+func (m *podMutator) getInjectionConfig(ctx context.Context, namespace string) (*dynatracev1beta1.InjectionConfig, error) {
+	var injectionConfigs dynatracev1beta1.InjectionConfigList
+	err := m.cache.List(ctx, &injectionConfigs, m.apiReader, client.InNamespace(namespace))
+	if isInjectionConfigUnavailable(err) {
+		podLog.Info("InjectionConfigs are unavailable, injecting without namespace defaults", "namespace", namespace, "error", err.Error())
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if len(injectionConfigs.Items) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(injectionConfigs.Items, func(i, j int) bool {
+		return injectionConfigs.Items[i].Name < injectionConfigs.Items[j].Name
+	})
+	if len(injectionConfigs.Items) > 1 {
+		podLog.Info("multiple InjectionConfigs in namespace, using the first one", "namespace", namespace, "name", injectionConfigs.Items[0].Name)
+	}
+	return &injectionConfigs.Items[0], nil
+}
+
+func isInjectionConfigUnavailable(err error) bool {
+	return meta.IsNoMatchError(err) || k8serrors.IsNotFound(err) || k8serrors.IsForbidden(err)
+}
+
+// injectionDefaults returns the values which are used for pods without the corresponding annotation
+func injectionDefaults(injectionConfig *dynatracev1beta1.InjectionConfig) dynatracev1beta1.InjectionConfigSpec {
+	defaults := dynatracev1beta1.InjectionConfigSpec{
+		Flavor:        dtclient.FlavorMultidistro,
+		Technologies:  []string{"all"},
+		InstallPath:   dtwebhook.DefaultInstallPath,
+		FailurePolicy: "silent",
+	}
+	if injectionConfig == nil {
+		return defaults
+	}
+
+	spec := injectionConfig.Spec
+	if spec.Flavor != "" {
+		defaults.Flavor = spec.Flavor
+	}
+	if len(spec.Technologies) > 0 {
+		defaults.Technologies = spec.Technologies
+	}
+	if spec.InstallPath != "" {
+		defaults.InstallPath = spec.InstallPath
+	}
+	if spec.InstallerURL != "" {
+		defaults.InstallerURL = spec.InstallerURL
+	}
+	if spec.FailurePolicy != "" {
+		defaults.FailurePolicy = spec.FailurePolicy
+	}
+	return defaults
+}
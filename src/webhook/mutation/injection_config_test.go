@@ -0,0 +1,152 @@
+package mutation
+
+import (
+	"context"
+	"testing"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	"github.com/Dynatrace/dynatrace-operator/src/dtclient"
+	"github.com/Dynatrace/dynatrace-operator/src/scheme"
+	"github.com/Dynatrace/dynatrace-operator/src/standalone"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/src/webhook"
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/json"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestInjectionConfig(t *testing.T) {
+	injectionConfig := &dynatracev1beta1.InjectionConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "defaults", Namespace: "test-namespace", Generation: 2},
+		Spec: dynatracev1beta1.InjectionConfigSpec{
+			Flavor:        "musl",
+			Technologies:  []string{"java", "nginx"},
+			InstallPath:   "/opt/custom",
+			FailurePolicy: "fail",
+		},
+	}
+
+	t.Run(`namespace defaults are used for pods without annotations`, func(t *testing.T) {
+		inj := createInjectionConfigPodInjector(t, injectionConfig)
+
+		env := injectInstallContainerEnv(t, inj, nil)
+
+		assert.Equal(t, "musl", env[standalone.InstallerFlavorEnv])
+		assert.Equal(t, "java%2Cnginx", env[standalone.InstallerTechEnv])
+		assert.Equal(t, "/opt/custom", env[standalone.InstallPathEnv])
+		assert.Equal(t, "", env[standalone.InstallerUrlEnv])
+		assert.Equal(t, "fail", env[standalone.CanFailEnv])
+
+		var unchanged dynatracev1beta1.InjectionConfig
+		require.NoError(t, inj.client.Get(context.TODO(), client.ObjectKeyFromObject(injectionConfig), &unchanged))
+		assert.Empty(t, unchanged.Status)
+	})
+	t.Run(`pod annotations take precedence over namespace defaults`, func(t *testing.T) {
+		inj := createInjectionConfigPodInjector(t, injectionConfig)
+
+		env := injectInstallContainerEnv(t, inj, map[string]string{
+			dtwebhook.AnnotationFlavor:        "default",
+			dtwebhook.AnnotationFailurePolicy: "silent",
+		})
+
+		assert.Equal(t, "default", env[standalone.InstallerFlavorEnv])
+		assert.Equal(t, "java%2Cnginx", env[standalone.InstallerTechEnv])
+		assert.Equal(t, "/opt/custom", env[standalone.InstallPathEnv])
+		assert.Equal(t, "silent", env[standalone.CanFailEnv])
+	})
+	t.Run(`first injection config by name is used`, func(t *testing.T) {
+		other := injectionConfig.DeepCopy()
+		other.Name = "zz-other"
+		other.Spec.Flavor = "default"
+		inj := createInjectionConfigPodInjector(t, injectionConfig, other)
+
+		env := injectInstallContainerEnv(t, inj, nil)
+
+		assert.Equal(t, "musl", env[standalone.InstallerFlavorEnv])
+	})
+	t.Run(`pods are injected with defaults if injection configs are unavailable`, func(t *testing.T) {
+		for name, err := range map[string]error{
+			"missing crd":         &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "dynatrace.com", Kind: "InjectionConfig"}},
+			"not found":           k8serrors.NewNotFound(schema.GroupResource{Group: "dynatrace.com", Resource: "injectionconfigs"}, ""),
+			"missing permissions": k8serrors.NewForbidden(schema.GroupResource{Group: "dynatrace.com", Resource: "injectionconfigs"}, "", errors.New("forbidden")),
+		} {
+			t.Run(name, func(t *testing.T) {
+				inj := createInjectionConfigPodInjector(t, injectionConfig)
+				inj.apiReader = &failingListReader{Reader: inj.apiReader, err: err}
+
+				env := injectInstallContainerEnv(t, inj, nil)
+
+				assert.Equal(t, dtclient.FlavorMultidistro, env[standalone.InstallerFlavorEnv])
+				assert.Equal(t, dtwebhook.DefaultInstallPath, env[standalone.InstallPathEnv])
+			})
+		}
+	})
+}
+
+// failingListReader returns the error for every list, like the api server does without the CRD or permissions
+type failingListReader struct {
+	client.Reader
+	err error
+}
+
+func (reader *failingListReader) List(_ context.Context, _ client.ObjectList, _ ...client.ListOption) error {
+	return reader.err
+}
+
+func createInjectionConfigPodInjector(t *testing.T, injectionConfigs ...*dynatracev1beta1.InjectionConfig) *podMutator {
+	decoder, err := admission.NewDecoder(scheme.Scheme)
+	require.NoError(t, err)
+
+	inj, _ := createPodInjector(t, decoder)
+	for _, injectionConfig := range injectionConfigs {
+		require.NoError(t, inj.client.Create(context.TODO(), injectionConfig.DeepCopy()))
+		require.NoError(t, inj.apiReader.(client.Client).Create(context.TODO(), injectionConfig.DeepCopy()))
+	}
+	return inj
+}
+
+func injectInstallContainerEnv(t *testing.T, inj *podMutator, annotations map[string]string) map[string]string {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pod-12345", Namespace: "test-namespace", Annotations: annotations},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "test-container", Image: "alpine"}},
+		},
+	}
+	podBytes, err := json.Marshal(&pod)
+	require.NoError(t, err)
+
+	req := admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Object:    runtime.RawExtension{Raw: podBytes},
+			Namespace: "test-namespace",
+		},
+	}
+	resp := inj.Handle(context.TODO(), req)
+	require.NoError(t, resp.Complete(req))
+	require.True(t, resp.Allowed)
+
+	patch, err := jsonpatch.DecodePatch(resp.Patch)
+	require.NoError(t, err)
+	updPodBytes, err := patch.Apply(podBytes)
+	require.NoError(t, err)
+
+	var updPod corev1.Pod
+	require.NoError(t, json.Unmarshal(updPodBytes, &updPod))
+	require.Len(t, updPod.Spec.InitContainers, 1)
+
+	env := map[string]string{}
+	for _, envVar := range updPod.Spec.InitContainers[0].Env {
+		env[envVar.Name] = envVar.Value
+	}
+	return env
+}
@@ -0,0 +1,144 @@
+package mutation
+
+import (
+	"context"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	injectionResultInjected = "injected"
+	injectionResultSkipped  = "skipped"
+	injectionResultFailed   = "failed"
+
+	// reasons of injected pods
+	reasonInjected  = "injected"
+	reasonReinvoked = "reinvoked"
+
+	// reasons of skipped pods, or of failed pods if the step failed
+	reasonOneAgentApmExists    = "oneagent-apm-exists"
+	reasonInvalidPod           = "invalid-pod"
+	reasonDisabled             = "disabled"
+	reasonNamespace            = "namespace"
+	reasonDynakube             = "dynakube"
+	reasonNoAppInjection       = "no-app-injection"
+	reasonInjectionConfig      = "injection-config"
+	reasonInitSecret           = "init-secret"
+	reasonDataIngestSecret     = "data-ingest-secret"
+	reasonAlreadyInjected      = "already-injected"
+	reasonNoSelectedContainers = "no-selected-containers"
+	reasonWorkload             = "workload"
+	reasonDryRun               = "dry-run"
+)
+
+// injectionReport collects the outcome of the admission of a pod, which is exported as metric and,
+// if the injection failed, recorded as event on the workload or the namespace of the pod
+type injectionReport struct {
+	namespace     string
+	dynakube      string
+	ownerKinds    []string
+	pod           *corev1.Pod
+	injectionInfo *InjectionInfo
+	result        string
+	reason        string
+
+	// skippedFeatures are the modes which were skipped for the pod while the others were injected
+	skippedFeatures map[FeatureType]string
+}
+
+func newInjectionReport(namespace string) *injectionReport {
+	return &injectionReport{
+		namespace:  namespace,
+		ownerKinds: dynatracev1beta1.DefaultWorkloadOwnerKinds,
+	}
+}
+
+func (report *injectionReport) inject(reason string) {
+	report.result, report.reason = injectionResultInjected, reason
+}
+
+func (report *injectionReport) skip(reason string) {
+	report.result, report.reason = injectionResultSkipped, reason
+}
+
+func (report *injectionReport) fail(reason string) {
+	report.result, report.reason = injectionResultFailed, reason
+}
+
+func (report *injectionReport) skipFeature(feature FeatureType, reason string) {
+	if report.skippedFeatures == nil {
+		report.skippedFeatures = make(map[FeatureType]string)
+	}
+	report.skippedFeatures[feature] = reason
+}
+
+func isFailedResponse(rsp admission.Response) bool {
+	return rsp.Result != nil && rsp.Result.Message != ""
+}
+
+// reportInjection exports the outcome of the admission per injection mode, modes which aren't enabled for the pod are
+// reported as skipped. Responses with an error message, see silentErrorResponse, are always reported as failed.
+func (m *podMutator) reportInjection(ctx context.Context, report *injectionReport, rsp admission.Response) {
+	if isFailedResponse(rsp) {
+		report.result = injectionResultFailed
+	}
+
+	for _, feature := range []FeatureType{OneAgent, DataIngest} {
+		result, reason := report.result, report.reason
+		if skipReason, skipped := report.skippedFeatures[feature]; skipped {
+			result, reason = injectionResultSkipped, skipReason
+		} else if report.injectionInfo != nil && !report.injectionInfo.enabled(feature) {
+			result, reason = injectionResultSkipped, reasonDisabled
+		}
+		podInjectionsMetric.WithLabelValues(report.namespace, report.dynakube, feature.name(), result, reason).Inc()
+	}
+
+	if report.result == injectionResultFailed && report.pod != nil {
+		m.recordInjectionFailure(ctx, report, rsp.Result.Message)
+	}
+}
+
+// recordInjectionFailure records the failure on the workload of the pod, so failures are noticed on the workload and
+// not only on its pods, which don't exist yet while being admitted. Failures of pods without workload are recorded on
+// their namespace, with the name of the pod in the message.
+func (m *podMutator) recordInjectionFailure(ctx context.Context, report *injectionReport, message string) {
+	if workload := m.findWorkload(ctx, report); workload != nil {
+		m.recorder.Event(workload, corev1.EventTypeWarning, injectionFailedEvent, message)
+		return
+	}
+
+	podName := report.pod.Name
+	if podName == "" {
+		podName = report.pod.GenerateName
+	}
+	m.recorder.Eventf(m.findNamespace(ctx, report.namespace), corev1.EventTypeWarning, injectionFailedEvent, "pod %s: %s", podName, message)
+}
+
+// findWorkload returns the workload of the pod, or nil if the pod has none
+func (m *podMutator) findWorkload(ctx context.Context, report *injectionReport) *metav1.PartialObjectMetadata {
+	resolver := ownerResolver{client: m.metaClient, informers: m.cache, cache: m.ownerCache, ownerKinds: report.ownerKinds}
+	workload, err := resolver.findWorkloadOfPod(ctx, report.pod, report.namespace)
+	if err != nil {
+		podLog.Info("failed to find the workload of the pod for the injection failure event", "error", err.Error())
+	}
+
+	if workload == nil || workload.Kind == "" || workload.Kind == "Pod" {
+		return nil
+	}
+	return workload
+}
+
+// findNamespace returns the namespace of the pod, the UID is needed for the event to show up on the namespace
+func (m *podMutator) findNamespace(ctx context.Context, name string) *corev1.Namespace {
+	var ns corev1.Namespace
+	if err := m.cache.Get(ctx, client.ObjectKey{Name: name}, &ns, m.client); err != nil {
+		podLog.Info("failed to query the namespace of the pod for the injection failure event", "error", err.Error())
+		ns.ObjectMeta = metav1.ObjectMeta{Name: name}
+	}
+	ns.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"}
+	return &ns
+}
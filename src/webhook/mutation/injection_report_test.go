@@ -0,0 +1,141 @@
+package mutation
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	t_utils "github.com/Dynatrace/dynatrace-operator/src/testing"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/src/webhook"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func podInjections(mode string, result string, reason string) float64 {
+	return testutil.ToFloat64(podInjectionsMetric.WithLabelValues("test-namespace", dynakubeName, mode, result, reason))
+}
+
+func handleReportedPod(t *testing.T, inj *podMutator, pod *corev1.Pod) admission.Response {
+	pod.Spec.Containers = []corev1.Container{{Name: "test-container", Image: "alpine"}}
+	podBytes, err := json.Marshal(pod)
+	require.NoError(t, err)
+
+	req := admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Object:    runtime.RawExtension{Raw: podBytes},
+			Namespace: "test-namespace",
+		},
+	}
+	resp := inj.Handle(context.TODO(), req)
+	require.NoError(t, resp.Complete(req))
+	require.True(t, resp.Allowed)
+	return resp
+}
+
+func TestInjectionReport(t *testing.T) {
+	t.Run(`counts injected pods per mode`, func(t *testing.T) {
+		inj := createInjectionConfigPodInjector(t)
+		oneAgentBefore := podInjections(dtwebhook.OneAgentPrefix, injectionResultInjected, reasonInjected)
+		dataIngestBefore := podInjections(dtwebhook.DataIngestPrefix, injectionResultInjected, reasonInjected)
+
+		handleReportedPod(t, inj, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-pod-12345", Namespace: "test-namespace"}})
+
+		assert.Equal(t, oneAgentBefore+1, podInjections(dtwebhook.OneAgentPrefix, injectionResultInjected, reasonInjected))
+		assert.Equal(t, dataIngestBefore+1, podInjections(dtwebhook.DataIngestPrefix, injectionResultInjected, reasonInjected))
+	})
+	t.Run(`counts disabled modes as skipped`, func(t *testing.T) {
+		inj := createInjectionConfigPodInjector(t)
+		oneAgentBefore := podInjections(dtwebhook.OneAgentPrefix, injectionResultInjected, reasonInjected)
+		dataIngestBefore := podInjections(dtwebhook.DataIngestPrefix, injectionResultSkipped, reasonDisabled)
+
+		handleReportedPod(t, inj, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-pod-12345",
+			Namespace:   "test-namespace",
+			Annotations: map[string]string{dtwebhook.AnnotationDataIngestInject: "false"},
+		}})
+
+		assert.Equal(t, oneAgentBefore+1, podInjections(dtwebhook.OneAgentPrefix, injectionResultInjected, reasonInjected))
+		assert.Equal(t, dataIngestBefore+1, podInjections(dtwebhook.DataIngestPrefix, injectionResultSkipped, reasonDisabled))
+	})
+	t.Run(`counts failed pods and records the failure on their workload`, func(t *testing.T) {
+		inj := createInjectionConfigPodInjector(t)
+		require.NoError(t, inj.client.Delete(context.TODO(), &dynatracev1beta1.DynaKube{ObjectMeta: metav1.ObjectMeta{Name: dynakubeName, Namespace: "dynatrace"}}))
+		inj.metaClient = newWorkloadClient(nil)
+		inj.recorder.(*record.FakeRecorder).IncludeObject = true
+		failedBefore := podInjections(dtwebhook.OneAgentPrefix, injectionResultFailed, reasonDynakube)
+
+		pod := newWorkloadPod()
+		pod.Namespace = "test-namespace"
+		resp := handleReportedPod(t, inj, pod)
+
+		assert.Contains(t, resp.Result.Message, "Failed to inject into pod")
+		assert.Equal(t, failedBefore+1, podInjections(dtwebhook.OneAgentPrefix, injectionResultFailed, reasonDynakube))
+		t_utils.AssertEvents(t,
+			inj.recorder.(*record.FakeRecorder).Events,
+			t_utils.Events{
+				t_utils.Event{
+					EventType: corev1.EventTypeWarning,
+					Reason:    missingDynakubeEvent,
+				},
+				t_utils.Event{
+					EventType: corev1.EventTypeWarning,
+					Reason:    injectionFailedEvent,
+					Message:   "involvedObject{kind=Deployment,apiVersion=apps/v1}",
+				},
+			},
+		)
+	})
+	t.Run(`records failures of pods without workload on the namespace`, func(t *testing.T) {
+		inj := createInjectionConfigPodInjector(t)
+		require.NoError(t, inj.client.Delete(context.TODO(), &dynatracev1beta1.DynaKube{ObjectMeta: metav1.ObjectMeta{Name: dynakubeName, Namespace: "dynatrace"}}))
+		inj.recorder.(*record.FakeRecorder).IncludeObject = true
+
+		handleReportedPod(t, inj, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-pod-12345", Namespace: "test-namespace"}})
+
+		events := inj.recorder.(*record.FakeRecorder).Events
+		require.Len(t, events, 2)
+		assert.Contains(t, <-events, missingDynakubeEvent)
+		event := <-events
+		assert.True(t, strings.HasPrefix(event, "Warning "+injectionFailedEvent+" pod test-pod-12345: Failed to inject into pod"), event)
+		assert.True(t, strings.HasSuffix(event, "involvedObject{kind=Namespace,apiVersion=v1}"), event)
+	})
+	t.Run(`counts pods of namespaces without dynakube as failed`, func(t *testing.T) {
+		inj := createInjectionConfigPodInjector(t)
+		var ns corev1.Namespace
+		require.NoError(t, inj.client.Get(context.TODO(), client.ObjectKey{Name: "test-namespace"}, &ns))
+		ns.Labels = nil
+		require.NoError(t, inj.client.Update(context.TODO(), &ns))
+		failed := podInjectionsMetric.WithLabelValues("test-namespace", "", dtwebhook.OneAgentPrefix, injectionResultFailed, reasonNamespace)
+		failedBefore := testutil.ToFloat64(failed)
+
+		resp := handleReportedPod(t, inj, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-pod-12345", Namespace: "test-namespace"}})
+
+		assert.Contains(t, resp.Result.Message, "no DynaKube instance set for namespace")
+		assert.Equal(t, failedBefore+1, testutil.ToFloat64(failed))
+	})
+	t.Run(`doesn't record events for skipped pods`, func(t *testing.T) {
+		inj := createInjectionConfigPodInjector(t)
+		// the DynaKube isn't looked up for pods which disabled the injection
+		skipped := podInjectionsMetric.WithLabelValues("test-namespace", "", dtwebhook.OneAgentPrefix, injectionResultSkipped, reasonDisabled)
+		skippedBefore := testutil.ToFloat64(skipped)
+
+		handleReportedPod(t, inj, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-pod-12345",
+			Namespace:   "test-namespace",
+			Annotations: map[string]string{dtwebhook.AnnotationOneAgentInject: "false"},
+		}})
+
+		assert.Equal(t, skippedBefore+1, testutil.ToFloat64(skipped))
+		assert.Empty(t, inj.recorder.(*record.FakeRecorder).Events)
+	})
+}
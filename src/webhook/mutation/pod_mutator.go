@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
 	dtcsi "github.com/Dynatrace/dynatrace-operator/src/controllers/csi"
@@ -17,7 +18,6 @@ import (
 	appvolumes "github.com/Dynatrace/dynatrace-operator/src/controllers/csi/driver/volumes/app"
 	"github.com/Dynatrace/dynatrace-operator/src/controllers/dynakube/oneagent/daemonset"
 	"github.com/Dynatrace/dynatrace-operator/src/deploymentmetadata"
-	"github.com/Dynatrace/dynatrace-operator/src/dtclient"
 	dtingestendpoint "github.com/Dynatrace/dynatrace-operator/src/ingestendpoint"
 	"github.com/Dynatrace/dynatrace-operator/src/initgeneration"
 	"github.com/Dynatrace/dynatrace-operator/src/kubeobjects"
@@ -47,14 +47,19 @@ func AddPodMutationWebhookToManager(mgr manager.Manager, ns string) error {
 		podLog.Info("no Pod name set for webhook container")
 	}
 
-	if err := registerInjectEndpoint(mgr, ns, podName); err != nil {
+	webhookCache, err := newWebhookCache(mgr)
+	if err != nil {
 		return err
 	}
-	registerHealthzEndpoint(mgr)
+
+	if err := registerInjectEndpoint(mgr, ns, podName, webhookCache); err != nil {
+		return err
+	}
+	registerHealthzEndpoint(mgr, webhookCache)
 	return nil
 }
 
-func registerInjectEndpoint(mgr manager.Manager, ns string, podName string) error {
+func registerInjectEndpoint(mgr manager.Manager, ns string, podName string, webhookCache *webhookCache) error {
 	// Don't use mgr.GetClient() on this function, or other cache-dependent functions from the manager. The cache may
 	// not be ready at this point, and queries for Kubernetes objects may fail. mgr.GetAPIReader() doesn't depend on the
 	// cache and is safe to use.
@@ -88,6 +93,9 @@ func registerInjectEndpoint(mgr manager.Manager, ns string, podName string) erro
 
 	mgr.GetWebhookServer().Register("/inject", &webhook.Admission{Handler: &podMutator{
 		metaClient: metaClient,
+		ownerCache: newOwnerCache(),
+		selections: newContainerSelectionCache(),
+		cache:      webhookCache,
 		apiReader:  mgr.GetAPIReader(),
 		namespace:  ns,
 		image:      pod.Spec.Containers[0].Image,
@@ -98,16 +106,27 @@ func registerInjectEndpoint(mgr manager.Manager, ns string, podName string) erro
 	return nil
 }
 
-func registerHealthzEndpoint(mgr manager.Manager) {
+func registerHealthzEndpoint(mgr manager.Manager, webhookCache *webhookCache) {
 	mgr.GetWebhookServer().Register("/livez", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	}))
+	// the webhook only gets ready once its cache is synced, so admissions aren't slowed down by reads from the API server
+	mgr.GetWebhookServer().Register("/readyz", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !webhookCache.isReady() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
 }
 
 // podMutator injects the OneAgent into Pods
 type podMutator struct {
 	client         client.Client
 	metaClient     client.Client
+	ownerCache     *ownerCache
+	selections     *containerSelectionCache
+	cache          *webhookCache
 	apiReader      client.Reader
 	decoder        *admission.Decoder
 	image          string
@@ -118,121 +137,93 @@ type podMutator struct {
 	recorder       record.EventRecorder
 }
 
-func findRootOwnerOfPod(ctx context.Context, clt client.Client, pod *corev1.Pod, namespace string) (string, string, error) {
-	obj := &metav1.PartialObjectMetadata{
-		TypeMeta: metav1.TypeMeta{
-			APIVersion: pod.APIVersion,
-			Kind:       pod.Kind,
-		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name: pod.ObjectMeta.Name,
-			// pod.ObjectMeta.Namespace is empty yet
-			Namespace:       namespace,
-			OwnerReferences: pod.ObjectMeta.OwnerReferences,
-		},
-	}
-	return findRootOwner(ctx, clt, obj)
-}
-
-func findRootOwner(ctx context.Context, clt client.Client, o *metav1.PartialObjectMetadata) (string, string, error) {
-	if len(o.ObjectMeta.OwnerReferences) == 0 {
-		kind := o.Kind
-		if kind == "Pod" {
-			kind = ""
-		}
-		return o.ObjectMeta.Name, kind, nil
-	}
-
-	om := o.ObjectMeta
-	for _, owner := range om.OwnerReferences {
-		if owner.Controller != nil && *owner.Controller && isWellKnownWorkload(owner) {
-			obj := &metav1.PartialObjectMetadata{
-				TypeMeta: metav1.TypeMeta{
-					APIVersion: owner.APIVersion,
-					Kind:       owner.Kind,
-				},
-			}
-			if err := clt.Get(ctx, client.ObjectKey{Name: owner.Name, Namespace: om.Namespace}, obj); err != nil {
-				podLog.Error(err, "failed to query the object", "apiVersion", owner.APIVersion, "kind", owner.Kind, "name", owner.Name, "namespace", om.Namespace)
-				return o.ObjectMeta.Name, o.Kind, err
-			}
-
-			return findRootOwner(ctx, clt, obj)
-		}
-	}
-	return o.ObjectMeta.Name, o.Kind, nil
-}
-
-func isWellKnownWorkload(ownerRef metav1.OwnerReference) bool {
-	knownWorkloads := []metav1.TypeMeta{
-		{Kind: "ReplicaSet", APIVersion: "apps/v1"},
-		{Kind: "Deployment", APIVersion: "apps/v1"},
-		{Kind: "ReplicationController", APIVersion: "v1"},
-		{Kind: "StatefulSet", APIVersion: "apps/v1"},
-		{Kind: "DaemonSet", APIVersion: "apps/v1"},
-		{Kind: "Job", APIVersion: "batch/v1"},
-		{Kind: "CronJob", APIVersion: "batch/v1"},
-		{Kind: "DeploymentConfig", APIVersion: "apps.openshift.io/v1"},
-	}
+// podMutator adds an annotation to every incoming pods
+func (m *podMutator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	defer func(start time.Time) {
+		admissionDurationMetric.Observe(time.Since(start).Seconds())
+	}(time.Now())
 
-	for _, knownController := range knownWorkloads {
-		if ownerRef.Kind == knownController.Kind &&
-			ownerRef.APIVersion == knownController.APIVersion {
-			return true
-		}
-	}
-	return false
+	report := newInjectionReport(req.Namespace)
+	rsp := m.handle(ctx, req, report)
+	m.reportInjection(ctx, report, rsp)
+	return rsp
 }
 
-// podMutator adds an annotation to every incoming pods
-func (m *podMutator) Handle(ctx context.Context, req admission.Request) admission.Response {
+func (m *podMutator) handle(ctx context.Context, req admission.Request, report *injectionReport) admission.Response {
 	emptyPatch := admission.Patched("")
 
 	if m.apmExists {
+		report.skip(reasonOneAgentApmExists)
 		return emptyPatch
 	}
 
 	pod, rsp := m.getPod(req)
 	if rsp != nil {
+		report.fail(reasonInvalidPod)
 		return *rsp
 	}
 	m.currentPodName = pod.Name
 	defer func() {
 		m.currentPodName = ""
 	}()
+	report.pod = pod
 
 	injectionInfo := NewInjectionInfoForPod(pod)
+	report.injectionInfo = injectionInfo
 	if !injectionInfo.anyEnabled() {
+		report.skip(reasonDisabled)
 		return emptyPatch
 	}
 
 	ns, dkName, nsResponse := m.getNsAndDkName(ctx, req)
 	if nsResponse != nil {
+		// namespaces without DynaKube are skipped on OLM
+		if isFailedResponse(*nsResponse) {
+			report.fail(reasonNamespace)
+		} else {
+			report.skip(reasonNamespace)
+		}
 		return *nsResponse
 	}
+	report.dynakube = dkName
 
 	dk, dkResponse := m.getDynakube(ctx, req, dkName)
 	if dkResponse != nil {
+		report.fail(reasonDynakube)
 		return *dkResponse
 	}
+	report.ownerKinds = dk.FeatureWorkloadOwnerKinds()
 
 	if dk.FeatureDisableMetadataEnrichment() {
 		injectionInfo.features[DataIngest] = false
 	}
 
 	if !dk.NeedAppInjection() {
+		report.skip(reasonNoAppInjection)
 		return emptyPatch
 	}
 
-	secretResponse := m.ensureInitSecret(ctx, ns, dk)
-	if secretResponse != nil {
-		return *secretResponse
+	injectionConfig, err := m.getInjectionConfig(ctx, req.Namespace)
+	if err != nil {
+		report.fail(reasonInjectionConfig)
+		return silentErrorResponse(m.currentPodName, err)
 	}
 
-	if injectionInfo.enabled(DataIngest) {
-		err := m.ensureDataIngestSecret(ctx, ns, dkName)
-		if err != nil {
-			return silentErrorResponse(m.currentPodName, err)
+	// in dry-run mode nothing is created in the namespace, the pod is only annotated with the summary of the injection
+	dryRun := isDryRun(dk, injectionConfig)
+	if !dryRun {
+		secretResponse := m.ensureInitSecret(ctx, ns, dk)
+		if secretResponse != nil {
+			report.fail(reasonInitSecret)
+			return *secretResponse
+		}
+
+		if injectionInfo.enabled(DataIngest) {
+			err := m.ensureDataIngestSecret(ctx, ns, dkName)
+			if err != nil {
+				report.fail(reasonDataIngestSecret)
+				return silentErrorResponse(m.currentPodName, err)
+			}
 		}
 	}
 
@@ -240,17 +231,36 @@ func (m *podMutator) Handle(ctx context.Context, req admission.Request) admissio
 
 	response := m.handleAlreadyInjectedPod(pod, dk, injectionInfo, req)
 	if response != nil {
+		if len(response.Patches) > 0 {
+			report.inject(reasonReinvoked)
+		} else {
+			report.skip(reasonAlreadyInjected)
+		}
 		return *response
 	}
 
+	containers := selectContainers(pod, m.selections.get(&dk))
+	if injectionInfo.enabled(OneAgent) && len(containers) == 0 {
+		// the metadata enrichment doesn't depend on the container selection, so it's still done without OneAgent
+		podLog.Info("no container is selected for instrumentation, skipping the OneAgent injection", "name", pod.Name, "generatedName", pod.GenerateName)
+		injectionInfo.features[OneAgent] = false
+		report.skipFeature(OneAgent, reasonNoSelectedContainers)
+		if !injectionInfo.anyEnabled() {
+			report.skip(reasonNoSelectedContainers)
+			return emptyPatch
+		}
+	}
+
+	original := pod.DeepCopy()
 	injectionInfo.fillAnnotations(pod)
 
-	workloadName, workloadKind, workloadResponse := m.retrieveWorkload(ctx, req, injectionInfo, pod)
+	workloadName, workloadKind, workloadResponse := m.retrieveWorkload(ctx, req, injectionInfo, pod, dk)
 	if workloadResponse != nil {
+		report.fail(reasonWorkload)
 		return *workloadResponse
 	}
 
-	flavor, technologies, installPath, installerURL, failurePolicy, image := m.getBasicData(pod)
+	flavor, technologies, installPath, installerURL, failurePolicy, image := m.getBasicData(pod, injectionDefaults(injectionConfig))
 
 	dkVol, mode := ensureDynakubeVolume(dk)
 
@@ -262,20 +272,26 @@ func (m *podMutator) Handle(ctx context.Context, req admission.Request) admissio
 	basePodName := getBasePodName(pod)
 	deploymentMetadata := m.getDeploymentMetadata(dk)
 
-	installContainer := createInstallInitContainerBase(image, pod, failurePolicy, basePodName, sc, dk)
+	installContainer := createInstallInitContainerBase(image, len(containers), failurePolicy, basePodName, sc, dk)
 
 	decorateInstallContainerWithOneAgent(&installContainer, injectionInfo, flavor, technologies, installPath, installerURL, mode)
 	decorateInstallContainerWithDataIngest(&installContainer, injectionInfo, workloadKind, workloadName)
 
-	updateContainers(pod, injectionInfo, &installContainer, dk, deploymentMetadata)
+	updateContainers(pod, containers, injectionInfo, &installContainer, dk, deploymentMetadata)
 
 	addToInitContainers(pod, installContainer)
 
+	if dryRun {
+		report.skip(reasonDryRun)
+		return m.handleDryRun(original, pod, dk, injectionInfo, req)
+	}
+
 	m.recorder.Eventf(&dk,
 		corev1.EventTypeNormal,
 		injectEvent,
 		"Injecting the necessary info into pod %s in namespace %s", basePodName, ns.Name)
 
+	report.inject(reasonInjected)
 	return getResponseForPod(pod, &req)
 }
 
@@ -296,16 +312,17 @@ func addToInitContainers(pod *corev1.Pod, installContainer corev1.Container) {
 	pod.Spec.InitContainers = append(pod.Spec.InitContainers, installContainer)
 }
 
-func updateContainers(pod *corev1.Pod, injectionInfo *InjectionInfo, ic *corev1.Container, dk dynatracev1beta1.DynaKube, deploymentMetadata *deploymentmetadata.DeploymentMetadata) {
-	for i := range pod.Spec.Containers {
-		c := &pod.Spec.Containers[i]
-
-		if injectionInfo.enabled(OneAgent) {
+func updateContainers(pod *corev1.Pod, containers []*corev1.Container, injectionInfo *InjectionInfo, ic *corev1.Container, dk dynatracev1beta1.DynaKube, deploymentMetadata *deploymentmetadata.DeploymentMetadata) {
+	if injectionInfo.enabled(OneAgent) {
+		for i, c := range containers {
 			updateInstallContainerOneAgent(ic, i+1, c.Name, c.Image)
 			updateContainerOneAgent(c, &dk, pod, deploymentMetadata)
 		}
-		if injectionInfo.enabled(DataIngest) {
-			updateContainerDataIngest(c, deploymentMetadata)
+	}
+	// the container selection only applies to the OneAgent, all containers are enriched with the metadata
+	if injectionInfo.enabled(DataIngest) {
+		for i := range pod.Spec.Containers {
+			updateContainerDataIngest(&pod.Spec.Containers[i], deploymentMetadata)
 		}
 	}
 }
@@ -350,14 +367,14 @@ func decorateInstallContainerWithOneAgent(ic *corev1.Container, injectionInfo *I
 	}
 }
 
-func createInstallInitContainerBase(image string, pod *corev1.Pod, failurePolicy string, basePodName string, sc *corev1.SecurityContext, dk dynatracev1beta1.DynaKube) corev1.Container {
+func createInstallInitContainerBase(image string, containerCount int, failurePolicy string, basePodName string, sc *corev1.SecurityContext, dk dynatracev1beta1.DynaKube) corev1.Container {
 	ic := corev1.Container{
 		Name:            dtwebhook.InstallContainerName,
 		Image:           image,
 		ImagePullPolicy: corev1.PullIfNotPresent,
 		Args:            []string{"init"},
 		Env: []corev1.EnvVar{
-			{Name: standalone.ContainerCountEnv, Value: strconv.Itoa(len(pod.Spec.Containers))},
+			{Name: standalone.ContainerCountEnv, Value: strconv.Itoa(containerCount)},
 			{Name: standalone.CanFailEnv, Value: failurePolicy},
 			{Name: standalone.K8PodNameEnv, ValueFrom: fieldEnvVar("metadata.name")},
 			{Name: standalone.K8PodUIDEnv, ValueFrom: fieldEnvVar("metadata.uid")},
@@ -457,7 +474,9 @@ func setupInjectionConfigVolume(pod *corev1.Pod) {
 	)
 }
 
-func (m *podMutator) getBasicData(pod *corev1.Pod) (
+// getBasicData returns the injection settings from the annotations of the pod, unset annotations fall back to the
+// defaults of the namespace
+func (m *podMutator) getBasicData(pod *corev1.Pod, defaults dynatracev1beta1.InjectionConfigSpec) (
 	flavor string,
 	technologies string,
 	installPath string,
@@ -465,11 +484,11 @@ func (m *podMutator) getBasicData(pod *corev1.Pod) (
 	failurePolicy string,
 	image string,
 ) {
-	flavor = kubeobjects.GetField(pod.Annotations, dtwebhook.AnnotationFlavor, dtclient.FlavorMultidistro)
-	technologies = url.QueryEscape(kubeobjects.GetField(pod.Annotations, dtwebhook.AnnotationTechnologies, "all"))
-	installPath = kubeobjects.GetField(pod.Annotations, dtwebhook.AnnotationInstallPath, dtwebhook.DefaultInstallPath)
-	installerURL = kubeobjects.GetField(pod.Annotations, dtwebhook.AnnotationInstallerUrl, "")
-	failurePolicy = kubeobjects.GetField(pod.Annotations, dtwebhook.AnnotationFailurePolicy, "silent")
+	flavor = kubeobjects.GetField(pod.Annotations, dtwebhook.AnnotationFlavor, defaults.Flavor)
+	technologies = url.QueryEscape(kubeobjects.GetField(pod.Annotations, dtwebhook.AnnotationTechnologies, strings.Join(defaults.Technologies, ",")))
+	installPath = kubeobjects.GetField(pod.Annotations, dtwebhook.AnnotationInstallPath, defaults.InstallPath)
+	installerURL = kubeobjects.GetField(pod.Annotations, dtwebhook.AnnotationInstallerUrl, defaults.InstallerURL)
+	failurePolicy = kubeobjects.GetField(pod.Annotations, dtwebhook.AnnotationFailurePolicy, defaults.FailurePolicy)
 	image = m.image
 	return
 }
@@ -493,12 +512,13 @@ func ensureDynakubeVolume(dk dynatracev1beta1.DynaKube) (corev1.VolumeSource, st
 	return dkVol, mode
 }
 
-func (m *podMutator) retrieveWorkload(ctx context.Context, req admission.Request, injectionInfo *InjectionInfo, pod *corev1.Pod) (string, string, *admission.Response) {
+func (m *podMutator) retrieveWorkload(ctx context.Context, req admission.Request, injectionInfo *InjectionInfo, pod *corev1.Pod, dk dynatracev1beta1.DynaKube) (string, string, *admission.Response) {
 	var rsp admission.Response
 	var workloadName, workloadKind string
 	if injectionInfo.enabled(DataIngest) {
 		var err error
-		workloadName, workloadKind, err = findRootOwnerOfPod(ctx, m.metaClient, pod, req.Namespace)
+		resolver := ownerResolver{client: m.metaClient, informers: m.cache, cache: m.ownerCache, ownerKinds: dk.FeatureWorkloadOwnerKinds()}
+		workloadName, workloadKind, err = resolver.findRootOwnerOfPod(ctx, pod, req.Namespace)
 		if err != nil {
 			rsp = silentErrorResponse(m.currentPodName, err)
 			return "", "", &rsp
@@ -510,9 +530,12 @@ func (m *podMutator) retrieveWorkload(ctx context.Context, req admission.Request
 func (m *podMutator) applyReinvocationPolicy(pod *corev1.Pod, dk dynatracev1beta1.DynaKube, injectionInfo *InjectionInfo, req admission.Request) admission.Response {
 	var needsUpdate = false
 	var installContainer *corev1.Container
+	selected := make(map[string]bool)
+	for _, c := range selectContainers(pod, m.selections.get(&dk)) {
+		selected[c.Name] = true
+	}
 	for i := range pod.Spec.Containers {
 		c := &pod.Spec.Containers[i]
-
 		oaInjected := false
 		if injectionInfo.enabled(OneAgent) {
 			for _, e := range c.Env {
@@ -532,7 +555,7 @@ func (m *podMutator) applyReinvocationPolicy(pod *corev1.Pod, dk dynatracev1beta
 			}
 		}
 
-		oaInjectionMissing := injectionInfo.enabled(OneAgent) && !oaInjected
+		oaInjectionMissing := injectionInfo.enabled(OneAgent) && selected[c.Name] && !oaInjected
 		diInjectionMissing := injectionInfo.enabled(DataIngest) && !diInjected
 
 		if oaInjectionMissing {
@@ -553,7 +576,7 @@ func (m *podMutator) applyReinvocationPolicy(pod *corev1.Pod, dk dynatracev1beta
 					}
 				}
 			}
-			updateInstallContainerOneAgent(installContainer, i+1, c.Name, c.Image)
+			addContainerToInstallContainer(installContainer, c)
 
 			needsUpdate = true
 		}
@@ -593,7 +616,7 @@ func (m *podMutator) getPod(req admission.Request) (*corev1.Pod, *admission.Resp
 func (m *podMutator) getNsAndDkName(ctx context.Context, req admission.Request) (ns corev1.Namespace, dkName string, rspPtr *admission.Response) {
 	var rsp admission.Response
 
-	if err := m.client.Get(ctx, client.ObjectKey{Name: req.Namespace}, &ns); err != nil {
+	if err := m.cache.Get(ctx, client.ObjectKey{Name: req.Namespace}, &ns, m.client); err != nil {
 		podLog.Error(err, "Failed to query the namespace before pod injection")
 		rsp = silentErrorResponse(m.currentPodName, err)
 		return corev1.Namespace{}, "", &rsp
@@ -615,7 +638,7 @@ func (m *podMutator) ensureDataIngestSecret(ctx context.Context, ns corev1.Names
 	endpointGenerator := dtingestendpoint.NewEndpointSecretGenerator(m.client, m.apiReader, m.namespace)
 
 	var endpointSecret corev1.Secret
-	if err := m.apiReader.Get(ctx, client.ObjectKey{Name: dtingestendpoint.SecretEndpointName, Namespace: ns.Name}, &endpointSecret); k8serrors.IsNotFound(err) {
+	if err := m.cache.Get(ctx, client.ObjectKey{Name: dtingestendpoint.SecretEndpointName, Namespace: ns.Name}, &endpointSecret, m.apiReader); k8serrors.IsNotFound(err) {
 		if _, err := endpointGenerator.GenerateForNamespace(ctx, dkName, ns.Name); err != nil {
 			podLog.Error(err, "failed to create the data-ingest endpoint secret before pod injection")
 			return err
@@ -652,7 +675,7 @@ func (m *podMutator) ensureInitSecret(ctx context.Context, ns corev1.Namespace,
 	var initSecret corev1.Secret
 	var rsp admission.Response
 
-	if err := m.apiReader.Get(ctx, client.ObjectKey{Name: dtwebhook.SecretConfigName, Namespace: ns.Name}, &initSecret); k8serrors.IsNotFound(err) {
+	if err := m.cache.Get(ctx, client.ObjectKey{Name: dtwebhook.SecretConfigName, Namespace: ns.Name}, &initSecret, m.apiReader); k8serrors.IsNotFound(err) {
 		if _, err := initgeneration.NewInitGenerator(m.client, m.apiReader, m.namespace).GenerateForNamespace(ctx, dk, ns.Name); err != nil {
 			podLog.Error(err, "Failed to create the init secret before pod injection")
 			rsp = silentErrorResponse(m.currentPodName, err)
@@ -686,6 +709,23 @@ func updateInstallContainerOneAgent(ic *corev1.Container, number int, name strin
 		corev1.EnvVar{Name: fmt.Sprintf("CONTAINER_%d_IMAGE", number), Value: image})
 }
 
+// addContainerToInstallContainer appends a container to the already configured containers of the Install Container
+func addContainerToInstallContainer(ic *corev1.Container, c *corev1.Container) {
+	number := 1
+	for _, e := range ic.Env {
+		if strings.HasPrefix(e.Name, "CONTAINER_") && strings.HasSuffix(e.Name, "_NAME") {
+			number++
+		}
+	}
+	updateInstallContainerOneAgent(ic, number, c.Name, c.Image)
+
+	for i := range ic.Env {
+		if ic.Env[i].Name == standalone.ContainerCountEnv {
+			ic.Env[i].Value = strconv.Itoa(number)
+		}
+	}
+}
+
 // updateContainerOA sets missing preload Variables
 func updateContainerOneAgent(c *corev1.Container, dk *dynatracev1beta1.DynaKube, pod *corev1.Pod, deploymentMetadata *deploymentmetadata.DeploymentMetadata) {
 
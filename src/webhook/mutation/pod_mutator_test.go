@@ -86,6 +86,11 @@ func TestInjectionWithMissingOneAgentAPM(t *testing.T) {
 				EventType: corev1.EventTypeWarning,
 				Reason:    missingDynakubeEvent,
 			},
+			t_utils.Event{
+				EventType: corev1.EventTypeWarning,
+				Reason:    injectionFailedEvent,
+				Message:   "doesn't exist",
+			},
 		},
 	)
 }
@@ -0,0 +1,260 @@
+package mutation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	dtingestendpoint "github.com/Dynatrace/dynatrace-operator/src/ingestendpoint"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/src/webhook"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// cacheSyncTimeout is how long the webhook stays unready while waiting for the informers,
+	// afterwards it gets ready anyway and reads from the API server until they are synced
+	cacheSyncTimeout = 2 * time.Minute
+
+	lookupSourceCache = "cache"
+	lookupSourceApi   = "api"
+)
+
+// wellKnownOwners are the owner kinds which are watched to resolve the workload of a pod, only their metadata is kept.
+// Other owner kinds, e.g. CronJobs which aren't served as batch/v1 by older clusters, are read from the API server.
+var wellKnownOwners = []metav1.TypeMeta{
+	{Kind: "ReplicaSet", APIVersion: "apps/v1"},
+	{Kind: "Deployment", APIVersion: "apps/v1"},
+	{Kind: "StatefulSet", APIVersion: "apps/v1"},
+	{Kind: "DaemonSet", APIVersion: "apps/v1"},
+	{Kind: "Job", APIVersion: "batch/v1"},
+	{Kind: "ReplicationController", APIVersion: "v1"},
+}
+
+type cachedKind struct {
+	gvk      schema.GroupVersionKind
+	metadata bool
+}
+
+// webhookCache serves the lookups of the pod mutator from shared informers once they are synced. Objects which
+// aren't in the informers, e.g. because they were just created, and kinds without informers are read from the given
+// fallback reader. A nil cache always uses the fallback reader.
+type webhookCache struct {
+	scheme  *runtime.Scheme
+	shared  cache.Cache
+	kinds   map[cachedKind]bool
+	secrets map[string]cache.Cache
+
+	ready  int32
+	synced int32
+}
+
+// newWebhookCache creates the informers for namespaces, InjectionConfigs, the secrets created for the injection and
+// the metadata of well known owners. They are started with the manager.
+func newWebhookCache(mgr manager.Manager) (*webhookCache, error) {
+	webhookCache := &webhookCache{
+		scheme:  mgr.GetScheme(),
+		kinds:   make(map[cachedKind]bool),
+		secrets: make(map[string]cache.Cache),
+	}
+
+	shared, err := cache.New(mgr.GetConfig(), cache.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()})
+	if err != nil {
+		return nil, err
+	}
+	webhookCache.shared = shared
+
+	objects := []client.Object{&corev1.Namespace{}, &dynatracev1beta1.InjectionConfig{}}
+	for _, owner := range wellKnownOwners {
+		objects = append(objects, &metav1.PartialObjectMetadata{TypeMeta: owner})
+	}
+	for _, obj := range objects {
+		err := webhookCache.addInformer(shared, obj)
+		// e.g. the InjectionConfig CRD may be applied after the webhook, objects of kinds without informer are read
+		// from the fallback reader
+		if meta.IsNoMatchError(err) {
+			podLog.Info("kind is not served by the API server, not watching it", "kind", objectKind(obj), "error", err.Error())
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	// the webhook is only allowed to watch the secrets with these names, which needs a field selector per name
+	for _, secretName := range []string{dtwebhook.SecretConfigName, dtingestendpoint.SecretEndpointName} {
+		secrets, err := cache.New(mgr.GetConfig(), cache.Options{
+			Scheme: mgr.GetScheme(),
+			Mapper: mgr.GetRESTMapper(),
+			SelectorsByObject: cache.SelectorsByObject{
+				&corev1.Secret{}: {Field: fields.OneTermEqualSelector("metadata.name", secretName)},
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := secrets.GetInformer(context.TODO(), &corev1.Secret{}); err != nil {
+			return nil, err
+		}
+		webhookCache.secrets[secretName] = secrets
+	}
+
+	return webhookCache, mgr.Add(webhookCache)
+}
+
+func (webhookCache *webhookCache) addInformer(informers cache.Cache, obj client.Object) error {
+	kind, err := webhookCache.cachedKindOf(obj)
+	if err != nil {
+		return err
+	}
+	if _, err := informers.GetInformer(context.TODO(), obj); err != nil {
+		return err
+	}
+	webhookCache.kinds[kind] = true
+	return nil
+}
+
+func (webhookCache *webhookCache) caches() []cache.Cache {
+	caches := []cache.Cache{webhookCache.shared}
+	for _, secrets := range webhookCache.secrets {
+		caches = append(caches, secrets)
+	}
+	return caches
+}
+
+// Start starts the informers and blocks until the context is done, the webhook becomes ready once they are synced
+func (webhookCache *webhookCache) Start(ctx context.Context) error {
+	for _, informers := range webhookCache.caches() {
+		go func(informers cache.Cache) {
+			if err := informers.Start(ctx); err != nil {
+				podLog.Error(err, "failed to start the webhook cache")
+			}
+		}(informers)
+	}
+
+	syncCtx, cancel := context.WithTimeout(ctx, cacheSyncTimeout)
+	defer cancel()
+	if webhookCache.waitForCacheSync(syncCtx) {
+		podLog.Info("webhook cache synced")
+	} else {
+		podLog.Info("webhook cache not synced yet, reading from the API server until it is", "timeout", cacheSyncTimeout)
+		go webhookCache.waitForCacheSync(ctx)
+	}
+	atomic.StoreInt32(&webhookCache.ready, 1)
+
+	<-ctx.Done()
+	return nil
+}
+
+// NeedLeaderElection is false, every webhook replica needs its own cache
+func (webhookCache *webhookCache) NeedLeaderElection() bool {
+	return false
+}
+
+func (webhookCache *webhookCache) waitForCacheSync(ctx context.Context) bool {
+	for _, informers := range webhookCache.caches() {
+		if !informers.WaitForCacheSync(ctx) {
+			return false
+		}
+	}
+	atomic.StoreInt32(&webhookCache.synced, 1)
+	return true
+}
+
+// isReady returns true once the informers are synced or the sync timed out
+func (webhookCache *webhookCache) isReady() bool {
+	return webhookCache == nil || atomic.LoadInt32(&webhookCache.ready) == 1
+}
+
+func (webhookCache *webhookCache) isSynced() bool {
+	return webhookCache != nil && atomic.LoadInt32(&webhookCache.synced) == 1
+}
+
+// Get reads the object from the informers, or from the fallback reader if it isn't cached
+func (webhookCache *webhookCache) Get(ctx context.Context, key client.ObjectKey, obj client.Object, fallback client.Reader) error {
+	start := time.Now()
+	kind := objectKind(obj)
+
+	if informers := webhookCache.cacheFor(obj, key.Name); informers != nil {
+		err := informers.Get(ctx, key, obj)
+		if !k8serrors.IsNotFound(err) {
+			observeLookup(kind, lookupSourceCache, start)
+			return err
+		}
+	}
+
+	err := fallback.Get(ctx, key, obj)
+	observeLookup(kind, lookupSourceApi, start)
+	return err
+}
+
+// List lists the objects from the informers, or from the fallback reader if they aren't cached
+func (webhookCache *webhookCache) List(ctx context.Context, list client.ObjectList, fallback client.Reader, opts ...client.ListOption) error {
+	start := time.Now()
+	kind := strings.TrimSuffix(objectKind(list), "List")
+
+	if informers := webhookCache.cacheFor(list, ""); informers != nil {
+		err := informers.List(ctx, list, opts...)
+		observeLookup(kind, lookupSourceCache, start)
+		return err
+	}
+
+	err := fallback.List(ctx, list, opts...)
+	observeLookup(kind, lookupSourceApi, start)
+	return err
+}
+
+// isCached returns true if the object is served by the informers
+func (webhookCache *webhookCache) isCached(obj runtime.Object, name string) bool {
+	return webhookCache.cacheFor(obj, name) != nil
+}
+
+func (webhookCache *webhookCache) cacheFor(obj runtime.Object, name string) cache.Cache {
+	if !webhookCache.isSynced() {
+		return nil
+	}
+	if _, isSecret := obj.(*corev1.Secret); isSecret {
+		return webhookCache.secrets[name]
+	}
+
+	kind, err := webhookCache.cachedKindOf(obj)
+	if err != nil {
+		return nil
+	}
+	kind.gvk.Kind = strings.TrimSuffix(kind.gvk.Kind, "List")
+	if !webhookCache.kinds[kind] {
+		return nil
+	}
+	return webhookCache.shared
+}
+
+func (webhookCache *webhookCache) cachedKindOf(obj runtime.Object) (cachedKind, error) {
+	gvk, err := apiutil.GVKForObject(obj, webhookCache.scheme)
+	if err != nil {
+		return cachedKind{}, err
+	}
+	_, isMetadata := obj.(*metav1.PartialObjectMetadata)
+	return cachedKind{gvk: gvk, metadata: isMetadata}, nil
+}
+
+func objectKind(obj runtime.Object) string {
+	if partialObject, ok := obj.(*metav1.PartialObjectMetadata); ok {
+		return partialObject.Kind
+	}
+	typeName := fmt.Sprintf("%T", obj)
+	return typeName[strings.LastIndex(typeName, ".")+1:]
+}
+
+func observeLookup(kind string, source string, start time.Time) {
+	lookupDurationMetric.WithLabelValues(kind, source).Observe(time.Since(start).Seconds())
+}
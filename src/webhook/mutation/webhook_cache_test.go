@@ -0,0 +1,141 @@
+package mutation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	"github.com/Dynatrace/dynatrace-operator/src/scheme"
+	"github.com/Dynatrace/dynatrace-operator/src/scheme/fake"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/src/webhook"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fakeInformers serves the objects of the reader instead of informers
+type fakeInformers struct {
+	informertest.FakeInformers
+	reader client.Reader
+}
+
+func (informers *fakeInformers) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	return informers.reader.Get(ctx, key, obj)
+}
+
+func (informers *fakeInformers) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	return informers.reader.List(ctx, list, opts...)
+}
+
+func newTestWebhookCache(t *testing.T, synced bool, cachedObjs ...client.Object) *webhookCache {
+	informers := &fakeInformers{FakeInformers: informertest.FakeInformers{Scheme: scheme.Scheme}, reader: fake.NewClient(cachedObjs...)}
+	webhookCache := &webhookCache{
+		scheme:  scheme.Scheme,
+		shared:  informers,
+		kinds:   make(map[cachedKind]bool),
+		secrets: map[string]cache.Cache{dtwebhook.SecretConfigName: informers},
+	}
+	for _, obj := range []client.Object{
+		&corev1.Namespace{},
+		&dynatracev1beta1.InjectionConfig{},
+	} {
+		require.NoError(t, webhookCache.addInformer(informers, obj))
+	}
+	// the fake informers only support typed objects
+	webhookCache.kinds[cachedKind{gvk: appsv1.SchemeGroupVersion.WithKind("ReplicaSet"), metadata: true}] = true
+	if synced {
+		webhookCache.synced = 1
+	}
+	return webhookCache
+}
+
+func newTestNamespace(source string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace", Labels: map[string]string{"source": source}}}
+}
+
+func lookupCount(t *testing.T, kind string, source string) uint64 {
+	var metric dto.Metric
+	require.NoError(t, lookupDurationMetric.WithLabelValues(kind, source).(prometheus.Histogram).Write(&metric))
+	return metric.GetHistogram().GetSampleCount()
+}
+
+func TestWebhookCache(t *testing.T) {
+	fallback := fake.NewClient(
+		newTestNamespace(lookupSourceApi),
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: dtwebhook.SecretConfigName, Namespace: "test-namespace"}},
+		&dynatracev1beta1.DynaKube{ObjectMeta: metav1.ObjectMeta{Name: dynakubeName, Namespace: "dynatrace"}},
+	)
+
+	t.Run(`reads from the informers once they are synced`, func(t *testing.T) {
+		webhookCache := newTestWebhookCache(t, true, newTestNamespace(lookupSourceCache))
+		lookupsBefore := lookupCount(t, "Namespace", lookupSourceCache)
+
+		var ns corev1.Namespace
+		require.NoError(t, webhookCache.Get(context.TODO(), client.ObjectKey{Name: "test-namespace"}, &ns, fallback))
+
+		assert.Equal(t, lookupSourceCache, ns.Labels["source"])
+		assert.Equal(t, lookupsBefore+1, lookupCount(t, "Namespace", lookupSourceCache))
+	})
+	t.Run(`reads from the fallback until the informers are synced`, func(t *testing.T) {
+		webhookCache := newTestWebhookCache(t, false, newTestNamespace(lookupSourceCache))
+		lookupsBefore := lookupCount(t, "Namespace", lookupSourceApi)
+
+		var ns corev1.Namespace
+		require.NoError(t, webhookCache.Get(context.TODO(), client.ObjectKey{Name: "test-namespace"}, &ns, fallback))
+
+		assert.Equal(t, lookupSourceApi, ns.Labels["source"])
+		assert.Equal(t, lookupsBefore+1, lookupCount(t, "Namespace", lookupSourceApi))
+	})
+	t.Run(`reads objects missing in the informers from the fallback`, func(t *testing.T) {
+		webhookCache := newTestWebhookCache(t, true)
+
+		var ns corev1.Namespace
+		require.NoError(t, webhookCache.Get(context.TODO(), client.ObjectKey{Name: "test-namespace"}, &ns, fallback))
+		assert.Equal(t, lookupSourceApi, ns.Labels["source"])
+
+		var secret corev1.Secret
+		require.NoError(t, webhookCache.Get(context.TODO(), client.ObjectKey{Name: dtwebhook.SecretConfigName, Namespace: "test-namespace"}, &secret, fallback))
+	})
+	t.Run(`reads kinds without informers from the fallback`, func(t *testing.T) {
+		webhookCache := newTestWebhookCache(t, true)
+
+		assert.False(t, webhookCache.isCached(&dynatracev1beta1.DynaKube{}, dynakubeName))
+		assert.False(t, webhookCache.isCached(&corev1.Secret{}, "other-secret"))
+		assert.False(t, webhookCache.isCached(&metav1.PartialObjectMetadata{TypeMeta: metav1.TypeMeta{Kind: "Rollout", APIVersion: "argoproj.io/v1alpha1"}}, "test-rollout"))
+		assert.True(t, webhookCache.isCached(&metav1.PartialObjectMetadata{TypeMeta: metav1.TypeMeta{Kind: "ReplicaSet", APIVersion: "apps/v1"}}, "test-replicaset"))
+		assert.True(t, webhookCache.isCached(&dynatracev1beta1.InjectionConfigList{}, ""))
+
+		var dk dynatracev1beta1.DynaKube
+		require.NoError(t, webhookCache.Get(context.TODO(), client.ObjectKey{Name: dynakubeName, Namespace: "dynatrace"}, &dk, fallback))
+	})
+	t.Run(`nil cache reads from the fallback`, func(t *testing.T) {
+		var webhookCache *webhookCache
+
+		var ns corev1.Namespace
+		require.NoError(t, webhookCache.Get(context.TODO(), client.ObjectKey{Name: "test-namespace"}, &ns, fallback))
+
+		assert.Equal(t, lookupSourceApi, ns.Labels["source"])
+		assert.True(t, webhookCache.isReady())
+	})
+	t.Run(`gets ready once the informers are synced`, func(t *testing.T) {
+		webhookCache := newTestWebhookCache(t, false)
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+		assert.False(t, webhookCache.isReady())
+
+		go func() {
+			_ = webhookCache.Start(ctx)
+		}()
+
+		assert.Eventually(t, webhookCache.isReady, time.Second, 10*time.Millisecond)
+		assert.True(t, webhookCache.isSynced())
+	})
+}
@@ -0,0 +1,194 @@
+package mutation
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	ownerCacheTTL        = 5 * time.Minute
+	maxOwnerCacheEntries = 1024
+)
+
+// ownerResolver follows the controller owner references of a pod up to its workload,
+// only owners of the given kinds are followed. Owners are read from the informers if they are watched,
+// otherwise from the client, whose results are kept in the owner cache.
+type ownerResolver struct {
+	client     client.Client
+	informers  *webhookCache
+	cache      *ownerCache
+	ownerKinds []string
+}
+
+func (resolver ownerResolver) findRootOwnerOfPod(ctx context.Context, pod *corev1.Pod, namespace string) (string, string, error) {
+	root, err := resolver.findWorkloadOfPod(ctx, pod, namespace)
+	kind := root.Kind
+	if kind == "Pod" {
+		kind = ""
+	}
+	return root.Name, kind, err
+}
+
+// findWorkloadOfPod returns the metadata of the workload of the pod, or of the pod itself if it has no workload
+func (resolver ownerResolver) findWorkloadOfPod(ctx context.Context, pod *corev1.Pod, namespace string) (*metav1.PartialObjectMetadata, error) {
+	obj := &metav1.PartialObjectMetadata{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: pod.APIVersion,
+			Kind:       pod.Kind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: pod.ObjectMeta.Name,
+			// pod.ObjectMeta.Namespace is empty yet
+			Namespace:       namespace,
+			OwnerReferences: pod.ObjectMeta.OwnerReferences,
+		},
+	}
+	return resolver.findRootOwner(ctx, obj)
+}
+
+func (resolver ownerResolver) findRootOwner(ctx context.Context, o *metav1.PartialObjectMetadata) (*metav1.PartialObjectMetadata, error) {
+	om := o.ObjectMeta
+	for _, owner := range om.OwnerReferences {
+		if owner.Controller != nil && *owner.Controller && resolver.isWorkloadOwner(owner) {
+			obj, err := resolver.getOwner(ctx, owner, om.Namespace)
+			if k8serrors.IsForbidden(err) || meta.IsNoMatchError(err) {
+				// the owner can't be followed any further, e.g. the webhook has no permissions for a custom kind
+				podLog.Info("failed to query the owner, using it as workload", "apiVersion", owner.APIVersion, "kind", owner.Kind, "name", owner.Name, "namespace", om.Namespace, "error", err.Error())
+				return ownerMetadata(owner, om.Namespace), nil
+			} else if err != nil {
+				podLog.Error(err, "failed to query the object", "apiVersion", owner.APIVersion, "kind", owner.Kind, "name", owner.Name, "namespace", om.Namespace)
+				return o, err
+			}
+
+			return resolver.findRootOwner(ctx, obj)
+		}
+	}
+	return o, nil
+}
+
+func ownerMetadata(owner metav1.OwnerReference, namespace string) *metav1.PartialObjectMetadata {
+	return &metav1.PartialObjectMetadata{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: owner.APIVersion,
+			Kind:       owner.Kind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      owner.Name,
+			Namespace: namespace,
+			UID:       owner.UID,
+		},
+	}
+}
+
+func (resolver ownerResolver) getOwner(ctx context.Context, owner metav1.OwnerReference, namespace string) (*metav1.PartialObjectMetadata, error) {
+	obj := &metav1.PartialObjectMetadata{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: owner.APIVersion,
+			Kind:       owner.Kind,
+		},
+	}
+	objectKey := client.ObjectKey{Name: owner.Name, Namespace: namespace}
+
+	// watched owners are always up to date, so they don't need the owner cache
+	if resolver.informers.isCached(obj, owner.Name) {
+		if err := resolver.informers.Get(ctx, objectKey, obj, resolver.client); err != nil {
+			return nil, err
+		}
+		return obj, nil
+	}
+
+	// owners are cached by UID, so owners which were recreated with the same name aren't mixed up
+	key := string(owner.UID)
+	if cachedObj, ok := resolver.cache.get(key, time.Now()); ok {
+		return cachedObj, nil
+	}
+	if err := resolver.informers.Get(ctx, objectKey, obj, resolver.client); err != nil {
+		return nil, err
+	}
+	resolver.cache.set(key, obj, time.Now())
+	return obj, nil
+}
+
+// isWorkloadOwner checks if the kind of the owner is one of the owner kinds, which are either "Kind.group" or just "Kind" for the core group
+func (resolver ownerResolver) isWorkloadOwner(ownerRef metav1.OwnerReference) bool {
+	groupVersion, err := schema.ParseGroupVersion(ownerRef.APIVersion)
+	if err != nil {
+		return false
+	}
+	ownerKind := ownerRef.Kind
+	if groupVersion.Group != "" {
+		ownerKind += "." + groupVersion.Group
+	}
+
+	for _, kind := range resolver.ownerKinds {
+		if kind == ownerKind {
+			return true
+		}
+	}
+	return false
+}
+
+type cachedOwner struct {
+	obj       *metav1.PartialObjectMetadata
+	expiresAt time.Time
+}
+
+// ownerCache keeps the metadata of owners, so the owners of pods of the same workload are only queried once per TTL.
+// A nil cache doesn't cache anything, neither are owners without UID cached.
+type ownerCache struct {
+	mutex   sync.Mutex
+	entries map[string]cachedOwner
+}
+
+func newOwnerCache() *ownerCache {
+	return &ownerCache{
+		entries: make(map[string]cachedOwner),
+	}
+}
+
+func (cache *ownerCache) get(key string, now time.Time) (*metav1.PartialObjectMetadata, bool) {
+	if cache == nil || key == "" {
+		return nil, false
+	}
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	entry, ok := cache.entries[key]
+	if !ok || now.After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.obj.DeepCopy(), true
+}
+
+func (cache *ownerCache) set(key string, obj *metav1.PartialObjectMetadata, now time.Time) {
+	if cache == nil || key == "" {
+		return
+	}
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if _, ok := cache.entries[key]; !ok && len(cache.entries) >= maxOwnerCacheEntries {
+		cache.prune(now)
+	}
+	cache.entries[key] = cachedOwner{obj: obj.DeepCopy(), expiresAt: now.Add(ownerCacheTTL)}
+}
+
+// prune drops expired entries, or everything if none have expired, to keep the cache bounded.
+func (cache *ownerCache) prune(now time.Time) {
+	for key, entry := range cache.entries {
+		if now.After(entry.expiresAt) {
+			delete(cache.entries, key)
+		}
+	}
+	if len(cache.entries) >= maxOwnerCacheEntries {
+		cache.entries = make(map[string]cachedOwner)
+	}
+}
@@ -0,0 +1,146 @@
+package mutation
+
+import (
+	"context"
+	"testing"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	"github.com/Dynatrace/dynatrace-operator/src/scheme/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const testWorkloadNamespace = "test-namespace"
+
+// countingClient counts the Get calls and returns the configured error instead of querying the wrapped client
+type countingClient struct {
+	client.Client
+	gets int
+	err  error
+}
+
+func (clt *countingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	clt.gets++
+	if clt.err != nil {
+		return clt.err
+	}
+	return clt.Client.Get(ctx, key, obj)
+}
+
+func controllerOwner(apiVersion string, kind string, name string) []metav1.OwnerReference {
+	isController := true
+	return []metav1.OwnerReference{{APIVersion: apiVersion, Kind: kind, Name: name, UID: types.UID(name + "-uid"), Controller: &isController}}
+}
+
+func newWorkloadClient(workloadOwners []metav1.OwnerReference) *countingClient {
+	workload := &unstructured.Unstructured{}
+	workload.SetGroupVersionKind(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Workload"})
+	workload.SetName("test-workload")
+	workload.SetNamespace(testWorkloadNamespace)
+
+	return &countingClient{Client: fake.NewClient(
+		workload,
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name:            "test-deployment",
+			Namespace:       testWorkloadNamespace,
+			OwnerReferences: workloadOwners,
+		}},
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+			Name:            "test-replicaset",
+			Namespace:       testWorkloadNamespace,
+			OwnerReferences: controllerOwner("apps/v1", "Deployment", "test-deployment"),
+		}},
+	)}
+}
+
+func newWorkloadPod() *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:            "test-pod",
+		OwnerReferences: controllerOwner("apps/v1", "ReplicaSet", "test-replicaset"),
+	}}
+}
+
+func TestOwnerResolver(t *testing.T) {
+	workloadOwners := controllerOwner("example.com/v1", "Workload", "test-workload")
+
+	t.Run(`follows well known workloads`, func(t *testing.T) {
+		resolver := ownerResolver{client: newWorkloadClient(nil), ownerKinds: dynatracev1beta1.DefaultWorkloadOwnerKinds}
+
+		name, kind, err := resolver.findRootOwnerOfPod(context.TODO(), newWorkloadPod(), testWorkloadNamespace)
+
+		require.NoError(t, err)
+		assert.Equal(t, "test-deployment", name)
+		assert.Equal(t, "Deployment", kind)
+	})
+	t.Run(`follows only configured owner kinds`, func(t *testing.T) {
+		resolver := ownerResolver{client: newWorkloadClient(workloadOwners), ownerKinds: dynatracev1beta1.DefaultWorkloadOwnerKinds}
+
+		name, kind, err := resolver.findRootOwnerOfPod(context.TODO(), newWorkloadPod(), testWorkloadNamespace)
+
+		require.NoError(t, err)
+		assert.Equal(t, "test-deployment", name)
+		assert.Equal(t, "Deployment", kind)
+
+		resolver.ownerKinds = append(resolver.ownerKinds, "Workload.example.com")
+
+		name, kind, err = resolver.findRootOwnerOfPod(context.TODO(), newWorkloadPod(), testWorkloadNamespace)
+
+		require.NoError(t, err)
+		assert.Equal(t, "test-workload", name)
+		assert.Equal(t, "Workload", kind)
+	})
+	t.Run(`uses owner as workload if it is forbidden to query it`, func(t *testing.T) {
+		clt := newWorkloadClient(nil)
+		clt.err = k8serrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "replicasets"}, "test-replicaset", nil)
+		resolver := ownerResolver{client: clt, ownerKinds: dynatracev1beta1.DefaultWorkloadOwnerKinds}
+
+		name, kind, err := resolver.findRootOwnerOfPod(context.TODO(), newWorkloadPod(), testWorkloadNamespace)
+
+		require.NoError(t, err)
+		assert.Equal(t, "test-replicaset", name)
+		assert.Equal(t, "ReplicaSet", kind)
+	})
+	t.Run(`returns other errors`, func(t *testing.T) {
+		clt := newWorkloadClient(nil)
+		clt.err = k8serrors.NewNotFound(schema.GroupResource{Group: "apps", Resource: "replicasets"}, "test-replicaset")
+		resolver := ownerResolver{client: clt, ownerKinds: dynatracev1beta1.DefaultWorkloadOwnerKinds}
+
+		_, _, err := resolver.findRootOwnerOfPod(context.TODO(), newWorkloadPod(), testWorkloadNamespace)
+
+		require.Error(t, err)
+	})
+	t.Run(`caches owner lookups`, func(t *testing.T) {
+		clt := newWorkloadClient(nil)
+		resolver := ownerResolver{client: clt, cache: newOwnerCache(), ownerKinds: dynatracev1beta1.DefaultWorkloadOwnerKinds}
+
+		for i := 0; i < 3; i++ {
+			name, _, err := resolver.findRootOwnerOfPod(context.TODO(), newWorkloadPod(), testWorkloadNamespace)
+			require.NoError(t, err)
+			assert.Equal(t, "test-deployment", name)
+		}
+		assert.Equal(t, 2, clt.gets)
+	})
+	t.Run(`doesn't use the cached owner for recreated owners`, func(t *testing.T) {
+		clt := newWorkloadClient(nil)
+		resolver := ownerResolver{client: clt, cache: newOwnerCache(), ownerKinds: dynatracev1beta1.DefaultWorkloadOwnerKinds}
+
+		_, _, err := resolver.findRootOwnerOfPod(context.TODO(), newWorkloadPod(), testWorkloadNamespace)
+		require.NoError(t, err)
+
+		pod := newWorkloadPod()
+		pod.OwnerReferences[0].UID = "recreated-uid"
+		name, _, err := resolver.findRootOwnerOfPod(context.TODO(), pod, testWorkloadNamespace)
+		require.NoError(t, err)
+
+		assert.Equal(t, "test-deployment", name)
+		assert.Equal(t, 3, clt.gets)
+	})
+}
@@ -16,6 +16,10 @@ const (
 	errorInvalidApiUrl = `The DynaKube's specification has an invalid API URL value set.
 	Make sure you correctly specify the URL in your custom resource (including the /api postfix).
 	`
+
+	errorInvalidFailoverApiUrl = `The DynaKube's specification has an invalid failover API URL value set.
+	Make sure you correctly specify the URLs in your custom resource (including the /api postfix).
+	`
 )
 
 func noApiUrl(dv *dynakubeValidator, dynakube *dynatracev1beta1.DynaKube) string {
@@ -35,17 +39,31 @@ func noApiUrl(dv *dynakubeValidator, dynakube *dynatracev1beta1.DynaKube) string
 }
 
 func isInvalidApiUrl(dv *dynakubeValidator, dynakube *dynatracev1beta1.DynaKube) string {
-	apiUrl := dynakube.Spec.APIURL
+	if !isValidApiUrl(dynakube.Spec.APIURL) {
+		return errorInvalidApiUrl
+	}
+	return ""
+}
 
+func isInvalidFailoverApiUrl(dv *dynakubeValidator, dynakube *dynatracev1beta1.DynaKube) string {
+	for _, failoverApiUrl := range dynakube.Spec.FailoverAPIURLs {
+		if !isValidApiUrl(failoverApiUrl) {
+			return errorInvalidFailoverApiUrl
+		}
+	}
+	return ""
+}
+
+func isValidApiUrl(apiUrl string) bool {
 	if !strings.HasSuffix(apiUrl, "/api") {
 		log.Info("api url does not end with /api", "apiUrl", apiUrl)
-		return errorInvalidApiUrl
+		return false
 	}
 
 	parsedUrl, err := url.Parse(apiUrl)
 	if err != nil {
 		log.Info("API URL is not a valid URL", "err", err.Error())
-		return errorInvalidApiUrl
+		return false
 	}
 
 	hostname := parsedUrl.Hostname()
@@ -55,8 +73,8 @@ func isInvalidApiUrl(dv *dynakubeValidator, dynakube *dynatracev1beta1.DynaKube)
 
 	if len(hostnameWithDomains) < 1 || len(hostnameWithDomains[0]) == 0 {
 		log.Info("invalid hostname in the api url", "hostname", hostname)
-		return errorInvalidApiUrl
+		return false
 	}
 
-	return ""
+	return true
 }
@@ -70,3 +70,22 @@ func TestHasApiUrl(t *testing.T) {
 		})
 	})
 }
+
+func TestFailoverApiUrls(t *testing.T) {
+	t.Run(`valid failover API URLs`, func(t *testing.T) {
+		assertAllowedResponse(t, &dynatracev1beta1.DynaKube{
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				APIURL:          "https://node1.managed.doma.in/e/tenantid/api",
+				FailoverAPIURLs: []string{"https://node2.managed.doma.in/e/tenantid/api", "https://dr.doma.in/e/tenantid/api"},
+			},
+		})
+	})
+	t.Run(`invalid failover API URL (without /api suffix)`, func(t *testing.T) {
+		assertDeniedResponse(t, []string{errorInvalidFailoverApiUrl}, &dynatracev1beta1.DynaKube{
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				APIURL:          "https://node1.managed.doma.in/e/tenantid/api",
+				FailoverAPIURLs: []string{"https://node2.managed.doma.in/e/tenantid"},
+			},
+		})
+	})
+}
@@ -12,6 +12,7 @@ type validator func(dv *dynakubeValidator, dynakube *dynatracev1beta1.DynaKube)
 var validators = []validator{
 	noApiUrl,
 	isInvalidApiUrl,
+	isInvalidFailoverApiUrl,
 	missingCSIDaemonSet,
 	conflictingActiveGateConfiguration,
 	invalidActiveGateCapabilities,
@@ -22,10 +23,12 @@ var validators = []validator{
 	conflictingReadOnlyFilesystemAndMultipleOsAgentsOnNode,
 	noResourcesAvailable,
 	imageFieldSetWithoutCSIFlag,
+	invalidSettingsObjects,
+	invalidMaintenanceWindows,
+	invalidContainerSelection,
 }
 
 var warnings = []validator{
-	deprecatedFeatureFlagFormat,
 	metricIngestPreviewWarning,
 	statsdIngestPreviewWarning,
 	missingActiveGateMemoryLimit,
@@ -0,0 +1,47 @@
+package validation
+
+import (
+	"fmt"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+)
+
+const (
+	errorInvalidContainerSelector = `The DynaKube's specification contains an invalid container selector: %s.
+Make sure the name and image of the selectors are valid regular expressions and at least one of them is set.
+`
+)
+
+func invalidContainerSelection(dv *dynakubeValidator, dynakube *dynatracev1beta1.DynaKube) string {
+	selection := dynakube.ContainerSelection()
+	if err := validateContainerSelectors("include", selection.Include); err != nil {
+		log.Info("requested dynakube has an invalid container selector", "name", dynakube.Name, "namespace", dynakube.Namespace, "error", err.Error())
+		return fmt.Sprintf(errorInvalidContainerSelector, err.Error())
+	}
+	if err := validateContainerSelectors("exclude", selection.Exclude); err != nil {
+		log.Info("requested dynakube has an invalid container selector", "name", dynakube.Name, "namespace", dynakube.Namespace, "error", err.Error())
+		return fmt.Sprintf(errorInvalidContainerSelector, err.Error())
+	}
+	return ""
+}
+
+func validateContainerSelectors(kind string, selectors []dynatracev1beta1.ContainerSelector) error {
+	for i, selector := range selectors {
+		if err := validateContainerSelector(selector); err != nil {
+			return fmt.Errorf("%s %d: %w", kind, i, err)
+		}
+	}
+	return nil
+}
+
+func validateContainerSelector(selector dynatracev1beta1.ContainerSelector) error {
+	if selector.Name == "" && selector.Image == "" {
+		return fmt.Errorf("neither name nor image is set")
+	}
+	for _, pattern := range []string{selector.Name, selector.Image} {
+		if _, err := dynatracev1beta1.CompileContainerPattern(pattern); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package validation
+
+import (
+	"fmt"
+	"testing"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+)
+
+func TestInvalidContainerSelection(t *testing.T) {
+	newDynakube := func(selection dynatracev1beta1.ContainerSelectionSpec) *dynatracev1beta1.DynaKube {
+		return &dynatracev1beta1.DynaKube{
+			ObjectMeta: defaultDynakubeObjectMeta,
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				APIURL: testApiUrl,
+				OneAgent: dynatracev1beta1.OneAgentSpec{
+					ApplicationMonitoring: &dynatracev1beta1.ApplicationMonitoringSpec{
+						AppInjectionSpec: dynatracev1beta1.AppInjectionSpec{ContainerSelection: selection},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run(`valid container selection`, func(t *testing.T) {
+		assertAllowedResponseWithoutWarnings(t, newDynakube(dynatracev1beta1.ContainerSelectionSpec{
+			Include: []dynatracev1beta1.ContainerSelector{{Name: "^app-.*"}},
+			Exclude: []dynatracev1beta1.ContainerSelector{{Name: "istio-proxy"}, {Image: "vault:.*"}},
+		}))
+	})
+	t.Run(`invalid name pattern`, func(t *testing.T) {
+		assertDeniedResponse(t,
+			[]string{fmt.Sprintf(errorInvalidContainerSelector, "exclude 1: error parsing regexp: missing closing ): `istio-(proxy`")},
+			newDynakube(dynatracev1beta1.ContainerSelectionSpec{
+				Exclude: []dynatracev1beta1.ContainerSelector{{Name: "vault-agent"}, {Name: "istio-(proxy"}},
+			}))
+	})
+	t.Run(`pattern escaping the anchors`, func(t *testing.T) {
+		assertDeniedResponse(t,
+			[]string{"include 0: error parsing regexp: unexpected ): `app)|(.*`"},
+			newDynakube(dynatracev1beta1.ContainerSelectionSpec{
+				Include: []dynatracev1beta1.ContainerSelector{{Name: "app)|(.*"}},
+			}))
+	})
+	t.Run(`empty selector`, func(t *testing.T) {
+		assertDeniedResponse(t,
+			[]string{"include 0: neither name nor image is set"},
+			newDynakube(dynatracev1beta1.ContainerSelectionSpec{
+				Include: []dynatracev1beta1.ContainerSelector{{}},
+			}))
+	})
+}
@@ -2,23 +2,57 @@ package validation
 
 import (
 	"fmt"
+	"sort"
 
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
 )
 
 const (
 	featureDeprecatedWarningMessage = `DEPRECATED: %s`
+	unknownFeatureFlagWarning       = `unknown feature flag '%s' is ignored`
+	invalidFeatureFlagWarning       = `feature flag '%s' has an invalid value '%s' (%s), %s is used instead`
 )
 
-func deprecatedFeatureFlagFormat(dv *dynakubeValidator, dynakube *dynatracev1beta1.DynaKube) string {
-	if dynakube.Annotations == nil {
-		return ""
+// featureFlagWarnings returns a warning for every feature flag annotation which is unknown, can't be parsed or is
+// deprecated, the warnings contain the value which is effectively used. Flags replaced by typed fields are only reported
+// if warnReplacedFlags is set.
+func featureFlagWarnings(dynakube *dynatracev1beta1.DynaKube, warnReplacedFlags bool) []string {
+	annotations := make([]string, 0, len(dynakube.Annotations))
+	for annotation := range dynakube.Annotations {
+		if dynatracev1beta1.IsFeatureFlagAnnotation(annotation) {
+			annotations = append(annotations, annotation)
+		}
 	}
+	sort.Strings(annotations)
 
-	deprecatedPrefix := dynatracev1beta1.DeprecatedFeatureFlagPrefix + dynatracev1beta1.FeatureFlagAnnotationBase
-	if len(dynatracev1beta1.FlagsWithPrefix(dynakube, deprecatedPrefix)) > 0 {
-		return fmt.Sprintf(featureDeprecatedWarningMessage, "'alpha.' prefix not necessary")
+	var warningMessages []string
+	for _, annotation := range annotations {
+		flag, ok := dynatracev1beta1.LookupFeatureFlag(annotation)
+		if !ok {
+			warningMessages = append(warningMessages, fmt.Sprintf(unknownFeatureFlagWarning, annotation))
+			continue
+		}
+
+		value := dynakube.Annotations[annotation]
+		if err := flag.Validate(value); err != nil {
+			warningMessages = append(warningMessages, fmt.Sprintf(invalidFeatureFlagWarning, annotation, value, err.Error(), effectiveDefault(flag)))
+		}
+		if annotation != flag.Annotation {
+			effectiveValue, _ := dynakube.FeatureFlagValue(flag.Annotation)
+			warningMessages = append(warningMessages, fmt.Sprintf(featureDeprecatedWarningMessage,
+				fmt.Sprintf("'alpha.' prefix not necessary for '%s', effective value is '%s'", flag.Annotation, effectiveValue)))
+		}
+		if flag.Deprecated != "" && warnReplacedFlags {
+			warningMessages = append(warningMessages, fmt.Sprintf(featureDeprecatedWarningMessage,
+				fmt.Sprintf("feature flag '%s', %s", flag.Annotation, flag.Deprecated)))
+		}
 	}
+	return warningMessages
+}
 
-	return ""
+func effectiveDefault(flag dynatracev1beta1.FeatureFlag) string {
+	if flag.Default == "" {
+		return "the default"
+	}
+	return fmt.Sprintf("'%s'", flag.Default)
 }
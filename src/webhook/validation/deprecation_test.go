@@ -1,14 +1,30 @@
 package validation
 
 import (
+	"context"
 	"testing"
 
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	dynatracev1beta2 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta2"
+	"github.com/Dynatrace/dynatrace-operator/src/scheme/fake"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
 )
 
 func TestDeprecationWarning(t *testing.T) {
 	t.Run(`no warning`, func(t *testing.T) {
+		dynakube := &dynatracev1beta1.DynaKube{
+			ObjectMeta: defaultDynakubeObjectMeta,
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				APIURL: testApiUrl,
+			},
+		}
+		assertAllowedResponseWithoutWarnings(t, dynakube)
+	})
+	t.Run(`warning for flag replaced by v1beta2 field`, func(t *testing.T) {
 		dynakubeMeta := defaultDynakubeObjectMeta
 		dynakubeMeta.Annotations = map[string]string{
 			dynatracev1beta1.AnnotationFeatureEnableWebhookReinvocationPolicy: "true",
@@ -19,10 +35,42 @@ func TestDeprecationWarning(t *testing.T) {
 				APIURL: testApiUrl,
 			},
 		}
-		assertAllowedResponseWithoutWarnings(t, dynakube)
+
+		response := assertAllowedResponse(t, dynakube)
+
+		assert.Equal(t, []string{
+			"DEPRECATED: feature flag 'operator.dynatrace.com/feature-enable-webhook-reinvocation-policy', use spec.injection.reinvocationPolicy of the v1beta2 DynaKube instead",
+		}, response.Warnings)
 		assert.True(t, dynakube.FeatureEnableWebhookReinvocationPolicy())
 	})
+	t.Run(`no warning for flag set by v1beta2 field`, func(t *testing.T) {
+		dynakubeMeta := defaultDynakubeObjectMeta
+		dynakubeMeta.Annotations = map[string]string{
+			dynatracev1beta1.AnnotationFeatureEnableWebhookReinvocationPolicy: "true",
+		}
+		request := newDynakubeRequest(t, &dynatracev1beta1.DynaKube{
+			ObjectMeta: dynakubeMeta,
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				APIURL: testApiUrl,
+			},
+		})
+		request.RequestKind = &metav1.GroupVersionKind{
+			Group:   dynatracev1beta2.GroupVersion.Group,
+			Version: dynatracev1beta2.GroupVersion.Version,
+			Kind:    "DynaKube",
+		}
+		clt := fake.NewClient()
+		validator := &dynakubeValidator{
+			clt:       clt,
+			apiReader: clt,
+			cfg:       &rest.Config{},
+		}
+
+		response := validator.Handle(context.TODO(), request)
 
+		assert.True(t, response.Allowed)
+		assert.Empty(t, response.Warnings)
+	})
 	t.Run(`warning present`, func(t *testing.T) {
 		dynakubeMeta := defaultDynakubeObjectMeta
 		dynakubeMeta.Annotations = map[string]string{
@@ -34,7 +82,48 @@ func TestDeprecationWarning(t *testing.T) {
 				APIURL: testApiUrl,
 			},
 		}
-		assertAllowedResponseWithWarnings(t, 1, dynakube)
+		assertAllowedResponseWithWarnings(t, 2, dynakube)
 		assert.True(t, dynakube.FeatureEnableWebhookReinvocationPolicy())
 	})
+	t.Run(`unknown and invalid feature flags`, func(t *testing.T) {
+		dynakubeMeta := defaultDynakubeObjectMeta
+		dynakubeMeta.Annotations = map[string]string{
+			dynatracev1beta1.AnnotationFeaturePrefix + "disable-hosts-request":       "true",
+			dynatracev1beta1.AnnotationFeatureOneAgentMaxUnavailable:                 "two",
+			dynatracev1beta1.AnnotationFeatureIgnoredNamespaces:                      `["^dynatrace$"`,
+			dynatracev1beta1.AnnotationFeatureEecResourcesLimits(corev1.ResourceCPU): "100m",
+			"other.annotation/feature-unrelated":                                     "value",
+		}
+		dynakube := &dynatracev1beta1.DynaKube{
+			ObjectMeta: dynakubeMeta,
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				APIURL: testApiUrl,
+			},
+		}
+
+		response := assertAllowedResponse(t, dynakube)
+
+		require.Len(t, response.Warnings, 6)
+		assert.Contains(t, response.Warnings[0], "use spec.activeGateOptions.extensionController.resources.limits of the v1beta2 DynaKube instead")
+		assert.Equal(t, "unknown feature flag 'operator.dynatrace.com/feature-disable-hosts-request' is ignored", response.Warnings[1])
+		assert.Contains(t, response.Warnings[2], "invalid value")
+		assert.Contains(t, response.Warnings[2], "the default is used instead")
+		assert.Contains(t, response.Warnings[3], "use spec.injection.ignoredNamespaces of the v1beta2 DynaKube instead")
+		assert.Contains(t, response.Warnings[4], "'1' is used instead")
+		assert.Contains(t, response.Warnings[5], "use spec.oneAgentOptions.maxUnavailable of the v1beta2 DynaKube instead")
+	})
+	t.Run(`effective value of deprecated prefix`, func(t *testing.T) {
+		warnings := featureFlagWarnings(&dynatracev1beta1.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					dynatracev1beta1.DeprecatedFeatureFlagPrefix + dynatracev1beta1.AnnotationFeatureDisableHostsRequests: "true",
+					dynatracev1beta1.AnnotationFeatureDisableHostsRequests:                                                "false",
+				},
+			},
+		}, false)
+
+		assert.Equal(t, []string{
+			"DEPRECATED: 'alpha.' prefix not necessary for 'operator.dynatrace.com/feature-disable-hosts-requests', effective value is 'false'",
+		}, warnings)
+	})
 }
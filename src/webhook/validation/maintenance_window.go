@@ -0,0 +1,22 @@
+package validation
+
+import (
+	"fmt"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	"github.com/Dynatrace/dynatrace-operator/src/controllers/dynakube/maintenance"
+)
+
+const (
+	errorInvalidMaintenanceWindow = `The DynaKube's specification contains an invalid maintenance window: %s.
+Make sure the schedule is a cron expression with 5 fields, the duration is positive and the time zone is a valid IANA time zone, e.g. "Europe/Vienna".
+`
+)
+
+func invalidMaintenanceWindows(dv *dynakubeValidator, dynakube *dynatracev1beta1.DynaKube) string {
+	if _, err := maintenance.NewWindows(dynakube); err != nil {
+		log.Info("requested dynakube has invalid maintenance windows", "name", dynakube.Name, "namespace", dynakube.Namespace, "error", err.Error())
+		return fmt.Sprintf(errorInvalidMaintenanceWindow, err.Error())
+	}
+	return ""
+}
@@ -0,0 +1,47 @@
+package validation
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestInvalidMaintenanceWindows(t *testing.T) {
+	newDynakube := func(windows ...dynatracev1beta1.MaintenanceWindowSpec) *dynatracev1beta1.DynaKube {
+		return &dynatracev1beta1.DynaKube{
+			ObjectMeta: defaultDynakubeObjectMeta,
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				APIURL:             testApiUrl,
+				MaintenanceWindows: windows,
+			},
+		}
+	}
+
+	t.Run(`valid maintenance windows`, func(t *testing.T) {
+		assertAllowedResponseWithoutWarnings(t, newDynakube(
+			dynatracev1beta1.MaintenanceWindowSpec{Schedule: "0 22 * * 6", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+			dynatracev1beta1.MaintenanceWindowSpec{Schedule: "*/30 1-5 1,15 * *", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "Europe/Vienna"},
+		))
+	})
+	t.Run(`invalid schedule`, func(t *testing.T) {
+		assertDeniedResponse(t,
+			[]string{fmt.Sprintf(errorInvalidMaintenanceWindow, `maintenance window 0: invalid schedule "0 24 * * *": invalid hour "24", expected a value from 0 to 23`)},
+			newDynakube(dynatracev1beta1.MaintenanceWindowSpec{Schedule: "0 24 * * *", Duration: metav1.Duration{Duration: time.Hour}}))
+	})
+	t.Run(`invalid time zone`, func(t *testing.T) {
+		assertDeniedResponse(t,
+			[]string{"maintenance window 1: invalid time zone \"Mars/Olympus\""},
+			newDynakube(
+				dynatracev1beta1.MaintenanceWindowSpec{Schedule: "0 0 * * *", Duration: metav1.Duration{Duration: time.Hour}},
+				dynatracev1beta1.MaintenanceWindowSpec{Schedule: "0 0 * * *", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "Mars/Olympus"},
+			))
+	})
+	t.Run(`missing duration`, func(t *testing.T) {
+		assertDeniedResponse(t,
+			[]string{"maintenance window 0: invalid duration 0s, it must be positive"},
+			newDynakube(dynatracev1beta1.MaintenanceWindowSpec{Schedule: "0 0 * * *"}))
+	})
+}
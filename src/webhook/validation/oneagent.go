@@ -15,6 +15,16 @@ const (
 
 	errorNodeSelectorConflict = `The DynaKube's specification tries to specify a nodeSelector conflicts with an another Dynakube's nodeSelector, which is not supported.
 The conflicting Dynakube: %s
+`
+
+	errorNodeGroupConflict = `The DynaKube's specification contains the node groups %s and %s, which select the same nodes, which is not supported.
+Node groups must differ in the value of at least one nodeSelector label.
+`
+
+	errorNodeGroupWithoutNodeSelector = `The DynaKube's specification contains the node group %s without a nodeSelector, which is not supported.
+`
+
+	errorDuplicateNodeGroup = `The DynaKube's specification contains the node group %s multiple times, which is not supported.
 `
 )
 
@@ -47,7 +57,13 @@ func conflictingReadOnlyFilesystemAndMultipleOsAgentsOnNode(_ *dynakubeValidator
 }
 
 func conflictingNodeSelector(dv *dynakubeValidator, dynakube *dynatracev1beta1.DynaKube) string {
-	if !dynakube.NeedsOneAgent() || dynakube.FeatureEnableMultipleOsAgentsOnNode() {
+	if !dynakube.NeedsOneAgent() {
+		return ""
+	}
+	if message := conflictingNodeGroups(dynakube); message != "" {
+		return message
+	}
+	if dynakube.FeatureEnableMultipleOsAgentsOnNode() {
 		return ""
 	}
 	validDynakubes := &dynatracev1beta1.DynaKubeList{}
@@ -71,6 +87,38 @@ func conflictingNodeSelector(dv *dynakubeValidator, dynakube *dynatracev1beta1.D
 	return ""
 }
 
+// conflictingNodeGroups makes sure that no node is part of multiple node groups, as each node group has its own DaemonSet.
+// Node groups are distinct only if they select different values for the same label, otherwise a node can carry the
+// labels of both.
+func conflictingNodeGroups(dynakube *dynatracev1beta1.DynaKube) string {
+	nodeGroups := dynakube.NodeGroups()
+	for i, nodeGroup := range nodeGroups {
+		if len(nodeGroup.NodeSelector) == 0 {
+			log.Info("requested dynakube has node group without nodeSelector", "name", dynakube.Name, "namespace", dynakube.Namespace)
+			return fmt.Sprintf(errorNodeGroupWithoutNodeSelector, nodeGroup.Name)
+		}
+		for _, other := range nodeGroups[:i] {
+			if other.Name == nodeGroup.Name {
+				return fmt.Sprintf(errorDuplicateNodeGroup, nodeGroup.Name)
+			}
+			if !hasDistinctLabelValue(nodeGroup.NodeSelector, other.NodeSelector) {
+				log.Info("requested dynakube has overlapping node groups", "name", dynakube.Name, "namespace", dynakube.Namespace)
+				return fmt.Sprintf(errorNodeGroupConflict, other.Name, nodeGroup.Name)
+			}
+		}
+	}
+	return ""
+}
+
+func hasDistinctLabelValue(labelMap, otherLabelMap map[string]string) bool {
+	for key, value := range labelMap {
+		if otherValue, ok := otherLabelMap[key]; ok && otherValue != value {
+			return true
+		}
+	}
+	return false
+}
+
 func imageFieldSetWithoutCSIFlag(dv *dynakubeValidator, dynakube *dynatracev1beta1.DynaKube) string {
 	if dynakube.ApplicationMonitoringMode() {
 		if !dynakube.NeedsCSIDriver() && len(dynakube.Spec.OneAgent.ApplicationMonitoring.CodeModulesImage) > 0 {
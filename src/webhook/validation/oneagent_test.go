@@ -161,7 +161,7 @@ func TestConflictingNodeSelector(t *testing.T) {
 			}, &defaultCSIDaemonSet)
 	})
 	t.Run(`valid dynakube specs with multitenant hostMonitoring`, func(t *testing.T) {
-		assertAllowedResponseWithWarnings(t, 0,
+		assertAllowedResponseWithWarnings(t, 1,
 			newCloudNativeDynakube("dk1", map[string]string{
 				dynatracev1beta1.AnnotationFeatureEnableMultipleOsAgentsOnNode: "true",
 			}, "1"),
@@ -170,7 +170,7 @@ func TestConflictingNodeSelector(t *testing.T) {
 			}, "2"),
 			&defaultCSIDaemonSet)
 
-		assertAllowedResponseWithWarnings(t, 0,
+		assertAllowedResponseWithWarnings(t, 1,
 			newCloudNativeDynakube("dk1", map[string]string{
 				dynatracev1beta1.AnnotationFeatureEnableMultipleOsAgentsOnNode: "true",
 			}, "1"),
@@ -214,6 +214,40 @@ func TestConflictingNodeSelector(t *testing.T) {
 				},
 			}, &defaultCSIDaemonSet)
 	})
+	t.Run(`node groups`, func(t *testing.T) {
+		newNodeGroupDynakube := func(nodeGroups ...dynatracev1beta1.NodeGroupSpec) *dynatracev1beta1.DynaKube {
+			return &dynatracev1beta1.DynaKube{
+				ObjectMeta: defaultDynakubeObjectMeta,
+				Spec: dynatracev1beta1.DynaKubeSpec{
+					APIURL: testApiUrl,
+					OneAgent: dynatracev1beta1.OneAgentSpec{
+						ClassicFullStack: &dynatracev1beta1.HostInjectSpec{
+							NodeGroups: nodeGroups,
+						},
+					},
+				},
+			}
+		}
+
+		assertAllowedResponseWithoutWarnings(t, newNodeGroupDynakube(
+			dynatracev1beta1.NodeGroupSpec{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}},
+			dynatracev1beta1.NodeGroupSpec{Name: "arm", NodeSelector: map[string]string{"pool": "default", "kubernetes.io/arch": "arm64"}},
+		), &defaultCSIDaemonSet)
+
+		assertDeniedResponse(t, []string{fmt.Sprintf(errorNodeGroupConflict, "gpu", "arm")}, newNodeGroupDynakube(
+			dynatracev1beta1.NodeGroupSpec{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}},
+			dynatracev1beta1.NodeGroupSpec{Name: "arm", NodeSelector: map[string]string{"kubernetes.io/arch": "arm64"}},
+		), &defaultCSIDaemonSet)
+
+		assertDeniedResponse(t, []string{fmt.Sprintf(errorNodeGroupWithoutNodeSelector, "gpu")}, newNodeGroupDynakube(
+			dynatracev1beta1.NodeGroupSpec{Name: "gpu"},
+		), &defaultCSIDaemonSet)
+
+		assertDeniedResponse(t, []string{fmt.Sprintf(errorDuplicateNodeGroup, "gpu")}, newNodeGroupDynakube(
+			dynatracev1beta1.NodeGroupSpec{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}},
+			dynatracev1beta1.NodeGroupSpec{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu2"}},
+		), &defaultCSIDaemonSet)
+	})
 	t.Run(`invalid dynakube specs with multitenant hostMonitoring`, func(t *testing.T) {
 		assertDeniedResponse(t, nil,
 			newCloudNativeDynakube("dk1", map[string]string{
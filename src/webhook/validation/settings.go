@@ -0,0 +1,39 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+)
+
+const (
+	errorInvalidSettingsObjectValue = `The DynaKube's specification contains a settings object with an invalid value, schemaId=%s.
+Make sure the value of every entry in the settings section is a JSON object.
+`
+
+	errorDuplicateSettingsObject = `The DynaKube's specification contains multiple settings objects with the same key for the same schema and scope, schemaId=%s scope=%s key=%s.
+Make sure the objects of a schema have different keys if the schema is declared more than once per scope in your custom resource.
+`
+)
+
+func invalidSettingsObjects(dv *dynakubeValidator, dynakube *dynatracev1beta1.DynaKube) string {
+	duplicateChecker := map[string]bool{}
+	for _, object := range dynakube.Spec.Settings {
+		var value map[string]interface{}
+		if err := json.Unmarshal(object.Value.Raw, &value); err != nil || value == nil {
+			log.Info("requested dynakube has invalid settings object value", "name", dynakube.Name, "namespace", dynakube.Namespace, "schemaId", object.SchemaID)
+			return fmt.Sprintf(errorInvalidSettingsObjectValue, object.SchemaID)
+		}
+
+		// whether the schema allows multiple objects is only known by the environment, the reconciler reports
+		// single-object schemas which are declared more than once
+		key := object.SchemaID + "/" + object.Scope + "/" + object.Key
+		if duplicateChecker[key] {
+			log.Info("requested dynakube has duplicate settings objects", "name", dynakube.Name, "namespace", dynakube.Namespace, "schemaId", object.SchemaID)
+			return fmt.Sprintf(errorDuplicateSettingsObject, object.SchemaID, object.Scope, object.Key)
+		}
+		duplicateChecker[key] = true
+	}
+	return ""
+}
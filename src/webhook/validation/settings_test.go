@@ -0,0 +1,65 @@
+package validation
+
+import (
+	"fmt"
+	"testing"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const testSchemaID = "builtin:test"
+
+func TestInvalidSettingsObjects(t *testing.T) {
+	t.Run(`valid settings objects`, func(t *testing.T) {
+		assertAllowedResponseWithoutWarnings(t, &dynatracev1beta1.DynaKube{
+			ObjectMeta: defaultDynakubeObjectMeta,
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				APIURL: testApiUrl,
+				Settings: []dynatracev1beta1.SettingsObjectSpec{
+					{SchemaID: testSchemaID, Value: runtime.RawExtension{Raw: []byte(`{"enabled": true}`)}},
+					{SchemaID: testSchemaID, Scope: "HOST-42", Value: runtime.RawExtension{Raw: []byte(`{"enabled": false}`)}},
+				},
+			},
+		})
+	})
+	t.Run(`value is not an object`, func(t *testing.T) {
+		assertDeniedResponse(t,
+			[]string{fmt.Sprintf(errorInvalidSettingsObjectValue, testSchemaID)},
+			&dynatracev1beta1.DynaKube{
+				ObjectMeta: defaultDynakubeObjectMeta,
+				Spec: dynatracev1beta1.DynaKubeSpec{
+					APIURL: testApiUrl,
+					Settings: []dynatracev1beta1.SettingsObjectSpec{
+						{SchemaID: testSchemaID, Value: runtime.RawExtension{Raw: []byte(`[1, 2]`)}},
+					},
+				},
+			})
+	})
+	t.Run(`same schema and scope with different keys`, func(t *testing.T) {
+		assertAllowedResponseWithoutWarnings(t, &dynatracev1beta1.DynaKube{
+			ObjectMeta: defaultDynakubeObjectMeta,
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				APIURL: testApiUrl,
+				Settings: []dynatracev1beta1.SettingsObjectSpec{
+					{SchemaID: testSchemaID, Key: "cpu", Value: runtime.RawExtension{Raw: []byte(`{"enabled": true}`)}},
+					{SchemaID: testSchemaID, Key: "memory", Value: runtime.RawExtension{Raw: []byte(`{"enabled": true}`)}},
+				},
+			},
+		})
+	})
+	t.Run(`duplicate schema, scope and key`, func(t *testing.T) {
+		assertDeniedResponse(t,
+			[]string{fmt.Sprintf(errorDuplicateSettingsObject, testSchemaID, "", "")},
+			&dynatracev1beta1.DynaKube{
+				ObjectMeta: defaultDynakubeObjectMeta,
+				Spec: dynatracev1beta1.DynaKubeSpec{
+					APIURL: testApiUrl,
+					Settings: []dynatracev1beta1.SettingsObjectSpec{
+						{SchemaID: testSchemaID, Value: runtime.RawExtension{Raw: []byte(`{"enabled": true}`)}},
+						{SchemaID: testSchemaID, Value: runtime.RawExtension{Raw: []byte(`{"enabled": false}`)}},
+					},
+				},
+			})
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta1"
+	dynatracev1beta2 "github.com/Dynatrace/dynatrace-operator/src/api/v1beta2"
 	"github.com/Dynatrace/dynatrace-operator/src/scheme"
 	"github.com/pkg/errors"
 	"k8s.io/client-go/rest"
@@ -56,6 +57,7 @@ func (validator *dynakubeValidator) Handle(_ context.Context, request admission.
 		response = admission.Denied(sumErrors(validationErrors))
 	}
 	warningMessages := validator.runValidators(warnings, dynakube)
+	warningMessages = append(warningMessages, featureFlagWarnings(dynakube, !isRequestedAsV1beta2(request))...)
 	if len(warningMessages) > 0 {
 		if hasPreviewWarning(warningMessages) {
 			warningMessages = append(warningMessages, basePreviewWarning)
@@ -96,6 +98,12 @@ func decodeRequestToDynakube(request admission.Request, dynakube *dynatracev1bet
 	return nil
 }
 
+// isRequestedAsV1beta2 returns true if the DynaKube was sent as v1beta2 and converted to v1beta1 for the validation,
+// its feature flag annotations are then set by the conversion from the typed fields and not by the user
+func isRequestedAsV1beta2(request admission.Request) bool {
+	return request.RequestKind != nil && request.RequestKind.Version == dynatracev1beta2.GroupVersion.Version
+}
+
 func hasPreviewWarning(warnings []string) bool {
 	for _, warning := range warnings {
 		if strings.Contains(warning, "PREVIEW") {
@@ -217,16 +217,20 @@ func handleRequest(t *testing.T, dynakube *dynatracev1beta1.DynaKube, other ...c
 		cfg:       &rest.Config{},
 	}
 
+	return validator.Handle(context.TODO(), newDynakubeRequest(t, dynakube))
+}
+
+func newDynakubeRequest(t *testing.T, dynakube *dynatracev1beta1.DynaKube) admission.Request {
 	data, err := json.Marshal(*dynakube)
 	require.NoError(t, err)
 
-	return validator.Handle(context.TODO(), admission.Request{
+	return admission.Request{
 		AdmissionRequest: v1.AdmissionRequest{
 			Name:      testName,
 			Namespace: testNamespace,
 			Object:    runtime.RawExtension{Raw: data},
 		},
-	})
+	}
 }
 
 func TestDynakubeValidator_InjectClient(t *testing.T) {